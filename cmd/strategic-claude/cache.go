@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/cache"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Show or clear the template clone cache",
+	Long: `Remote templates are cloned once into a cache under $XDG_CACHE_HOME/strategic-claude
+(~/.cache/strategic-claude by default) and reused by later runs. A pinned commit
+that is already cached installs without network access; templates that follow
+their branch are fetched to pick up new commits. Templates sharing a repository,
+like the built-in ones, share one clone holding all of its branches.
+
+Run without a subcommand to show where the cache is and how much space it uses.
+Pass --no-cache to any command to clone afresh instead, or --offline to use only
+what is cached and never the network.
+
+Examples:
+  strategic-claude-basic-cli cache         # Show the cache location and size
+  strategic-claude-basic-cli cache refresh # Fetch every template repository
+  strategic-claude-basic-cli cache clean   # Remove every cached clone`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cacheService := cache.New()
+		if !cacheService.Enabled() {
+			utils.DisplayInfo("No cache directory is available; templates are cloned on every run")
+			return nil
+		}
+
+		size, err := cacheService.Size()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cache directory: %s\n", cacheService.Dir())
+		fmt.Printf("Size: %s\n", formatBytes(size))
+		return nil
+	},
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove every cached template clone",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		freed, err := cache.New().Clean()
+		if err != nil {
+			return fmt.Errorf("failed to clean cache: %w", err)
+		}
+		utils.DisplaySuccess(fmt.Sprintf("Removed cached templates (%s freed)", formatBytes(freed)))
+		return nil
+	},
+}
+
+var cacheRefreshCmd = &cobra.Command{
+	Use:   "refresh [template-id...]",
+	Short: "Fetch the latest commits of cached template repositories",
+	Long: `Fetch every branch and tag of the repositories behind the given templates, or
+of every remote template in the registry when none are given, cloning any that
+are not cached yet. Each repository is fetched once however many templates use
+it. Refreshing ahead of time lets later runs, including --offline ones, install
+the newest branch heads without waiting on the network.
+
+Examples:
+  strategic-claude-basic-cli cache refresh        # Refresh every template repository
+  strategic-claude-basic-cli cache refresh main   # Refresh the repository of one template`,
+	ValidArgsFunction: completeTemplateIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if offline {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "cache refresh fetches from the network; it cannot be used with --offline", nil)
+		}
+		cacheService := cache.New()
+		if !cacheService.Enabled() {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "No cache directory is available to refresh", nil)
+		}
+
+		urls, err := refreshURLs(args)
+		if err != nil {
+			return err
+		}
+		if len(urls) == 0 {
+			utils.DisplayInfo("No remote templates to refresh")
+			return nil
+		}
+
+		ctx, cancel := context.Background(), func() {}
+		if gitTimeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, gitTimeout)
+		}
+		defer cancel()
+
+		for _, url := range urls {
+			cloned, err := cacheService.Refresh(ctx, url, gitRetryOptions())
+			if err != nil {
+				return fmt.Errorf("failed to refresh %s: %w", url, err)
+			}
+			if cloned {
+				utils.DisplaySuccess(fmt.Sprintf("Cloned %s", url))
+			} else {
+				utils.DisplaySuccess(fmt.Sprintf("Refreshed %s", url))
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+	cacheCmd.AddCommand(cacheRefreshCmd)
+}
+
+// refreshURLs returns the repositories of the given templates, or of every
+// registered template when ids is empty, once each in order. Local templates
+// are not cached and are left out.
+func refreshURLs(ids []string) ([]string, error) {
+	var selected []templates.Template
+	if len(ids) == 0 {
+		selected = templates.ListTemplates()
+	}
+	for _, id := range ids {
+		template, err := templates.GetTemplate(id)
+		if err != nil {
+			return nil, suggestTemplates(err, id)
+		}
+		selected = append(selected, template)
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, template := range selected {
+		if template.IsLocal() || seen[template.RepoURL] {
+			continue
+		}
+		seen[template.RepoURL] = true
+		urls = append(urls, template.RepoURL)
+	}
+	return urls, nil
+}
+
+// formatBytes renders a size in the largest binary unit, e.g. "1.5 MiB"
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
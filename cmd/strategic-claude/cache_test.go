@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestRefreshURLs(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+	templates.Registry.Set(map[string]templates.Template{
+		"main":  {ID: "main", Name: "main", Description: "Test", RepoURL: "https://example.com/base.git", Branch: "main", FollowBranch: true},
+		"ccr":   {ID: "ccr", Name: "ccr", Description: "Test", RepoURL: "https://example.com/base.git", Branch: "ccr", FollowBranch: true},
+		"other": {ID: "other", Name: "other", Description: "Test", RepoURL: "https://example.com/other.git", Branch: "main", FollowBranch: true},
+		"local": {ID: "local", Name: "local", Description: "Test", RepoURL: t.TempDir(), Branch: "main", FollowBranch: true},
+	})
+
+	tests := []struct {
+		name string
+		ids  []string
+		want []string
+	}{
+		{"every remote repository once", nil, []string{"https://example.com/base.git", "https://example.com/other.git"}},
+		{"templates sharing a repository", []string{"main", "ccr"}, []string{"https://example.com/base.git"}},
+		{"local templates are skipped", []string{"local", "other"}, []string{"https://example.com/other.git"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls, err := refreshURLs(tt.ids)
+			if err != nil {
+				t.Fatalf("refreshURLs() error = %v", err)
+			}
+			if strings.Join(urls, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("refreshURLs() = %v, want %v", urls, tt.want)
+			}
+		})
+	}
+
+	if _, err := refreshURLs([]string{"missing"}); !errors.Is(err, templates.ErrNotFound) {
+		t.Errorf("refreshURLs() of an unknown template = %v, want ErrNotFound", err)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -49,10 +50,7 @@ Examples:
 			return fmt.Errorf("failed to resolve target directory: %w", err)
 		}
 
-		if verbose {
-			fmt.Printf("Cleaning directory: %s\n", absTarget)
-			fmt.Printf("Force: %v\n", cleanForce)
-		}
+		slog.Debug("Cleaning directory", "dir", absTarget, "force", cleanForce)
 
 		// Initialize services
 		cleanerService := cleaner.New()
@@ -3,16 +3,23 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
 
 	"github.com/spf13/cobra"
 )
 
 var completionsCmd = &cobra.Command{
-	Use:   "completions [bash|zsh|fish|powershell]",
-	Short: "Generate shell completion scripts",
+	Use:     "completions [bash|zsh|fish|powershell]",
+	Aliases: []string{"completion"},
+	Short:   "Generate shell completion scripts",
 	Long: `Generate shell completion scripts for strategic-claude-basic-cli.
 
-The completion script must be sourced to take effect.
+The completion script must be sourced to take effect. Besides commands and
+flags, it completes template IDs (for init --template and info) from the
+registry, leaving out deprecated templates unless nothing else matches.
 
 Bash:
   # Add to ~/.bashrc or ~/.bash_profile
@@ -61,4 +68,76 @@ PowerShell:
 
 func init() {
 	rootCmd.AddCommand(completionsCmd)
+
+	// completionsCmd replaces cobra's own completion command
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+}
+
+// completeTemplateIDs completes template IDs from the built-in and user
+// registries, described by their names. Deprecated templates are only offered when no active template
+// matches what has been typed, so an old ID can still be completed in full.
+func completeTemplateIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Completion skips the hooks that load the user registry; load it quietly,
+	// since any warning would end up among the completions
+	if path := userRegistryPath(); path != "" {
+		_, _ = templates.LoadRegistryFile(path, registryOverride)
+	}
+
+	var active, deprecated []string
+	for _, template := range templates.ListTemplates() {
+		if !strings.HasPrefix(template.ID, toComplete) {
+			continue
+		}
+		completion := template.ID + "\t" + template.DisplayName()
+		if template.Deprecated {
+			deprecated = append(deprecated, completion)
+		} else {
+			active = append(active, completion)
+		}
+	}
+
+	if len(active) == 0 {
+		return deprecated, cobra.ShellCompDirectiveNoFileComp
+	}
+	return active, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplateBranches completes --branch with the branches registry
+// templates follow
+func completeTemplateBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if path := userRegistryPath(); path != "" {
+		_, _ = templates.LoadRegistryFile(path, registryOverride)
+	}
+
+	var branches []string
+	seen := make(map[string]bool)
+	for _, template := range templates.ListTemplates() {
+		if template.Branch == "" || seen[template.Branch] || !strings.HasPrefix(template.Branch, toComplete) {
+			continue
+		}
+		seen[template.Branch] = true
+		branches = append(branches, template.Branch+"\t"+template.ID)
+	}
+	return branches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeInstalledTemplateIDs completes the templates recorded in the lock
+// file of the directory argument, or of --target
+func completeInstalledTemplateIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	dir := targetDir
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	lock, err := state.ReadLock(dir)
+	if err != nil || lock == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, id := range lock.TemplateIDs() {
+		if strings.HasPrefix(id, toComplete) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
 }
@@ -3,9 +3,13 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+
 	"github.com/spf13/cobra"
 )
 
@@ -307,3 +311,46 @@ func TestCompletionsCommandHelp(t *testing.T) {
 		}
 	}
 }
+
+func TestCompleteTemplateIDs(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origRegistryFile := registryFile
+	defer func() {
+		templates.Registry.Set(original)
+		registryFile = origRegistryFile
+	}()
+
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	templates.Registry.Set(map[string]templates.Template{
+		"main":   {ID: "main", Name: "Main", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit},
+		"ccr":    {ID: "ccr", Name: "CCR", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit},
+		"legacy": {ID: "legacy", Name: "Legacy", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit, Deprecated: true, ReplacedBy: "main"},
+	})
+	registryFile = filepath.Join(t.TempDir(), "templates.yaml")
+	if err := os.WriteFile(registryFile, []byte("templates:\n  mine:\n    name: Mine\n    repo_url: /tmp/mine\n"), 0644); err != nil {
+		t.Fatalf("Failed to write registry: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{name: "all active templates", toComplete: "", want: []string{"ccr\tCCR (ccr)", "main\tMain (main)", "mine\tMine (mine)"}},
+		{name: "prefix", toComplete: "m", want: []string{"main\tMain (main)", "mine\tMine (mine)"}},
+		{name: "deprecated only when nothing else matches", toComplete: "le", want: []string{"legacy\tLegacy (legacy) [deprecated, use main]"}},
+		{name: "no match", toComplete: "zzz", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := completeTemplateIDs(initCmd, nil, tt.toComplete)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("completeTemplateIDs(%q) = %q, want %q", tt.toComplete, got, tt.want)
+			}
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("Expected ShellCompDirectiveNoFileComp, got %d", directive)
+			}
+		})
+	}
+}
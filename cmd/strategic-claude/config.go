@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/userconfig"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// projectConfig holds the settings of the target's project config, read
+// before each command runs
+var projectConfig = &userconfig.Project{}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or edit your default settings",
+	Long: `Defaults for commonly repeated flags are read from config.yaml in
+$XDG_CONFIG_HOME/strategic-claude (~/.config/strategic-claude by default):
+
+  default_template  template installed by init (--template)
+  registry_url      remote template registry (--registry-url)
+  no_backup         skip backing up existing files (--no-backup)
+  jobs              templates fetched at once (--jobs)
+  offline           use only cached templates and registries (--offline)
+  archive_dir       archives of pinned template commits (--archive-dir)
+
+A project can commit its own settings in .strategic-claude/config.yaml, read
+from the target directory. It takes the same keys, for example to pin the
+template the project installs, plus settings init and update apply for everyone:
+
+  exclude_patterns  patterns for files not to install, used when --exclude
+                    is not given
+  variables         template variable values; --set overrides them by name
+
+Each setting can also come from an environment variable, such as
+STRATEGIC_CLAUDE_TEMPLATE. An explicit flag wins over the environment variable,
+which wins over the project config, then the config file, then the built-in
+default.
+
+Run without a subcommand to list every setting with its value and source.
+
+Examples:
+  strategic-claude-basic-cli config                              # List settings
+  strategic-claude-basic-cli config get default_template         # Print one value
+  strategic-claude-basic-cli config set default_template ccr     # Save a default
+  strategic-claude-basic-cli config unset default_template       # Remove it`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, userConfig, err := loadUserConfig()
+		if err != nil {
+			return err
+		}
+		projectPath, project, err := loadProjectConfig(targetDir)
+		if err != nil {
+			return err
+		}
+		settings, err := project.Resolve(userConfig)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Config file: %s\n", path)
+		if _, err := os.Stat(projectPath); err == nil {
+			fmt.Printf("Project config: %s\n", projectPath)
+		}
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE\tSOURCE\tENVIRONMENT")
+		for _, setting := range settings {
+			value, source := setting.Value, string(setting.Source)
+			if setting.Source == userconfig.SourceUnset {
+				value, source = "-", "default"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", setting.Name, value, source, setting.Env)
+		}
+		return w.Flush()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Long: `Print the value a setting currently resolves to, from its environment
+variable, the project config, or the config file. Nothing is printed for a
+setting that is not set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := userconfig.LookupKey(args[0])
+		if err != nil {
+			return err
+		}
+		_, userConfig, err := loadUserConfig()
+		if err != nil {
+			return err
+		}
+		_, project, err := loadProjectConfig(targetDir)
+		if err != nil {
+			return err
+		}
+		settings, err := project.Resolve(userConfig)
+		if err != nil {
+			return err
+		}
+		for _, setting := range settings {
+			if setting.Name == key.Name && setting.Value != "" {
+				fmt.Println(setting.Value)
+			}
+		}
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Save a setting in the config file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := userconfig.LookupKey(args[0])
+		if err != nil {
+			return err
+		}
+		path, userConfig, err := loadUserConfig()
+		if err != nil {
+			return err
+		}
+		if err := userConfig.Set(key, args[1]); err != nil {
+			return err
+		}
+		if err := userConfig.Save(path); err != nil {
+			return err
+		}
+
+		utils.DisplaySuccess(fmt.Sprintf("Set %s to %s in %s", key.Name, args[1], path))
+		if value, ok := os.LookupEnv(key.Env); ok && value != "" {
+			utils.DisplayWarning(fmt.Sprintf("%s is set in the environment and takes precedence", key.Env))
+		} else if projectPath, project, err := loadProjectConfig(targetDir); err == nil && project.Get(key) != "" {
+			utils.DisplayWarning(fmt.Sprintf("%s sets %s and takes precedence in this project", projectPath, key.Name))
+		}
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting from the config file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := userconfig.LookupKey(args[0])
+		if err != nil {
+			return err
+		}
+		path, userConfig, err := loadUserConfig()
+		if err != nil {
+			return err
+		}
+		userConfig.Unset(key)
+		if err := userConfig.Save(path); err != nil {
+			return err
+		}
+
+		utils.DisplaySuccess(fmt.Sprintf("Removed %s from %s", key.Name, path))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd)
+
+	completeKey := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := make([]string, 0, len(userconfig.Keys))
+		for _, key := range userconfig.Keys {
+			names = append(names, key.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+	configGetCmd.ValidArgsFunction = completeKey
+	configSetCmd.ValidArgsFunction = completeKey
+	configUnsetCmd.ValidArgsFunction = completeKey
+}
+
+// loadUserConfig reads the user config file, returning its path too
+func loadUserConfig() (string, *userconfig.Config, error) {
+	path, err := userconfig.Path()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to locate config file: %w", err)
+	}
+	userConfig, err := userconfig.Load(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, userConfig, nil
+}
+
+// loadProjectConfig reads the project config of the target directory,
+// returning its path too
+func loadProjectConfig(target string) (string, *userconfig.Project, error) {
+	path := userconfig.ProjectPath(target)
+	project, err := userconfig.LoadProject(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, project, nil
+}
+
+// projectTarget returns the directory a command works on, whose project config
+// applies: its [directory] argument when given, else --target
+func projectTarget(cmd *cobra.Command, args []string) string {
+	if strings.HasSuffix(cmd.Use, "[directory]") && len(args) > 0 {
+		return args[0]
+	}
+	return targetDir
+}
+
+// applyUserConfig fills in the flags the user did not set on the command line
+// from the environment, the project config, and the config file. The project's
+// exclude patterns stand in for --exclude when it is not given, and its
+// variables are defaults that --set overrides one by one.
+func applyUserConfig(cmd *cobra.Command, args []string) error {
+	userConfig := &userconfig.Config{}
+	if path, err := userconfig.Path(); err == nil { // No home directory, nothing to load
+		if userConfig, err = userconfig.Load(path); err != nil {
+			return err
+		}
+	}
+	_, project, err := loadProjectConfig(projectTarget(cmd, args))
+	if err != nil {
+		return err
+	}
+	projectConfig = project
+
+	settings, err := project.Resolve(userConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range settings {
+		flag := cmd.Flags().Lookup(setting.Flag)
+		if setting.Source == userconfig.SourceUnset || flag == nil || flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(setting.Value); err != nil {
+			return fmt.Errorf("invalid %s from the %s: %w", setting.Name, setting.Source, err)
+		}
+		utils.VerbosePrintf(verbose, "Using %s=%s from the %s\n", setting.Name, setting.Value, setting.Source)
+	}
+
+	if flag := cmd.Flags().Lookup("exclude"); flag != nil && !flag.Changed && len(project.ExcludePatterns) > 0 {
+		if err := flag.Value.(pflag.SliceValue).Replace(project.ExcludePatterns); err != nil {
+			return err
+		}
+		utils.VerbosePrintf(verbose, "Using exclude_patterns from the %s\n", userconfig.SourceProject)
+	}
+	if flag := cmd.Flags().Lookup("set"); flag != nil && len(project.Variables) > 0 {
+		// Later pairs win, so the command line's come last
+		values := flag.Value.(pflag.SliceValue)
+		var pairs []string
+		for _, name := range slices.Sorted(maps.Keys(project.Variables)) {
+			pairs = append(pairs, name+"="+project.Variables[name])
+		}
+		if err := values.Replace(append(pairs, values.GetSlice()...)); err != nil {
+			return err
+		}
+		utils.VerbosePrintf(verbose, "Using variables from the %s\n", userconfig.SourceProject)
+	}
+	return nil
+}
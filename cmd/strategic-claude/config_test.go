@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/userconfig"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyUserConfig(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("STRATEGIC_CLAUDE_TEMPLATE", "")
+	t.Setenv("STRATEGIC_CLAUDE_NO_BACKUP", "")
+	t.Setenv("STRATEGIC_CLAUDE_JOBS", "6")
+
+	path := filepath.Join(configHome, "strategic-claude", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "default_template: ccr\nno_backup: true\njobs: 2\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var template []string
+	var backup bool
+	var jobCount int
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringSliceVar(&template, "template", nil, "")
+	cmd.Flags().BoolVar(&backup, "no-backup", false, "")
+	cmd.Flags().IntVar(&jobCount, "jobs", 1, "")
+	if err := cmd.ParseFlags([]string{"--template", "main"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyUserConfig(cmd, nil); err != nil {
+		t.Fatalf("applyUserConfig() error = %v", err)
+	}
+
+	if len(template) != 1 || template[0] != "main" {
+		t.Errorf("template = %v, want the explicit flag [main]", template)
+	}
+	if !backup {
+		t.Errorf("no-backup = false, want true from the config file")
+	}
+	if jobCount != 6 {
+		t.Errorf("jobs = %d, want 6 from the environment", jobCount)
+	}
+}
+
+func TestApplyUserConfig_Project(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("STRATEGIC_CLAUDE_TEMPLATE", "")
+	t.Setenv("STRATEGIC_CLAUDE_NO_BACKUP", "")
+	t.Cleanup(func() { projectConfig = &userconfig.Project{} })
+
+	writeConfig := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(filepath.Join(configHome, "strategic-claude", "config.yaml"), "default_template: ccr\nno_backup: true\n")
+	project := t.TempDir()
+	writeConfig(userconfig.ProjectPath(project), `default_template: main
+exclude_patterns: ["drafts/"]
+variables:
+  Team: platform
+  ProjectName: Acme
+`)
+
+	var template, exclude, set []string
+	var backup bool
+	cmd := &cobra.Command{Use: "test [directory]"}
+	cmd.Flags().StringSliceVar(&template, "template", nil, "")
+	cmd.Flags().BoolVar(&backup, "no-backup", false, "")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "")
+	cmd.Flags().StringArrayVar(&set, "set", nil, "")
+	if err := cmd.ParseFlags([]string{"--set", "Team=cli"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyUserConfig(cmd, []string{project}); err != nil {
+		t.Fatalf("applyUserConfig() error = %v", err)
+	}
+
+	if len(template) != 1 || template[0] != "main" {
+		t.Errorf("template = %v, want [main] from the project config", template)
+	}
+	if !backup {
+		t.Errorf("no-backup = false, want true from the config file")
+	}
+	if !reflect.DeepEqual(exclude, []string{"drafts/"}) {
+		t.Errorf("exclude = %v, want the project's patterns", exclude)
+	}
+	variables, err := parseVariables(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"Team": "cli", "ProjectName": "Acme"}; !reflect.DeepEqual(variables, want) {
+		t.Errorf("variables = %v, want %v with --set winning", variables, want)
+	}
+	if !reflect.DeepEqual(projectConfig.ExcludePatterns, []string{"drafts/"}) {
+		t.Errorf("projectConfig = %+v, want the loaded project config", projectConfig)
+	}
+}
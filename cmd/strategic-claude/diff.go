@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/color"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/installer"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffNameOnly bool
+	diffSet      []string
+	diffFrom     string
+	diffTo       string
+	diffTemplate string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [directory]",
+	Short: "Show how installed files differ from the template",
+	Long: `Compare the files installed into a project with the template at the commit
+currently pinned in the registry (or the branch head for templates that follow
+their branch), printing a unified diff for each file that differs.
+
+Only files recorded in the lock file at install time are compared, so your own
+files are never listed. When several templates are layered, each file is
+compared with the template that installed it. Template variables are rendered as on install; values
+given with --set during init are not recorded, so pass them again to keep them
+out of the diff.
+
+With --from, no project is involved: the template given with --template (the
+default template otherwise) is fetched at the --from commit and at the --to
+commit, or at the commit pinned in the registry when --to is left out, and the
+files each would install are compared. This is handy for writing a changelog
+between two pins.
+
+Output is colorized when written to a terminal, unless --no-color or NO_COLOR
+is set.
+
+Examples:
+  strategic-claude-basic-cli diff                         # Diff the current directory
+  strategic-claude-basic-cli diff ./my-project           # Diff a specific directory
+  strategic-claude-basic-cli diff --name-only            # Only list the files that differ
+  strategic-claude-basic-cli diff --set ProjectName=Acme # Render variables as installed
+  strategic-claude-basic-cli diff --template ccr --from 2c9fa88 --to 5d1e0b7 --name-only  # Files changed between two commits`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffFrom != "" {
+			if len(args) > 0 {
+				return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--from compares two commits of a template and takes no directory", nil)
+			}
+			return runDiffCommits()
+		}
+		if diffTo != "" || diffTemplate != "" {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--to and --template require --from", nil)
+		}
+
+		target := targetDir
+		if len(args) > 0 {
+			target = args[0]
+		}
+
+		absTarget, err := filepath.Abs(target)
+		if err != nil {
+			return fmt.Errorf("failed to resolve target directory: %w", err)
+		}
+
+		lock, err := state.ReadLock(absTarget)
+		if err != nil {
+			return err
+		}
+		if lock == nil {
+			return missingLockError(absTarget)
+		}
+		if len(lock.AllFiles()) == 0 {
+			return models.NewAppError(
+				models.ErrorCodeNotInstalled,
+				"The lock file has no record of installed files (installed by an older version); run 'update --force' to record them",
+				nil,
+			)
+		}
+
+		variableValues, err := parseVariables(diffSet)
+		if err != nil {
+			return err
+		}
+
+		// Each layered template is compared on the files it installed
+		var diffs []models.FileDiff
+		for _, entry := range lock.Templates {
+			template, err := templates.GetTemplate(entry.TemplateID)
+			if err != nil {
+				return fmt.Errorf("installed template is no longer available: %w", suggestTemplates(err, entry.TemplateID))
+			}
+
+			utils.VerbosePrintf(verbose, "Comparing %s with %s at %s\n", absTarget, template.ID, describeTargetCommit(template))
+
+			templateDiffs, err := installer.New().DiffInstalled(models.InstallConfig{
+				TargetDir:      absTarget,
+				TemplateID:     template.ID,
+				CloneDepth:     config.DefaultCloneDepth,
+				NoCache:        noCache,
+				Offline:        offline,
+				ArchiveDir:     archiveDir,
+				Retries:        gitRetries,
+				GitTimeout:     gitTimeout,
+				Verbose:        verbose,
+				Variables:      variableValues,
+				RenderPatterns: config.GetDefaultRenderPatterns(),
+			}, entry.Files)
+			if err != nil {
+				return fmt.Errorf("diff failed: %w", err)
+			}
+			diffs = append(diffs, templateDiffs...)
+		}
+
+		displayFileDiffs(diffs, diffNameOnly, color.Enabled())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().BoolVar(&diffNameOnly, "name-only", false, "only list the paths of files that differ")
+	diffCmd.Flags().StringArrayVar(&diffSet, "set", nil, "set a template variable as name=value, expanding $NAME from the environment (repeatable)")
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "compare the template's files at this commit instead of an installed project")
+	diffCmd.Flags().StringVar(&diffTo, "to", "", "with --from, the commit to compare with (default: the registry's pinned commit)")
+	diffCmd.Flags().StringVar(&diffTemplate, "template", "", "with --from, the template to compare (default: main)")
+
+	// Custom completion for directory argument
+	diffCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return []string{}, cobra.ShellCompDirectiveFilterDirs
+		}
+		return []string{}, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// runDiffCommits prints how the files of the --template template differ
+// between the --from and --to commits
+func runDiffCommits() error {
+	templateID := diffTemplate
+	if templateID == "" {
+		templateID = templates.DefaultTemplateID
+	}
+	template, err := templates.GetTemplate(templateID)
+	if err != nil {
+		return suggestTemplates(err, templateID)
+	}
+
+	variableValues, err := parseVariables(diffSet)
+	if err != nil {
+		return err
+	}
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+
+	to := diffTo
+	if to == "" {
+		to = describeTargetCommit(template)
+	}
+	utils.VerbosePrintf(verbose, "Comparing %s at %s with %s\n", template.ID, diffFrom, to)
+
+	diffs, err := installer.New().DiffCommits(models.InstallConfig{
+		TargetDir:      absTarget,
+		TemplateID:     template.ID,
+		CloneDepth:     config.DefaultCloneDepth,
+		NoCache:        noCache,
+		Offline:        offline,
+		Retries:        gitRetries,
+		GitTimeout:     gitTimeout,
+		Verbose:        verbose,
+		Variables:      variableValues,
+		RenderPatterns: config.GetDefaultRenderPatterns(),
+	}, diffFrom, diffTo)
+	if err != nil {
+		return fmt.Errorf("diff failed: %w", err)
+	}
+
+	if len(diffs) == 0 {
+		if !diffNameOnly {
+			utils.DisplaySuccess(fmt.Sprintf("Template %s installs the same files at %s and %s", template.ID, diffFrom, to))
+		}
+		return nil
+	}
+	displayFileDiffs(diffs, diffNameOnly, color.Enabled())
+	return nil
+}
+
+// displayFileDiffs prints a unified diff from the template's copy to the local
+// one for each file, or just the paths
+func displayFileDiffs(diffs []models.FileDiff, nameOnly, colorize bool) {
+	if len(diffs) == 0 {
+		if !nameOnly {
+			utils.DisplaySuccess("Installed files match the template")
+		}
+		return
+	}
+
+	for _, diff := range diffs {
+		if nameOnly {
+			fmt.Println(diff.Path)
+			continue
+		}
+
+		fromName, toName := "a/"+diff.Path, "b/"+diff.Path
+		if diff.MissingInTemplate {
+			fromName = "/dev/null"
+		}
+		if diff.MissingLocally {
+			toName = "/dev/null"
+		}
+
+		text := utils.UnifiedDiff(fromName, toName, diff.Template, diff.Local)
+		if text == "" {
+			// Both copies are empty; only one side is missing
+			text = fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName)
+		}
+		if colorize {
+			text = utils.ColorizeDiff(text)
+		}
+		fmt.Print(text)
+	}
+}
+
+// stdoutIsTerminal reports whether output goes to an interactive terminal
+func stdoutIsTerminal() bool {
+	return isTerminal(os.Stdout)
+}
+
+// stdinIsTerminal reports whether input comes from an interactive terminal
+func stdinIsTerminal() bool {
+	return isTerminal(os.Stdin)
+}
+
+// stderrIsTerminal reports whether log and progress output goes to an interactive terminal
+func stderrIsTerminal() bool {
+	return isTerminal(os.Stderr)
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/doctor"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [directory]",
+	Short: "Check that the environment can install templates",
+	Long: `Run a self-check of everything init depends on and print a checklist.
+
+This command will:
+- Verify git is installed and report its version
+- Check that the default template repository can be reached
+- Validate every template in the registry, including user-defined ones
+- Confirm the target directory is writable
+
+Each failed check includes a hint on how to fix it. The command exits with
+an error if any check fails.
+
+Examples:
+  strategic-claude-basic-cli doctor                 # Check using the current directory
+  strategic-claude-basic-cli doctor ./my-project   # Check a specific target directory`,
+	Args: cobra.MaximumNArgs(1),
+	// A failed check is reported in the checklist, not as a usage mistake
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := targetDir
+		if len(args) > 0 {
+			target = args[0]
+		}
+
+		report := doctor.New().Run(target)
+		displayDoctorReport(report)
+
+		if failed := report.Failed(); failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(report.Checks))
+		}
+		return nil
+	},
+}
+
+// displayDoctorReport prints each check as a checklist line with hints for failures
+func displayDoctorReport(report *doctor.Report) {
+	for _, check := range report.Checks {
+		if check.Passed {
+			fmt.Printf("✅ %s: %s\n", check.Name, check.Detail)
+			continue
+		}
+		fmt.Printf("❌ %s: %s\n", check.Name, check.Detail)
+		if check.Hint != "" {
+			fmt.Printf("   → %s\n", check.Hint)
+		}
+	}
+
+	if report.Failed() == 0 {
+		fmt.Printf("\nAll %d checks passed.\n", len(report.Checks))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
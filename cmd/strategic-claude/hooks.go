@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/color"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/settings"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var hooksDryRun bool
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage the Claude hooks a template provides",
+	Long: `Manage the Claude hooks a template provides.
+
+Templates ship hook scripts along with a settings template listing when Claude
+should run them. The hooks only take effect once they are registered in the
+project's .claude/settings.json.`,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install [directory]",
+	Short: "Register the installed template's hooks in .claude/settings.json",
+	Long: `Register the hooks of the installed template in the project's
+.claude/settings.json, reading them from the template's
+` + config.SettingsTemplateFile + `.
+
+init does this on install; run it again after editing the settings template,
+or to restore hook registrations removed from settings.json. Hooks and
+permissions you added yourself are kept, and a hook already registered is not
+added twice. The current settings.json is backed up next to it first.
+
+Use --dry-run to print the change to settings.json as a unified diff without
+writing anything.
+
+Examples:
+  strategic-claude-basic-cli hooks install              # Register hooks in the current directory
+  strategic-claude-basic-cli hooks install ./my-project # Register hooks in a specific directory
+  strategic-claude-basic-cli hooks install --dry-run    # Preview the change to settings.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := targetDir
+		if len(args) > 0 {
+			target = args[0]
+		}
+
+		absTarget, err := filepath.Abs(target)
+		if err != nil {
+			return fmt.Errorf("failed to resolve target directory: %w", err)
+		}
+		if info, err := os.Stat(filepath.Join(absTarget, config.StrategicClaudeBasicDir)); err != nil || !info.IsDir() {
+			return models.NewAppError(
+				models.ErrorCodeNotInstalled,
+				fmt.Sprintf("No template is installed in %s; run 'init' first", absTarget),
+				nil,
+			)
+		}
+
+		settingsService := settings.New()
+		plan, err := settingsService.PlanSettings(absTarget)
+		if err != nil {
+			return err
+		}
+		if plan == nil {
+			utils.DisplayInfo(fmt.Sprintf("The installed template provides no hooks (%s not found)", config.SettingsTemplateFile))
+			return nil
+		}
+		if !plan.Changed() {
+			utils.DisplaySuccess("Hooks are already registered in " + plan.SettingsPath)
+			return nil
+		}
+
+		if hooksDryRun {
+			displaySettingsPlan(plan, color.Enabled())
+			return nil
+		}
+
+		if err := settingsService.ApplySettings(plan); err != nil {
+			return err
+		}
+		if plan.BackupPath != "" {
+			utils.VerbosePrintf(verbose, "Backed up settings to %s\n", plan.BackupPath)
+		}
+		utils.DisplaySuccess("Registered hooks in " + plan.SettingsPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+
+	hooksInstallCmd.Flags().BoolVar(&hooksDryRun, "dry-run", false, "print the change to settings.json without writing it")
+
+	// Custom completion for directory argument
+	hooksInstallCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return []string{}, cobra.ShellCompDirectiveFilterDirs
+		}
+		return []string{}, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// displaySettingsPlan prints the change to settings.json as a unified diff,
+// noting where the current file would be backed up
+func displaySettingsPlan(plan *settings.SettingsPlan, colorize bool) {
+	fromName := "a/" + config.ClaudeDir + "/" + config.ClaudeSettingsFile
+	if plan.Before == nil {
+		fromName = "/dev/null"
+	}
+	text := utils.UnifiedDiff(fromName, "b/"+config.ClaudeDir+"/"+config.ClaudeSettingsFile, plan.Before, plan.After)
+	if colorize {
+		text = utils.ColorizeDiff(text)
+	}
+	fmt.Print(text)
+
+	if plan.BackupPath != "" {
+		utils.DisplayInfo("The current settings would be backed up to " + plan.BackupPath)
+	}
+}
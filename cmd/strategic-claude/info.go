@@ -0,0 +1,408 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/cache"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/installer"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var infoOutput string
+
+var infoCmd = &cobra.Command{
+	Use:   "info <template>",
+	Short: "Show details for a template, including its pinned commit",
+	Long: `Show the registry metadata for a template and details of the commit it installs.
+
+The template repository is cloned shallowly to resolve the pinned commit (or the
+branch head for templates that follow their branch) to its author date and
+message subject, and to compute the tree hash of the files it installs, which
+registry authors can copy into the template's tree_hash field. When the
+repository cannot be reached, only the registry metadata is shown.
+
+When the target directory (--target, the current directory by default) has a
+lock file, info also says whether the template is installed there and at which
+commit. Use --output json for machine-readable output.
+
+Examples:
+  strategic-claude-basic-cli info main            # Show the main template
+  strategic-claude-basic-cli info web-explorer    # Show how old the web-explorer pin is
+  strategic-claude-basic-cli info ccr -o json     # Print every field as JSON`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeTemplateIDs(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if infoOutput != "text" && infoOutput != "json" {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("invalid output format '%s'. Must be one of: text, json", infoOutput), nil)
+		}
+
+		template, err := templates.GetTemplate(args[0])
+		if err != nil {
+			return suggestTemplates(err, args[0])
+		}
+		installed := installedTemplate(targetDir, template.ID)
+
+		if infoOutput == "json" {
+			return writeTemplateInfoJSON(cmd, template, installed)
+		}
+
+		displayTemplateInfo(template)
+		displayInstalledTemplate(installed)
+
+		commitInfo, treeHash, err := resolveTemplateCommit(template)
+		if err != nil {
+			fmt.Printf("\nCommit details unavailable: %v\n", err)
+			return nil
+		}
+		if commitInfo != nil {
+			displayCommitInfo(template, commitInfo)
+		}
+		displayTreeHash(template, treeHash)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+
+	infoCmd.Flags().StringVarP(&infoOutput, "output", "o", "text", "output format: text or json")
+
+	if err := infoCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		// This should not happen in normal operation, but we handle it for completeness
+		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --output flag: %v\n", err)
+	}
+}
+
+// installedState says whether a template is installed in a target directory
+type installedState struct {
+	// Directory whose lock file was read
+	TargetDir string `json:"target_dir"`
+
+	// Whether the lock file records the template; the other fields are only
+	// set when it does
+	Installed bool `json:"installed"`
+
+	Commit      string     `json:"commit,omitempty"`
+	Ref         string     `json:"ref,omitempty"`
+	InstalledAt *time.Time `json:"installed_at,omitempty"`
+}
+
+// installedTemplate reads the lock file in targetDir to report whether
+// templateID is installed there. It returns nil when there is no readable lock
+// file, as for a directory the CLI never installed into.
+func installedTemplate(targetDir, templateID string) *installedState {
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return nil
+	}
+	lock, err := state.ReadLock(absTarget)
+	if err != nil {
+		utils.VerbosePrintf(verbose, "Not checking the installation: %v\n", err)
+		return nil
+	}
+	if lock == nil {
+		return nil
+	}
+
+	installed := &installedState{TargetDir: absTarget}
+	if entry := lock.Find(templateID); entry != nil {
+		installed.Installed = true
+		installed.Commit = entry.Commit
+		installed.Ref = entry.Ref
+		installed.InstalledAt = &entry.InstalledAt
+	}
+	return installed
+}
+
+// templateInfoJSON is the --output json form of info: every registry field,
+// the installation state, and the details of the commit the template installs
+type templateInfoJSON struct {
+	templates.Template
+
+	ShortCommit string          `json:"short_commit,omitempty"`
+	Installed   *installedState `json:"installed_here,omitempty"`
+
+	// Resolved from the repository; CommitError says why when it could not be
+	ResolvedCommit *commitJSON `json:"resolved_commit,omitempty"`
+	CommitError    string      `json:"commit_error,omitempty"`
+}
+
+// commitJSON is the JSON form of git.CommitInfo, with the computed tree hash
+type commitJSON struct {
+	Hash       string    `json:"hash,omitempty"`
+	AuthorDate time.Time `json:"author_date,omitzero"`
+	Subject    string    `json:"subject,omitempty"`
+	TreeHash   string    `json:"tree_hash"`
+}
+
+// writeTemplateInfoJSON resolves the template's commit and prints everything
+// info knows about the template as JSON
+func writeTemplateInfoJSON(cmd *cobra.Command, template templates.Template, installed *installedState) error {
+	info := templateInfoJSON{Template: template, Installed: installed}
+	if len(template.Commit) == 40 {
+		info.ShortCommit = template.ShortCommit()
+	}
+
+	commitInfo, treeHash, err := resolveTemplateCommit(template)
+	if err != nil {
+		info.CommitError = err.Error()
+	} else {
+		info.ResolvedCommit = &commitJSON{TreeHash: treeHash}
+		if commitInfo != nil {
+			info.ResolvedCommit.Hash = commitInfo.Hash
+			info.ResolvedCommit.AuthorDate = commitInfo.AuthorDate
+			info.ResolvedCommit.Subject = commitInfo.Subject
+		}
+	}
+
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return nil
+}
+
+// displayInstalledTemplate prints whether the template is installed in the
+// target directory, when that directory has a lock file
+func displayInstalledTemplate(installed *installedState) {
+	switch {
+	case installed == nil:
+	case !installed.Installed:
+		fmt.Printf("  Installed: not in %s\n", installed.TargetDir)
+	default:
+		at := shortCommit(installed.Commit)
+		if installed.Ref != "" {
+			at = fmt.Sprintf("%s (%s)", installed.Ref, at)
+		}
+		fmt.Printf("  Installed: in %s at %s on %s\n", installed.TargetDir, at, installed.InstalledAt.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+// displayTemplateInfo prints the registry metadata for a template
+func displayTemplateInfo(template templates.Template) {
+	fmt.Println(template.DisplayName())
+	if template.Description != "" {
+		fmt.Printf("  Description: %s\n", template.Description)
+	}
+	fmt.Printf("  Repository: %s\n", template.RepoURL)
+	if template.Branch != "" {
+		fmt.Printf("  Branch: %s\n", template.Branch)
+	}
+	switch {
+	case template.FollowBranch:
+		fmt.Printf("  Commit: follows branch head\n")
+	case template.Tag != "":
+		fmt.Printf("  Tag: %s\n", template.Tag)
+	case template.IsPinned():
+		fmt.Printf("  Commit: %s (%s)\n", template.ShortCommit(), template.Commit)
+	case template.Commit != "":
+		fmt.Printf("  Commit: %s\n", template.Commit)
+	}
+	if template.TreeHash != "" {
+		fmt.Printf("  Tree hash: %s\n", template.TreeHash)
+	}
+	if template.Language != "" {
+		fmt.Printf("  Language: %s\n", template.Language)
+	}
+	if template.MinCLIVersion != "" {
+		fmt.Printf("  Requires CLI: %s or newer\n", template.MinCLIVersion)
+	}
+	if len(template.Tags) > 0 {
+		fmt.Printf("  Tags: %s\n", strings.Join(template.Tags, ", "))
+	}
+	if len(template.IncludePatterns) > 0 {
+		fmt.Printf("  Include patterns: %s\n", strings.Join(template.IncludePatterns, ", "))
+	}
+	if len(template.ExcludePatterns) > 0 {
+		fmt.Printf("  Exclude patterns: %s\n", strings.Join(template.ExcludePatterns, ", "))
+	}
+	if len(template.Variables) > 0 {
+		fmt.Printf("  Variable defaults (override with init --set):\n")
+		for _, name := range slices.Sorted(maps.Keys(template.Variables)) {
+			variable := template.Variables[name]
+			if variable.Description != "" {
+				fmt.Printf("    %s=%s (%s)\n", name, variable.Default, variable.Description)
+			} else {
+				fmt.Printf("    %s=%s\n", name, variable.Default)
+			}
+		}
+	}
+	if len(template.PostInstall) > 0 {
+		fmt.Printf("  Post-install hooks (run with init --run-hooks):\n")
+		for _, command := range template.PostInstall {
+			fmt.Printf("    $ %s\n", command)
+		}
+	}
+	if template.Deprecated {
+		fmt.Printf("  Deprecated: yes\n")
+		if template.ReplacedBy != "" {
+			fmt.Printf("  Replaced by: %s\n", template.ReplacedBy)
+		}
+		if template.DeprecationNote != "" {
+			fmt.Printf("  Deprecation note: %s\n", template.DeprecationNote)
+		}
+	}
+}
+
+// displayCommitInfo prints the git-derived details of the commit a template installs
+func displayCommitInfo(template templates.Template, commitInfo *git.CommitInfo) {
+	label := "Pinned commit"
+	if template.FollowBranch {
+		label = "Branch head"
+	}
+
+	fmt.Printf("\n%s:\n", label)
+	fmt.Printf("  Hash: %s\n", commitInfo.Hash)
+	fmt.Printf("  Date: %s (%s)\n", commitInfo.AuthorDate.Format("2006-01-02 15:04:05 -0700"), formatAge(time.Since(commitInfo.AuthorDate)))
+	fmt.Printf("  Message: %s\n", commitInfo.Subject)
+}
+
+// displayTreeHash prints the tree hash computed from the checkout, and whether
+// it matches the one the template declares
+func displayTreeHash(template templates.Template, treeHash string) {
+	fmt.Printf("\nTree hash: %s\n", treeHash)
+	switch {
+	case template.TreeHash == "":
+	case strings.EqualFold(template.TreeHash, treeHash):
+		fmt.Printf("  Matches the declared tree hash\n")
+	default:
+		fmt.Printf("  Does not match the declared tree hash %s\n", template.TreeHash)
+	}
+}
+
+// resolveTemplateCommit clones the template repository, looks up the commit it
+// installs, and computes the tree hash of its files. Plain local directories
+// have no commit, so the commit is nil for them.
+func resolveTemplateCommit(template templates.Template) (*git.CommitInfo, string, error) {
+	if template.IsLocal() && !template.IsLocalGitRepo() {
+		localPath, err := template.LocalPath()
+		if err != nil {
+			return nil, "", err
+		}
+		treeHash, err := installer.TreeHash(localPath, template)
+		return nil, treeHash, err
+	}
+
+	gitService := git.New()
+
+	ctx, cancel := context.Background(), func() {}
+	if gitTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, gitTimeout)
+	}
+	defer cancel()
+
+	// Remote templates come from the clone cache like an install would, and
+	// only from what is cached when offline
+	if !template.IsLocal() && offline {
+		treeDir, _, err := cache.New().CheckoutCached(git.CloneOptions{
+			URL:    template.RepoURL,
+			Branch: template.CheckoutRef(),
+			Commit: template.PinnedCommit(),
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		return inspectCheckout(gitService, treeDir, template)
+	}
+	if cacheService := cache.New(); !noCache && !template.IsLocal() && cacheService.Enabled() {
+		treeDir, _, err := cacheService.Checkout(ctx, git.CloneOptions{
+			URL:    template.RepoURL,
+			Branch: template.CheckoutRef(),
+			Commit: template.PinnedCommit(),
+			Retry:  gitRetryOptions(),
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		return inspectCheckout(gitService, treeDir, template)
+	}
+
+	repoDir, err := gitService.CloneWithOptions(ctx, git.CloneOptions{
+		URL:    template.RepoURL,
+		Branch: template.CheckoutRef(),
+		Commit: template.PinnedCommit(),
+		Depth:  config.DefaultCloneDepth,
+		Retry:  gitRetryOptions(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		_ = gitService.CleanupTempDir(repoDir) // Best effort cleanup
+	}()
+
+	return inspectCheckout(gitService, repoDir, template)
+}
+
+// inspectCheckout reads the checked-out commit and tree hash of a template checkout
+func inspectCheckout(gitService *git.Service, dir string, template templates.Template) (*git.CommitInfo, string, error) {
+	commitInfo, err := gitService.GetCommitInfo(dir, "HEAD")
+	if err != nil {
+		return nil, "", err
+	}
+	treeHash, err := installer.TreeHash(dir, template)
+	if err != nil {
+		return nil, "", err
+	}
+	return commitInfo, treeHash, nil
+}
+
+// gitRetryOptions configures retries of template clones and fetches from the
+// --retries flag, reporting each retry in verbose mode
+func gitRetryOptions() git.RetryOptions {
+	return git.RetryOptions{
+		Attempts: gitRetries,
+		OnRetry: func(message string) {
+			utils.VerbosePrintln(verbose, message)
+		},
+	}
+}
+
+// formatAge describes a duration in the largest whole unit, e.g. "3 months ago"
+func formatAge(age time.Duration) string {
+	day := 24 * time.Hour
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * day},
+		{"month", 30 * day},
+		{"day", day},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+
+	for _, unit := range units {
+		if count := int(age / unit.size); count >= 1 {
+			if count == 1 {
+				return fmt.Sprintf("1 %s ago", unit.name)
+			}
+			return fmt.Sprintf("%d %ss ago", count, unit.name)
+		}
+	}
+	return "just now"
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{5 * time.Hour, "5 hours ago"},
+		{36 * time.Hour, "1 day ago"},
+		{95 * 24 * time.Hour, "3 months ago"},
+		{800 * 24 * time.Hour, "2 years ago"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestResolveTemplateCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	// A local git repository stands in for the remote template
+	repoDir := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	run("init", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("# Template\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	run("add", "README.md")
+	run("commit", "-m", "Initial template\n\nLonger description.")
+	hash := run("rev-parse", "HEAD")
+
+	t.Run("pinned commit", func(t *testing.T) {
+		template := templates.Template{ID: "local", Name: "Local", RepoURL: repoDir, Branch: "main", Commit: hash}
+		info, treeHash, err := resolveTemplateCommit(template)
+		if err != nil {
+			t.Fatalf("resolveTemplateCommit() error = %v", err)
+		}
+		if info.Hash != hash || info.Subject != "Initial template" {
+			t.Errorf("resolveTemplateCommit() = %+v, want hash %s and subject line only", info, hash)
+		}
+		if !strings.HasPrefix(treeHash, templates.TreeHashPrefix) {
+			t.Errorf("resolveTemplateCommit() tree hash = %q, want a %s hash", treeHash, templates.TreeHashPrefix)
+		}
+	})
+
+	t.Run("plain directory has no commit", func(t *testing.T) {
+		template := templates.Template{ID: "plain", Name: "Plain", RepoURL: t.TempDir()}
+		info, treeHash, err := resolveTemplateCommit(template)
+		if err != nil || info != nil || treeHash == "" {
+			t.Errorf("resolveTemplateCommit() = %v, %q, %v, want nil, a tree hash, nil", info, treeHash, err)
+		}
+	})
+}
+
+func TestInfoCommand_JSON(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origOutput, origTarget := infoOutput, targetDir
+	defer func() {
+		templates.Registry.Set(original)
+		infoOutput, targetDir = origOutput, origTarget
+	}()
+
+	// A plain directory template needs no git to resolve
+	templateDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(templateDir, "README.md"), []byte("# Template\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"plain": {ID: "plain", Name: "Plain", RepoURL: templateDir, Tags: []string{"local"}, Deprecated: true, DeprecationNote: "Use a git template"},
+	})
+
+	installedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	targetDir = t.TempDir()
+	if err := state.WriteLock(targetDir, &state.Lock{Templates: []state.TemplateLock{
+		{TemplateID: "plain", Commit: "1234567890abcdef1234567890abcdef12345678", InstalledAt: installedAt},
+	}}); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+	infoOutput = "json"
+
+	var out bytes.Buffer
+	infoCmd.SetOut(&out)
+	defer infoCmd.SetOut(nil)
+
+	if err := infoCmd.RunE(infoCmd, []string{"plain"}); err != nil {
+		t.Fatalf("info command failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, out.String())
+	}
+	if got["id"] != "plain" || got["deprecated"] != true || got["deprecation_note"] != "Use a git template" {
+		t.Errorf("Expected the registry fields, got %v", got)
+	}
+	installed, _ := got["installed_here"].(map[string]any)
+	if installed["installed"] != true || installed["commit"] != "1234567890abcdef1234567890abcdef12345678" || installed["installed_at"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("Expected the installation from the lock file, got %v", got["installed_here"])
+	}
+	resolved, _ := got["resolved_commit"].(map[string]any)
+	if tree, _ := resolved["tree_hash"].(string); !strings.HasPrefix(tree, templates.TreeHashPrefix) || resolved["hash"] != nil {
+		t.Errorf("Expected a tree hash and no commit for a plain directory, got %v", got["resolved_commit"])
+	}
+
+	// Without a lock file there is nothing to say about the installation
+	if installed := installedTemplate(t.TempDir(), "plain"); installed != nil {
+		t.Errorf("installedTemplate() without a lock file = %+v, want nil", installed)
+	}
+	if installed := installedTemplate(targetDir, "other"); installed == nil || installed.Installed {
+		t.Errorf("installedTemplate() of a template not in the lock = %+v, want not installed", installed)
+	}
+
+	if err := infoCmd.RunE(infoCmd, []string{"missing"}); err == nil {
+		t.Error("Expected an error for an unknown template")
+	}
+}
+
+func TestSuggestTemplates(t *testing.T) {
+	_, notFound := templates.GetTemplate("web-explore")
+	if err := suggestTemplates(notFound, "web-explore"); !strings.HasSuffix(err.Error(), "did you mean: web-explorer?") || !errors.Is(err, templates.ErrNotFound) {
+		t.Errorf("suggestTemplates() = %v, want a did you mean hint wrapping ErrNotFound", err)
+	}
+
+	_, unrelated := templates.GetTemplate("completely-unrelated")
+	if err := suggestTemplates(unrelated, "completely-unrelated"); err != unrelated {
+		t.Errorf("suggestTemplates() without close IDs = %v, want the error unchanged", err)
+	}
+
+	other := errors.New("registry unavailable")
+	if err := suggestTemplates(other, "web-explore"); err != other {
+		t.Errorf("suggestTemplates() for another error = %v, want it unchanged", err)
+	}
+}
@@ -1,28 +1,72 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/logging"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/installer"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/ui"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	force         bool
-	forceCore     bool
-	yes           bool
-	noBackup      bool
-	dryRun        bool
-	templateID    string
-	gitignoreMode string
+	force             bool
+	forceCore         bool
+	addLayer          bool
+	yes               bool
+	noBackup          bool
+	dryRun            bool
+	showPlan          bool
+	manifestOnly      bool
+	manifestOut       string
+	printCommit       bool
+	templateIDs       []string
+	jobs              int
+	gitignoreMode     string
+	followReplacement bool
+	skipVerify        bool
+	cloneDepth        int
+	setVariables      []string
+	renderPatterns    []string
+	strictVariables   bool
+	promptVariables   bool
+	excludePatterns   []string
+	includePatterns   []string
+	onlyPaths         []string
+	noCreate          bool
+	allowNonEmpty     bool
+	dereference       bool
+	fromCommit        string
+	installRef        string
+	templateBranch    string
+	repoURL           string
+	installArchive    string
+	runHooks          bool
+	selectCommit      bool
+	keepGit           bool
+	partialClone      bool
+	includeSubmodules bool
+	noLock            bool
+
+	// Whether fromCommit was taken from the project's version file
+	commitFromVersionFile bool
 )
 
 var initCmd = &cobra.Command{
@@ -40,41 +84,246 @@ This command will:
 Installation modes:
 - New installation: Install in a clean directory
 - Update core only (--force-core): Update only core framework files, preserve user content
-- Full overwrite (--force): Replace all framework files
+- Full overwrite (--force): Back up the existing installation and reinstall it
+  from scratch, with a new lock file
+- Layer (--add): Install over the templates already in place, file by file
+
+Running init again where a lock file already exists fails unless one of
+--force, --force-core, or --add is given. To move an installation to the
+template's current commit while keeping your work, use 'update' instead.
+
+Unmanaged installs:
+- --no-lock installs the files without writing a lock file, for throwaway
+  scaffolds. This disables safe updates: with no record of what was installed,
+  status reports the target as not managed and update, verify, and diff
+  refuse to run. A lock file from an earlier install is removed. It installs
+  one template, so it cannot be combined with --add or several templates;
+  'init --force' later reinstalls with a lock file
 
 Template selection:
 - Use --template to specify a template ID directly
+- Use --branch to install the registry template that follows that branch,
+  such as --branch web-explorer; it fails when no template, or more than one,
+  uses the branch. When --template is also given, --template wins and
+  --branch is ignored with a warning
+- A .strategic-claude-version file in the target directory pins the template,
+  and optionally the commit, for everyone on the project: one line such as
+  "main <commit>". It is used when none of --template, --branch, --repo-url,
+  --archive, --from-commit, --ref, --select-commit, or --add is given, ahead of a
+  default_template from the config
 - Without --template, you'll be prompted to choose interactively
+- With --yes and no --template, the default is main, or a registry template
+  written for the project's language when its build files show one (go.mod for
+  Go, Cargo.toml for Rust, package.json for JavaScript, and so on)
+- Repeat --template (or separate IDs with commas) to install several templates
+  in order; they are fetched concurrently (--jobs at a time) and each later
+  one is layered over the earlier ones
+
+Layered templates:
+- Each layered template is recorded in the lock file with its own manifest,
+  and status, update, and diff cover all of them
+- Where two templates install the same file, the one added last wins and a
+  warning lists the files it took over
+- --add layers the selected templates over an existing installation instead
+  of replacing it; a full or core reinstall records only the templates it
+  installs
 
 Gitignore behavior:
 - track: Track all files (default)
 - all: Ignore entire framework directories
 - non-user: Ignore only framework files (core, guides, templates)
 
+Template variables:
+- Files matching --render-glob (default *.md, *.tmpl) may use placeholders such
+  as {{.ProjectName}} (defaults to the directory name) or {{.GoModule}} (read
+  from go.mod)
+- A template may declare defaults for its variables in the registry, each with
+  an optional description
+- Supply values with --set name=value, overriding any default; missing values
+  are prompted for unless --yes is given, and --strict fails the install if
+  any are still unset
+- --prompt asks for every variable --set leaves out, showing its description
+  and offering its default; it needs an interactive terminal
+- A --set value may reference environment variables as $NAME or ${NAME}
+  ($$ for a literal $); quote it so the shell does not expand it first. The
+  CLI expands it once, when rendering, and --strict fails on unset ones
+
+Excluded files:
+- Template repository files matching .git (at any depth), /.github/, or
+  /README.md are never installed; templates may add their own exclude patterns
+- Use --exclude with a gitignore-style pattern, relative to the template
+  repository root, to leave out more files
+- Use --include with a pattern in the same syntax to install only the files
+  it matches; templates may add their own include patterns. Includes select
+  the candidates and excludes then remove files from them
+
+Partial installs:
+- --only installs just the named template directories, relative to the
+  template repository root (e.g. .strategic-claude-basic/core/commands), and
+  leaves the rest of the project untouched
+
+Installing another commit:
+- --from-commit installs a single template at the given full commit SHA instead
+  of the commit pinned in the registry, e.g. to try an upcoming change on its
+  branch; the lock file records the override so status does not report the
+  installation as behind
+- --ref installs a single template at a branch or tag of its repository, such
+  as a feature branch; with --repo-url it installs that repository instead of
+  a registry template, named after the repository. The lock file records the
+  ref and the commit it resolved to
+- --select-commit lists the latest commits on the branch a template follows,
+  or on --ref, after fetching it, and installs the one you pick. Without a
+  terminal it warns and installs the template's usual commit instead
+
+Installing from an archive:
+- --archive installs the exported template tree in a .tar, .tar.gz, .tgz, or
+  .zip file instead of a registry template, without git, for air-gapped
+  networks. The archive holds .strategic-claude-basic at its top level or in
+  its only top-level directory, as in the archives git forges offer. It is
+  checked before anything is unpacked, and rejected if any entry has an
+  absolute path or would leave the directory it is unpacked into. Excludes,
+  includes, --only, and variables apply as usual, and the lock file records
+  the archive's SHA-256 in place of a commit
+
+Partial clones:
+- With --only or --include, --partial-clone fetches only the blobs of the
+  selected files (git clone --filter=blob:none with a sparse checkout), for
+  large templates on slow or metered connections. It bypasses the cache, and
+  the commit is still verified but the tree hash cannot be. Git or servers
+  without partial clone support fall back to a normal clone
+
+Submodules:
+- Template repositories may use git submodules, such as shared agent
+  definitions. They are left out, as empty directories, unless
+  --include-submodules is given: then they are checked out recursively and
+  their files installed. Each submodule is fetched with the same retries and
+  --timeout as the clone, and a failure names the submodule. It bypasses the
+  cache and archives, and the commit is still verified but the tree hash
+  cannot be. update keeps including them
+
+Keeping the template's history:
+- --keep-git keeps the template's full git history in .strategic-claude-basic/.git,
+  with the project as its work tree, so later template commits can be diffed or
+  rebased onto with git. This significantly increases the installed size. The
+  history is not part of the manifest, so verify ignores it; update keeps it at
+  the new commit and uninstall removes it
+
+Target directory:
+- Give the directory as an argument or with --target; it is created if it does
+  not exist yet, unless --no-create is given
+- A directory given explicitly must be empty for a new installation, apart
+  from .git and the version file, unless --allow-non-empty is given; init in
+  the current directory installs alongside the project's files
+- The lock file and its manifest are written inside the target, with paths
+  relative to it
+- Template files are copied into a staging directory there, --jobs at a time,
+  and moved into place once all of them are copied
+
+Post-install hooks:
+- A template may list shell commands to run in the target directory once it is
+  installed (post_install in the registry). They run only with --run-hooks,
+  which executes them with your user permissions, so review them first with
+  'info <template>'. A failing hook fails the install and rolls back what the
+  installer wrote, including symlinks and settings, but not changes the hook made
+
+Dry run:
+- --dry-run fetches the template into a temporary directory and lists each file
+  that would be created, overwritten, removed, or skipped, without touching the
+  target directory
+- --plan does the same but prints the files the template would install as a
+  tree, marking each one as new or overwritten
+- --manifest-only fetches and filters the template the same way and prints the
+  manifest a new installation would record (paths with their SHA-256 hashes
+  and the framework symlinks), in the format of the lock file's "files", or
+  writes it to --manifest-out. Compare two exports, or an export with a lock
+  file, to see what changed between template versions
+- --print-commit prints only the commit SHA an installation would check out,
+  taking --ref, --from-commit, and templates that follow their branch into
+  account, and exits. A pinned commit is printed without fetching; otherwise
+  the template is fetched, from the cache when it holds it
+
 Examples:
   strategic-claude-basic-cli init                      # Install with template selection
   strategic-claude-basic-cli init --template=main     # Install main template
   strategic-claude-basic-cli init --template=ccr      # Install CCR template
+  strategic-claude-basic-cli init --branch=web-explorer # Install the template on the web-explorer branch
+  strategic-claude-basic-cli init --template=main,ccr # Layer CCR over main
+  strategic-claude-basic-cli init --add --template=ccr # Layer CCR over the installed templates
   strategic-claude-basic-cli init ./my-project        # Install in specific directory
+  strategic-claude-basic-cli init ../new-service      # Create and set up a new directory
   strategic-claude-basic-cli init --force-core        # Update core files only
   strategic-claude-basic-cli init --gitignore-mode=all # Ignore all framework files
-  strategic-claude-basic-cli init --dry-run           # Preview what would be done`,
+  strategic-claude-basic-cli init --dry-run           # Preview what would be done
+  strategic-claude-basic-cli init --plan              # Preview the installed files as a tree
+  strategic-claude-basic-cli init --manifest-only --manifest-out main.json # Export the file manifest
+  strategic-claude-basic-cli init --ref feature/agents --print-commit # Print the commit the branch points to
+  strategic-claude-basic-cli init --set Team=platform # Set a template variable
+  strategic-claude-basic-cli init --exclude '**/examples/' # Skip example directories
+  strategic-claude-basic-cli init --include '**/*.md'  # Install only Markdown files
+  strategic-claude-basic-cli init --template=ccr --from-commit <sha> # Install CCR at another commit
+  strategic-claude-basic-cli init --ref feature/agents # Install main from a feature branch
+  strategic-claude-basic-cli init --ref feature/agents --select-commit # Pick a recent commit of the branch
+  strategic-claude-basic-cli init --archive template.tar.gz # Install an exported template without git
+  strategic-claude-basic-cli init --no-lock ../scratch # Scaffold a throwaway project, unmanaged`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runInit(args)
+		return runInit(cmd, args)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
 
-	initCmd.Flags().BoolVarP(&force, "force", "f", false, "force installation, overwriting existing files")
+	initCmd.Flags().BoolVarP(&force, "force", "f", false, "back up an existing installation and reinstall it from scratch")
 	initCmd.Flags().BoolVar(&forceCore, "force-core", false, "update only core framework files, preserving user content")
+	initCmd.Flags().BoolVar(&addLayer, "add", false, "layer the templates over the existing installation instead of replacing it")
+	initCmd.MarkFlagsMutuallyExclusive("add", "force")
+	initCmd.MarkFlagsMutuallyExclusive("add", "force-core")
 	initCmd.Flags().BoolVarP(&yes, "yes", "y", false, "automatically answer yes to all prompts")
 	initCmd.Flags().BoolVar(&noBackup, "no-backup", false, "skip creating backups of existing files")
-	initCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be done without making changes")
-	initCmd.Flags().StringVar(&templateID, "template", "", "template ID to install (main, ccr, etc.)")
+	initCmd.Flags().BoolVar(&dryRun, "dry-run", false, "list the files that would change without modifying the target")
+	initCmd.Flags().BoolVar(&showPlan, "plan", false, "print the files that would be installed as a tree without modifying the target")
+	initCmd.Flags().BoolVar(&manifestOnly, "manifest-only", false, "print the manifest of files and hashes an installation would record, without installing")
+	initCmd.Flags().StringVar(&manifestOut, "manifest-out", "", "with --manifest-only, write the manifest to this file instead of stdout")
+	initCmd.Flags().BoolVar(&printCommit, "print-commit", false, "print the commit SHA an installation would check out, without installing")
+	initCmd.MarkFlagsMutuallyExclusive("dry-run", "plan")
+	initCmd.MarkFlagsMutuallyExclusive("print-commit", "manifest-only")
+	initCmd.MarkFlagsMutuallyExclusive("print-commit", "dry-run")
+	initCmd.MarkFlagsMutuallyExclusive("print-commit", "plan")
+	initCmd.Flags().BoolVar(&noCreate, "no-create", false, "fail instead of creating a missing target directory")
+	initCmd.Flags().BoolVar(&allowNonEmpty, "allow-non-empty", false, "install into a target directory given explicitly even when it already holds files")
+	initCmd.Flags().StringSliceVar(&templateIDs, "template", nil, "template ID to install (main, ccr, etc.); repeat to layer several templates in order")
+	initCmd.Flags().StringVar(&templateBranch, "branch", "", "install the registry template that follows this branch (ignored when --template is given)")
+	initCmd.Flags().IntVar(&jobs, "jobs", runtime.NumCPU(), "number of templates fetched at once when installing several, and of files copied at once")
 	initCmd.Flags().StringVar(&gitignoreMode, "gitignore-mode", "", "gitignore behavior: track, all, or non-user (default: track)")
+	initCmd.Flags().BoolVar(&partialClone, "partial-clone", false, "fetch only the files --only or include patterns select, skipping the cache (the tree hash is not checked)")
+	initCmd.Flags().IntVar(&cloneDepth, "depth", config.DefaultCloneDepth, "history depth for uncached template clones (0 for a full clone)")
+	initCmd.Flags().StringArrayVar(&setVariables, "set", nil, "set a template variable as name=value, expanding $NAME from the environment (repeatable)")
+	initCmd.Flags().StringSliceVar(&renderPatterns, "render-glob", config.GetDefaultRenderPatterns(), "file globs rendered for template variables")
+	initCmd.Flags().BoolVar(&strictVariables, "strict", false, "fail if a template references a variable with no value")
+	initCmd.Flags().BoolVar(&promptVariables, "prompt", false, "ask for every template variable --set does not supply, offering its default")
+	initCmd.MarkFlagsMutuallyExclusive("prompt", "yes")
+	initCmd.Flags().StringArrayVar(&excludePatterns, "exclude", nil, "gitignore-style pattern, relative to the template repository root, for files not to install (repeatable)")
+	initCmd.Flags().StringArrayVar(&includePatterns, "include", nil, "gitignore-style pattern, relative to the template repository root, selecting the only files to install; --exclude still applies (repeatable)")
+	initCmd.Flags().StringArrayVar(&onlyPaths, "only", nil, "install only this template directory, relative to the template repository root (repeatable)")
+	initCmd.Flags().BoolVar(&dereference, "dereference", false, "copy the files template symlinks point to instead of recreating the symlinks")
+	initCmd.Flags().StringVar(&fromCommit, "from-commit", "", "install the template at this commit SHA instead of the registry's pinned commit")
+	initCmd.Flags().StringVar(&installRef, "ref", "", "install the template at this branch or tag of its repository instead of the registry's pinned commit")
+	initCmd.Flags().BoolVar(&selectCommit, "select-commit", false, fmt.Sprintf("choose which of the %d latest commits on the template's branch or --ref to install", config.RecentCommitLimit))
+	initCmd.MarkFlagsMutuallyExclusive("from-commit", "select-commit")
+	initCmd.Flags().StringVar(&repoURL, "repo-url", "", "install this repository at --ref instead of a registry template")
+	initCmd.Flags().StringVar(&installArchive, "archive", "", "install the template tree in this .tar, .tar.gz, or .zip file instead of a registry template, without git")
+	initCmd.MarkFlagsMutuallyExclusive("archive", "repo-url")
+	initCmd.Flags().BoolVar(&keepGit, "keep-git", false, "keep the template's full git history in .strategic-claude-basic/.git (significantly increases the installed size)")
+	initCmd.MarkFlagsMutuallyExclusive("keep-git", "partial-clone")
+	initCmd.Flags().BoolVar(&includeSubmodules, "include-submodules", false, "check out the template's git submodules recursively and install their files, skipping the cache (the tree hash is not checked)")
+	initCmd.MarkFlagsMutuallyExclusive("include-submodules", "keep-git")
+	initCmd.MarkFlagsMutuallyExclusive("include-submodules", "partial-clone")
+	initCmd.Flags().BoolVar(&noLock, "no-lock", false, "install without writing a lock file, leaving the files unmanaged: update, verify, and diff will not run")
+	initCmd.MarkFlagsMutuallyExclusive("no-lock", "add")
+	initCmd.Flags().BoolVar(&skipVerify, "skip-verify", false, "skip verifying the cloned commit and tree hash match the template's pins")
+	initCmd.Flags().BoolVar(&runHooks, "run-hooks", false, "run the template's post-install hook commands in the target directory (they run with your permissions)")
+	initCmd.Flags().BoolVar(&followReplacement, "follow-replacement", false, "install the replacement when the selected template is deprecated")
 
 	// Custom completion for directory argument
 	initCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -85,14 +334,17 @@ func init() {
 	}
 
 	// Add completion for template flag
-	if err := initCmd.RegisterFlagCompletionFunc("template", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		templateIDs := templates.GetTemplateIDs()
-		return templateIDs, cobra.ShellCompDirectiveNoFileComp
-	}); err != nil {
+	if err := initCmd.RegisterFlagCompletionFunc("template", completeTemplateIDs); err != nil {
 		// This should not happen in normal operation, but we handle it for completeness
 		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --template flag: %v\n", err)
 	}
 
+	// Add completion for branch flag
+	if err := initCmd.RegisterFlagCompletionFunc("branch", completeTemplateBranches); err != nil {
+		// This should not happen in normal operation, but we handle it for completeness
+		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --branch flag: %v\n", err)
+	}
+
 	// Add completion for gitignore-mode flag
 	if err := initCmd.RegisterFlagCompletionFunc("gitignore-mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"track", "all", "non-user"}, cobra.ShellCompDirectiveNoFileComp
@@ -102,8 +354,18 @@ func init() {
 	}
 }
 
+// targetGiven reports whether init was told where to install, as an argument
+// or with --target, rather than installing into the current directory
+func targetGiven(cmd *cobra.Command, args []string) bool {
+	if len(args) > 0 {
+		return true
+	}
+	flag := cmd.Flag("target")
+	return flag != nil && flag.Changed
+}
+
 // runInit executes the init command logic
-func runInit(args []string) error {
+func runInit(cmd *cobra.Command, args []string) error {
 	// Determine target directory
 	target := targetDir
 	if len(args) > 0 {
@@ -113,35 +375,74 @@ func runInit(args []string) error {
 	// Convert to absolute path
 	absTarget, err := filepath.Abs(target)
 	if err != nil {
-		utils.DisplayError(fmt.Errorf("failed to resolve target directory: %w", err))
-		return err
+		return fmt.Errorf("failed to resolve target directory: %w", err)
 	}
 
 	utils.VerbosePrintf(verbose, "Target directory: %s\n", absTarget)
 	utils.VerbosePrintf(verbose, "Flags - Force: %v, Force Core: %v, Yes: %v, No Backup: %v, Dry Run: %v, Template: %s, Gitignore Mode: %s\n",
-		force, forceCore, yes, noBackup, dryRun, templateID, gitignoreMode)
+		force, forceCore, yes, noBackup, dryRun, strings.Join(templateIDs, ","), gitignoreMode)
+
+	// A template pinned in the project's version file stands in for --template
+	if err := applyVersionPin(cmd.Flags(), absTarget); err != nil {
+		return err
+	}
 
-	// Handle template selection
-	selectedTemplateID, err := selectTemplate(templateID, yes)
+	// Handle template selection; a repository given with --repo-url is installed instead
+	selectedTemplateIDs, err := selectInitTemplates(absTarget)
 	if err != nil {
-		utils.DisplayError(err)
 		return err
 	}
+	selectedTemplateID := selectedTemplateIDs[0]
+
+	utils.VerbosePrintf(verbose, "Selected templates: %s\n", strings.Join(selectedTemplateIDs, ", "))
+
+	if (dryRun || showPlan) && len(selectedTemplateIDs) > 1 {
+		err := models.NewAppError(models.ErrorCodeInvalidConfiguration, "--dry-run and --plan preview one template at a time", nil)
+		return err
+	}
+
+	if manifestOut != "" && !manifestOnly {
+		return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--manifest-out requires --manifest-only", nil)
+	}
+	if manifestOnly && len(selectedTemplateIDs) > 1 {
+		return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--manifest-only exports one template at a time", nil)
+	}
+	if printCommit && len(selectedTemplateIDs) > 1 {
+		return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--print-commit resolves one template at a time", nil)
+	}
 
-	utils.VerbosePrintf(verbose, "Selected template: %s\n", selectedTemplateID)
+	if (fromCommit != "" || installRef != "" || selectCommit) && len(selectedTemplateIDs) > 1 {
+		err := models.NewAppError(models.ErrorCodeInvalidConfiguration, "--from-commit, --ref, and --select-commit apply to a single template", nil)
+		return err
+	}
 
 	// Handle gitignore mode selection
-	selectedGitignoreMode, err := selectGitignoreMode(gitignoreMode, yes)
+	// A manifest export or commit query writes no .gitignore, so there is nothing to ask
+	selectedGitignoreMode, err := selectGitignoreMode(gitignoreMode, yes || manifestOnly || printCommit)
 	if err != nil {
-		utils.DisplayError(err)
 		return err
 	}
 
 	utils.VerbosePrintf(verbose, "Selected gitignore mode: %s\n", selectedGitignoreMode)
 
+	// Layering needs the lock file to know which template owns each file
+	if noLock && len(selectedTemplateIDs) > 1 {
+		return models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			"--no-lock installs a single template; layering needs the lock file",
+			nil,
+		)
+	}
+
 	// Validate prerequisites
-	if err := validatePrerequisites(); err != nil {
-		utils.DisplayError(err)
+	for _, id := range selectedTemplateIDs {
+		if err := validatePrerequisites(id); err != nil {
+			return err
+		}
+	}
+
+	variableValues, err := parseVariables(setVariables)
+	if err != nil {
 		return err
 	}
 
@@ -149,41 +450,141 @@ func runInit(args []string) error {
 	installConfig := models.InstallConfig{
 		TargetDir:     absTarget,
 		TemplateID:    selectedTemplateID,
+		FromCommit:    fromCommit,
+		Ref:           installRef,
+		RepoURL:       repoURL,
+		Archive:       installArchive,
 		Force:         force,
 		ForceCore:     forceCore,
+		Layer:         addLayer,
 		SkipConfirm:   yes,
 		NoBackup:      noBackup,
 		Verbose:       verbose,
 		GitignoreMode: selectedGitignoreMode,
+		SkipVerify:    skipVerify,
+		CloneDepth:    cloneDepth,
+		KeepGit:       keepGit,
+		PartialClone:  partialClone,
+		Submodules:    includeSubmodules,
+		NoLock:        noLock,
+		Jobs:          jobs,
+		NoCache:       noCache,
+		Offline:       offline,
+		ArchiveDir:    archiveDir,
+		CreateTarget:  !noCreate,
+		RequireEmpty:  targetGiven(cmd, args) && !allowNonEmpty,
+		Retries:       gitRetries,
+		GitTimeout:    gitTimeout,
+		Dereference:   dereference,
+		RunHooks:      runHooks,
+		HookOutput:    os.Stdout,
+
+		ExcludePatterns: excludePatterns,
+		IncludePatterns: includePatterns,
+		OnlyPaths:       onlyPaths,
+
+		Variables:       variableValues,
+		RenderPatterns:  renderPatterns,
+		StrictVariables: strictVariables,
+	}
+
+	// Prompt for template variables nobody supplied, unless running unattended;
+	// --prompt asks for every one --set left out, offering its default
+	if promptVariables && !stdinIsTerminal() {
+		return models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			"--prompt needs an interactive terminal; supply template variables with --set instead",
+			nil,
+		)
+	}
+	if !yes || promptVariables {
+		interactionService := utils.NewInteractionService()
+		installConfig.PromptVariables = promptVariables
+		installConfig.PromptVariable = func(name string, variable templates.Variable) (string, error) {
+			message := fmt.Sprintf("Value for template variable %s", name)
+			if variable.Description != "" {
+				message += fmt.Sprintf(" (%s)", variable.Description)
+			}
+			return interactionService.PromptWithDefault(message, variable.Default)
+		}
+	}
+
+	// Core updates keep local edits to framework files; report them afterwards
+	var modified []models.ModifiedFile
+	installConfig.OnModifiedFile = func(file models.ModifiedFile) {
+		modified = append(modified, file)
+	}
+
+	// Layered templates may ship files another one already installed; the last wins
+	var overlaps []state.FileOverlap
+	installConfig.OnOverlap = func(overlap state.FileOverlap) {
+		overlaps = append(overlaps, overlap)
 	}
 
 	// Validate install configuration
 	if err := installConfig.Validate(); err != nil {
-		utils.DisplayError(err)
 		return err
 	}
 
 	// Create installer service
 	installerService := installer.New()
 
+	if selectCommit {
+		if err := selectInstallCommit(installerService, &installConfig); err != nil {
+			return err
+		}
+	}
+
+	// The manifest comes from a scratch installation, whatever is in the target
+	if manifestOnly {
+		return exportManifest(installerService, installConfig, manifestOut)
+	}
+
+	// Only the commit is printed, so scripts can capture it
+	if printCommit {
+		utils.VerbosePrintln(verbose, "Resolving the template commit...")
+		commit, err := installerService.ResolveCommit(installConfig)
+		if err != nil {
+			return fmt.Errorf("failed to resolve commit: %w", err)
+		}
+		fmt.Println(commit)
+		return nil
+	}
+
 	// Step 1: Analyze installation requirements
 	utils.VerbosePrintln(verbose, "Analyzing installation requirements...")
 	plan, err := installerService.AnalyzeInstallation(installConfig)
 	if err != nil {
-		utils.DisplayError(fmt.Errorf("installation analysis failed: %w", err))
-		return err
+		return fmt.Errorf("installation analysis failed: %w", err)
 	}
 
+	// Install into the backup location shown to the user
+	installConfig.BackupDir = plan.BackupDir
+
 	// Step 2: Display installation plan and get confirmation
-	if dryRun {
+	if dryRun || showPlan {
+		// Fetch the template so the preview can list individual files; the
+		// target directory is left untouched
+		if plan.IsValid() {
+			utils.VerbosePrintln(verbose, "Fetching template to preview file changes...")
+			if err := installerService.PreviewFiles(installConfig, plan); err != nil {
+				return fmt.Errorf("failed to preview file changes: %w", err)
+			}
+		}
+		if showPlan {
+			return displayPlanTree(plan)
+		}
 		return displayDryRun(plan)
 	}
 
+	if len(selectedTemplateIDs) > 1 {
+		fmt.Printf("\nThen layering, in order: %s\n", strings.Join(selectedTemplateIDs[1:], ", "))
+	}
+
 	if !installConfig.SkipConfirm {
 		confirmed, err := getInstallationConfirmation(plan)
 		if err != nil {
-			utils.DisplayError(fmt.Errorf("confirmation failed: %w", err))
-			return err
+			return fmt.Errorf("confirmation failed: %w", err)
 		}
 		if !confirmed {
 			utils.DisplayInfo("Installation cancelled by user")
@@ -192,24 +593,257 @@ func runInit(args []string) error {
 	}
 
 	// Step 3: Perform installation
+	if len(selectedTemplateIDs) > 1 {
+		if err := prefetchTemplates(installerService, selectedTemplateIDs, installConfig); err != nil {
+			return err
+		}
+		defer func() {
+			if err := installerService.Release(); err != nil {
+				fmt.Printf("Warning: Failed to cleanup temporary directory: %v\n", err)
+			}
+		}()
+	}
+
 	utils.DisplayInfo(fmt.Sprintf("Installing Strategic Claude Basic in %s...", plan.TargetDir))
 
 	if err := installerService.Install(installConfig); err != nil {
-		utils.DisplayError(fmt.Errorf("installation failed: %w", err))
-		return err
+		if plan.BackupDir != "" {
+			if _, statErr := os.Stat(plan.BackupDir); statErr == nil {
+				utils.DisplayInfo(fmt.Sprintf("Existing files were backed up to %s", plan.BackupDir))
+			}
+		}
+		return fmt.Errorf("installation failed: %w", err)
+	}
+
+	// Later templates are layered over the earlier ones, so nothing installed
+	// before them needs confirming or backing up again
+	for _, id := range selectedTemplateIDs[1:] {
+		layerConfig := installConfig
+		layerConfig.TemplateID = id
+		layerConfig.Force = false
+		layerConfig.ForceCore = false
+		layerConfig.Layer = true
+		layerConfig.KeepGit = false // Only the first template's history is kept
+		layerConfig.NoBackup = true
+		layerConfig.BackupDir = ""
+
+		utils.DisplayInfo(fmt.Sprintf("Layering template '%s'...", id))
+		if err := installerService.Install(layerConfig); err != nil {
+			err = fmt.Errorf("installing template '%s' failed: %w", id, err)
+			return err
+		}
 	}
 
 	// Step 4: Display success message
 	utils.DisplaySuccess("Strategic Claude Basic installation completed successfully!")
+	displayModifiedFiles(modified, false)
+	displayOverlaps(overlaps)
 	displayPostInstallInfo(plan)
 
 	return nil
 }
 
+// displayOverlaps warns about the files layered templates both ship: those the
+// installed template put over another template's copy, and those it left to
+// a template that takes precedence
+func displayOverlaps(overlaps []state.FileOverlap) {
+	if len(overlaps) == 0 {
+		return
+	}
+
+	fmt.Println()
+	utils.DisplayWarning(fmt.Sprintf("%d file(s) are shipped by more than one template; the last one added wins:", len(overlaps)))
+	for _, overlap := range overlaps {
+		if overlap.Shadowed {
+			fmt.Printf("  • %s (kept the copy from '%s', which takes precedence)\n", overlap.Path, overlap.TemplateID)
+		} else {
+			fmt.Printf("  • %s (replaced the copy from '%s')\n", overlap.Path, overlap.TemplateID)
+		}
+	}
+	utils.DisplayInfo("Uninstalling one of them with 'uninstall --template' leaves these files to the other")
+}
+
+// prefetchTemplates fetches every template concurrently, bounded by --jobs,
+// reporting how long each took with --verbose
+func prefetchTemplates(installerService *installer.Service, ids []string, installConfig models.InstallConfig) error {
+	templateList := make([]templates.Template, 0, len(ids))
+	for _, id := range ids {
+		template, err := templates.GetTemplate(id)
+		if err != nil {
+			return err
+		}
+		templateList = append(templateList, template)
+	}
+
+	utils.DisplayInfo(fmt.Sprintf("Fetching %d templates (%d at a time)...", len(templateList), max(jobs, 1)))
+	results, err := installerService.Prefetch(templateList, installConfig, jobs)
+	for _, result := range results {
+		if result.Err == nil {
+			utils.VerbosePrintf(verbose, "Fetched template '%s' in %s\n", result.TemplateID, result.Duration.Round(time.Millisecond))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch templates:\n%w", err)
+	}
+	return nil
+}
+
+// versionPinFlags choose what init installs, so any of them given on the
+// command line overrides the project's version file
+var versionPinFlags = []string{"template", "branch", "repo-url", "archive", "from-commit", "ref", "select-commit", "add"}
+
+// applyVersionPin selects the template and commit pinned in the target's
+// version file, as if given with --template and --from-commit, unless the
+// command line chooses what to install. It takes precedence over a
+// default_template from the config, which is only a default.
+func applyVersionPin(flags *pflag.FlagSet, targetDir string) error {
+	commitFromVersionFile = false
+	pin, err := state.ReadVersionPin(targetDir)
+	if err != nil || pin == nil {
+		return err
+	}
+
+	for _, name := range versionPinFlags {
+		if flags.Changed(name) {
+			utils.DisplayWarning(fmt.Sprintf("Ignoring %s (%s); --%s chooses what to install", config.VersionFileName, describeVersionPin(pin), name))
+			return nil
+		}
+	}
+
+	template, err := templates.GetTemplate(pin.TemplateID)
+	if err != nil {
+		return fmt.Errorf("invalid template ID '%s' in %s: %w", pin.TemplateID, state.VersionFilePath(targetDir), suggestTemplates(err, pin.TemplateID))
+	}
+	if pin.Commit != "" {
+		if _, err := template.WithCommit(pin.Commit); err != nil {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "invalid commit in "+state.VersionFilePath(targetDir), err)
+		}
+	}
+
+	utils.DisplayInfo(fmt.Sprintf("Installing %s as pinned in %s", describeVersionPin(pin), config.VersionFileName))
+	templateIDs = []string{template.ID}
+	fromCommit = pinnedCommit(pin, template)
+	commitFromVersionFile = fromCommit != ""
+	return nil
+}
+
+// pinnedCommit returns the commit the version file pins for a template, or ""
+// when it pins none, pins another template, or pins the registry's own commit
+func pinnedCommit(pin *state.VersionPin, template templates.Template) string {
+	if pin == nil || pin.TemplateID != template.ID || pin.Commit == "" {
+		return ""
+	}
+	if template.IsPinned() && strings.EqualFold(pin.Commit, template.Commit) {
+		return ""
+	}
+	return pin.Commit
+}
+
+// describeVersionPin formats a version file's pin for display
+func describeVersionPin(pin *state.VersionPin) string {
+	if pin.Commit == "" {
+		return fmt.Sprintf("template '%s'", pin.TemplateID)
+	}
+	return fmt.Sprintf("template '%s' at %s", pin.TemplateID, shortCommit(pin.Commit))
+}
+
+// selectInitTemplates returns the templates to install: those chosen with
+// --template, --branch, or the picker, or the repository given with --repo-url
+// or archive given with --archive. --template takes precedence over --branch.
+func selectInitTemplates(dir string) ([]string, error) {
+	if installArchive != "" {
+		if len(templateIDs) > 0 || templateBranch != "" {
+			return nil, models.NewAppError(models.ErrorCodeInvalidConfiguration, "--archive installs an archive instead of a registry template; drop --template and --branch", nil)
+		}
+		template, err := templates.FromArchive(installArchive)
+		if err != nil {
+			return nil, models.NewAppError(models.ErrorCodeInvalidConfiguration, "invalid --archive", err)
+		}
+		return []string{template.ID}, nil
+	}
+
+	if repoURL == "" {
+		if templateBranch == "" {
+			return selectTemplates(templateIDs, yes, dir)
+		}
+		if len(templateIDs) > 0 {
+			utils.DisplayWarning(fmt.Sprintf("Ignoring --branch %s; --template takes precedence", templateBranch))
+			return selectTemplates(templateIDs, yes, dir)
+		}
+		id, err := selectTemplateByBranch(templateBranch)
+		if err != nil {
+			return nil, err
+		}
+		return []string{id}, nil
+	}
+
+	if len(templateIDs) > 0 || templateBranch != "" {
+		return nil, models.NewAppError(models.ErrorCodeInvalidConfiguration, "--repo-url installs a repository instead of a registry template; drop --template and --branch", nil)
+	}
+	if installRef == "" {
+		return nil, models.NewAppError(models.ErrorCodeInvalidConfiguration, "--repo-url requires --ref naming the branch or tag to install", nil)
+	}
+
+	template, err := templates.FromRepository(repoURL, installRef)
+	if err != nil {
+		return nil, models.NewAppError(models.ErrorCodeInvalidConfiguration, "invalid --repo-url or --ref", err)
+	}
+	return []string{template.ID}, nil
+}
+
+// selectInstallCommit fetches the template and lets the user choose which of
+// the latest commits on its branch to install. Without a terminal nobody can
+// choose, so the template's usual commit is installed after a warning.
+func selectInstallCommit(installerService *installer.Service, installConfig *models.InstallConfig) error {
+	branch, err := installerService.CommitBranch(*installConfig)
+	if err != nil {
+		return fmt.Errorf("cannot select a commit: %w", err)
+	}
+
+	if !ui.IsTTY() {
+		utils.DisplayWarning(fmt.Sprintf("No terminal available for --select-commit; installing the template's usual commit on %s", branch))
+		return nil
+	}
+
+	commits, err := installerService.RecentCommits(*installConfig, config.RecentCommitLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list commits on %s: %w", branch, err)
+	}
+
+	commit, err := ui.SelectCommit(branch, commits)
+	if err != nil {
+		return err
+	}
+	installConfig.SelectedCommit = commit
+	return installConfig.Validate()
+}
+
 // validatePrerequisites checks that all required tools are available
-func validatePrerequisites() error {
+func validatePrerequisites(selectedTemplateID string) error {
 	utils.VerbosePrintln(verbose, "Validating prerequisites...")
 
+	// Archives given with --archive are unpacked without git
+	if installArchive != "" {
+		utils.VerbosePrintf(verbose, "Using archive: %s\n", installArchive)
+		return nil
+	}
+
+	// Plain local template directories are copied without git
+	if template, err := templates.GetTemplate(selectedTemplateID); err == nil && template.IsLocal() && !template.IsLocalGitRepo() {
+		utils.VerbosePrintf(verbose, "Using local template directory: %s\n", template.RepoURL)
+		return nil
+	}
+
+	// So are pinned commits already archived with --archive-dir
+	if template, err := templates.GetTemplate(selectedTemplateID); err == nil {
+		if archive := installer.ArchivePath(template, archiveDir); archive != "" {
+			if _, err := os.Stat(archive); err == nil {
+				utils.VerbosePrintf(verbose, "Using template archive: %s\n", archive)
+				return nil
+			}
+		}
+	}
+
 	// Check if git is installed
 	gitService := git.New()
 	if err := gitService.ValidateGitInstalled(); err != nil {
@@ -219,25 +853,119 @@ func validatePrerequisites() error {
 	return nil
 }
 
-// selectTemplate handles template selection based on flags and user input
-func selectTemplate(templateFlag string, skipPrompt bool) (string, error) {
-	// If template is specified via flag, validate and use it
-	if templateFlag != "" {
-		if err := templates.ValidateTemplateID(templateFlag); err != nil {
-			return "", fmt.Errorf("invalid template ID '%s': %w", templateFlag, err)
+// parseVariables converts --set name=value pairs into a variable map
+func parseVariables(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !templates.IsVariableName(name) {
+			return nil, models.NewAppError(
+				models.ErrorCodeInvalidConfiguration,
+				fmt.Sprintf("invalid --set value '%s'. Must be name=value with a name like ProjectName", pair),
+				nil,
+			)
 		}
-		return templateFlag, nil
+		values[name] = value
 	}
+	return values, nil
+}
 
-	// If skipping prompts, use default template
-	if skipPrompt {
-		return templates.DefaultTemplateID, nil
+// selectTemplateByBranch resolves --branch to the one registry template that
+// follows that branch
+func selectTemplateByBranch(branch string) (string, error) {
+	template, err := templates.FindTemplateByBranch(branch)
+	if errors.Is(err, templates.ErrNotFound) {
+		return "", fmt.Errorf("invalid --branch '%s': %w", branch, err)
+	}
+	if err != nil {
+		return "", models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("invalid --branch '%s'; choose one with --template", branch), err)
+	}
+	return checkDeprecation(template.ID)
+}
+
+// selectTemplates resolves the --template values, in order and without
+// duplicates, falling back to a single selected template when none are given
+func selectTemplates(templateFlags []string, skipPrompt bool, dir string) ([]string, error) {
+	if len(templateFlags) == 0 {
+		id, err := selectTemplate("", skipPrompt, dir)
+		if err != nil {
+			return nil, err
+		}
+		return []string{id}, nil
+	}
+
+	var selected []string
+	seen := make(map[string]bool)
+	for _, flag := range templateFlags {
+		id, err := selectTemplate(strings.TrimSpace(flag), skipPrompt, dir)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			selected = append(selected, id)
+		}
+	}
+	return selected, nil
+}
+
+// selectTemplate handles template selection based on flags and user input.
+// Skipping the prompt without --template installs the default for the
+// project in dir, which depends on its detected language.
+func selectTemplate(templateFlag string, skipPrompt bool, dir string) (string, error) {
+	// Use the template given with --template, or the default when skipping prompts
+	if templateFlag != "" || skipPrompt {
+		template, err := templates.GetTemplateOrDefaultForDir(templateFlag, dir)
+		if err != nil {
+			return "", suggestTemplates(err, templateFlag)
+		}
+		if templateFlag == "" && template.ID != templates.DefaultTemplateID {
+			utils.DisplayInfo(fmt.Sprintf("Detected a %s project; installing '%s' instead of '%s' (pass --template %s to install it)",
+				template.Language, template.ID, templates.DefaultTemplateID, templates.DefaultTemplateID))
+		}
+		return checkDeprecation(template.ID)
 	}
 
 	// Interactive template selection
 	return selectTemplateInteractively()
 }
 
+// checkDeprecation warns about deprecated templates and, with --follow-replacement,
+// resolves them to their replacement
+func checkDeprecation(id string) (string, error) {
+	template, err := templates.GetTemplate(id)
+	if err != nil {
+		return "", err
+	}
+
+	if !template.Deprecated {
+		return id, nil
+	}
+
+	if template.ReplacedBy == "" {
+		if template.DeprecationNote != "" {
+			utils.DisplayWarning(fmt.Sprintf("Template '%s' is deprecated: %s", id, template.DeprecationNote))
+			return id, nil
+		}
+		utils.DisplayWarning(fmt.Sprintf("Template '%s' is deprecated", id))
+		return id, nil
+	}
+
+	if !followReplacement {
+		utils.DisplayWarning(fmt.Sprintf("Template '%s' is deprecated; use '%s' instead (or pass --follow-replacement)", id, template.ReplacedBy))
+		return id, nil
+	}
+
+	replacement, err := templates.ResolveReplacement(id)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve replacement for template '%s': %w", id, err)
+	}
+
+	utils.DisplayWarning(fmt.Sprintf("Template '%s' is deprecated; installing replacement '%s'", id, replacement.ID))
+	return replacement.ID, nil
+}
+
 // selectTemplateInteractively presents template options to the user for selection using Bubble Tea
 func selectTemplateInteractively() (string, error) {
 	return ui.SelectTemplate()
@@ -256,7 +984,7 @@ func selectGitignoreMode(modeFlag string, skipPrompt bool) (string, error) {
 			}
 		}
 		if !validMode {
-			return "", fmt.Errorf("invalid gitignore mode '%s'. Must be one of: %v", modeFlag, validModes)
+			return "", models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("invalid gitignore mode '%s'. Must be one of: %v", modeFlag, validModes), nil)
 		}
 		return modeFlag, nil
 	}
@@ -279,16 +1007,37 @@ func selectGitignoreModeInteractively() (string, error) {
 func getInstallationConfirmation(plan *models.InstallationPlan) (bool, error) {
 	fmt.Println() // Empty line for readability
 	fmt.Printf("Target directory: %s\n", plan.TargetDir)
+	if plan.CreateTarget {
+		fmt.Println("  (does not exist yet and will be created)")
+	}
 	fmt.Printf("Installation type: %s\n", plan.InstallationType)
 
 	// Display template information
 	template := plan.Template
-	fmt.Printf("Template: %s (%s)\n", template.DisplayName(), template.ID)
+	fmt.Printf("Template: %s\n", template.DisplayName())
 	if template.Description != "" {
 		fmt.Printf("Description: %s\n", template.Description)
 	}
-	fmt.Printf("Branch: %s\n", template.Branch)
-	fmt.Printf("Commit: %s\n", template.Commit)
+	if installRef != "" {
+		fmt.Printf("Ref: %s (--ref, instead of the registry pin)\n", installRef)
+	} else {
+		fmt.Printf("Branch: %s\n", template.Branch)
+	}
+	if selectCommit && !template.FollowBranch {
+		fmt.Printf("Commit: %s (--select-commit)\n", template.Commit)
+	} else if installRef != "" {
+		fmt.Printf("Commit: latest on %s, resolved at install time\n", installRef)
+	} else if template.FollowBranch {
+		fmt.Printf("Commit: latest on %s (tracking branch)\n", template.Branch)
+	} else if commitFromVersionFile {
+		fmt.Printf("Commit: %s (pinned in %s, instead of the registry pin)\n", template.Commit, config.VersionFileName)
+	} else if fromCommit != "" {
+		fmt.Printf("Commit: %s (--from-commit, instead of the registry pin)\n", template.Commit)
+	} else if template.Tag != "" {
+		fmt.Printf("Commit: tag %s, resolved at install time\n", template.Tag)
+	} else {
+		fmt.Printf("Commit: %s\n", template.Commit)
+	}
 	fmt.Println()
 
 	// Display what will happen
@@ -350,11 +1099,60 @@ func getInstallationConfirmation(plan *models.InstallationPlan) (bool, error) {
 		fmt.Println()
 	}
 
+	displayPostInstallHooks(template, runHooks)
+
 	// Ask for confirmation
 	interactionService := utils.NewInteractionService()
 	return interactionService.ConfirmPrompt("This will install Strategic Claude Basic in the above directory.\nAre you sure you want to proceed?")
 }
 
+// displayPostInstallHooks lists the template's post-install hooks, saying
+// whether they will run
+func displayPostInstallHooks(template templates.Template, run bool) {
+	if len(template.PostInstall) == 0 {
+		return
+	}
+
+	if run {
+		fmt.Println("Post-install hooks to be run in the target directory:")
+	} else {
+		fmt.Println("Post-install hooks (not run without --run-hooks):")
+	}
+	for _, command := range template.PostInstall {
+		fmt.Printf("  $ %s\n", command)
+	}
+	if run {
+		fmt.Println("⚠️  WARNING: These commands will be executed with your user permissions.")
+	}
+	fmt.Println()
+}
+
+// exportManifest writes the manifest a new installation would record to path,
+// or to stdout when path is empty
+func exportManifest(installerService *installer.Service, installConfig models.InstallConfig, path string) error {
+	utils.VerbosePrintln(verbose, "Fetching template to compute its manifest...")
+	files, err := installerService.ExportManifest(installConfig)
+	if err != nil {
+		return fmt.Errorf("failed to compute manifest: %w", err)
+	}
+
+	data, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	data = append(data, '\n')
+
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, config.FilePermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+	utils.DisplaySuccess(fmt.Sprintf("Wrote the manifest of %d files to %s", len(files), path))
+	return nil
+}
+
 // displayDryRun shows what would happen without making changes
 func displayDryRun(plan *models.InstallationPlan) error {
 	fmt.Println("=== DRY RUN MODE ===")
@@ -362,6 +1160,9 @@ func displayDryRun(plan *models.InstallationPlan) error {
 	fmt.Println()
 
 	fmt.Printf("Target directory: %s\n", plan.TargetDir)
+	if plan.CreateTarget {
+		fmt.Println("  (does not exist yet and would be created)")
+	}
 	fmt.Printf("Installation type: %s\n", plan.InstallationType)
 	fmt.Println()
 
@@ -413,6 +1214,8 @@ func displayDryRun(plan *models.InstallationPlan) error {
 		fmt.Println()
 	}
 
+	displayFileChanges(plan)
+
 	if plan.BackupRequired {
 		fmt.Printf("Would create backup at: %s\n", plan.BackupDir)
 		fmt.Println()
@@ -430,6 +1233,8 @@ func displayDryRun(plan *models.InstallationPlan) error {
 		fmt.Println()
 	}
 
+	displayPostInstallHooks(plan.Template, runHooks)
+
 	if len(plan.Warnings) > 0 {
 		fmt.Println("Warnings:")
 		for _, warning := range plan.Warnings {
@@ -450,8 +1255,140 @@ func displayDryRun(plan *models.InstallationPlan) error {
 	return nil
 }
 
-// displayPostInstallInfo shows helpful information after successful installation
+// displayFileChanges lists the per-file operations of a previewed plan, grouped by action
+func displayFileChanges(plan *models.InstallationPlan) {
+	groups := []struct {
+		action models.FileAction
+		title  string
+		marker string
+	}{
+		{models.FileActionCreate, "Files that would be created", "+"},
+		{models.FileActionOverwrite, "Files that would be overwritten", "~"},
+		{models.FileActionRemove, "Files that would be removed", "-"},
+		{models.FileActionSkip, "Files that would be skipped", "="},
+	}
+
+	for _, group := range groups {
+		paths := plan.FileChangesByAction(group.action)
+		if len(paths) == 0 {
+			continue
+		}
+		fmt.Printf("%s (%d):\n", group.title, len(paths))
+		for _, path := range paths {
+			fmt.Printf("  %s %s\n", group.marker, path)
+		}
+		fmt.Println()
+	}
+}
+
+// displayPlanTree prints the files a previewed plan would install, and those it
+// would remove, as an indented tree with the action marked on each file
+func displayPlanTree(plan *models.InstallationPlan) error {
+	fmt.Printf("Install plan for %s (%s)\n", plan.TargetDir, plan.InstallationType)
+	if plan.CreateTarget {
+		fmt.Println("  (does not exist yet and would be created)")
+	}
+	fmt.Println()
+
+	if len(plan.Errors) > 0 {
+		for _, err := range plan.Errors {
+			utils.DisplayError(fmt.Errorf("%s", err))
+		}
+		return fmt.Errorf("installation plan has errors")
+	}
+
+	lines := formatFileTree(plan.FileChanges)
+	if len(lines) == 0 {
+		fmt.Println("No template files would be installed.")
+		return nil
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\n%d new, %d overwritten, %d removed\n",
+		len(plan.FileChangesByAction(models.FileActionCreate)),
+		len(plan.FileChangesByAction(models.FileActionOverwrite)),
+		len(plan.FileChangesByAction(models.FileActionRemove)))
+	return nil
+}
+
+// fileTreeNode is a directory or file in a plan tree
+type fileTreeNode struct {
+	action   models.FileAction // Empty for directories
+	children map[string]*fileTreeNode
+}
+
+// fileTreeLabels marks each planned file action in a tree
+var fileTreeLabels = map[models.FileAction]string{
+	models.FileActionCreate:    "new",
+	models.FileActionOverwrite: "overwrite",
+	models.FileActionRemove:    "remove",
+}
+
+// formatFileTree renders file changes as tree(1)-style lines, directories
+// first within each level and names in lexical order. Skipped files are left
+// out, along with directories holding nothing else.
+func formatFileTree(changes []models.FileChange) []string {
+	root := &fileTreeNode{children: map[string]*fileTreeNode{}}
+	for _, change := range changes {
+		if _, shown := fileTreeLabels[change.Action]; !shown {
+			continue
+		}
+		node := root
+		for _, part := range strings.Split(filepath.ToSlash(change.Path), "/") {
+			child, ok := node.children[part]
+			if !ok {
+				child = &fileTreeNode{children: map[string]*fileTreeNode{}}
+				node.children[part] = child
+			}
+			node = child
+		}
+		node.action = change.Action
+	}
+
+	var lines []string
+	var walk func(node *fileTreeNode, indent string, top bool)
+	walk = func(node *fileTreeNode, indent string, top bool) {
+		names := make([]string, 0, len(node.children))
+		for name := range node.children {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			iDir, jDir := node.children[names[i]].action == "", node.children[names[j]].action == ""
+			if iDir != jDir {
+				return iDir
+			}
+			return names[i] < names[j]
+		})
+
+		for i, name := range names {
+			child := node.children[name]
+			branch, nextIndent := "├── ", indent+"│   "
+			switch {
+			case top:
+				branch, nextIndent = "", "" // Top-level entries start the tree
+			case i == len(names)-1:
+				branch, nextIndent = "└── ", indent+"    "
+			}
+			if child.action == "" {
+				lines = append(lines, indent+branch+name+"/")
+				walk(child, nextIndent, false)
+			} else {
+				lines = append(lines, fmt.Sprintf("%s%s%s (%s)", indent, branch, name, fileTreeLabels[child.action]))
+			}
+		}
+	}
+	walk(root, "", true)
+	return lines
+}
+
+// displayPostInstallInfo shows helpful information after successful installation,
+// unless --quiet asked for errors only
 func displayPostInstallInfo(plan *models.InstallationPlan) {
+	if !logging.Enabled(slog.LevelInfo) {
+		return
+	}
 	fmt.Println()
 	fmt.Println("🎉 Strategic Claude Basic has been installed!")
 	fmt.Println()
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,10 @@ import (
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/installer"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/status"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+
+	"github.com/spf13/pflag"
 )
 
 // Test constants
@@ -204,6 +209,7 @@ func runInitTest(targetDir string, force, forceCore, dryRun bool) error {
 		DryRun:        dryRun,
 		Verbose:       false,   // Keep quiet for tests
 		GitignoreMode: "track", // Default gitignore mode for tests
+		NoCache:       true,    // Leave the user's clone cache alone
 	}
 
 	// Validate configuration
@@ -361,3 +367,312 @@ func checkSymlinkExists(t *testing.T, symlinkPath, description string) {
 		t.Errorf("%s exists but target is invalid: %s (%v)", description, symlinkPath, err)
 	}
 }
+
+// TestCheckDeprecation tests redirecting deprecated templates to their replacement
+func TestCheckDeprecation(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origFollow := followReplacement
+	defer func() {
+		templates.Registry.Set(original)
+		followReplacement = origFollow
+	}()
+
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	templates.Registry.Set(map[string]templates.Template{
+		"old": {ID: "old", Name: "Old", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit, Deprecated: true, ReplacedBy: "new"},
+		"new": {ID: "new", Name: "New", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit},
+	})
+
+	followReplacement = false
+	if id, err := checkDeprecation("old"); err != nil || id != "old" {
+		t.Errorf("checkDeprecation() without --follow-replacement = %q, %v; want old", id, err)
+	}
+
+	followReplacement = true
+	if id, err := checkDeprecation("old"); err != nil || id != "new" {
+		t.Errorf("checkDeprecation() with --follow-replacement = %q, %v; want new", id, err)
+	}
+}
+
+func TestSelectInitTemplates_Branch(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origIDs, origBranch := templateIDs, templateBranch
+	defer func() {
+		templates.Registry.Set(original)
+		templateIDs, templateBranch = origIDs, origBranch
+	}()
+
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	templates.Registry.Set(map[string]templates.Template{
+		"main":  {ID: "main", Name: "Main", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit},
+		"web":   {ID: "web", Name: "Web", RepoURL: "https://example.com/repo.git", Branch: "web-explorer", Commit: commit},
+		"fork":  {ID: "fork", Name: "Fork", RepoURL: "https://example.com/fork.git", Branch: "shared", Commit: commit},
+		"fork2": {ID: "fork2", Name: "Fork 2", RepoURL: "https://example.com/fork2.git", Branch: "shared", Commit: commit},
+	})
+
+	tests := []struct {
+		name     string
+		ids      []string
+		branch   string
+		want     string
+		wantCode models.ErrorCode
+		wantErr  error
+	}{
+		{name: "branch", branch: "web-explorer", want: "web"},
+		{name: "template wins", ids: []string{"main"}, branch: "web-explorer", want: "main"},
+		{name: "no match", branch: "missing", wantErr: templates.ErrNotFound},
+		{name: "several match", branch: "shared", wantCode: models.ErrorCodeInvalidConfiguration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateIDs, templateBranch = tt.ids, tt.branch
+			got, err := selectInitTemplates(t.TempDir())
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("selectInitTemplates() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantCode != "":
+				if !models.IsErrorCode(err, tt.wantCode) || !strings.Contains(err.Error(), "fork, fork2") {
+					t.Errorf("selectInitTemplates() error = %v, want %s listing fork, fork2", err, tt.wantCode)
+				}
+			case err != nil:
+				t.Fatalf("selectInitTemplates() error = %v", err)
+			case len(got) != 1 || got[0] != tt.want:
+				t.Errorf("selectInitTemplates() = %v, want [%s]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectTemplate(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	defer templates.Registry.Set(original)
+
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	templates.Registry.Set(map[string]templates.Template{
+		"main": {ID: "main", Name: "Main", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit},
+		"go":   {ID: "go", Name: "Go", RepoURL: "https://example.com/repo.git", Branch: "go", Commit: commit, Language: "go"},
+	})
+
+	goProject := t.TempDir()
+	if err := os.WriteFile(filepath.Join(goProject, "go.mod"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		flag    string
+		want    string
+		wantErr string
+	}{
+		{name: "given", flag: "main", want: "main"},
+		{name: "detected default", want: "go"},
+		{name: "unknown", flag: "mian", wantErr: "template 'mian' not found; did you mean: main?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectTemplate(tt.flag, true, goProject)
+			switch {
+			case tt.wantErr != "":
+				if err == nil || err.Error() != tt.wantErr || !errors.Is(err, templates.ErrNotFound) {
+					t.Errorf("selectTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("selectTemplate() error = %v", err)
+			case got != tt.want:
+				t.Errorf("selectTemplate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// A missing default is reported as such
+	templates.Registry.Set(map[string]templates.Template{
+		"go": {ID: "go", Name: "Go", RepoURL: "https://example.com/repo.git", Branch: "go", Commit: commit, Language: "go"},
+	})
+	if _, err := selectTemplate("", true, t.TempDir()); err == nil || err.Error() != "template 'main' not found" {
+		t.Errorf("selectTemplate() without a default error = %v, want template 'main' not found", err)
+	}
+}
+
+func TestApplyVersionPin(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origIDs, origCommit := templateIDs, fromCommit
+	defer func() {
+		templates.Registry.Set(original)
+		templateIDs, fromCommit = origIDs, origCommit
+	}()
+
+	registryCommit := "1234567890abcdef1234567890abcdef12345678"
+	otherCommit := "fedcba0987654321fedcba0987654321fedcba09"
+	templates.Registry.Set(map[string]templates.Template{
+		"main": {ID: "main", Name: "Main", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: registryCommit},
+	})
+
+	tests := []struct {
+		name       string
+		content    string
+		flag       string
+		wantIDs    []string
+		wantCommit string
+		wantErr    error
+	}{
+		{name: "no version file", wantIDs: []string{"default"}},
+		{name: "other commit", content: "main " + otherCommit, wantIDs: []string{"main"}, wantCommit: otherCommit},
+		{name: "registry commit", content: "main " + registryCommit, wantIDs: []string{"main"}},
+		{name: "template only", content: "main\n", wantIDs: []string{"main"}},
+		{name: "flag wins", content: "main " + otherCommit, flag: "template", wantIDs: []string{"default"}},
+		{name: "unknown template", content: "missing", wantErr: templates.ErrNotFound},
+		{name: "malformed", content: "main abc", wantErr: state.ErrMalformedVersionFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetDir := t.TempDir()
+			if tt.content != "" {
+				if err := os.WriteFile(state.VersionFilePath(targetDir), []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			flags := pflag.NewFlagSet("init", pflag.ContinueOnError)
+			flags.StringSlice("template", nil, "")
+			if tt.flag != "" {
+				if err := flags.Set(tt.flag, "default"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			templateIDs, fromCommit = []string{"default"}, ""
+
+			err := applyVersionPin(flags, targetDir)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("applyVersionPin() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyVersionPin() error = %v", err)
+			}
+			if strings.Join(templateIDs, ",") != strings.Join(tt.wantIDs, ",") || fromCommit != tt.wantCommit {
+				t.Errorf("applyVersionPin() selected %v at %q, want %v at %q", templateIDs, fromCommit, tt.wantIDs, tt.wantCommit)
+			}
+		})
+	}
+}
+
+func TestParseVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "name and value",
+			pairs: []string{"ProjectName=demo", "GoModule=github.com/acme/demo"},
+			want:  map[string]string{"ProjectName": "demo", "GoModule": "github.com/acme/demo"},
+		},
+		{
+			name:  "value containing equals and empty value",
+			pairs: []string{"Flags=-a=b", "Empty="},
+			want:  map[string]string{"Flags": "-a=b", "Empty": ""},
+		},
+		{name: "missing equals", pairs: []string{"ProjectName"}, wantErr: true},
+		{name: "empty name", pairs: []string{"=demo"}, wantErr: true},
+		{name: "name not usable in a template", pairs: []string{"project-name=demo"}, wantErr: true},
+		{name: "name starting with digit", pairs: []string{"1st=demo"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVariables(tt.pairs)
+			if tt.wantErr {
+				if !models.IsErrorCode(err, models.ErrorCodeInvalidConfiguration) {
+					t.Errorf("parseVariables() error = %v, want INVALID_CONFIGURATION", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseVariables() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseVariables() = %v, want %v", got, tt.want)
+			}
+			for name, value := range tt.want {
+				if got[name] != value {
+					t.Errorf("%s = %q, want %q", name, got[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatFileTree(t *testing.T) {
+	changes := []models.FileChange{
+		{Path: filepath.Join(".strategic-claude-basic", "core", "commands", "plan.md"), Action: models.FileActionCreate},
+		{Path: filepath.Join(".strategic-claude-basic", "core", "README.md"), Action: models.FileActionOverwrite},
+		{Path: filepath.Join(".strategic-claude-basic", "core", "old.md"), Action: models.FileActionRemove},
+		{Path: filepath.Join(".strategic-claude-basic", "templates", "draft.md"), Action: models.FileActionSkip},
+		{Path: filepath.Join(".strategic-claude-basic", "guides", "start.md"), Action: models.FileActionCreate},
+	}
+
+	want := []string{
+		".strategic-claude-basic/",
+		"├── core/",
+		"│   ├── commands/",
+		"│   │   └── plan.md (new)",
+		"│   ├── README.md (overwrite)",
+		"│   └── old.md (remove)",
+		"└── guides/",
+		"    └── start.md (new)",
+	}
+
+	got := formatFileTree(changes)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("formatFileTree() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestInitCommand_NonEmptyTarget(t *testing.T) {
+	useLocalTemplate(t, "local")
+	origTemplateIDs, origYes, origNoBackup, origGitignoreMode, origAllowNonEmpty := templateIDs, yes, noBackup, gitignoreMode, allowNonEmpty
+	t.Cleanup(func() {
+		templateIDs, yes, noBackup, gitignoreMode, allowNonEmpty = origTemplateIDs, origYes, origNoBackup, origGitignoreMode, origAllowNonEmpty
+	})
+	templateIDs = []string{"local"}
+	yes = true
+	noBackup = true
+	gitignoreMode = "track"
+	allowNonEmpty = false
+
+	writeProjectFile := func(dir string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+			t.Fatalf("Failed to write project file: %v", err)
+		}
+	}
+
+	// init in an existing project installs alongside its files
+	projectDir := t.TempDir()
+	writeProjectFile(projectDir)
+	t.Chdir(projectDir)
+	if err := runInit(initCmd, nil); err != nil {
+		t.Fatalf("runInit() in a project directory error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, config.StrategicClaudeBasicDir, config.LockFileName)); err != nil {
+		t.Errorf("Expected the lock file after init: %v", err)
+	}
+
+	// A directory named explicitly has to be empty unless --allow-non-empty
+	explicitDir := t.TempDir()
+	writeProjectFile(explicitDir)
+	if err := runInit(initCmd, []string{explicitDir}); !models.IsErrorCode(err, models.ErrorCodeDirectoryNotEmpty) {
+		t.Fatalf("runInit() into a populated target error = %v, want %s", err, models.ErrorCodeDirectoryNotEmpty)
+	}
+	allowNonEmpty = true
+	if err := runInit(initCmd, []string{explicitDir}); err != nil {
+		t.Fatalf("runInit() with --allow-non-empty error = %v", err)
+	}
+}
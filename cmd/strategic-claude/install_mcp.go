@@ -42,8 +42,7 @@ func runInstallMCP(cmd *cobra.Command, args []string) error {
 	// Get target directory
 	absTargetDir, err := filepath.Abs(targetDir)
 	if err != nil {
-		utils.DisplayError(fmt.Errorf("failed to resolve target directory: %w", err))
-		return err
+		return fmt.Errorf("failed to resolve target directory: %w", err)
 	}
 
 	utils.VerbosePrintf(verbose, "Target directory: %s\n", absTargetDir)
@@ -57,7 +56,6 @@ func runInstallMCP(cmd *cobra.Command, args []string) error {
 
 	availableMCPs, err := mcpService.ScanAvailableMCPs(strategicDir)
 	if err != nil {
-		utils.DisplayError(err)
 		return err
 	}
 
@@ -75,7 +73,6 @@ func runInstallMCP(cmd *cobra.Command, args []string) error {
 
 	selectedMCPs, err := ui.SelectMCPs(availableMCPs)
 	if err != nil {
-		utils.DisplayError(err)
 		return err
 	}
 
@@ -86,15 +83,13 @@ func runInstallMCP(cmd *cobra.Command, args []string) error {
 
 	plan, err := mcpService.AnalyzeInstallation(absTargetDir, selectedMCPs)
 	if err != nil {
-		utils.DisplayError(fmt.Errorf("failed to analyze installation: %w", err))
-		return err
+		return fmt.Errorf("failed to analyze installation: %w", err)
 	}
 
 	// Step 4: Display installation plan and get confirmation
 	confirmed, err := getMCPInstallationConfirmation(plan)
 	if err != nil {
-		utils.DisplayError(fmt.Errorf("confirmation failed: %w", err))
-		return err
+		return fmt.Errorf("confirmation failed: %w", err)
 	}
 	if !confirmed {
 		utils.DisplayInfo("MCP installation cancelled by user")
@@ -105,8 +100,7 @@ func runInstallMCP(cmd *cobra.Command, args []string) error {
 	utils.DisplayInfo("Installing selected MCP servers...")
 
 	if err := mcpService.InstallMCPServers(plan); err != nil {
-		utils.DisplayError(fmt.Errorf("MCP installation failed: %w", err))
-		return err
+		return fmt.Errorf("MCP installation failed: %w", err)
 	}
 
 	// Step 6: Display success message
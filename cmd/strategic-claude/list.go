@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	listTags     []string
+	listMatchAll bool
+	listLanguage string
+	listStrict   bool
+	listOutput   string
+	listGroupBy  string
+	listCount    bool
+
+	listDeprecated     bool
+	listOnlyDeprecated bool
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available templates",
+	Long: `List the templates available for installation.
+
+Deprecated templates are not shown unless --deprecated is given, which lists
+them alongside the others, marked with the template that replaces them, and
+--only-deprecated lists nothing else, for finding templates to migrate off.
+
+Use --tag to filter by one or more tags;
+by default a template matching any of the tags is listed, and --match-all
+requires a template to have every tag.
+
+Use --language to list the templates for a language along with the
+language-agnostic ones; add --strict to leave out the language-agnostic ones.
+
+Use --output json for machine-readable output. Without a filter the JSON
+includes deprecated templates, with their "deprecated" field set, so tooling
+can skip them.
+
+Use --group-by tag to list the templates under each tag they carry; a template
+with several tags appears once per tag. Use --group-by repo to list them under
+the repository they install from, showing the branch and commit each one uses.
+With --output json the result is an object mapping each tag or repository URL
+to its templates.
+
+Use --count for a quick overview of the registry: the number of active
+templates, with the total and deprecated counts, then how many active templates
+there are for each language and carry each tag. It takes no filters, and with
+--output json prints the counts as an object.
+
+Examples:
+  strategic-claude-basic-cli list                                # List all templates
+  strategic-claude-basic-cli list --tag web                      # Templates tagged "web"
+  strategic-claude-basic-cli list --tag web --tag workflow       # Tagged "web" or "workflow"
+  strategic-claude-basic-cli list --tag web --tag workflow --match-all  # Tagged with both
+  strategic-claude-basic-cli list --language go --strict                # Only templates written for Go
+  strategic-claude-basic-cli list --group-by tag                        # Templates grouped by tag
+  strategic-claude-basic-cli list --group-by repo                       # Templates grouped by repository
+  strategic-claude-basic-cli list --only-deprecated                     # Templates to migrate off
+  strategic-claude-basic-cli list --count                               # Registry size at a glance
+  strategic-claude-basic-cli list --output json | jq '.[].id'           # Script against the registry`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if listStrict && listLanguage == "" {
+			return fmt.Errorf("--strict requires --language")
+		}
+
+		if listCount {
+			counts := countTemplates()
+			switch listOutput {
+			case "text":
+				displayTemplateCounts(cmd, counts)
+				return nil
+			case "json":
+				return writeTemplateListJSON(cmd, counts)
+			}
+		}
+
+		switch listGroupBy {
+		case "":
+		case "tag":
+			switch listOutput {
+			case "text":
+				displayTemplatesByTag(selectListTemplates(false))
+				return nil
+			case "json":
+				return writeTemplateListJSON(cmd, templates.GroupByTag(selectListTemplates(false)))
+			}
+		case "repo":
+			switch listOutput {
+			case "text":
+				displayTemplatesByRepo(selectListTemplates(false))
+				return nil
+			case "json":
+				return writeTemplateListJSON(cmd, groupTemplatesByRepo(selectListTemplates(false)))
+			}
+		default:
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("invalid group '%s'. Must be one of: tag, repo", listGroupBy), nil)
+		}
+
+		switch listOutput {
+		case "text":
+			displayTemplateList(selectListTemplates(false))
+			return nil
+		case "json":
+			return writeTemplateListJSON(cmd, selectListTemplates(true))
+		default:
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("invalid output format '%s'. Must be one of: text, json", listOutput), nil)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().StringSliceVar(&listTags, "tag", nil, "only list templates with this tag (repeatable)")
+	listCmd.Flags().BoolVar(&listMatchAll, "match-all", false, "require templates to have every --tag instead of any")
+	listCmd.Flags().StringVar(&listLanguage, "language", "", "only list templates for this language, or language-agnostic ones")
+	listCmd.Flags().BoolVar(&listStrict, "strict", false, "with --language, leave out language-agnostic templates")
+	listCmd.Flags().StringVarP(&listOutput, "output", "o", "text", "output format: text or json")
+	listCmd.Flags().StringVar(&listGroupBy, "group-by", "", "group the listed templates: tag or repo")
+	listCmd.Flags().BoolVar(&listDeprecated, "deprecated", false, "include deprecated templates")
+	listCmd.Flags().BoolVar(&listOnlyDeprecated, "only-deprecated", false, "list only deprecated templates")
+	listCmd.Flags().BoolVar(&listCount, "count", false, "print the number of templates, per language and per tag, instead of listing them")
+	listCmd.MarkFlagsMutuallyExclusive("deprecated", "only-deprecated")
+	for _, filter := range []string{"tag", "language", "group-by", "deprecated", "only-deprecated"} {
+		listCmd.MarkFlagsMutuallyExclusive("count", filter)
+	}
+
+	if err := listCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		// This should not happen in normal operation, but we handle it for completeness
+		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --output flag: %v\n", err)
+	}
+	if err := listCmd.RegisterFlagCompletionFunc("group-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"tag", "repo"}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --group-by flag: %v\n", err)
+	}
+}
+
+// selectListTemplates applies the deprecation, tag, and language filters.
+// Without --deprecated or --only-deprecated, deprecated templates are only
+// included on request and when no filter is given.
+func selectListTemplates(includeDeprecated bool) []templates.Template {
+	var templateList []templates.Template
+	switch {
+	case listOnlyDeprecated:
+		templateList = templates.ListDeprecatedTemplates()
+	case listDeprecated, includeDeprecated && len(listTags) == 0 && listLanguage == "":
+		templateList = templates.ListTemplates()
+	default:
+		templateList = templates.ListActiveTemplates()
+	}
+
+	filtered := make([]templates.Template, 0, len(templateList))
+	for _, template := range templateList {
+		if len(listTags) > 0 && !template.MatchesTags(listTags, listMatchAll) {
+			continue
+		}
+		if listLanguage != "" && !template.MatchesLanguage(listLanguage, listStrict) {
+			continue
+		}
+		filtered = append(filtered, template)
+	}
+	return filtered
+}
+
+// groupTemplatesByRepo groups the selected templates by repository URL,
+// keeping their order within each group
+func groupTemplatesByRepo(selected []templates.Template) map[string][]templates.Template {
+	groups := make(map[string][]templates.Template)
+	for _, template := range selected {
+		groups[template.RepoURL] = append(groups[template.RepoURL], template)
+	}
+	return groups
+}
+
+// agnosticLanguage is the language templateCounts counts language-agnostic
+// templates under
+const agnosticLanguage = "agnostic"
+
+// templateCounts summarizes the registry for list --count. Languages and Tags
+// count active templates only.
+type templateCounts struct {
+	Active     int            `json:"active"`
+	Deprecated int            `json:"deprecated"`
+	Total      int            `json:"total"`
+	Languages  map[string]int `json:"languages"`
+	Tags       map[string]int `json:"tags"`
+}
+
+// countTemplates counts the registry's templates for list --count
+func countTemplates() templateCounts {
+	all := templates.ListTemplates()
+	active := templates.ListActiveTemplates()
+	counts := templateCounts{
+		Active:     len(active),
+		Deprecated: len(all) - len(active),
+		Total:      len(all),
+		Languages:  make(map[string]int),
+		Tags:       make(map[string]int),
+	}
+
+	for _, template := range active {
+		language := template.Language
+		if language == "" {
+			language = agnosticLanguage
+		}
+		counts.Languages[language]++
+	}
+	index := templates.TagsIndex()
+	for _, tag := range templates.AllTags() {
+		counts.Tags[tag] = len(index[tag])
+	}
+	return counts
+}
+
+// displayTemplateCounts prints the counts, languages and tags in name order
+func displayTemplateCounts(cmd *cobra.Command, counts templateCounts) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%d active templates (%d total, %d deprecated)\n", counts.Active, counts.Total, counts.Deprecated)
+
+	printCounts := func(heading string, byName map[string]int) {
+		if len(byName) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "\n%s:\n", heading)
+		for _, name := range slices.Sorted(maps.Keys(byName)) {
+			fmt.Fprintf(out, "  %-20s %d\n", name, byName[name])
+		}
+	}
+	printCounts("By language", counts.Languages)
+	printCounts("By tag", counts.Tags)
+}
+
+// writeTemplateListJSON marshals templates (already sorted by ID) to the command's output
+func writeTemplateListJSON(cmd *cobra.Command, templateList any) error {
+	data, err := json.MarshalIndent(templateList, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal templates: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return nil
+}
+
+// displayTemplateList prints one line per template with its pin and tags
+func displayTemplateList(templateList []templates.Template) {
+	if len(templateList) == 0 {
+		fmt.Println("No templates match the given filters.")
+		return
+	}
+
+	for _, template := range templateList {
+		displayTemplateEntry(template)
+		if len(template.Tags) > 0 {
+			fmt.Printf("    tags: %s\n", strings.Join(template.Tags, ", "))
+		}
+	}
+}
+
+// displayTemplatesByTag prints the templates under a heading for each tag, in
+// tag order, followed by the untagged ones
+func displayTemplatesByTag(templateList []templates.Template) {
+	if len(templateList) == 0 {
+		fmt.Println("No templates match the given filters.")
+		return
+	}
+
+	groups := templates.GroupByTag(templateList)
+	var untagged []templates.Template
+	for _, template := range templateList {
+		if len(template.Tags) == 0 {
+			untagged = append(untagged, template)
+		}
+	}
+
+	first := true
+	printGroup := func(heading string, group []templates.Template) {
+		if !first {
+			fmt.Println()
+		}
+		first = false
+		fmt.Printf("%s (%d):\n", heading, len(group))
+		for _, template := range group {
+			displayTemplateEntry(template)
+		}
+	}
+	for _, tag := range slices.Sorted(maps.Keys(groups)) {
+		printGroup(tag, groups[tag])
+	}
+	if len(untagged) > 0 {
+		printGroup("untagged", untagged)
+	}
+}
+
+// displayTemplatesByRepo prints the templates under a heading for each
+// repository, in URL order
+func displayTemplatesByRepo(templateList []templates.Template) {
+	if len(templateList) == 0 {
+		fmt.Println("No templates match the given filters.")
+		return
+	}
+
+	groups := groupTemplatesByRepo(templateList)
+	for i, url := range slices.Sorted(maps.Keys(groups)) {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%d):\n", url, len(groups[url]))
+		for _, template := range groups[url] {
+			displayTemplateEntry(template)
+		}
+	}
+}
+
+// displayTemplateEntry prints a template's display name and pin, with its
+// description in verbose mode. Deprecated templates are marked, along with
+// their replacement.
+func displayTemplateEntry(template templates.Template) {
+	fmt.Printf("  %-40s %s @ %s\n",
+		template.DisplayName(),
+		template.ShortCommit(),
+		template.Branch)
+	if verbose && template.Description != "" {
+		fmt.Printf("    %s\n", template.Description)
+	}
+	if verbose && template.DeprecationNote != "" {
+		fmt.Printf("    %s\n", template.DeprecationNote)
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestListCommand_JSONOutput(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origOutput, origTags := listOutput, listTags
+	defer func() {
+		templates.Registry.Set(original)
+		listOutput, listTags = origOutput, origTags
+	}()
+
+	templates.Registry.Set(map[string]templates.Template{
+		"zeta":  {ID: "zeta", Name: "Zeta", Tags: []string{"web"}},
+		"alpha": {ID: "alpha", Name: "Alpha", Deprecated: true},
+	})
+	listOutput = "json"
+	listTags = nil
+
+	var out bytes.Buffer
+	listCmd.SetOut(&out)
+	defer listCmd.SetOut(nil)
+
+	if err := listCmd.RunE(listCmd, []string{}); err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+
+	var got []map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, out.String())
+	}
+
+	if len(got) != 2 || got[0]["id"] != "alpha" || got[1]["id"] != "zeta" {
+		t.Fatalf("Expected templates sorted by ID including deprecated, got %v", got)
+	}
+	if got[0]["deprecated"] != true || got[1]["deprecated"] != false {
+		t.Errorf("Expected deprecated field on every template, got %v", got)
+	}
+}
+
+func TestListCommand_GroupByTagJSON(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origOutput, origTags, origGroupBy := listOutput, listTags, listGroupBy
+	defer func() {
+		templates.Registry.Set(original)
+		listOutput, listTags, listGroupBy = origOutput, origTags, origGroupBy
+	}()
+
+	templates.Registry.Set(map[string]templates.Template{
+		"web":     {ID: "web", Name: "Web", Tags: []string{"web", "workflow"}},
+		"cli":     {ID: "cli", Name: "CLI", Tags: []string{"cli", "workflow"}},
+		"retired": {ID: "retired", Name: "Retired", Tags: []string{"web"}, Deprecated: true},
+	})
+	listOutput, listGroupBy = "json", "tag"
+	listTags = []string{"web"}
+
+	var out bytes.Buffer
+	listCmd.SetOut(&out)
+	defer listCmd.SetOut(nil)
+
+	if err := listCmd.RunE(listCmd, []string{}); err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+
+	var got map[string][]map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, out.String())
+	}
+
+	// Only the --tag web selection is grouped, under each of its tags
+	if len(got) != 2 || len(got["web"]) != 1 || got["web"][0]["id"] != "web" ||
+		len(got["workflow"]) != 1 || got["workflow"][0]["id"] != "web" {
+		t.Errorf("Expected web grouped under web and workflow, got %v", got)
+	}
+}
+
+func TestGroupTemplatesByRepo(t *testing.T) {
+	selected := []templates.Template{
+		{ID: "ccr", RepoURL: "https://example.com/base.git", Branch: "ccr"},
+		{ID: "main", RepoURL: "https://example.com/base.git", Branch: "main"},
+		{ID: "other", RepoURL: "https://example.com/other.git", Branch: "main"},
+	}
+
+	groups := groupTemplatesByRepo(selected)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 repositories, got %v", groups)
+	}
+	base := groups["https://example.com/base.git"]
+	if len(base) != 2 || base[0].ID != "ccr" || base[1].ID != "main" {
+		t.Errorf("Expected ccr and main in order under the shared repository, got %v", base)
+	}
+	if other := groups["https://example.com/other.git"]; len(other) != 1 || other[0].ID != "other" {
+		t.Errorf("Expected other alone under its repository, got %v", other)
+	}
+}
+
+func TestListCommand_InvalidGroupBy(t *testing.T) {
+	origGroupBy := listGroupBy
+	defer func() { listGroupBy = origGroupBy }()
+
+	listGroupBy = "language"
+	err := listCmd.RunE(listCmd, []string{})
+	if err == nil || !strings.Contains(err.Error(), "invalid group") {
+		t.Errorf("Expected invalid group error, got %v", err)
+	}
+}
+
+func TestListCommand_InvalidOutput(t *testing.T) {
+	origOutput := listOutput
+	defer func() { listOutput = origOutput }()
+
+	listOutput = "yaml"
+	err := listCmd.RunE(listCmd, []string{})
+	if err == nil || !strings.Contains(err.Error(), "invalid output format") {
+		t.Errorf("Expected invalid output format error, got %v", err)
+	}
+}
+
+func TestSelectListTemplates_Language(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origTags, origLanguage, origStrict := listTags, listLanguage, listStrict
+	defer func() {
+		templates.Registry.Set(original)
+		listTags, listLanguage, listStrict = origTags, origLanguage, origStrict
+	}()
+
+	templates.Registry.Set(map[string]templates.Template{
+		"any":    {ID: "any", Tags: []string{"web"}},
+		"go":     {ID: "go", Language: "go", Tags: []string{"web"}},
+		"go-cli": {ID: "go-cli", Language: "go", Tags: []string{"cli"}},
+		"python": {ID: "python", Language: "python", Tags: []string{"web"}},
+	})
+
+	tests := []struct {
+		name    string
+		tags    []string
+		strict  bool
+		wantIDs []string
+	}{
+		{name: "lenient", wantIDs: []string{"any", "go", "go-cli"}},
+		{name: "strict", strict: true, wantIDs: []string{"go", "go-cli"}},
+		{name: "strict with tag", tags: []string{"web"}, strict: true, wantIDs: []string{"go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listTags, listLanguage, listStrict = tt.tags, "go", tt.strict
+
+			var gotIDs []string
+			for _, template := range selectListTemplates(true) {
+				gotIDs = append(gotIDs, template.ID)
+			}
+			if strings.Join(gotIDs, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("selectListTemplates() = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestSelectListTemplates_Deprecated(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origTags, origDeprecated, origOnly := listTags, listDeprecated, listOnlyDeprecated
+	defer func() {
+		templates.Registry.Set(original)
+		listTags, listDeprecated, listOnlyDeprecated = origTags, origDeprecated, origOnly
+	}()
+
+	templates.Registry.Set(map[string]templates.Template{
+		"new":     {ID: "new", Tags: []string{"web"}},
+		"old":     {ID: "old", Tags: []string{"web"}, Deprecated: true, ReplacedBy: "new"},
+		"retired": {ID: "retired", Tags: []string{"cli"}, Deprecated: true},
+	})
+
+	tests := []struct {
+		name           string
+		tags           []string
+		deprecated     bool
+		onlyDeprecated bool
+		wantIDs        []string
+	}{
+		{name: "active by default", wantIDs: []string{"new"}},
+		{name: "deprecated included", deprecated: true, wantIDs: []string{"new", "old", "retired"}},
+		{name: "deprecated with tag", tags: []string{"web"}, deprecated: true, wantIDs: []string{"new", "old"}},
+		{name: "only deprecated", onlyDeprecated: true, wantIDs: []string{"old", "retired"}},
+		{name: "only deprecated with tag", tags: []string{"cli"}, onlyDeprecated: true, wantIDs: []string{"retired"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listTags, listDeprecated, listOnlyDeprecated = tt.tags, tt.deprecated, tt.onlyDeprecated
+
+			var gotIDs []string
+			for _, template := range selectListTemplates(false) {
+				gotIDs = append(gotIDs, template.ID)
+			}
+			if strings.Join(gotIDs, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("selectListTemplates() = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestListCommand_Count(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	origOutput, origCount := listOutput, listCount
+	defer func() {
+		templates.Registry.Set(original)
+		listOutput, listCount = origOutput, origCount
+	}()
+
+	templates.Registry.Set(map[string]templates.Template{
+		"any":     {ID: "any", Tags: []string{"web", "Workflow"}},
+		"go":      {ID: "go", Language: "go", Tags: []string{"web"}},
+		"go-cli":  {ID: "go-cli", Language: "go"},
+		"retired": {ID: "retired", Language: "python", Tags: []string{"web"}, Deprecated: true},
+	})
+	listCount = true
+
+	var out bytes.Buffer
+	listCmd.SetOut(&out)
+	defer listCmd.SetOut(nil)
+
+	listOutput = "json"
+	if err := listCmd.RunE(listCmd, []string{}); err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+
+	var got templateCounts
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, out.String())
+	}
+	if got.Active != 3 || got.Deprecated != 1 || got.Total != 4 {
+		t.Errorf("Expected 3 active, 1 deprecated, 4 total, got %+v", got)
+	}
+	// The deprecated python template is counted in neither breakdown
+	if len(got.Languages) != 2 || got.Languages["go"] != 2 || got.Languages[agnosticLanguage] != 1 {
+		t.Errorf("Expected 2 go and 1 agnostic template, got %v", got.Languages)
+	}
+	if len(got.Tags) != 2 || got.Tags["web"] != 2 || got.Tags["workflow"] != 1 {
+		t.Errorf("Expected 2 web and 1 workflow template, got %v", got.Tags)
+	}
+
+	out.Reset()
+	listOutput = "text"
+	if err := listCmd.RunE(listCmd, []string{}); err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	text := out.String()
+	if !strings.HasPrefix(text, "3 active templates (4 total, 1 deprecated)\n") {
+		t.Errorf("Expected the totals first, got:\n%s", text)
+	}
+	for _, want := range []string{"By language:", "By tag:", "workflow"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in output:\n%s", want, text)
+		}
+	}
+}
@@ -1,5 +1,9 @@
 package main
 
+import "github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+
 func main() {
+	// Templates may require a minimum CLI version
+	templates.CLIVersion = version
 	Execute()
 }
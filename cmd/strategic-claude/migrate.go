@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [directory]",
+	Short: "Upgrade a project's lock file to the current format",
+	Long: `Upgrade the lock file of a project installed by an older release to the format
+this version writes. The format version recorded in the file decides what
+changes; the original is kept next to it as lock.json.v<version>.bak.
+
+Other commands read older lock files as they are and upgrade them the next
+time they write one, so migrating is only needed to commit the new format
+ahead of that, or to check that a project's lock file is readable. A lock file
+already in the current format is left untouched. One written by a newer
+release is refused rather than rewritten; upgrade the CLI instead.
+
+Examples:
+  strategic-claude-basic-cli migrate                 # Migrate the current directory
+  strategic-claude-basic-cli migrate ./my-project   # Migrate a specific directory`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := targetDir
+		if len(args) > 0 {
+			target = args[0]
+		}
+		absTarget, err := filepath.Abs(target)
+		if err != nil {
+			return fmt.Errorf("failed to resolve target directory: %w", err)
+		}
+
+		migration, err := state.MigrateLock(absTarget)
+		switch {
+		case errors.Is(err, state.ErrUnsupportedLock):
+			return models.NewAppError(
+				models.ErrorCodeValidationFailed,
+				"The lock file was written by a newer release of strategic-claude; upgrade the CLI to use it",
+				err,
+			)
+		case errors.Is(err, state.ErrMalformedLock):
+			return models.NewAppError(
+				models.ErrorCodeValidationFailed,
+				"The lock file is not in a format migrate recognizes; restore it from version control, or reinstall with 'init --force' to record a new one",
+				err,
+			)
+		case err != nil:
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+		switch {
+		case migration == nil:
+			fmt.Fprintf(w, "No lock file in %s; nothing to migrate\n", absTarget)
+		case !migration.Migrated():
+			fmt.Fprintf(w, "Lock file is already at version %d\n", migration.ToVersion)
+		default:
+			fmt.Fprintf(w, "Migrated lock file from version %d to %d\n", migration.FromVersion, migration.ToVersion)
+			fmt.Fprintf(w, "The original is saved as %s\n", migration.BackupPath)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
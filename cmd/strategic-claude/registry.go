@@ -0,0 +1,386 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/progress"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+
+	"github.com/spf13/cobra"
+)
+
+var registryCmd = &cobra.Command{
+	Use:     "registry",
+	Aliases: []string{"templates"},
+	Short:   "Work with template registries",
+	Long: `Commands for people who maintain template registries, such as forks of the
+built-in templates or a team's own registry file.`,
+	Args: cobra.NoArgs,
+}
+
+var registryCheckRemote bool
+
+var registryValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check every template in the registry for problems",
+	Long: `Check every template in the active registry and list all problems found with
+each one, so a registry can be fixed in one pass.
+
+The active registry is the built-in templates plus the templates loaded with
+--registry and --registry-url. Besides the checks made when a registry is
+loaded, a deprecated template must set replaced_by or deprecation_note, and a
+language must be one of the known languages. Entries that were skipped while
+loading their registry are reported too.
+
+With --check-remote, each template's repository is also contacted to confirm it
+can be reached and has the template's branch (or tag) and pinned commit. Only
+refs are listed, and a pinned commit that no branch or tag points at is fetched
+on its own, so nothing is cloned. A template in a plain local directory is
+checked to exist.
+
+The command exits with an error if any template has a problem, so it can gate CI.
+
+Examples:
+  strategic-claude-basic-cli registry validate
+  strategic-claude-basic-cli --registry ./templates.yaml registry validate
+  strategic-claude-basic-cli --registry-url https://example.com/templates.json registry validate --check-remote`,
+	Args: cobra.NoArgs,
+	// Invalid templates are reported in the listing, not as a usage mistake
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if registryCheckRemote && offline {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--check-remote cannot be used with --offline", nil)
+		}
+
+		invalid, total := displayRegistryProblems(cmd.OutOrStdout(), registryCheckRemote)
+		if invalid > 0 {
+			return models.NewAppError(models.ErrorCodeValidationFailed, fmt.Sprintf("%d of %d templates are invalid", invalid, total), nil)
+		}
+		return nil
+	},
+}
+
+// displayRegistryProblems lists each template in the registry, and each entry
+// skipped while loading it, with its problems; checkRemote adds the problems
+// found by contacting each repository. It returns how many templates are
+// invalid out of how many were checked.
+func displayRegistryProblems(w io.Writer, checkRemote bool) (int, int) {
+	gitService := git.New()
+
+	registered := templates.ListTemplates()
+
+	invalid := 0
+	for _, template := range registered {
+		id := template.ID
+		problems := validationProblems(template.Validate())
+		if checkRemote {
+			problems = append(problems, remoteProblems(gitService, template)...)
+		}
+		if len(problems) > 0 {
+			displayTemplateProblems(w, id, problems)
+			invalid++
+			continue
+		}
+		fmt.Fprintf(w, "✅ %s\n", id)
+	}
+
+	for _, skipped := range templates.Skipped {
+		displayTemplateProblems(w, fmt.Sprintf("%s (from %s, not loaded)", skipped.ID, skipped.Source), validationProblems(skipped.Err))
+		invalid++
+	}
+
+	total := len(registered) + len(templates.Skipped)
+	if invalid == 0 {
+		fmt.Fprintf(w, "\nAll %d templates are valid.\n", total)
+	}
+	return invalid, total
+}
+
+// displayTemplateProblems prints a failed template with one line per problem
+func displayTemplateProblems(w io.Writer, name string, problems []error) {
+	fmt.Fprintf(w, "❌ %s\n", name)
+	for _, problem := range problems {
+		fmt.Fprintf(w, "   - %v\n", problem)
+	}
+}
+
+// remoteProblems contacts a template's repository and reports whether it can be
+// reached and has the template's branch and pinned commit. A plain local
+// directory only has to exist.
+func remoteProblems(gitService *git.Service, template templates.Template) []error {
+	if template.RepoURL == "" {
+		return nil
+	}
+
+	if template.IsLocal() && !template.IsLocalGitRepo() {
+		path, err := template.LocalPath()
+		if err == nil {
+			var info os.FileInfo
+			if info, err = os.Stat(path); err == nil && !info.IsDir() {
+				err = fmt.Errorf("not a directory")
+			}
+		}
+		if err != nil {
+			return []error{fmt.Errorf("template directory %s cannot be used: %w", template.RepoURL, err)}
+		}
+		return nil
+	}
+
+	checking := progress.Start("Checking " + template.RepoURL)
+	defer checking.Done()
+
+	url := template.RepoURL
+	if template.IsLocal() {
+		url, _ = template.LocalPath()
+	}
+	if err := gitService.CheckRemote(url); err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	if template.Branch != "" {
+		if err := gitService.CheckRemoteRef(url, template.Branch); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if template.Tag != "" {
+		if err := gitService.CheckRemoteRef(url, template.Tag); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	// A malformed commit is already reported by Validate
+	if template.IsPinned() {
+		if err := gitService.CheckRemoteCommit(url, template.Commit); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// validationProblems splits an error joined by Template.Validate into its
+// problems, returning none for a nil error
+func validationProblems(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+var registrySchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the registry file format",
+	Long: `Print a JSON Schema describing registry documents: the templates map and each
+template's fields, their types, which are required, and the accepted values of
+fields such as commit and language. Point your editor at it to validate a
+templates.yaml or templates.json file as you write it.
+
+The schema is generated from the CLI's own template definition, so it matches
+the version you run; its $id names the format version.
+
+Examples:
+  strategic-claude-basic-cli registry schema > registry.schema.json
+
+  # In templates.yaml, for editors using the YAML language server:
+  # yaml-language-server: $schema=./registry.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := json.MarshalIndent(templates.RegistrySchema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode schema: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	},
+}
+
+var registryRefreshWrite string
+
+var registryRefreshCmd = &cobra.Command{
+	Use:   "refresh [template-id]",
+	Short: "Move pinned commits to the tips of their branches",
+	Long: `Look up the commit at the tip of each template's branch and print it next to
+the template's pinned commit, so a registry can be bumped without copying SHAs
+by hand. Give a template ID to refresh only that template.
+
+Only refs are listed, so nothing is cloned, except for templates that pin a
+tree_hash: their new commit is checked out to compute the matching hash.
+Templates that follow their branch, are pinned to a tag, or are plain local
+directories have no commit to move and are skipped.
+
+By default the templates of the active registry are refreshed and nothing is
+written. With --write, the templates of the given registry file are refreshed
+and their commit and tree_hash values rewritten in place, keeping the file's
+comments and formatting.
+
+Examples:
+  strategic-claude-basic-cli registry refresh
+  strategic-claude-basic-cli registry refresh ccr
+  strategic-claude-basic-cli templates refresh --write ./templates.yaml`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if offline {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "refresh cannot be used with --offline; it contacts each repository", nil)
+		}
+
+		var selected []templates.Template
+		var data []byte
+		if registryRefreshWrite != "" {
+			var err error
+			if data, err = os.ReadFile(registryRefreshWrite); err != nil {
+				return models.NewFileSystemError(models.ErrorCodeFileSystemError, registryRefreshWrite, err)
+			}
+			entries, err := templates.ParseRegistry(data)
+			if err != nil {
+				return models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("failed to parse registry file %s", registryRefreshWrite), err)
+			}
+			for _, template := range entries {
+				selected = append(selected, template)
+			}
+			sort.Slice(selected, func(i, j int) bool { return selected[i].ID < selected[j].ID })
+		} else {
+			selected = templates.ListTemplates()
+		}
+
+		if len(args) > 0 {
+			var found []templates.Template
+			for _, template := range selected {
+				if template.ID == args[0] {
+					found = append(found, template)
+				}
+			}
+			if len(found) == 0 {
+				if registryRefreshWrite != "" {
+					return models.NewAppError(models.ErrorCodeTemplateNotFound, fmt.Sprintf("template '%s' is not in %s", args[0], registryRefreshWrite), nil)
+				}
+				_, err := templates.GetTemplate(args[0])
+				return suggestTemplates(err, args[0])
+			}
+			selected = found
+		}
+
+		updates, failed := refreshTemplates(cmd.OutOrStdout(), selected)
+
+		if registryRefreshWrite != "" && len(updates) > 0 {
+			rewritten, err := templates.SetRegistryFields(data, updates)
+			if err != nil {
+				return models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("failed to rewrite registry file %s", registryRefreshWrite), err)
+			}
+			if err := writeFileKeepingMode(registryRefreshWrite, rewritten); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "\nUpdated %d template(s) in %s\n", len(updates), registryRefreshWrite)
+		}
+
+		if failed > 0 {
+			return models.NewAppError(models.ErrorCodeGitError, fmt.Sprintf("failed to refresh %d of %d templates", failed, len(selected)), nil)
+		}
+		return nil
+	},
+}
+
+// refreshTemplates prints the branch tip of each template next to its pinned
+// commit and returns the registry fields to set for the templates that moved,
+// keyed by ID, along with how many could not be refreshed
+func refreshTemplates(w io.Writer, selected []templates.Template) (map[string]map[string]string, int) {
+	gitService := git.New()
+	updates := make(map[string]map[string]string)
+	failed := 0
+
+	for _, template := range selected {
+		switch {
+		case template.FollowBranch:
+			fmt.Fprintf(w, "- %s: skipped, follows branch %s\n", template.ID, template.Branch)
+			continue
+		case template.Tag != "":
+			fmt.Fprintf(w, "- %s: skipped, pinned to tag %s\n", template.ID, template.Tag)
+			continue
+		case template.IsLocal() && !template.IsLocalGitRepo():
+			fmt.Fprintf(w, "- %s: skipped, plain local directory\n", template.ID)
+			continue
+		}
+
+		fields, err := refreshTemplate(gitService, template)
+		switch {
+		case err != nil:
+			fmt.Fprintf(w, "❌ %s: %v\n", template.ID, err)
+			failed++
+		case fields == nil:
+			fmt.Fprintf(w, "✅ %s: up to date at %s\n", template.ID, template.ShortCommit())
+		default:
+			fmt.Fprintf(w, "⬆️  %s: %s → %s (%s)\n", template.ID, template.ShortCommit(), fields["commit"], template.Branch)
+			updates[template.ID] = fields
+		}
+	}
+	return updates, failed
+}
+
+// refreshTemplate looks up the tip of a template's branch, returning the
+// commit and, when the template pins one, the tree hash to set, or nil when
+// the pinned commit is already the tip
+func refreshTemplate(gitService *git.Service, template templates.Template) (map[string]string, error) {
+	url := template.RepoURL
+	if template.IsLocal() {
+		url, _ = template.LocalPath()
+	}
+
+	checking := progress.Start("Checking " + template.RepoURL)
+	tip, err := gitService.RemoteBranchCommit(url, template.Branch)
+	checking.Done()
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(tip, template.Commit) {
+		return nil, nil
+	}
+
+	fields := map[string]string{"commit": tip}
+	if template.TreeHash != "" {
+		bumped, err := template.WithCommit(tip)
+		if err != nil {
+			return nil, err
+		}
+		_, treeHash, err := resolveTemplateCommit(bumped)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute the tree hash at %s: %w", tip, err)
+		}
+		fields["tree_hash"] = treeHash
+	}
+	return fields, nil
+}
+
+// writeFileKeepingMode replaces the contents of an existing file, keeping its
+// permissions
+func writeFileKeepingMode(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+	if err := os.WriteFile(path, data, info.Mode().Perm()); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+	registryCmd.AddCommand(registryValidateCmd)
+	registryCmd.AddCommand(registrySchemaCmd)
+	registryCmd.AddCommand(registryRefreshCmd)
+
+	registryRefreshCmd.Flags().StringVar(&registryRefreshWrite, "write", "", "refresh the templates of this registry file and rewrite their commits in place")
+
+	registryValidateCmd.Flags().BoolVar(&registryCheckRemote, "check-remote", false, "also contact each repository to check its branch and pinned commit exist")
+}
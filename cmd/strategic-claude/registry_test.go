@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestRegistryValidateCommand(t *testing.T) {
+	original, originalSkipped := templates.Registry.Snapshot(), templates.Skipped
+	defer func() { templates.Registry.Set(original); templates.Skipped = originalSkipped }()
+
+	valid := templates.Template{
+		ID:      "good",
+		Name:    "Good",
+		RepoURL: "https://example.com/repo.git",
+		Branch:  "main",
+		Commit:  "1234567890abcdef1234567890abcdef12345678",
+	}
+	retired := valid
+	retired.ID, retired.Deprecated, retired.Language = "retired", true, "cobol"
+
+	templates.Registry.Set(map[string]templates.Template{"good": valid, "retired": retired})
+	templates.Skipped = []templates.SkippedTemplate{
+		{ID: "broken", Source: "templates.yaml", Err: errors.New("template branch cannot be empty")},
+	}
+
+	var out bytes.Buffer
+	registryValidateCmd.SetOut(&out)
+	defer registryValidateCmd.SetOut(nil)
+
+	err := registryValidateCmd.RunE(registryValidateCmd, []string{})
+	if err == nil || !strings.HasSuffix(err.Error(), ": 2 of 3 templates are invalid") || models.ExitCode(err) != models.ExitCodeValidation {
+		t.Errorf("Expected 2 of 3 invalid with the validation exit code, got %v", err)
+	}
+
+	want := []string{
+		"✅ good",
+		"❌ retired",
+		"   - deprecated template must set replaced_by or deprecation_note",
+		"   - template language 'cobol' is not one of",
+		"❌ broken (from templates.yaml, not loaded)",
+		"   - template branch cannot be empty",
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Output has %d lines, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("Line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+
+	// A clean registry passes
+	templates.Registry.Set(map[string]templates.Template{"good": valid})
+	templates.Skipped = nil
+	out.Reset()
+	if err := registryValidateCmd.RunE(registryValidateCmd, []string{}); err != nil {
+		t.Errorf("Expected a valid registry to pass, got %v", err)
+	}
+	if !strings.Contains(out.String(), "All 1 templates are valid.") {
+		t.Errorf("Expected a summary, got %q", out.String())
+	}
+}
+
+func TestRegistryValidateCommand_CheckRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git not available, skipping remote checks")
+	}
+	original, originalSkipped, originalCheck := templates.Registry.Snapshot(), templates.Skipped, registryCheckRemote
+	defer func() {
+		templates.Registry.Set(original)
+		templates.Skipped, registryCheckRemote = originalSkipped, originalCheck
+	}()
+
+	repoDir := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	run("init", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	run("commit", "--allow-empty", "-m", "init")
+	head := run("rev-parse", "HEAD")
+
+	pinned := templates.Template{ID: "pinned", Name: "Pinned", RepoURL: "file://" + repoDir, Branch: "main", Commit: head}
+	gone := pinned
+	gone.ID, gone.Branch, gone.Commit = "gone", "release", strings.Repeat("a", 40)
+	missingDir := templates.Template{ID: "missing-dir", Name: "Missing", RepoURL: filepath.Join(t.TempDir(), "missing")}
+
+	templates.Registry.Set(map[string]templates.Template{"pinned": pinned, "gone": gone, "missing-dir": missingDir})
+	templates.Skipped = nil
+	registryCheckRemote = true
+
+	var out bytes.Buffer
+	registryValidateCmd.SetOut(&out)
+	defer registryValidateCmd.SetOut(nil)
+
+	if err := registryValidateCmd.RunE(registryValidateCmd, []string{}); err == nil || !strings.HasSuffix(err.Error(), ": 2 of 3 templates are invalid") {
+		t.Errorf("Expected 2 of 3 invalid, got %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"❌ gone\n   - GIT_REF_NOT_FOUND: Branch or tag release not found",
+		"   - GIT_COMMIT_NOT_FOUND: Commit " + strings.Repeat("a", 40),
+		"❌ missing-dir\n   - template directory",
+		"✅ pinned",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRegistryRefreshCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git not available, skipping remote checks")
+	}
+	original, originalWrite := templates.Registry.Snapshot(), registryRefreshWrite
+	defer func() {
+		templates.Registry.Set(original)
+		registryRefreshWrite = originalWrite
+	}()
+
+	repoDir := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	run("init", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	run("commit", "--allow-empty", "-m", "init")
+	old := run("rev-parse", "HEAD")
+	run("tag", "v1")
+	run("commit", "--allow-empty", "-m", "later")
+	tip := run("rev-parse", "HEAD")
+
+	registryFile := filepath.Join(t.TempDir(), "templates.yaml")
+	document := `# Maintained by the platform team
+templates:
+  current:
+    name: Current
+    repo_url: file://` + repoDir + `
+    branch: main
+    commit: ` + tip + `
+  stale:
+    name: Stale
+    repo_url: file://` + repoDir + `
+    branch: main
+    commit: ` + old + ` # bump me
+  edge:
+    name: Edge
+    repo_url: file://` + repoDir + `
+    branch: main
+    follow_branch: true
+  release:
+    name: Release
+    repo_url: file://` + repoDir + `
+    branch: main
+    tag: v1
+`
+	if err := os.WriteFile(registryFile, []byte(document), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	registryRefreshCmd.SetOut(&out)
+	defer registryRefreshCmd.SetOut(nil)
+
+	registryRefreshWrite = registryFile
+	if err := registryRefreshCmd.RunE(registryRefreshCmd, []string{}); err != nil {
+		t.Fatalf("refresh error = %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"✅ current: up to date at " + tip[:7],
+		"- edge: skipped, follows branch main",
+		"- release: skipped, pinned to tag v1",
+		"⬆️  stale: " + old[:7] + " → " + tip + " (main)",
+		"Updated 1 template(s) in " + registryFile,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	rewritten, err := os.ReadFile(registryFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(document, "commit: "+old+" # bump me", "commit: "+tip+" # bump me", 1)
+	if string(rewritten) != want {
+		t.Errorf("Rewritten registry =\n%s\nwant\n%s", rewritten, want)
+	}
+
+	// A template missing from the file is reported, and nothing is contacted
+	if err := registryRefreshCmd.RunE(registryRefreshCmd, []string{"missing"}); !models.IsErrorCode(err, models.ErrorCodeTemplateNotFound) {
+		t.Errorf("Expected %s for a template not in the file, got %v", models.ErrorCodeTemplateNotFound, err)
+	}
+}
@@ -1,15 +1,44 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/color"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/logging"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/progress"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	verbose   bool
-	targetDir string
+	verbose          bool
+	quiet            bool
+	gitVerbose       bool
+	targetDir        string
+	registryFile     string
+	registryURL      string
+	registryOverride bool
+	registryTimeout  time.Duration
+	noCache          bool
+	offline          bool
+	archiveDir       string
+	gitRetries       int
+	gitTimeout       time.Duration
+	noColor          bool
+	jsonErrors       bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -20,22 +49,141 @@ var rootCmd = &cobra.Command{
 of the Strategic Claude Basic framework into your development projects.
 
 It provides commands to install, update, check status, and clean up the framework
-installation while preserving your custom configurations and user content.`,
+installation while preserving your custom configurations and user content.
+
+Exit codes: 0 success, 1 other failure, 2 unknown template, 3 git or network
+failure, 4 filesystem or permission failure, 5 validation failure (invalid
+flags, configuration, or template), 6 already installed without --force.
+With --json-errors, a failure is reported on stderr as one JSON object with its
+code, exit_code, message, template, and the messages of its causes.
+
+Output is colorized only on a terminal; --no-color or the NO_COLOR environment
+variable turns color off everywhere.`,
 	Version: getVersion(),
+	// Execute prints errors itself, once, and picks the exit code
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Flags and arguments have been parsed, so any error from here on is
+		// not a usage mistake and the usage text would only bury it
+		cmd.SilenceUsage = true
+
+		if verbose && quiet {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--verbose and --quiet cannot be used together", nil)
+		}
+		logging.Setup(os.Stderr, logging.Level(verbose, quiet))
+		color.Setup(color.Decide(noColor, os.Getenv("NO_COLOR"), stdoutIsTerminal()))
+
+		// git's own output, with --git-verbose, goes to stderr as it is written;
+		// --quiet wins and keeps it captured
+		streamGit := gitVerbose && !quiet
+		if streamGit {
+			git.SetOutputStream(os.Stderr)
+		} else {
+			git.SetOutputStream(nil)
+		}
+
+		// Progress is redrawn in place on a terminal, except with --verbose or
+		// --git-verbose, whose many lines would break up the redrawn line
+		var progressOutput io.Writer
+		if !quiet {
+			progressOutput = os.Stderr
+		}
+		progress.Setup(progressOutput, !verbose && !streamGit && stderrIsTerminal())
+
+		if gitRetries < 1 {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--retries must be at least 1", nil)
+		}
+		if gitTimeout < 0 {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--timeout cannot be negative", nil)
+		}
+		if registryTimeout < 0 {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--registry-timeout cannot be negative", nil)
+		}
+
+		// The config commands must work even when the saved settings do not
+		if cmd != configCmd && cmd.Parent() != configCmd {
+			if err := applyUserConfig(cmd, args); err != nil {
+				return err
+			}
+		}
+		// Settings may turn offline mode on, so this is checked once they apply
+		if offline && noCache {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, "--offline needs the cache; it cannot be used with --no-cache", nil)
+		}
+		if err := loadRemoteRegistry(); err != nil {
+			return err
+		}
+		return loadUserRegistry()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// A failed command exits with the code models.ExitCode gives for its error.
 func Execute() {
+	// Checked before parsing, since a flag mistake may stop parsing before it
+	reportJSON := jsonErrorsRequested(os.Args[1:])
+	if reportJSON {
+		rootCmd.SilenceUsage = true // Nothing but the report goes to stderr
+	}
+
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		if reportJSON || jsonErrors {
+			writeErrorJSON(os.Stderr, err)
+		} else {
+			utils.DisplayError(err)
+		}
+		os.Exit(models.ExitCode(err))
 	}
 }
 
+// jsonErrorsRequested reports whether the command line turns --json-errors on
+func jsonErrorsRequested(args []string) bool {
+	requested := false
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--json-errors="); ok {
+			requested, _ = strconv.ParseBool(value)
+		} else if arg == "--json-errors" {
+			requested = true
+		}
+	}
+	return requested
+}
+
+// writeErrorJSON prints the models.ErrorReport of err as a single line of JSON
+func writeErrorJSON(w io.Writer, err error) {
+	data, marshalErr := json.Marshal(models.NewErrorReport(err))
+	if marshalErr != nil {
+		// Context values are plain data, so this should not happen
+		data, _ = json.Marshal(models.ErrorReport{Code: models.ErrorCodeUnknown, ExitCode: models.ExitCode(err), Message: err.Error()})
+	}
+	fmt.Fprintln(w, string(data))
+}
+
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output, logging each git command, copied file, and timing")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only print errors and the command's own output")
+	rootCmd.PersistentFlags().BoolVar(&gitVerbose, "git-verbose", false, "stream the output of every git command to stderr as it runs (--quiet turns it off)")
 	rootCmd.PersistentFlags().StringVarP(&targetDir, "target", "t", ".", "target directory for operations")
+	rootCmd.PersistentFlags().StringVar(&registryFile, "registry", "", "path to a user-defined template registry file (default: ~/.config/strategic-claude/templates.yaml)")
+	rootCmd.PersistentFlags().StringVar(&registryURL, "registry-url", "", "URL of a JSON or YAML template registry merged with the built-in templates")
+	rootCmd.PersistentFlags().DurationVar(&registryTimeout, "registry-timeout", config.DefaultNetworkTimeout, "give up each attempt at fetching the --registry-url document after this long (0 for no limit)")
+	rootCmd.PersistentFlags().BoolVar(&registryOverride, "registry-override", false, "allow user-defined templates to override built-in templates with the same ID")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "fetch templates and remote registries afresh instead of using the cache")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "never contact the network; use only cached templates and registries")
+	rootCmd.PersistentFlags().StringVar(&archiveDir, "archive-dir", "", "install pinned template commits from tar archives in this directory, archiving them there on first fetch")
+	rootCmd.PersistentFlags().IntVar(&gitRetries, "retries", config.DefaultGitRetries, "most attempts at a template clone or fetch, or a registry download, that fails on the network")
+	rootCmd.PersistentFlags().BoolVar(&jsonErrors, "json-errors", false, "report a failure on stderr as a JSON object instead of text")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "never colorize output (also set by the NO_COLOR environment variable)")
+	rootCmd.PersistentFlags().DurationVar(&gitTimeout, "timeout", config.DefaultCloneTimeout, "give up fetching a template after this long, e.g. 2m (0 for no limit)")
+
+	// Flag mistakes exit with the validation code, like other invalid input
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return models.NewAppError(models.ErrorCodeInvalidConfiguration, err.Error(), nil)
+	})
 
 	// Custom completions for flags
 	if err := rootCmd.RegisterFlagCompletionFunc("target", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -45,3 +193,88 @@ func init() {
 		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --target flag: %v\n", err)
 	}
 }
+
+// loadRemoteRegistry merges the templates from --registry-url, if given, into
+// the built-in registry. Its templates replace built-in ones with the same ID.
+func loadRemoteRegistry() error {
+	if registryURL == "" {
+		return nil
+	}
+
+	cacheDir := ""
+	if userCacheDir, err := config.GetUserCacheDir(); err == nil && !noCache {
+		cacheDir = filepath.Join(userCacheDir, config.RegistryCacheDirName)
+	}
+
+	utils.VerbosePrintf(verbose, "Loading template registry from %s\n", registryURL)
+
+	warnings, err := templates.LoadRegistryURL(registryURL, templates.RemoteOptions{
+		CacheDir: cacheDir,
+		TTL:      config.RegistryCacheTTL,
+		Timeout:  registryTimeout,
+		Attempts: gitRetries,
+		Offline:  offline,
+		OnRetry: func(message string) {
+			utils.VerbosePrintf(verbose, "%s\n", message)
+		},
+	})
+	for _, warning := range warnings {
+		utils.DisplayWarning(warning)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load template registry: %w", err)
+	}
+
+	return nil
+}
+
+// userRegistryPath returns the user registry file to load: the --registry
+// path, or the default location when a file exists there, or "" for none
+func userRegistryPath() string {
+	if registryFile != "" {
+		return registryFile
+	}
+
+	defaultPath, err := config.GetDefaultRegistryPath()
+	if err != nil {
+		return "" // No home directory, nothing to load
+	}
+	if _, err := os.Stat(defaultPath); os.IsNotExist(err) {
+		return ""
+	}
+	return defaultPath
+}
+
+// loadUserRegistry merges user-defined templates into the built-in registry.
+// An explicit --registry path must exist; the default location is optional.
+func loadUserRegistry() error {
+	path := userRegistryPath()
+	if path == "" {
+		return nil
+	}
+
+	utils.VerbosePrintf(verbose, "Loading template registry from %s\n", path)
+
+	warnings, err := templates.LoadRegistryFile(path, registryOverride)
+	for _, warning := range warnings {
+		utils.DisplayWarning(warning)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load template registry: %w", err)
+	}
+
+	return nil
+}
+
+// suggestTemplates adds "did you mean" hints to err when it reports that the
+// template id is not in the registry, naming the closest template IDs
+func suggestTemplates(err error, id string) error {
+	if !errors.Is(err, templates.ErrNotFound) {
+		return err
+	}
+	suggestions := templates.SuggestTemplateIDs(id)
+	if len(suggestions) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w; did you mean: %s?", err, strings.Join(suggestions, ", "))
+}
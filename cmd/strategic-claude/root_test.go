@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+func TestJSONErrorsRequested(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"init"}, want: false},
+		{args: []string{"--json-errors", "init"}, want: true},
+		{args: []string{"init", "--json-errors=true"}, want: true},
+		{args: []string{"init", "--json-errors", "--json-errors=false"}, want: false},
+		{args: []string{"init", "--", "--json-errors"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := jsonErrorsRequested(tt.args); got != tt.want {
+				t.Errorf("jsonErrorsRequested(%v) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteErrorJSON(t *testing.T) {
+	var out bytes.Buffer
+	writeErrorJSON(&out, models.NewAppError(models.ErrorCodeInvalidConfiguration, "bad flag", nil))
+
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("Expected a single line, got %q", out.String())
+	}
+	var report map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, out.String())
+	}
+	if report["code"] != "INVALID_CONFIGURATION" || report["exit_code"] != float64(models.ExitCodeValidation) || report["message"] != "bad flag" {
+		t.Errorf("writeErrorJSON() = %v", report)
+	}
+}
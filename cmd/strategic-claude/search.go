@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search templates by name, description, or tag",
+	Long: `Search the available templates for a query string.
+
+The query is matched case-insensitively against each template's ID, name,
+description, and tags. Deprecated templates are not shown.
+
+Examples:
+  strategic-claude-basic-cli search browser     # Finds web-explorer via its description
+  strategic-claude-basic-cli search workflow    # Matches templates tagged "workflow"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		displayTemplateList(templates.SearchTemplates(strings.Join(args, " ")))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+}
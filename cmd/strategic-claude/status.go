@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"path/filepath"
 
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
@@ -20,6 +21,7 @@ This command will:
 - Verify .claude directory structure
 - Check symlink integrity
 - Report any configuration issues
+- Compare the installed template commit with the registry
 - Display detailed installation information
 
 Examples:
@@ -40,9 +42,7 @@ Examples:
 			return fmt.Errorf("failed to resolve target directory: %w", err)
 		}
 
-		if verbose {
-			fmt.Printf("Checking directory: %s\n", absTarget)
-		}
+		slog.Debug("Checking directory", "dir", absTarget)
 
 		// Create status service and check installation
 		statusService := status.NewService()
@@ -90,11 +90,17 @@ func displayStatus(statusInfo *models.StatusInfo, statusService *status.Service,
 	if statusInfo.InstalledTemplate != nil {
 		fmt.Printf("\nTemplate Information:\n")
 		template := statusInfo.InstalledTemplate.Template
-		fmt.Printf("  Name: %s\n", template.DisplayName())
-		fmt.Printf("  ID: %s\n", template.ID)
+		fmt.Printf("  Template: %s\n", template.DisplayName())
 		fmt.Printf("  Description: %s\n", template.Description)
-		fmt.Printf("  Branch: %s\n", template.Branch)
-		fmt.Printf("  Commit: %s\n", template.Commit)
+		if template.FollowBranch {
+			fmt.Printf("  Tracking branch: %s\n", template.Branch)
+			if statusInfo.InstalledTemplate.InstalledCommit != "" {
+				fmt.Printf("  Installed Commit: %s\n", shortCommit(statusInfo.InstalledTemplate.InstalledCommit))
+			}
+		} else {
+			fmt.Printf("  Branch: %s\n", template.Branch)
+			fmt.Printf("  Commit: %s\n", template.ShortCommit())
+		}
 		if statusInfo.InstalledTemplate.InstalledAt != "" {
 			fmt.Printf("  Installed At: %s\n", statusInfo.InstalledTemplate.InstalledAt)
 		}
@@ -106,6 +112,17 @@ func displayStatus(statusInfo *models.StatusInfo, statusService *status.Service,
 		}
 	}
 
+	// Display installed version compared with the registry
+	if len(statusInfo.VersionChecks) > 0 {
+		displayVersionChecks(statusInfo.VersionChecks)
+	}
+	if statusInfo.LockError != "" {
+		fmt.Printf("\n⚠️  Lock file could not be read: %s\n", statusInfo.LockError)
+	}
+	if statusInfo.Unmanaged {
+		fmt.Printf("\n⚠️  Not managed: installed without a lock file (init --no-lock), so update, verify, and diff will not run\n")
+	}
+
 	// Display symlink information
 	if len(statusInfo.Symlinks) > 0 {
 		fmt.Printf("\nSymlinks:\n")
@@ -170,3 +187,76 @@ func init() {
 		return []string{}, cobra.ShellCompDirectiveNoFileComp
 	}
 }
+
+// displayVersionChecks prints how each installed template compares with the
+// registry, in the order the templates are layered
+func displayVersionChecks(checks []*models.VersionCheck) {
+	if len(checks) == 1 {
+		fmt.Printf("\nVersion:\n")
+		displayVersionCheck(checks[0])
+		return
+	}
+
+	fmt.Printf("\nVersions (%d templates layered, later ones take precedence):\n", len(checks))
+	for i, check := range checks {
+		if i > 0 {
+			fmt.Println()
+		}
+		displayVersionCheck(check)
+	}
+}
+
+// displayVersionCheck prints how an installed commit compares with the registry
+func displayVersionCheck(check *models.VersionCheck) {
+	if check.State == models.VersionStateTemplateMissing {
+		fmt.Printf("  ⚠️  Installed template '%s' no longer exists in the registry\n", check.TemplateID)
+		fmt.Printf("  Installed Commit: %s\n", shortCommit(check.InstalledCommit))
+		if check.SuggestedID != "" {
+			fmt.Printf("  Closest available template: %s\n", check.SuggestedID)
+		}
+		return
+	}
+
+	// Repositories installed with --repo-url are not in the registry
+	if check.TemplateName == "" && check.Ref != "" {
+		fmt.Printf("  Template: %s (installed from a repository)\n", check.TemplateID)
+		fmt.Printf("  Ref: %s\n", check.Ref)
+		fmt.Printf("  Installed Commit: %s\n", shortCommit(check.InstalledCommit))
+		fmt.Printf("  📌 Not in the registry; run 'init --repo-url <url> --ref %s' again to update it\n", check.Ref)
+		return
+	}
+
+	fmt.Printf("  Template: %s (%s)\n", check.TemplateName, check.TemplateID)
+	if check.Branch != "" {
+		fmt.Printf("  Branch: %s\n", check.Branch)
+	}
+	if check.Ref != "" {
+		fmt.Printf("  Ref: %s\n", check.Ref)
+	}
+	if check.InstalledTag != "" {
+		fmt.Printf("  Installed Commit: %s (tag %s)\n", shortCommit(check.InstalledCommit), check.InstalledTag)
+	} else {
+		fmt.Printf("  Installed Commit: %s\n", shortCommit(check.InstalledCommit))
+	}
+	if check.RegistryTag != "" {
+		fmt.Printf("  Registry Tag: %s\n", check.RegistryTag)
+	} else if check.RegistryCommit != "" {
+		fmt.Printf("  Registry Commit: %s\n", shortCommit(check.RegistryCommit))
+	} else if check.Branch != "" {
+		fmt.Printf("  Registry Commit: latest on %s\n", check.Branch)
+	} else {
+		// Plain local directories have no branch or commit to follow
+		fmt.Printf("  Registry Commit: none (plain local directory)\n")
+	}
+
+	switch check.State {
+	case models.VersionStateCurrent:
+		fmt.Printf("  ✅ Up to date\n")
+	case models.VersionStateBehind:
+		fmt.Printf("  ⬆️  Update available (run 'strategic-claude-basic-cli update')\n")
+	case models.VersionStateOverridden:
+		fmt.Printf("  📌 Installed at a commit chosen with --from-commit or --ref ('strategic-claude-basic-cli update' returns to the registry commit)\n")
+	default:
+		fmt.Printf("  ❔ Unable to compare installed and registry commits\n")
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/cleaner"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	uninstallForce    bool
+	uninstallYes      bool
+	uninstallTemplate string
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall [directory]",
+	Short: "Remove exactly the files the last install created",
+	Long: `Remove the files recorded in the lock file when the template was installed.
+
+Unlike clean, which removes the whole .strategic-claude-basic directory, this
+command deletes only the files and symlinks the installer created, then prunes
+the directories this leaves empty. Anything you added yourself stays.
+
+Files you changed after installing are detected by their content hash and kept,
+along with the lock file, unless --force is given.
+
+With --template, only one of several layered templates is removed. Files only
+that template installed are deleted; files another installed template also
+ships stay in place and are handed off to it, and the framework and the other
+templates are left alone.
+
+Examples:
+  strategic-claude-basic-cli uninstall                 # Uninstall from the current directory
+  strategic-claude-basic-cli uninstall ./my-project   # Uninstall from a specific directory
+  strategic-claude-basic-cli uninstall --force --yes  # Also delete modified files, without prompting
+  strategic-claude-basic-cli uninstall --template ccr # Remove one layered template, keeping the rest`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := targetDir
+		if len(args) > 0 {
+			target = args[0]
+		}
+
+		absTarget, err := filepath.Abs(target)
+		if err != nil {
+			return fmt.Errorf("failed to resolve target directory: %w", err)
+		}
+
+		if !uninstallYes {
+			what := "the installed template files"
+			if uninstallTemplate != "" {
+				what = fmt.Sprintf("the files of template '%s'", uninstallTemplate)
+			}
+			message := fmt.Sprintf("Remove %s from %s?", what, absTarget)
+			if uninstallForce {
+				message = fmt.Sprintf("Remove %s from %s, including files you modified?", what, absTarget)
+			}
+			confirmed, err := utils.NewInteractionService().ConfirmPrompt(message)
+			if err != nil {
+				return fmt.Errorf("failed to get user confirmation: %w", err)
+			}
+			if !confirmed {
+				fmt.Println("Uninstall cancelled by user")
+				return nil
+			}
+		}
+
+		var result *cleaner.UninstallResult
+		if uninstallTemplate != "" {
+			result, err = cleaner.New().UninstallTemplate(absTarget, uninstallTemplate, uninstallForce)
+		} else {
+			result, err = cleaner.New().Uninstall(absTarget, uninstallForce)
+		}
+		if result != nil {
+			displayUninstallResult(result)
+		}
+		if err != nil {
+			return fmt.Errorf("uninstall failed: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+
+	uninstallCmd.Flags().BoolVarP(&uninstallForce, "force", "f", false, "also remove files modified since installation")
+	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "skip the confirmation prompt")
+	uninstallCmd.Flags().StringVar(&uninstallTemplate, "template", "", "remove only this layered template, handing shared files to the others")
+
+	if err := uninstallCmd.RegisterFlagCompletionFunc("template", completeInstalledTemplateIDs); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --template flag: %v\n", err)
+	}
+
+	// Custom completion for directory argument
+	uninstallCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return []string{}, cobra.ShellCompDirectiveFilterDirs
+		}
+		return []string{}, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// displayUninstallResult summarizes what was removed and what was kept
+func displayUninstallResult(result *cleaner.UninstallResult) {
+	fmt.Println()
+
+	utils.DisplaySuccess(fmt.Sprintf("Removed %d installed file(s)", len(result.Removed)))
+	if verbose {
+		for _, path := range result.Removed {
+			fmt.Printf("  • %s\n", path)
+		}
+	}
+
+	if len(result.PrunedDirectories) > 0 {
+		utils.DisplaySuccess(fmt.Sprintf("Removed %d empty director(ies)", len(result.PrunedDirectories)))
+		if verbose {
+			for _, dir := range result.PrunedDirectories {
+				fmt.Printf("  • %s\n", dir)
+			}
+		}
+	}
+
+	if len(result.HandedOff) > 0 {
+		utils.DisplayInfo(fmt.Sprintf("Kept %d file(s) other installed templates also ship:", len(result.HandedOff)))
+		for _, handoff := range result.HandedOff {
+			fmt.Printf("  • %s (now recorded under '%s')\n", handoff.Path, handoff.TemplateID)
+		}
+	}
+
+	if result.CleanedSettings {
+		utils.DisplaySuccess("Removed strategic hooks from settings.json")
+	}
+
+	if len(result.Missing) > 0 {
+		utils.DisplayInfo(fmt.Sprintf("%d installed file(s) were already gone", len(result.Missing)))
+	}
+
+	if len(result.Modified) > 0 {
+		if result.RemovedLock || result.RemovedTemplate != "" {
+			utils.DisplayWarning(fmt.Sprintf("Removed %d file(s) modified since installation:", len(result.Modified)))
+		} else {
+			utils.DisplayWarning(fmt.Sprintf("Kept %d file(s) modified since installation:", len(result.Modified)))
+		}
+		for _, path := range result.Modified {
+			fmt.Printf("  • %s\n", path)
+		}
+		if !result.RemovedLock && result.RemovedTemplate == "" {
+			utils.DisplayInfo("Run uninstall --force to remove them as well")
+		}
+	}
+
+	for _, warning := range result.Warnings {
+		utils.DisplayWarning(warning)
+	}
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/installer"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	updateForce     bool
+	updateYes       bool
+	updateNoBackup  bool
+	updateOverwrite bool
+	updateDiff      bool
+	updateRunHooks  bool
+	updatePrune     bool
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update [directory]",
+	Short: "Update an installation to the template's current commit",
+	Long: `Update an existing Strategic Claude Basic installation to the commit currently
+pinned in the template registry.
+
+This command will:
+- Read the lock file written by init to find the installed templates and commits
+- Compare each installed commit with the registry's current commit
+- Re-apply the framework files (core, templates) if they differ, preserving user content
+
+When several templates are layered in the project (see init --add), each one
+that is behind is re-applied over the others in the order they were added, so
+later templates still take precedence where they install the same file.
+
+Framework files you edited since the last install are detected by comparing
+them with the hashes recorded in the lock file, and your edits are kept. When
+the template changed such a file too, it is reported as a conflict. Use
+--overwrite to take the template's copy instead, and --diff to see how each
+edited file differs from it.
+
+Core and templates are replaced as a whole, so files the template dropped from
+them go too, unless you edited them. Recorded files elsewhere in the framework
+directory that the template no longer ships, such as the seed files of the
+plan or research directories, are reported as removed upstream and kept; pass
+--prune to delete them as well, except those you edited since.
+
+The exclude patterns and variable values in the project's
+.strategic-claude/config.yaml apply to every update, so everyone working on
+the project gets the same files; see config --help.
+
+A .strategic-claude-version file in the project (see init --help) must name
+one of the installed templates. When it pins a commit, update moves that
+template to the pinned commit instead of the registry's, and says where the
+registry is; change the file to move on.
+
+The template's post-install hooks are skipped unless --run-hooks is given; see
+init --help for what they are and the risks of running them.
+
+Use --force to reinstall even when the commits match, for example with
+--overwrite to recover from manual edits to framework files.
+
+Examples:
+  strategic-claude-basic-cli update                      # Update current directory
+  strategic-claude-basic-cli update ./my-project        # Update specific directory
+  strategic-claude-basic-cli update --diff              # Show how edited files differ from the template
+  strategic-claude-basic-cli update --prune             # Delete unedited files the template removed
+  strategic-claude-basic-cli update --force --overwrite # Reinstall the current commit, discarding edits`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUpdate(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	updateCmd.Flags().BoolVarP(&updateForce, "force", "f", false, "reinstall even if the installed commit is current")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "automatically answer yes to all prompts")
+	updateCmd.Flags().BoolVar(&updateNoBackup, "no-backup", false, "skip creating a backup of the existing installation")
+	updateCmd.Flags().BoolVar(&updateOverwrite, "overwrite", false, "replace locally edited framework files with the template's copy")
+	updateCmd.Flags().BoolVar(&updateRunHooks, "run-hooks", false, "run each updated template's post-install hook commands (they run with your permissions)")
+	updateCmd.Flags().BoolVar(&updateDiff, "diff", false, "print a unified diff for each locally edited framework file")
+	updateCmd.Flags().BoolVar(&updatePrune, "prune", false, "delete recorded files the template no longer ships, unless edited locally")
+}
+
+// runUpdate executes the update command logic
+func runUpdate(args []string) error {
+	// Determine target directory
+	target := targetDir
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	// Convert to absolute path
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+
+	utils.VerbosePrintf(verbose, "Target directory: %s\n", absTarget)
+
+	// The lock file is the only reliable record of what was installed
+	lock, err := state.ReadLock(absTarget)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return missingLockError(absTarget)
+	}
+
+	// A version file pins the project to one of its templates, and perhaps a
+	// commit, which update then moves to instead of the registry's
+	pin, err := state.ReadVersionPin(absTarget)
+	if err != nil {
+		return err
+	}
+	if pin != nil && lock.Find(pin.TemplateID) == nil {
+		installed := make([]string, len(lock.Templates))
+		for i, entry := range lock.Templates {
+			installed[i] = entry.TemplateID
+		}
+		err := models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			fmt.Sprintf("%s pins template '%s', but %s is installed; run 'init --force' to install the pinned template", config.VersionFileName, pin.TemplateID, strings.Join(installed, ", ")),
+			nil,
+		)
+		return err
+	}
+
+	// Every recorded template must still be in the registry to update any of them
+	var outdated []state.TemplateLock
+	var outdatedTemplates []templates.Template
+	for _, entry := range lock.Templates {
+		// Installed with --archive, which only a newer archive can update
+		if entry.ArchiveDigest != "" {
+			return models.NewAppError(
+				models.ErrorCodeInvalidConfiguration,
+				fmt.Sprintf("template '%s' was installed from the archive %s, not from the registry; run 'init --archive <newer archive> --force-core' to update it",
+					entry.TemplateID, entry.RepoURL),
+				nil,
+			)
+		}
+
+		template, err := templates.GetTemplate(entry.TemplateID)
+		if err != nil {
+			err = fmt.Errorf("installed template is no longer available: %w", suggestTemplates(err, entry.TemplateID))
+			if entry.Ref != "" {
+				// Installed with --repo-url, so the registry never had it
+				err = fmt.Errorf("template '%s' was installed from %s at ref %s, not from the registry; run 'init --repo-url %s --ref %s' to update it",
+					entry.TemplateID, entry.RepoURL, entry.Ref, entry.RepoURL, entry.Ref)
+			}
+			return err
+		}
+
+		target := template
+		if commit := pinnedCommit(pin, template); commit != "" {
+			if target, err = template.WithCommit(commit); err != nil {
+				return models.NewAppError(models.ErrorCodeInvalidConfiguration, "invalid commit in "+state.VersionFilePath(absTarget), err)
+			}
+			utils.DisplayInfo(fmt.Sprintf("%s pins '%s' at %s; the registry is at %s. Change the pin to move to the registry's commit",
+				config.VersionFileName, template.ID, shortCommit(commit), describeTargetCommit(template)))
+		}
+
+		if isUpToDate(entry, target) && !updateForce {
+			if !strings.EqualFold(target.Commit, template.Commit) {
+				utils.DisplaySuccess(fmt.Sprintf("Template '%s' is at the commit pinned in %s (%s)", template.ID, config.VersionFileName, shortCommit(entry.Commit)))
+			} else {
+				utils.DisplaySuccess(fmt.Sprintf("Template '%s' is already up to date (%s)", template.ID, shortCommit(entry.Commit)))
+			}
+			continue
+		}
+		outdated = append(outdated, entry)
+		outdatedTemplates = append(outdatedTemplates, template)
+	}
+	if len(outdated) == 0 {
+		return nil
+	}
+
+	for i, template := range outdatedTemplates {
+		fmt.Printf("Template: %s\n", template.DisplayName())
+		if commit := pinnedCommit(pin, template); commit != "" {
+			fmt.Printf("Commit: %s → %s (pinned in %s)\n", shortCommit(outdated[i].Commit), shortCommit(commit), config.VersionFileName)
+		} else {
+			fmt.Printf("Commit: %s → %s\n", shortCommit(outdated[i].Commit), describeTargetCommit(template))
+		}
+	}
+
+	if !updateYes {
+		interactionService := utils.NewInteractionService()
+		message := "Framework files will be replaced; user content and local edits are preserved.\nProceed with update?"
+		if updateOverwrite {
+			message = "Framework files will be replaced, including your local edits; user content is preserved.\nProceed with update?"
+		}
+		confirmed, err := interactionService.ConfirmPrompt(message)
+		if err != nil {
+			return fmt.Errorf("confirmation failed: %w", err)
+		}
+		if !confirmed {
+			utils.DisplayInfo("Update cancelled by user")
+			return nil
+		}
+	}
+
+	// A single template gets a core update; layered templates are re-applied
+	// one by one in lock order, so each keeps its precedence over the others
+	layered := len(lock.Templates) > 1
+
+	var modified []models.ModifiedFile
+	var removed []models.RemovedFile
+	var overlaps []state.FileOverlap
+	var changes models.FileChanges
+	for i, template := range outdatedTemplates {
+		installConfig := models.InstallConfig{
+			TargetDir:     absTarget,
+			TemplateID:    template.ID,
+			FromCommit:    pinnedCommit(pin, template),
+			ForceCore:     !layered,
+			Layer:         layered,
+			SkipConfirm:   true,
+			NoBackup:      updateNoBackup,
+			Verbose:       verbose,
+			GitignoreMode: "track", // Leave existing gitignore files untouched
+			CloneDepth:    config.DefaultCloneDepth,
+			NoCache:       noCache,
+			Offline:       offline,
+			ArchiveDir:    archiveDir,
+			Retries:       gitRetries,
+			GitTimeout:    gitTimeout,
+			OnlyPaths:     outdated[i].Only, // A partial installation stays partial
+			KeepGit:       outdated[i].KeepGit && !layered,
+			Submodules:    outdated[i].Submodules,
+			// Everyone updating the project filters and renders alike
+			ExcludePatterns: projectConfig.ExcludePatterns,
+			Variables:       projectConfig.Variables,
+			RunHooks:        updateRunHooks,
+			HookOutput:      os.Stdout,
+
+			OverwriteModified: updateOverwrite,
+			Prune:             updatePrune,
+
+			RenderPatterns: config.GetDefaultRenderPatterns(),
+		}
+		installConfig.OnModifiedFile = func(file models.ModifiedFile) {
+			modified = append(modified, file)
+		}
+		installConfig.OnRemovedFile = func(file models.RemovedFile) {
+			removed = append(removed, file)
+		}
+		installConfig.OnOverlap = func(overlap state.FileOverlap) {
+			overlaps = append(overlaps, overlap)
+		}
+		installConfig.OnFileChanges = func(written models.FileChanges) {
+			changes.Unchanged += written.Unchanged
+			changes.Updated += written.Updated
+		}
+
+		if err := installer.New().Install(installConfig); err != nil {
+			return fmt.Errorf("update of '%s' failed: %w", template.ID, err)
+		}
+		if layered {
+			utils.DisplaySuccess(fmt.Sprintf("Updated template '%s'", template.ID))
+		}
+	}
+
+	utils.DisplaySuccess("Strategic Claude Basic update completed successfully!")
+	utils.DisplayInfo(fmt.Sprintf("%d unchanged, %d updated", changes.Unchanged, changes.Updated))
+	displayModifiedFiles(modified, updateDiff)
+	displayRemovedFiles(removed)
+	displayOverlaps(overlaps)
+	return nil
+}
+
+// displayModifiedFiles summarizes the locally edited framework files an update
+// found, optionally with a diff from each edited file to the template's copy
+func displayModifiedFiles(files []models.ModifiedFile, showDiff bool) {
+	if len(files) == 0 {
+		return
+	}
+
+	var conflicts, kept, overwritten []models.ModifiedFile
+	for _, file := range files {
+		switch {
+		case file.Overwritten:
+			overwritten = append(overwritten, file)
+		case file.Conflict:
+			conflicts = append(conflicts, file)
+		default:
+			kept = append(kept, file)
+		}
+	}
+
+	fmt.Println()
+	if len(conflicts) > 0 {
+		utils.DisplayWarning(fmt.Sprintf("%d conflict(s): edited locally and changed in the template; your version was kept:", len(conflicts)))
+		for _, file := range conflicts {
+			if file.UpstreamRemoved {
+				fmt.Printf("  • %s (removed from the template)\n", file.Path)
+			} else {
+				fmt.Printf("  • %s\n", file.Path)
+			}
+		}
+	}
+	if len(kept) > 0 {
+		utils.DisplayInfo(fmt.Sprintf("Kept local edits to %d file(s) the template did not change:", len(kept)))
+		for _, file := range kept {
+			fmt.Printf("  • %s\n", file.Path)
+		}
+	}
+	if len(overwritten) > 0 {
+		utils.DisplayWarning(fmt.Sprintf("Replaced %d locally edited file(s) with the template's copy:", len(overwritten)))
+		for _, file := range overwritten {
+			fmt.Printf("  • %s\n", file.Path)
+		}
+	}
+	if len(conflicts) > 0 {
+		utils.DisplayInfo("Run update --force --overwrite to take the template's versions")
+	}
+
+	if showDiff {
+		for _, file := range files {
+			fmt.Println()
+			fmt.Print(utils.UnifiedDiff("a/"+file.Path+" (local)", "b/"+file.Path+" (template)", file.Local, file.Upstream))
+		}
+	}
+}
+
+// displayRemovedFiles lists the recorded files the template no longer ships,
+// split into those pruned and those kept
+func displayRemovedFiles(files []models.RemovedFile) {
+	var pruned, kept []models.RemovedFile
+	for _, file := range files {
+		if file.Pruned {
+			pruned = append(pruned, file)
+		} else {
+			kept = append(kept, file)
+		}
+	}
+
+	if len(pruned) > 0 {
+		fmt.Println()
+		utils.DisplayInfo(fmt.Sprintf("Pruned %d file(s) removed from the template:", len(pruned)))
+		for _, file := range pruned {
+			fmt.Printf("  • %s\n", file.Path)
+		}
+	}
+	if len(kept) > 0 {
+		fmt.Println()
+		utils.DisplayWarning(fmt.Sprintf("%d file(s) removed upstream, kept locally:", len(kept)))
+		prunable := false
+		for _, file := range kept {
+			if file.Modified {
+				fmt.Printf("  • %s (edited locally)\n", file.Path)
+			} else {
+				fmt.Printf("  • %s\n", file.Path)
+				prunable = true
+			}
+		}
+		if prunable {
+			utils.DisplayInfo("Run update --prune to delete the unedited ones")
+		}
+	}
+}
+
+// isUpToDate reports whether the installed entry already has what target pins:
+// the same commit, or a commit resolved from the same tag, since a tag names
+// one commit. A template following its branch always needs fetching to tell.
+func isUpToDate(entry state.TemplateLock, target templates.Template) bool {
+	if target.Tag != "" {
+		return entry.Tag == target.Tag
+	}
+	return target.IsPinned() && strings.EqualFold(entry.Commit, target.Commit)
+}
+
+// describeTargetCommit formats the commit an update will install
+func describeTargetCommit(template templates.Template) string {
+	if template.FollowBranch {
+		return fmt.Sprintf("latest on %s", template.Branch)
+	}
+	if template.Tag != "" {
+		return fmt.Sprintf("tag %s", template.Tag)
+	}
+	return shortCommit(template.Commit)
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(commit string) string {
+	if commit == "" {
+		return "unknown"
+	}
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// missingLockError explains why a command that works from the lock file cannot
+// run in targetDir: either nothing is installed there, or the framework was
+// installed with init --no-lock and is not managed
+func missingLockError(targetDir string) error {
+	if info, err := os.Stat(filepath.Join(targetDir, config.StrategicClaudeBasicDir)); err == nil && info.IsDir() {
+		return models.NewAppError(
+			models.ErrorCodeNotInstalled,
+			fmt.Sprintf("%s is not managed: the framework was installed without a lock file (init --no-lock); run 'init --force' to reinstall it with one", targetDir),
+			nil,
+		)
+	}
+	return models.NewAppError(
+		models.ErrorCodeNotInstalled,
+		fmt.Sprintf("No lock file found in %s; run 'init' first", targetDir),
+		nil,
+	)
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/installer"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// useLocalTemplate registers a plain-directory template so commands can run without network access
+func useLocalTemplate(t *testing.T, id string) string {
+	t.Helper()
+
+	sourceDir := t.TempDir()
+	dirs := []string{
+		filepath.Join(config.CoreDir, config.AgentsDir),
+		filepath.Join(config.CoreDir, config.CommandsDir),
+		filepath.Join(config.CoreDir, config.HooksDir),
+		config.TemplatesDir,
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(sourceDir, config.StrategicClaudeBasicDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create template directory: %v", err)
+		}
+	}
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Core v1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template file: %v", err)
+	}
+
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+	templates.Registry.Set(map[string]templates.Template{
+		id: {ID: id, Name: "Local", RepoURL: sourceDir},
+	})
+
+	return sourceDir
+}
+
+// withUpdateFlags sets update command flags and restores them after the test
+func withUpdateFlags(t *testing.T, force bool) {
+	t.Helper()
+	origForce, origYes, origNoBackup := updateForce, updateYes, updateNoBackup
+	t.Cleanup(func() {
+		updateForce, updateYes, updateNoBackup = origForce, origYes, origNoBackup
+	})
+	updateForce = force
+	updateYes = true
+	updateNoBackup = true
+}
+
+func TestUpdateCommand_NoLock(t *testing.T) {
+	withUpdateFlags(t, false)
+
+	err := runUpdate([]string{t.TempDir()})
+	if !models.IsErrorCode(err, models.ErrorCodeNotInstalled) {
+		t.Errorf("Expected NOT_INSTALLED error, got %v", err)
+	}
+}
+
+func TestUpdateCommand_UpToDate(t *testing.T) {
+	withUpdateFlags(t, false)
+	tempDir := t.TempDir()
+
+	template, err := templates.GetTemplate(templates.DefaultTemplateID)
+	if err != nil {
+		t.Fatalf("Failed to get default template: %v", err)
+	}
+
+	lock := &state.Lock{Templates: []state.TemplateLock{{
+		TemplateID:  template.ID,
+		RepoURL:     template.RepoURL,
+		Branch:      template.Branch,
+		Commit:      template.Commit,
+		InstalledAt: time.Now(),
+	}}}
+	if err := state.WriteLock(tempDir, lock); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+
+	// Matching commits return early without touching the network
+	if err := runUpdate([]string{tempDir}); err != nil {
+		t.Errorf("Update with current commit failed: %v", err)
+	}
+}
+
+func TestUpdateCommand_TagUpToDate(t *testing.T) {
+	withUpdateFlags(t, false)
+	tempDir := t.TempDir()
+
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+	templates.Registry.Set(map[string]templates.Template{
+		"tagged": {ID: "tagged", Name: "Tagged", RepoURL: "https://example.invalid/repo.git", Branch: "main", Tag: "v1.2.0"},
+	})
+
+	lock := &state.Lock{Templates: []state.TemplateLock{{
+		TemplateID:  "tagged",
+		RepoURL:     "https://example.invalid/repo.git",
+		Branch:      "main",
+		Commit:      "1111111111111111111111111111111111111111",
+		Tag:         "v1.2.0",
+		InstalledAt: time.Now(),
+	}}}
+	if err := state.WriteLock(tempDir, lock); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+
+	// The commit resolved from the registry tag is current, so nothing is fetched
+	if err := runUpdate([]string{tempDir}); err != nil {
+		t.Errorf("Update at the registry tag failed: %v", err)
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	commit := "1111111111111111111111111111111111111111"
+	tests := []struct {
+		name   string
+		entry  state.TemplateLock
+		target templates.Template
+		want   bool
+	}{
+		{"same commit", state.TemplateLock{Commit: commit}, templates.Template{Commit: commit}, true},
+		{"same commit in another case", state.TemplateLock{Commit: commit}, templates.Template{Commit: strings.ToUpper(commit)}, true},
+		{"other commit", state.TemplateLock{Commit: commit}, templates.Template{Commit: "2222222222222222222222222222222222222222"}, false},
+		{"same tag", state.TemplateLock{Commit: commit, Tag: "v1.2.0"}, templates.Template{Tag: "v1.2.0"}, true},
+		{"older tag", state.TemplateLock{Commit: commit, Tag: "v1.1.0"}, templates.Template{Tag: "v1.2.0"}, false},
+		{"commit before the tag pin", state.TemplateLock{Commit: commit}, templates.Template{Tag: "v1.2.0"}, false},
+		{"following branch", state.TemplateLock{Commit: commit}, templates.Template{Commit: templates.HeadCommit, FollowBranch: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUpToDate(tt.entry, tt.target); got != tt.want {
+				t.Errorf("isUpToDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateCommand_ArchiveInstall(t *testing.T) {
+	withUpdateFlags(t, true)
+	tempDir := t.TempDir()
+
+	lock := &state.Lock{Templates: []state.TemplateLock{{
+		TemplateID:    templates.DefaultTemplateID,
+		RepoURL:       "/media/offline/main.tar.gz",
+		ArchiveDigest: templates.TreeHashPrefix + strings.Repeat("0", 64),
+		InstalledAt:   time.Now(),
+	}}}
+	if err := state.WriteLock(tempDir, lock); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+
+	// The registry's template of the same name is not what was installed
+	err := runUpdate([]string{tempDir})
+	if !models.IsErrorCode(err, models.ErrorCodeInvalidConfiguration) || !strings.Contains(err.Error(), "init --archive") {
+		t.Errorf("Expected an error pointing at init --archive, got %v", err)
+	}
+}
+
+func TestUpdateCommand_Unmanaged(t *testing.T) {
+	withUpdateFlags(t, true)
+	tempDir := t.TempDir()
+
+	// init --no-lock leaves the framework without a lock file
+	if err := os.MkdirAll(filepath.Join(tempDir, config.StrategicClaudeBasicDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runUpdate([]string{tempDir})
+	if !models.IsErrorCode(err, models.ErrorCodeNotInstalled) || !strings.Contains(err.Error(), "not managed") {
+		t.Errorf("Expected a not managed error, got %v", err)
+	}
+}
+
+func TestUpdateCommand_ForceReinstall(t *testing.T) {
+	withUpdateFlags(t, true)
+	sourceDir := useLocalTemplate(t, "local")
+	tempDir := t.TempDir()
+
+	installConfig := models.InstallConfig{
+		TargetDir:     tempDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+	if err := installer.New().Install(installConfig); err != nil {
+		t.Fatalf("Initial install failed: %v", err)
+	}
+
+	// Simulate a newer template and a manual edit to a framework file
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Core v2\n"), 0644); err != nil {
+		t.Fatalf("Failed to update template file: %v", err)
+	}
+
+	if err := runUpdate([]string{tempDir}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	installed, err := os.ReadFile(filepath.Join(tempDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read installed file: %v", err)
+	}
+	if string(installed) != "# Core v2\n" {
+		t.Errorf("Expected updated framework file, got %q", string(installed))
+	}
+}
+
+func TestUpdateCommand_VersionPin(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git not available, skipping version pin test")
+	}
+	withUpdateFlags(t, false)
+	sourceDir := useLocalTemplate(t, "local")
+	tempDir := t.TempDir()
+
+	run := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = sourceDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	// Git does not keep the template's empty directories
+	for _, dir := range []string{config.AgentsDir, config.CommandsDir, config.HooksDir, "../" + config.TemplatesDir} {
+		if err := os.WriteFile(filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, dir, ".gitkeep"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readme := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	run("init", "-b", "main")
+	run("add", "-A")
+	run("commit", "-m", "v1")
+	pinned := run("rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(sourceDir, readme), []byte("# Core v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("commit", "-am", "v2")
+	tip := run("rev-parse", "HEAD")
+
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, Branch: "main", Commit: tip},
+	})
+	installConfig := models.InstallConfig{
+		TargetDir:     tempDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		NoCache:       true,
+		GitignoreMode: "track",
+	}
+	if err := installer.New().Install(installConfig); err != nil {
+		t.Fatalf("Initial install failed: %v", err)
+	}
+
+	// The pin moves the installation back from the registry's commit
+	if err := os.WriteFile(state.VersionFilePath(tempDir), []byte("local "+pinned+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runUpdate([]string{tempDir}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	lock, err := state.ReadLock(tempDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if entry := lock.Templates[0]; entry.Commit != pinned || !entry.CommitOverride {
+		t.Errorf("Lock entry = %s (override %v), want the pinned commit %s", entry.Commit, entry.CommitOverride, pinned)
+	}
+	if installed, err := os.ReadFile(filepath.Join(tempDir, readme)); err != nil || string(installed) != "# Core v1\n" {
+		t.Errorf("Installed README = %q, %v; want the pinned commit's", installed, err)
+	}
+
+	// At the pin, there is nothing left to do
+	if err := runUpdate([]string{tempDir}); err != nil {
+		t.Errorf("Update at the pinned commit failed: %v", err)
+	}
+
+	// A pin naming a template the project does not have is not reconciled
+	if err := os.WriteFile(state.VersionFilePath(tempDir), []byte("other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runUpdate([]string{tempDir}); !models.IsErrorCode(err, models.ErrorCodeInvalidConfiguration) {
+		t.Errorf("Expected %s for a pin of another template, got %v", models.ErrorCodeInvalidConfiguration, err)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/verify"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyOutput string
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [directory]",
+	Short: "Check installed files against the lock file's manifest",
+	Long: `Check that the files installed into a project still match the hashes recorded
+in its lock file, and report drift:
+
+- Missing: recorded files or symlinks that were deleted
+- Modified: recorded files whose content, or symlinks whose target, changed
+- Untracked: files in the framework directories (core, templates) that no
+  install recorded
+
+Files in the user content directories (plan, research, and so on) are meant to
+be edited and are not checked. Nothing is fetched, so verify works offline.
+
+The command exits with an error (exit code 5) when anything drifted, so CI can
+gate on an unmodified baseline. Use --output json for dashboards; the report is
+printed either way.
+
+Examples:
+  strategic-claude-basic-cli verify                 # Verify the current directory
+  strategic-claude-basic-cli verify ./my-project   # Verify a specific directory
+  strategic-claude-basic-cli verify -o json        # Print the report as JSON`,
+	Args: cobra.MaximumNArgs(1),
+	// Drift is reported in the output, not as a usage mistake
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verifyOutput != "text" && verifyOutput != "json" {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("invalid output format '%s'. Must be one of: text, json", verifyOutput), nil)
+		}
+
+		target := targetDir
+		if len(args) > 0 {
+			target = args[0]
+		}
+		absTarget, err := filepath.Abs(target)
+		if err != nil {
+			return fmt.Errorf("failed to resolve target directory: %w", err)
+		}
+
+		lock, err := state.ReadLock(absTarget)
+		if err != nil {
+			return err
+		}
+		if lock == nil {
+			return missingLockError(absTarget)
+		}
+		if len(lock.AllFiles()) == 0 {
+			return models.NewAppError(
+				models.ErrorCodeNotInstalled,
+				"The lock file has no record of installed files (installed by an older version); run 'update --force' to record them",
+				nil,
+			)
+		}
+
+		report, err := verify.New().Verify(absTarget, lock)
+		if err != nil {
+			return err
+		}
+
+		if verifyOutput == "json" {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode report: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		} else {
+			displayVerifyReport(cmd.OutOrStdout(), report)
+		}
+
+		if report.Drifted() {
+			return models.NewAppError(
+				models.ErrorCodeValidationFailed,
+				fmt.Sprintf("installation drifted: %d missing, %d modified, %d untracked", len(report.Missing), len(report.Modified), len(report.Untracked)),
+				nil,
+			)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVarP(&verifyOutput, "output", "o", "text", "output format: text or json")
+
+	if err := verifyCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		// This should not happen in normal operation, but we handle it for completeness
+		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --output flag: %v\n", err)
+	}
+}
+
+// displayVerifyReport lists each drifted path under its kind of drift
+func displayVerifyReport(w io.Writer, report *verify.Report) {
+	if !report.Drifted() {
+		fmt.Fprintf(w, "✅ All %d installed files match the manifest\n", report.Checked)
+		return
+	}
+
+	for _, group := range []struct {
+		label string
+		paths []string
+	}{
+		{"Missing", report.Missing},
+		{"Modified", report.Modified},
+		{"Untracked", report.Untracked},
+	} {
+		if len(group.paths) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%d):\n", group.label, len(group.paths))
+		for _, path := range group.paths {
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+	}
+}
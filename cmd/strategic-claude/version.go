@@ -33,10 +33,9 @@ var versionCmd = &cobra.Command{
 		fmt.Printf("\nTemplate Registry:\n")
 		templateList := templates.ListTemplates()
 		for _, template := range templateList {
-			fmt.Printf("  %-4s: %s (%s @ %s)\n",
-				template.ID,
-				template.Name,
-				template.Commit[:7],
+			fmt.Printf("  %s: %s @ %s\n",
+				template.DisplayName(),
+				template.ShortCommit(),
 				template.Branch)
 		}
 	},
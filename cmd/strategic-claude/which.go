@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+
+	"github.com/spf13/cobra"
+)
+
+var whichOutput string
+
+var whichCmd = &cobra.Command{
+	Use:   "which [directory]",
+	Short: "Show which template a project was installed from",
+	Long: `Show what a project was initialized from, as recorded in its lock file: the
+template ID, repository, branch, and installed commit of each installed template.
+
+This is the local counterpart of 'info', which describes a template as the
+registry defines it. Nothing is fetched, so which works offline. Without a lock
+file it prints "no template installed". Use --output json to print the lock
+file as it is on disk (null when there is none).
+
+Examples:
+  strategic-claude-basic-cli which                 # Check the current directory
+  strategic-claude-basic-cli which ./my-project   # Check a specific directory
+  strategic-claude-basic-cli which -o json        # Print the raw lock file`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whichOutput != "text" && whichOutput != "json" {
+			return models.NewAppError(models.ErrorCodeInvalidConfiguration, fmt.Sprintf("invalid output format '%s'. Must be one of: text, json", whichOutput), nil)
+		}
+
+		target := targetDir
+		if len(args) > 0 {
+			target = args[0]
+		}
+		absTarget, err := filepath.Abs(target)
+		if err != nil {
+			return fmt.Errorf("failed to resolve target directory: %w", err)
+		}
+
+		lock, err := state.ReadLock(absTarget)
+		if err != nil {
+			return err
+		}
+
+		if whichOutput == "json" {
+			return writeRawLock(cmd.OutOrStdout(), absTarget, lock)
+		}
+		displayWhich(cmd.OutOrStdout(), absTarget, lock)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+
+	whichCmd.Flags().StringVarP(&whichOutput, "output", "o", "text", "output format: text or json")
+
+	if err := whichCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		// This should not happen in normal operation, but we handle it for completeness
+		fmt.Fprintf(os.Stderr, "Warning: failed to register completion for --output flag: %v\n", err)
+	}
+}
+
+// writeRawLock prints the lock file in targetDir byte for byte, or null when
+// lock is nil because there is none
+func writeRawLock(w io.Writer, targetDir string, lock *state.Lock) error {
+	if lock == nil {
+		fmt.Fprintln(w, "null")
+		return nil
+	}
+
+	data, err := os.ReadFile(state.LockPath(targetDir))
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, state.LockPath(targetDir), err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// displayWhich prints the source of each template the lock file records, in
+// the order they were layered
+func displayWhich(w io.Writer, targetDir string, lock *state.Lock) {
+	if lock == nil {
+		fmt.Fprintf(w, "No template installed in %s\n", targetDir)
+		return
+	}
+
+	for i, entry := range lock.Templates {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		name := entry.TemplateID
+		if template, err := templates.GetTemplate(entry.TemplateID); err == nil {
+			name = template.DisplayName()
+		}
+		fmt.Fprintln(w, name)
+		fmt.Fprintf(w, "  Repository: %s\n", entry.RepoURL)
+		if entry.Ref != "" {
+			fmt.Fprintf(w, "  Ref: %s\n", entry.Ref)
+		} else if entry.Branch != "" {
+			fmt.Fprintf(w, "  Branch: %s\n", entry.Branch)
+		}
+		switch {
+		case entry.Commit == "":
+			fmt.Fprintln(w, "  Commit: none (plain local directory)")
+		case entry.CommitOverride:
+			fmt.Fprintf(w, "  Commit: %s (chosen instead of the registry pin)\n", entry.Commit)
+		case entry.Tag != "":
+			fmt.Fprintf(w, "  Commit: %s (tag %s)\n", entry.Commit, entry.Tag)
+		default:
+			fmt.Fprintf(w, "  Commit: %s\n", entry.Commit)
+		}
+		if entry.Submodules {
+			fmt.Fprintln(w, "  Submodules: included")
+		}
+		if len(entry.Only) > 0 {
+			fmt.Fprintf(w, "  Only: %s\n", strings.Join(entry.Only, ", "))
+		}
+		if !entry.InstalledAt.IsZero() {
+			fmt.Fprintf(w, "  Installed: %s\n", entry.InstalledAt.Local().Format("2006-01-02 15:04"))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+func TestDisplayWhich(t *testing.T) {
+	targetDir := t.TempDir()
+
+	var out bytes.Buffer
+	displayWhich(&out, targetDir, nil)
+	if !strings.Contains(out.String(), "No template installed in "+targetDir) {
+		t.Errorf("Expected no template installed, got %q", out.String())
+	}
+
+	lock := &state.Lock{Templates: []state.TemplateLock{
+		{TemplateID: "main", RepoURL: "https://example.com/base.git", Branch: "main", Commit: strings.Repeat("a", 40), InstalledAt: time.Now()},
+		{TemplateID: "fork", RepoURL: "https://example.com/fork.git", Branch: "feature/agents", Ref: "feature/agents", Commit: strings.Repeat("b", 40), CommitOverride: true},
+	}}
+	out.Reset()
+	displayWhich(&out, targetDir, lock)
+	for _, want := range []string{
+		"Strategic Claude Basic (main)",
+		"Repository: https://example.com/base.git",
+		"Branch: main",
+		"Commit: " + strings.Repeat("a", 40) + "\n",
+		"Installed: ",
+		"fork\n",
+		"Ref: feature/agents",
+		"Commit: " + strings.Repeat("b", 40) + " (chosen instead of the registry pin)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWhichCommand_JSON(t *testing.T) {
+	targetDir := t.TempDir()
+	originalOutput := whichOutput
+	t.Cleanup(func() { whichOutput = originalOutput })
+	whichOutput = "json"
+
+	var out bytes.Buffer
+	whichCmd.SetOut(&out)
+	defer whichCmd.SetOut(nil)
+
+	if err := whichCmd.RunE(whichCmd, []string{targetDir}); err != nil {
+		t.Fatalf("which -o json error = %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "null" {
+		t.Errorf("Expected null without a lock file, got %q", out.String())
+	}
+
+	if err := state.WriteLock(targetDir, &state.Lock{Templates: []state.TemplateLock{{TemplateID: "main", Commit: strings.Repeat("a", 40)}}}); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+	raw, err := os.ReadFile(state.LockPath(targetDir))
+	if err != nil {
+		t.Fatalf("Failed to read lock file: %v", err)
+	}
+
+	out.Reset()
+	if err := whichCmd.RunE(whichCmd, []string{targetDir}); err != nil {
+		t.Fatalf("which -o json error = %v", err)
+	}
+	if out.String() != string(raw) {
+		t.Errorf("Expected the raw lock file, got %q, want %q", out.String(), raw)
+	}
+}
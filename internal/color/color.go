@@ -0,0 +1,42 @@
+// Package color decides once, for every command, whether output is colorized.
+// Color is on only when stdout is a terminal, and --no-color or a non-empty
+// NO_COLOR environment variable (https://no-color.org) turns it off, so logs
+// and CI output never carry escape codes. Code that colorizes output checks
+// Enabled rather than deciding for itself.
+package color
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var (
+	mu      sync.Mutex
+	enabled bool
+)
+
+// Decide reports whether output should be colorized, given the --no-color
+// flag, the value of NO_COLOR, and whether stdout is a terminal
+func Decide(noColor bool, noColorEnv string, terminal bool) bool {
+	return !noColor && noColorEnv == "" && terminal
+}
+
+// Setup turns colorized output on or off for the rest of the run, including
+// the styles of the interactive selectors. Color is off until Setup is called.
+func Setup(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = on
+	if !on {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// Enabled reports whether output is colorized
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
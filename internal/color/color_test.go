@@ -0,0 +1,40 @@
+package color
+
+import "testing"
+
+func TestDecide(t *testing.T) {
+	tests := []struct {
+		name       string
+		noColor    bool
+		noColorEnv string
+		terminal   bool
+		want       bool
+	}{
+		{name: "terminal", terminal: true, want: true},
+		{name: "not a terminal", terminal: false, want: false},
+		{name: "--no-color", noColor: true, terminal: true, want: false},
+		{name: "NO_COLOR set", noColorEnv: "1", terminal: true, want: false},
+		{name: "NO_COLOR set to anything", noColorEnv: "false", terminal: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Decide(tt.noColor, tt.noColorEnv, tt.terminal); got != tt.want {
+				t.Errorf("Decide(%v, %q, %v) = %v, want %v", tt.noColor, tt.noColorEnv, tt.terminal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	t.Cleanup(func() { Setup(false) })
+
+	Setup(true)
+	if !Enabled() {
+		t.Error("Expected color to be enabled after Setup(true)")
+	}
+	Setup(false)
+	if Enabled() {
+		t.Error("Expected color to be disabled after Setup(false)")
+	}
+}
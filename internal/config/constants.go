@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -19,6 +21,7 @@ const (
 	ClaudeDir               = ".claude"
 	CodexDir                = ".codex"
 	BackupDirPrefix         = "strategic-claude-basic-backup-"
+	StagingDirPrefix        = ".strategic-claude-basic-staging-"
 
 	// Framework directory structure within .strategic-claude-basic/
 	CoreDir      = "core"
@@ -65,6 +68,21 @@ const (
 	DefaultGitTimeout     = 30 * time.Second
 	DefaultNetworkTimeout = 30 * time.Second
 
+	// Default limit on the git work of fetching one template, retries included
+	DefaultCloneTimeout = 5 * time.Minute
+
+	// Most attempts at a template clone or fetch that fails on the network,
+	// and the backoff between them, which doubles up to the maximum
+	DefaultGitRetries = 3
+	GitRetryBaseDelay = 1 * time.Second
+	GitRetryMaxDelay  = 30 * time.Second
+
+	// Default history depth for template clones (0 clones full history)
+	DefaultCloneDepth = 1
+
+	// Number of recent branch commits offered to choose from with --select-commit
+	RecentCommitLimit = 20
+
 	// Validation constants
 	MaxPathLength       = 260 // Windows compatibility
 	MaxDirectoryNameLen = 255
@@ -78,6 +96,32 @@ const (
 	// Template metadata file
 	TemplateInfoFile = ".template-info"
 
+	// Lock file recording the installed template (stored in .strategic-claude-basic/)
+	LockFileName = "lock.json"
+
+	// Template git directory kept by --keep-git (stored in .strategic-claude-basic/)
+	KeptGitDir = ".git"
+
+	// User configuration (stored under $XDG_CONFIG_HOME or ~/.config)
+	UserConfigDirName  = "strategic-claude"
+	RegistryFileName   = "templates.yaml"
+	UserConfigFileName = "config.yaml"
+
+	// Per-project settings, committed with the project (stored in .strategic-claude/)
+	ProjectConfigDirName  = ".strategic-claude"
+	ProjectConfigFileName = "config.yaml"
+
+	// Template and commit a project pins, committed at the project root
+	VersionFileName = ".strategic-claude-version"
+
+	// Cached template clones (stored under $XDG_CACHE_HOME or ~/.cache)
+	UserCacheDirName = "strategic-claude"
+
+	// Copies of remote registries, kept in the cache directory and fetched
+	// again once older than the TTL
+	RegistryCacheDirName = "registries"
+	RegistryCacheTTL     = time.Hour
+
 	// Installation scripts
 	PreInstallScript  = "pre-install.sh"
 	PostInstallScript = "post-install.sh"
@@ -132,6 +176,19 @@ func GetUserPreservedDirectories() []string {
 	}
 }
 
+// GetDefaultRenderPatterns returns the file globs rendered for template variables by default
+func GetDefaultRenderPatterns() []string {
+	return []string{"*.md", "*.tmpl"}
+}
+
+// GetDefaultExcludePatterns returns the gitignore-style patterns for template
+// repository files that are never installed. They are anchored to the repository
+// root so framework files with the same names are still installed, except .git,
+// which no git tree contains but every checked-out submodule does.
+func GetDefaultExcludePatterns() []string {
+	return []string{".git", "/.github/", "/README.md"}
+}
+
 // GetRequiredSymlinks returns the symlinks that should be created for .claude
 func GetRequiredSymlinks() map[string]string {
 	return map[string]string{
@@ -173,3 +230,38 @@ func IsCoreFile(path string) bool {
 	}
 	return false
 }
+
+// GetUserConfigDir returns the directory holding user-level CLI configuration
+func GetUserConfigDir() (string, error) {
+	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
+		return filepath.Join(xdgConfig, UserConfigDirName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", UserConfigDirName), nil
+}
+
+// GetUserCacheDir returns the directory holding cached template clones
+func GetUserCacheDir() (string, error) {
+	if xdgCache := os.Getenv("XDG_CACHE_HOME"); xdgCache != "" {
+		return filepath.Join(xdgCache, UserCacheDirName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", UserCacheDirName), nil
+}
+
+// GetDefaultRegistryPath returns the path of the user-defined template registry file
+func GetDefaultRegistryPath() (string, error) {
+	configDir, err := GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, RegistryFileName), nil
+}
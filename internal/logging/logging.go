@@ -0,0 +1,147 @@
+// Package logging configures the CLI's leveled logger. Services log through
+// log/slog; messages are written to stderr as one line each, prefixed by level,
+// so the regular output on stdout stays clean and parsable.
+//
+// The default level shows informational messages and warnings. --verbose
+// lowers it to debug, which adds each git command, each copied file, and
+// timing summaries; --quiet raises it so only errors are shown.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Level returns the log level for the --verbose and --quiet flags
+func Level(verbose, quiet bool) slog.Level {
+	switch {
+	case verbose:
+		return slog.LevelDebug
+	case quiet:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Setup makes a logger writing to w at level the default for log/slog
+func Setup(w io.Writer, level slog.Level) {
+	slog.SetDefault(slog.New(NewHandler(w, level)))
+}
+
+// Enabled reports whether the default logger shows messages at level, which
+// also decides whether non-error status messages are displayed
+func Enabled(level slog.Level) bool {
+	return slog.Default().Enabled(context.Background(), level)
+}
+
+// Handler writes records as a level marker, the message, and key=value
+// attributes on one line
+type Handler struct {
+	w     io.Writer
+	level slog.Leveler
+	mu    *sync.Mutex
+	attrs []slog.Attr
+	group string
+}
+
+// NewHandler returns a handler writing records at level or above to w
+func NewHandler(w io.Writer, level slog.Leveler) *Handler {
+	return &Handler{w: w, level: level, mu: &sync.Mutex{}}
+}
+
+// Enabled reports whether records at level are written
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle writes one record
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	b.WriteString(marker(record.Level))
+	b.WriteString(record.Message)
+
+	for _, attr := range h.attrs {
+		writeAttr(&b, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&b, h.group, attr)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// WithAttrs returns a handler that adds attrs to every record
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, attr := range attrs {
+		if h.group != "" {
+			attr.Key = h.group + "." + attr.Key
+		}
+		clone.attrs = append(clone.attrs, attr)
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that qualifies later attribute keys with name
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	if h.group != "" {
+		name = h.group + "." + name
+	}
+	clone.group = name
+	return &clone
+}
+
+// marker is the prefix for a level, matching the CLI's status messages
+func marker(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "❌ "
+	case level >= slog.LevelWarn:
+		return "⚠️  "
+	case level >= slog.LevelInfo:
+		return "ℹ️  "
+	default:
+		return "🔍 "
+	}
+}
+
+// writeAttr appends " key=value", quoting values that contain spaces
+func writeAttr(b *strings.Builder, group string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	key := attr.Key
+	if group != "" {
+		key = group + "." + key
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, nested := range attr.Value.Group() {
+			writeAttr(b, key, nested)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\"=") {
+		value = fmt.Sprintf("%q", value)
+	}
+	fmt.Fprintf(b, " %s=%s", key, value)
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		verbose bool
+		quiet   bool
+		want    slog.Level
+	}{
+		{name: "default", want: slog.LevelInfo},
+		{name: "verbose", verbose: true, want: slog.LevelDebug},
+		{name: "quiet", quiet: true, want: slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Level(tt.verbose, tt.quiet); got != tt.want {
+				t.Errorf("Level(%v, %v) = %v, want %v", tt.verbose, tt.quiet, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name  string
+		level slog.Level
+		log   func(logger *slog.Logger)
+		want  string
+	}{
+		{
+			name:  "message with attributes",
+			level: slog.LevelDebug,
+			log: func(logger *slog.Logger) {
+				logger.Debug("Running git fetch", "dir", "/tmp/repo", "took", "2ms")
+			},
+			want: "🔍 Running git fetch dir=/tmp/repo took=2ms\n",
+		},
+		{
+			name:  "values with spaces are quoted",
+			level: slog.LevelInfo,
+			log: func(logger *slog.Logger) {
+				logger.Warn("Failed to remove file", "error", "permission denied", "path", "")
+			},
+			want: "⚠️  Failed to remove file error=\"permission denied\" path=\"\"\n",
+		},
+		{
+			name:  "attributes and groups from the logger",
+			level: slog.LevelInfo,
+			log: func(logger *slog.Logger) {
+				logger.With("template", "ccr").WithGroup("git").Info("Fetched", "commit", "abc123")
+			},
+			want: "ℹ️  Fetched template=ccr git.commit=abc123\n",
+		},
+		{
+			name:  "records below the level are dropped",
+			level: slog.LevelInfo,
+			log: func(logger *slog.Logger) {
+				logger.Debug("Copied file")
+			},
+			want: "",
+		},
+		{
+			name:  "quiet keeps errors only",
+			level: slog.LevelError,
+			log: func(logger *slog.Logger) {
+				logger.Info("Rendered template variables")
+				logger.Warn("No value for template variables")
+				logger.Error("Installation failed")
+			},
+			want: "❌ Installation failed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			tt.log(slog.New(NewHandler(&output, tt.level)))
+
+			if got := output.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	defer Setup(os.Stderr, slog.LevelInfo)
+
+	Setup(&bytes.Buffer{}, Level(false, true))
+	if Enabled(slog.LevelInfo) {
+		t.Error("Enabled(info) = true with --quiet, want false")
+	}
+	if !Enabled(slog.LevelError) {
+		t.Error("Enabled(error) = false with --quiet, want true")
+	}
+
+	Setup(&bytes.Buffer{}, Level(true, false))
+	if !Enabled(slog.LevelDebug) {
+		t.Error("Enabled(debug) = false with --verbose, want true")
+	}
+}
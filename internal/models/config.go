@@ -1,8 +1,13 @@
 package models
 
 import (
+	"io"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
 )
 
@@ -13,20 +18,95 @@ type InstallConfig struct {
 
 	// Template selection
 	TemplateID string // ID of the template to install
+	FromCommit string // Commit to install instead of the template's registry pin (--from-commit flag)
+	Ref        string // Branch or tag to install instead of the template's registry pin (--ref flag)
+	RepoURL    string // Repository to install at Ref instead of a registry template (--repo-url flag)
+	Archive    string // Tar, gzip-compressed tar, or zip of a template tree to install without git instead of a registry template (--archive flag)
+
+	// Commit picked from the recent history of Ref, or of the branch a template
+	// follows, to install instead of its head (--select-commit flag)
+	SelectedCommit string
 
 	// Installation behavior flags
 	Force         bool   // Force installation, overwriting existing files
 	ForceCore     bool   // Update only core framework files, preserving user content
+	Layer         bool   // Install over the templates already in place, file by file (--add flag)
 	SkipConfirm   bool   // Skip confirmation prompts (--yes flag)
 	NoBackup      bool   // Skip creating backups of existing files
 	DryRun        bool   // Show what would be done without making changes
 	Verbose       bool   // Enable verbose output
 	GitignoreMode string // Gitignore behavior: "track", "all", or "non-user"
+	SkipVerify    bool   // Skip verifying the cloned commit and tree hash against the template's pins
+	CloneDepth    int    // Shallow clone depth (0 clones full history)
+	NoCache       bool   // Clone afresh instead of using the template clone cache
+	Offline       bool   // Install only from the template clone cache, never contacting a repository
+	ArchiveDir    string // Directory of tar archives of pinned commits, installed from when present and written otherwise
+	CreateTarget  bool   // Create the target directory if it does not exist
+	RequireEmpty  bool   // Refuse a new installation into a target that already holds files
+	Retries       int    // Most attempts at a clone or fetch that fails on the network (0 for the default)
+	Dereference   bool   // Copy what template symlinks point to instead of recreating the links
+	KeepGit       bool   // Keep the template's git directory, with full history, in the framework directory
+	PartialClone  bool   // Fetch only the files OnlyPaths or the include patterns select, bypassing the cache
+	Submodules    bool   // Check out the template's git submodules, recursively, and install their files
+	NoLock        bool   // Install the files without writing a lock file, leaving them unmanaged (--no-lock flag)
+	Jobs          int    // Template files copied at once while staging (0 for one per CPU, --jobs flag)
+
+	// Gitignore-style patterns for template files to leave out, added to the
+	// defaults and the template's own patterns (--exclude flag)
+	ExcludePatterns []string
+
+	// Gitignore-style patterns selecting the only template files to install,
+	// added to the template's own patterns; excludes still apply (--include flag)
+	IncludePatterns []string
+
+	// Template repository subtrees to install instead of the whole framework (--only flag)
+	OnlyPaths []string
+
+	// Files edited since the last install are kept during core updates; this
+	// applies the template's copy instead (--overwrite flag)
+	OverwriteModified bool
+
+	// Called for each recorded file found edited during a core update; nil to
+	// ignore them
+	OnModifiedFile func(ModifiedFile)
+
+	// Recorded files the template no longer ships are deleted during core
+	// updates unless edited, instead of being kept (--prune flag)
+	Prune bool
+
+	// Called for each recorded file a core update found the template no longer
+	// ships; nil to ignore them
+	OnRemovedFile func(RemovedFile)
+
+	// Called for each file a layered install took over from another installed
+	// template; nil to ignore them
+	OnOverlap func(state.FileOverlap)
+
+	// Called once the files are in place with how many were left alone because
+	// the installed copy already matched; nil to ignore it
+	OnFileChanges func(FileChanges)
+
+	// Run the template's post-install hooks once it is installed (--run-hooks
+	// flag); without it they are skipped with a warning
+	RunHooks bool
+
+	// Receives what the post-install hooks print on stdout and stderr; nil to
+	// discard it
+	HookOutput io.Writer
+
+	// Template variable substitution
+	Variables       map[string]string                                              // Values supplied with --set, overriding built-in defaults
+	RenderPatterns  []string                                                       // File globs rendered for variables
+	StrictVariables bool                                                           // Fail when a referenced variable has no value
+	PromptVariable  func(name string, variable templates.Variable) (string, error) // Asks for a value, offering variable.Default; nil when prompting is disabled
+	PromptVariables bool                                                           // Ask for every variable --set does not supply, not only those without a value
 
 	// Optional custom backup directory
 	BackupDir string
 
-	// Timeout for git operations
+	// Limit on the git work of fetching each template, retries included (0 for
+	// no limit); a clone still running is stopped and its temporary directory
+	// removed (--timeout flag)
 	GitTimeout time.Duration
 }
 
@@ -47,17 +127,20 @@ type CleanConfig struct {
 // NewInstallConfig creates a new InstallConfig with default values
 func NewInstallConfig(targetDir string) *InstallConfig {
 	return &InstallConfig{
-		TargetDir:     targetDir,
-		TemplateID:    templates.DefaultTemplateID,
-		Force:         false,
-		ForceCore:     false,
-		SkipConfirm:   false,
-		NoBackup:      false,
-		DryRun:        false,
-		Verbose:       false,
-		GitignoreMode: "track",
-		BackupDir:     "",
-		GitTimeout:    30 * time.Second,
+		TargetDir:      targetDir,
+		TemplateID:     templates.DefaultTemplateID,
+		Force:          false,
+		ForceCore:      false,
+		SkipConfirm:    false,
+		NoBackup:       false,
+		DryRun:         false,
+		Verbose:        false,
+		GitignoreMode:  "track",
+		BackupDir:      "",
+		GitTimeout:     config.DefaultCloneTimeout,
+		CloneDepth:     config.DefaultCloneDepth,
+		Retries:        config.DefaultGitRetries,
+		RenderPatterns: config.GetDefaultRenderPatterns(),
 	}
 }
 
@@ -98,8 +181,108 @@ func (c *InstallConfig) Validate() error {
 		return NewAppError(ErrorCodeInvalidConfiguration, "template ID cannot be empty", nil)
 	}
 
-	if err := templates.ValidateTemplateID(c.TemplateID); err != nil {
-		return NewAppError(ErrorCodeInvalidConfiguration, "invalid template ID: "+c.TemplateID, err)
+	// A repository or archive given directly is not in the registry
+	if c.RepoURL == "" && c.Archive == "" {
+		if err := templates.ValidateTemplateID(c.TemplateID); err != nil {
+			return NewAppError(ErrorCodeInvalidConfiguration, "invalid template ID: "+c.TemplateID, err)
+		}
+	}
+
+	if c.FromCommit != "" && c.Ref != "" {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --from-commit and --ref", nil)
+	}
+
+	if c.SelectedCommit != "" && c.FromCommit != "" {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --from-commit and --select-commit", nil)
+	}
+
+	if c.RepoURL != "" && c.Ref == "" {
+		return NewAppError(ErrorCodeInvalidConfiguration, "--repo-url requires --ref naming the branch or tag to install", nil)
+	}
+
+	// An archive holds one tree of files, with no history to choose from
+	if c.Archive != "" {
+		conflicts := []struct {
+			flag string
+			set  bool
+		}{
+			{"--repo-url", c.RepoURL != ""},
+			{"--from-commit", c.FromCommit != ""},
+			{"--ref", c.Ref != ""},
+			{"--select-commit", c.SelectedCommit != ""},
+			{"--keep-git", c.KeepGit},
+			{"--partial-clone", c.PartialClone},
+			{"--include-submodules", c.Submodules},
+		}
+		for _, conflict := range conflicts {
+			if conflict.set {
+				return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --archive and "+conflict.flag+"; an archive installs its files as they are, without git", nil)
+			}
+		}
+		if _, err := c.GetTemplate(); err != nil {
+			return NewAppError(ErrorCodeInvalidConfiguration, "invalid --archive", err)
+		}
+	}
+
+	if c.FromCommit != "" {
+		if _, err := c.GetTemplate(); err != nil {
+			return NewAppError(ErrorCodeInvalidConfiguration, "invalid --from-commit for template "+c.TemplateID, err)
+		}
+	}
+
+	if c.Ref != "" {
+		if _, err := c.GetTemplate(); err != nil {
+			return NewAppError(ErrorCodeInvalidConfiguration, "invalid --ref for template "+c.TemplateID, err)
+		}
+	}
+
+	if c.SelectedCommit != "" {
+		if _, err := c.GetTemplate(); err != nil {
+			return NewAppError(ErrorCodeInvalidConfiguration, "invalid selected commit for template "+c.TemplateID, err)
+		}
+	}
+
+	if c.KeepGit && c.Layer {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --keep-git and --add; only the base template's history can be kept", nil)
+	}
+
+	if c.NoLock && c.Layer {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --no-lock and --add; layering needs the lock file to know which template owns each file", nil)
+	}
+
+	if c.KeepGit && c.PartialClone {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --keep-git and --partial-clone; a kept git directory needs every file", nil)
+	}
+
+	if c.Submodules && c.KeepGit {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --include-submodules and --keep-git; a kept git directory does not carry the submodules' history", nil)
+	}
+
+	if c.Submodules && c.PartialClone {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --include-submodules and --partial-clone; a sparse checkout leaves submodules out", nil)
+	}
+
+	if c.Submodules && c.Offline {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --include-submodules and --offline; submodules are never cached", nil)
+	}
+
+	if c.CloneDepth < 0 {
+		return NewAppError(ErrorCodeInvalidConfiguration, "clone depth cannot be negative", nil)
+	}
+
+	if c.GitTimeout < 0 {
+		return NewAppError(ErrorCodeInvalidConfiguration, "git timeout cannot be negative", nil)
+	}
+
+	if c.Retries < 0 {
+		return NewAppError(ErrorCodeInvalidConfiguration, "retries cannot be negative", nil)
+	}
+
+	for _, path := range c.OnlyPaths {
+		cleaned := filepath.Clean(path)
+		if filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+			return NewAppError(ErrorCodeInvalidPath, "--only path must be relative to the template repository root: "+path, nil)
+		}
 	}
 
 	// Both force and force-core cannot be true at the same time
@@ -107,6 +290,11 @@ func (c *InstallConfig) Validate() error {
 		return NewAppError(ErrorCodeInvalidConfiguration, "cannot specify both --force and --force-core flags", nil)
 	}
 
+	// Layering keeps the other templates' files, which either force flag would replace
+	if c.Layer && (c.Force || c.ForceCore) {
+		return NewAppError(ErrorCodeInvalidConfiguration, "cannot layer a template with --force or --force-core", nil)
+	}
+
 	// Validate gitignore mode
 	validModes := []string{"track", "all", "non-user"}
 	validMode := false
@@ -123,7 +311,36 @@ func (c *InstallConfig) Validate() error {
 	return nil
 }
 
-// GetTemplate returns the template configuration for this install
+// GetTemplate returns the template configuration for this install: the
+// archive given with Archive, or the registry template or the repository given
+// with RepoURL, at FromCommit or Ref when one was given, and at SelectedCommit
+// when one was picked
 func (c *InstallConfig) GetTemplate() (templates.Template, error) {
-	return templates.GetTemplate(c.TemplateID)
+	template, err := c.requestedTemplate()
+	if err != nil || c.SelectedCommit == "" {
+		return template, err
+	}
+	return template.WithCommit(c.SelectedCommit)
+}
+
+// requestedTemplate returns the template GetTemplate starts from, before a
+// selected commit is applied
+func (c *InstallConfig) requestedTemplate() (templates.Template, error) {
+	if c.Archive != "" {
+		return templates.FromArchive(c.Archive)
+	}
+	if c.RepoURL != "" {
+		return templates.FromRepository(c.RepoURL, c.Ref)
+	}
+
+	template, err := templates.GetTemplate(c.TemplateID)
+	switch {
+	case err != nil:
+		return template, err
+	case c.FromCommit != "":
+		return template.WithCommit(c.FromCommit)
+	case c.Ref != "":
+		return template.WithRef(c.Ref)
+	}
+	return template, nil
 }
@@ -18,6 +18,9 @@ const (
 	ErrorCodeGitCheckoutError  ErrorCode = "GIT_CHECKOUT_ERROR"
 	ErrorCodeGitError          ErrorCode = "GIT_ERROR"
 	ErrorCodeGitCommitNotFound ErrorCode = "GIT_COMMIT_NOT_FOUND"
+	ErrorCodeGitCommitMismatch ErrorCode = "GIT_COMMIT_MISMATCH"
+	ErrorCodeGitAuthFailed     ErrorCode = "GIT_AUTH_FAILED"
+	ErrorCodeGitRefNotFound    ErrorCode = "GIT_REF_NOT_FOUND"
 
 	// File system errors
 	ErrorCodeFileSystemError       ErrorCode = "FILE_SYSTEM_ERROR"
@@ -34,6 +37,10 @@ const (
 	ErrorCodeNotInstalled       ErrorCode = "NOT_INSTALLED"
 	ErrorCodeBackupFailed       ErrorCode = "BACKUP_FAILED"
 	ErrorCodeRestoreFailed      ErrorCode = "RESTORE_FAILED"
+	ErrorCodeTreeHashMismatch   ErrorCode = "TREE_HASH_MISMATCH"
+
+	// Security errors
+	ErrorCodePathTraversal ErrorCode = "PATH_TRAVERSAL"
 
 	// Validation errors
 	ErrorCodeInvalidPath          ErrorCode = "INVALID_PATH"
@@ -43,6 +50,7 @@ const (
 	// Network errors
 	ErrorCodeNetworkTimeout ErrorCode = "NETWORK_TIMEOUT"
 	ErrorCodeNetworkError   ErrorCode = "NETWORK_ERROR"
+	ErrorCodeNotCached      ErrorCode = "NOT_CACHED"
 
 	// User interaction errors
 	ErrorCodeUserCancelled ErrorCode = "USER_CANCELLED"
@@ -140,7 +148,8 @@ func IsGitError(err error) bool {
 		switch appErr.Code {
 		case ErrorCodeGitCloneFailed, ErrorCodeGitCheckoutFailed, ErrorCodeGitNotInstalled,
 			ErrorCodeGitNotFound, ErrorCodeGitCloneError, ErrorCodeGitCheckoutError,
-			ErrorCodeGitError, ErrorCodeGitCommitNotFound:
+			ErrorCodeGitError, ErrorCodeGitCommitNotFound, ErrorCodeGitCommitMismatch,
+			ErrorCodeGitAuthFailed:
 			return true
 		}
 	}
@@ -176,6 +185,12 @@ func GetUserFriendlyMessage(err error) string {
 		return "Failed to checkout the specified commit. The repository may be corrupted or the commit may not exist."
 	case ErrorCodeGitCommitNotFound:
 		return "The specified commit was not found in the repository."
+	case ErrorCodeGitCommitMismatch:
+		return "The downloaded template does not match the pinned commit. The repository may have been tampered with."
+	case ErrorCodeTreeHashMismatch:
+		return "The template files do not match the tree hash the template declares. The repository history may have been rewritten."
+	case ErrorCodeGitAuthFailed:
+		return "Git could not authenticate with the template repository. Check that your SSH keys or credentials have access to it."
 	case ErrorCodeGitError:
 		return "A git operation failed. Please ensure the repository is valid and try again."
 	case ErrorCodePermissionDenied:
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// Exit codes the CLI ends with, so scripts can branch on the kind of failure.
+// They are part of the CLI's interface: documented in the README and never
+// renumbered.
+const (
+	ExitCodeOK               = 0 // Success
+	ExitCodeError            = 1 // Any failure without a more specific code
+	ExitCodeTemplateNotFound = 2 // The template ID is not in the registry
+	ExitCodeGit              = 3 // A git command or network access failed
+	ExitCodeFileSystem       = 4 // Reading or writing files failed, including permissions
+	ExitCodeValidation       = 5 // Invalid flags, configuration, or template, or a failed verification
+	ExitCodeAlreadyInstalled = 6 // The target is already installed and no --force was given
+)
+
+// exitCodes maps error codes to the exit code for their kind of failure
+var exitCodes = map[ErrorCode]int{
+	ErrorCodeGitCloneFailed:    ExitCodeGit,
+	ErrorCodeGitCheckoutFailed: ExitCodeGit,
+	ErrorCodeGitNotInstalled:   ExitCodeGit,
+	ErrorCodeGitNotFound:       ExitCodeGit,
+	ErrorCodeGitCloneError:     ExitCodeGit,
+	ErrorCodeGitCheckoutError:  ExitCodeGit,
+	ErrorCodeGitError:          ExitCodeGit,
+	ErrorCodeGitCommitNotFound: ExitCodeGit,
+	ErrorCodeGitAuthFailed:     ExitCodeGit,
+	ErrorCodeGitRefNotFound:    ExitCodeGit,
+	ErrorCodeNetworkTimeout:    ExitCodeGit,
+	ErrorCodeNetworkError:      ExitCodeGit,
+	ErrorCodeNotCached:         ExitCodeGit,
+
+	ErrorCodeFileSystemError:       ExitCodeFileSystem,
+	ErrorCodeDirectoryNotFound:     ExitCodeFileSystem,
+	ErrorCodeDirectoryNotEmpty:     ExitCodeFileSystem,
+	ErrorCodePermissionDenied:      ExitCodeFileSystem,
+	ErrorCodeFileAlreadyExists:     ExitCodeFileSystem,
+	ErrorCodeSymlinkCreationFailed: ExitCodeFileSystem,
+	ErrorCodeSymlinkInvalid:        ExitCodeFileSystem,
+	ErrorCodeBackupFailed:          ExitCodeFileSystem,
+	ErrorCodeRestoreFailed:         ExitCodeFileSystem,
+	ErrorCodePathTraversal:         ExitCodeFileSystem,
+	ErrorCodeInvalidPath:           ExitCodeFileSystem,
+
+	ErrorCodeInvalidConfiguration: ExitCodeValidation,
+	ErrorCodeValidationFailed:     ExitCodeValidation,
+	ErrorCodeTreeHashMismatch:     ExitCodeValidation,
+	ErrorCodeGitCommitMismatch:    ExitCodeValidation,
+
+	ErrorCodeAlreadyInstalled: ExitCodeAlreadyInstalled,
+}
+
+// ExitCode returns the exit code for an error returned by a command: ExitCodeOK
+// for nil, and otherwise the code for the innermost cause that has one, since
+// that names what actually went wrong (a failed installation caused by a
+// network error exits with ExitCodeGit). Errors without one give ExitCodeError.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	code := ExitCodeError
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if e == templates.ErrNotFound {
+			return ExitCodeTemplateNotFound
+		}
+		if e == templates.ErrNoTemplate {
+			return ExitCodeValidation
+		}
+		switch e := e.(type) {
+		case *AppError:
+			if mapped, ok := exitCodes[e.Code]; ok {
+				code = mapped
+			}
+		case *fs.PathError:
+			code = ExitCodeFileSystem
+		}
+	}
+	return code
+}
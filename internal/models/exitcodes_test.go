@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestExitCode(t *testing.T) {
+	_, notFound := templates.GetTemplate("no-such-template")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "success",
+			err:  nil,
+			want: ExitCodeOK,
+		},
+		{
+			name: "plain error",
+			err:  errors.New("something failed"),
+			want: ExitCodeError,
+		},
+		{
+			name: "unknown template",
+			err:  notFound,
+			want: ExitCodeTemplateNotFound,
+		},
+		{
+			name: "unknown template behind a validation error",
+			err:  NewAppError(ErrorCodeInvalidConfiguration, "invalid template ID: no-such-template", notFound),
+			want: ExitCodeTemplateNotFound,
+		},
+		{
+			name: "blank template ID",
+			err:  fmt.Errorf("failed to get template: %w", templates.ErrNoTemplate),
+			want: ExitCodeValidation,
+		},
+		{
+			name: "git failure",
+			err:  NewAppError(ErrorCodeGitCloneError, "clone failed", nil),
+			want: ExitCodeGit,
+		},
+		{
+			name: "network failure behind an installation error",
+			err: fmt.Errorf("installation failed: %w",
+				NewAppError(ErrorCodeInstallationFailed, "fetch failed", NewAppError(ErrorCodeNetworkError, "no route", nil))),
+			want: ExitCodeGit,
+		},
+		{
+			name: "offline without a cache",
+			err:  NewAppError(ErrorCodeNotCached, "not cached", nil),
+			want: ExitCodeGit,
+		},
+		{
+			name: "permission denied",
+			err:  NewFileSystemError(ErrorCodePermissionDenied, "/target", fs.ErrPermission),
+			want: ExitCodeFileSystem,
+		},
+		{
+			name: "raw path error",
+			err:  fmt.Errorf("failed to read: %w", &fs.PathError{Op: "open", Path: "/target", Err: fs.ErrNotExist}),
+			want: ExitCodeFileSystem,
+		},
+		{
+			name: "invalid configuration",
+			err:  NewAppError(ErrorCodeInvalidConfiguration, "--verbose and --quiet cannot be used together", nil),
+			want: ExitCodeValidation,
+		},
+		{
+			name: "tree hash mismatch",
+			err:  NewAppError(ErrorCodeTreeHashMismatch, "mismatch", nil),
+			want: ExitCodeValidation,
+		},
+		{
+			name: "already installed",
+			err:  fmt.Errorf("installation analysis failed: %w", NewAppError(ErrorCodeAlreadyInstalled, "installed", nil)),
+			want: ExitCodeAlreadyInstalled,
+		},
+		{
+			name: "unmapped app error",
+			err:  NewAppError(ErrorCodeInstallationFailed, "hook failed", errors.New("exit status 1")),
+			want: ExitCodeError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// Codes reported for errors that do not carry an ErrorCode of their own
+const (
+	ErrorCodeTemplateNotFound ErrorCode = "TEMPLATE_NOT_FOUND"
+	ErrorCodeUnknown          ErrorCode = "ERROR"
+)
+
+// TemplateError records which template an error happened for, without
+// changing its message
+type TemplateError struct {
+	TemplateID string
+	Err        error
+}
+
+// Error implements the error interface
+func (e *TemplateError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// NewTemplateError attributes err to the template, returning nil for a nil
+// err so it can wrap any return value
+func NewTemplateError(templateID string, err error) error {
+	if err == nil || templateID == "" {
+		return err
+	}
+	return &TemplateError{TemplateID: templateID, Err: err}
+}
+
+// ErrorReport is the machine-readable form of an error a command failed with,
+// printed by --json-errors
+type ErrorReport struct {
+	// Code names the innermost cause with an ErrorCode, as ExitCode does, or
+	// is TEMPLATE_NOT_FOUND or ERROR for errors without one
+	Code     ErrorCode `json:"code"`
+	ExitCode int       `json:"exit_code"`
+
+	// Message is the outermost error's own message, without its causes
+	Message  string `json:"message"`
+	Template string `json:"template,omitempty"`
+
+	// Cause lists the messages of the wrapped errors, outermost first
+	Cause   []string               `json:"cause,omitempty"`
+	Context map[string]interface{} `json:"context,omitempty"`
+}
+
+// NewErrorReport describes err, flattening its chain of wrapped errors into
+// one message per error. The context of every AppError in the chain is merged,
+// the outer ones taking precedence.
+func NewErrorReport(err error) ErrorReport {
+	report := ErrorReport{Code: ErrorCodeUnknown, ExitCode: ExitCode(err)}
+
+	var messages []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch e := e.(type) {
+		case *TemplateError:
+			if report.Template == "" {
+				report.Template = e.TemplateID
+			}
+			continue // Adds no message of its own
+		case *templates.NotFoundError:
+			if report.Template == "" {
+				report.Template = e.ID
+			}
+		case *AppError:
+			report.Code = e.Code
+			for key, value := range e.Context {
+				if report.Context == nil {
+					report.Context = make(map[string]interface{})
+				}
+				if _, ok := report.Context[key]; !ok {
+					report.Context[key] = value
+				}
+			}
+			messages = append(messages, e.Message)
+			continue
+		}
+		if e == templates.ErrNotFound {
+			continue // Already in the message wrapping it, and in Code
+		}
+		messages = append(messages, ownMessage(e))
+	}
+
+	// A missing template decides the exit code wherever it is in the chain
+	if errors.Is(err, templates.ErrNotFound) {
+		report.Code = ErrorCodeTemplateNotFound
+	}
+	if len(messages) > 0 {
+		report.Message, report.Cause = messages[0], messages[1:]
+	}
+	if len(report.Cause) == 0 {
+		report.Cause = nil
+	}
+	return report
+}
+
+// ownMessage returns the part of an error's message that its wrapped error
+// does not account for, such as "failed to clone" for an error made with
+// fmt.Errorf("failed to clone: %w", cause). Messages that put the cause
+// elsewhere are kept whole.
+func ownMessage(err error) string {
+	message := err.Error()
+	if cause := errors.Unwrap(err); cause != nil {
+		message = strings.TrimSuffix(message, ": "+cause.Error())
+	}
+	return message
+}
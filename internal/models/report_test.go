@@ -0,0 +1,83 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestNewErrorReport(t *testing.T) {
+	_, notFound := templates.GetTemplate("no-such-template")
+	exitErr := errors.New("exit status 128")
+
+	tests := []struct {
+		name string
+		err  error
+		want ErrorReport
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("something failed"),
+			want: ErrorReport{Code: ErrorCodeUnknown, ExitCode: ExitCodeError, Message: "something failed"},
+		},
+		{
+			name: "wrapped chain is flattened",
+			err: fmt.Errorf("installation failed: %w", NewTemplateError("main",
+				fmt.Errorf("failed to clone repository: %w", NewGitError(ErrorCodeGitCloneFailed, "clone", exitErr)))),
+			want: ErrorReport{
+				Code:     ErrorCodeGitCloneFailed,
+				ExitCode: ExitCodeGit,
+				Message:  "installation failed",
+				Template: "main",
+				Cause:    []string{"failed to clone repository", "Git operation failed: clone", "exit status 128"},
+				Context:  map[string]interface{}{"operation": "clone"},
+			},
+		},
+		{
+			name: "innermost code wins",
+			err: NewAppError(ErrorCodeInstallationFailed, "install failed",
+				NewFileSystemError(ErrorCodePermissionDenied, "/x", errors.New("denied"))).WithContext("path", "/outer"),
+			want: ErrorReport{
+				Code:     ErrorCodePermissionDenied,
+				ExitCode: ExitCodeFileSystem,
+				Message:  "install failed",
+				Cause:    []string{"File system operation failed for path: /x", "denied"},
+				Context:  map[string]interface{}{"path": "/outer"},
+			},
+		},
+		{
+			name: "unknown template",
+			err:  fmt.Errorf("%w; did you mean: main?", notFound),
+			want: ErrorReport{
+				Code:     ErrorCodeTemplateNotFound,
+				ExitCode: ExitCodeTemplateNotFound,
+				Message:  "template 'no-such-template' not found; did you mean: main?",
+				Template: "no-such-template",
+				Cause:    []string{"template 'no-such-template' not found"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewErrorReport(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewErrorReport() = %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTemplateError(t *testing.T) {
+	if err := NewTemplateError("main", nil); err != nil {
+		t.Errorf("NewTemplateError() of nil = %v, want nil", err)
+	}
+
+	cause := NewAppError(ErrorCodeNotCached, "not cached", nil)
+	err := NewTemplateError("main", cause)
+	if err.Error() != cause.Error() || !IsErrorCode(err, ErrorCodeNotCached) {
+		t.Errorf("NewTemplateError() = %v, want the cause's message and code", err)
+	}
+}
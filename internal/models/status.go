@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
 )
 
@@ -13,8 +14,71 @@ const (
 	InstallationTypeNew       InstallationType = "New Installation"
 	InstallationTypeUpdate    InstallationType = "Update Core Only"
 	InstallationTypeOverwrite InstallationType = "Full Overwrite"
+	InstallationTypeLayer     InstallationType = "Layer Over Existing"
 )
 
+// VersionState describes how an installed template commit relates to the registry
+type VersionState string
+
+const (
+	VersionStateCurrent         VersionState = "current"          // Installed commit matches the registry
+	VersionStateBehind          VersionState = "behind"           // Registry pins a different (newer) commit
+	VersionStateOverridden      VersionState = "overridden"       // Installed with --from-commit or --ref at a commit other than the registry's
+	VersionStateUnknown         VersionState = "unknown"          // Comparison not possible (no commit recorded or branch-following template)
+	VersionStateTemplateMissing VersionState = "template-missing" // Installed template ID is no longer in the registry
+)
+
+// FileAction describes what an installation would do to a single file
+type FileAction string
+
+const (
+	FileActionCreate    FileAction = "create"    // File does not exist in the target yet
+	FileActionOverwrite FileAction = "overwrite" // Existing file would be replaced by the template's copy
+	FileActionSkip      FileAction = "skip"      // Template file is not copied because the target keeps its own
+	FileActionRemove    FileAction = "remove"    // Existing file would be deleted along with its framework directory
+)
+
+// FileChange is a planned operation on one file, relative to the target directory
+type FileChange struct {
+	Path   string     `json:"path"`
+	Action FileAction `json:"action"`
+}
+
+// ModifiedFile is an installed file edited since the last install, found while
+// re-applying the template over it
+type ModifiedFile struct {
+	Path            string `json:"path"`             // Relative to the target directory
+	Conflict        bool   `json:"conflict"`         // The template's copy changed too
+	UpstreamRemoved bool   `json:"upstream_removed"` // The template no longer has the file
+	Overwritten     bool   `json:"overwritten"`      // The template's copy replaced the local edits
+	Local           []byte `json:"-"`                // Edited content in the target
+	Upstream        []byte `json:"-"`                // Content from the template, empty when removed
+}
+
+// RemovedFile is a recorded file the template no longer ships, found during a
+// core update outside the directories it replaces
+type RemovedFile struct {
+	Path     string `json:"path"`     // Relative to the target directory
+	Modified bool   `json:"modified"` // Edited since the last install, so never pruned
+	Pruned   bool   `json:"pruned"`   // Deleted from the target (--prune)
+}
+
+// FileChanges counts the files an install wrote, split into those identical
+// to the installed copy, which were left alone, and those new or different
+type FileChanges struct {
+	Unchanged int `json:"unchanged"`
+	Updated   int `json:"updated"`
+}
+
+// FileDiff is an installed file whose content differs from the template's copy
+type FileDiff struct {
+	Path              string `json:"path"`                // Relative to the target directory
+	MissingLocally    bool   `json:"missing_locally"`     // Deleted from the target since installation
+	MissingInTemplate bool   `json:"missing_in_template"` // The template no longer has the file
+	Template          []byte `json:"-"`                   // Content from the template, rendered as on install
+	Local             []byte `json:"-"`                   // Content in the target
+}
+
 // StatusInfo represents the overall installation status
 type StatusInfo struct {
 	// Basic installation status
@@ -26,6 +90,12 @@ type StatusInfo struct {
 	// Template information
 	InstalledTemplate *templates.TemplateInfo `json:"installed_template,omitempty"`
 
+	// Lock file and comparison against the registry
+	Lock          *state.Lock     `json:"lock,omitempty"`
+	LockError     string          `json:"lock_error,omitempty"`
+	Unmanaged     bool            `json:"unmanaged,omitempty"`      // Framework present without a lock file, as init --no-lock leaves it
+	VersionChecks []*VersionCheck `json:"version_checks,omitempty"` // One per locked template, in lock order
+
 	// Script detection
 	HasPreInstallScript  bool `json:"has_pre_install_script"`
 	HasPostInstallScript bool `json:"has_post_install_script"`
@@ -47,6 +117,20 @@ type StatusInfo struct {
 	CodexDirPath           string `json:"codex_dir_path"`
 }
 
+// VersionCheck compares the installed template commit with the registry
+type VersionCheck struct {
+	State           VersionState `json:"state"`
+	TemplateID      string       `json:"template_id"`
+	TemplateName    string       `json:"template_name,omitempty"`
+	Branch          string       `json:"branch,omitempty"`
+	Ref             string       `json:"ref,omitempty"` // Branch or tag installed with --ref
+	InstalledCommit string       `json:"installed_commit,omitempty"`
+	InstalledTag    string       `json:"installed_tag,omitempty"` // Registry tag the installed commit was resolved from
+	RegistryCommit  string       `json:"registry_commit,omitempty"`
+	RegistryTag     string       `json:"registry_tag,omitempty"` // Tag the registry pins instead of a commit
+	SuggestedID     string       `json:"suggested_id,omitempty"` // Closest active template when the installed one is missing
+}
+
 // SymlinkStatus represents the status of an individual symlink
 type SymlinkStatus struct {
 	Name   string `json:"name"`            // Name of the symlink (e.g., "core", "guides")
@@ -62,6 +146,7 @@ type InstallationPlan struct {
 	// Basic information
 	TargetDir        string           `json:"target_dir"`
 	InstallationType InstallationType `json:"installation_type"`
+	CreateTarget     bool             `json:"create_target,omitempty"` // The target directory does not exist yet
 
 	// Template information
 	Template templates.Template `json:"template"`
@@ -81,6 +166,9 @@ type InstallationPlan struct {
 	SymlinksToCreate    []string `json:"symlinks_to_create"`
 	SymlinksToUpdate    []string `json:"symlinks_to_update"`
 
+	// Per-file operations, only populated by a file preview (dry run)
+	FileChanges []FileChange `json:"file_changes,omitempty"`
+
 	// Backup information
 	BackupRequired bool   `json:"backup_required"`
 	BackupDir      string `json:"backup_dir,omitempty"`
@@ -187,6 +275,22 @@ func (p *InstallationPlan) IsValid() bool {
 	return !p.HasConflicts && len(p.Errors) == 0
 }
 
+// AddFileChange records a planned file operation
+func (p *InstallationPlan) AddFileChange(path string, action FileAction) {
+	p.FileChanges = append(p.FileChanges, FileChange{Path: path, Action: action})
+}
+
+// FileChangesByAction returns the paths of planned file operations with the given action
+func (p *InstallationPlan) FileChangesByAction(action FileAction) []string {
+	var paths []string
+	for _, change := range p.FileChanges {
+		if change.Action == action {
+			paths = append(paths, change.Path)
+		}
+	}
+	return paths
+}
+
 // RequiresConfirmation returns true if the plan requires user confirmation
 func (p *InstallationPlan) RequiresConfirmation() bool {
 	return len(p.WillReplace) > 0 || p.HasConflicts || len(p.Warnings) > 0
@@ -0,0 +1,204 @@
+// Package progress shows how far the long-running steps of an installation
+// have got, such as cloning a template or copying its files, so a slow link
+// does not look like a hang.
+//
+// On a terminal each step is a single line redrawn in place with a spinner;
+// elsewhere (CI logs, pipes) a plain line is written every few seconds while a
+// step runs. Progress goes to stderr next to the log messages and is turned
+// off entirely with --quiet.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// redrawInterval is how often a live line is redrawn, which also animates the spinner
+	redrawInterval = 100 * time.Millisecond
+
+	// lineInterval is how often a line is written when output is not a terminal
+	lineInterval = 5 * time.Second
+)
+
+// spinnerFrames animate a live line
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+var (
+	mu     sync.Mutex
+	output io.Writer
+	live   bool
+	drawn  *Reporter // The live reporter that owns the terminal line, if any
+)
+
+// Setup sends progress to w, redrawn in place when liveOutput is set (w is a
+// terminal) and as an occasional line otherwise. A nil w turns progress off,
+// which is also the default.
+func Setup(w io.Writer, liveOutput bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = w
+	live = liveOutput
+}
+
+// Reporter shows the progress of one step. Start returns nil when progress is
+// off; every method of a nil Reporter does nothing, so callers need no checks.
+type Reporter struct {
+	w        io.Writer
+	label    string
+	live     bool
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	started time.Time
+	written time.Time // When a line was last written (zero before the first)
+	status  string
+	count   int
+	unit    string
+	frame   int
+	stop    chan struct{}
+	done    bool
+}
+
+// Start begins reporting a step described by label, e.g. "Cloning <url>".
+// A terminal has one live line, so while another step is drawing it, steps
+// started alongside (such as concurrent fetches) are not shown.
+func Start(label string) *Reporter {
+	mu.Lock()
+	defer mu.Unlock()
+	if output == nil || (live && drawn != nil) {
+		return nil
+	}
+
+	r := newReporter(output, label, live, time.Now)
+	if r.live {
+		drawn = r
+	}
+	r.stop = make(chan struct{})
+	go r.run()
+	return r
+}
+
+// newReporter creates a reporter without starting its redraw loop
+func newReporter(w io.Writer, label string, liveOutput bool, now func() time.Time) *Reporter {
+	interval := lineInterval
+	if liveOutput {
+		interval = redrawInterval
+	}
+	return &Reporter{w: w, label: label, live: liveOutput, interval: interval, now: now, started: now()}
+}
+
+// Status replaces the text shown after the label, such as a line of git's own
+// progress ("Receiving objects:  45% (450/1000)")
+func (r *Reporter) Status(status string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// Increment counts one more unit of work done, e.g. a copied file; unit names
+// what is counted in the displayed line
+func (r *Reporter) Increment(unit string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	r.unit = unit
+}
+
+// Done ends the step: a live line is cleared, and when lines have been written
+// a final one records how the step finished
+func (r *Reporter) Done() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	r.done = true
+	if r.stop != nil {
+		close(r.stop)
+	}
+	mu.Lock()
+	if drawn == r {
+		drawn = nil
+	}
+	mu.Unlock()
+
+	if r.written.IsZero() {
+		return
+	}
+	if r.live {
+		fmt.Fprint(r.w, "\r\033[K")
+		return
+	}
+	fmt.Fprintf(r.w, "%s: done in %s%s\n", r.label, r.now().Sub(r.started).Round(100*time.Millisecond), r.countSuffix(", "))
+}
+
+// run writes a line every interval until Done. A live line is redrawn often
+// enough to animate the spinner; plain lines only start after a full
+// interval, so quick steps stay silent.
+func (r *Reporter) run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// tick writes the line for the current state
+func (r *Reporter) tick() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+
+	r.written = r.now()
+	line := r.line()
+	if r.live {
+		r.frame = (r.frame + 1) % len(spinnerFrames)
+		fmt.Fprintf(r.w, "\r\033[K%s %s", spinnerFrames[r.frame], line)
+		return
+	}
+	fmt.Fprintln(r.w, line)
+}
+
+// line formats the label, status, and count
+func (r *Reporter) line() string {
+	var b strings.Builder
+	b.WriteString(r.label)
+	if r.status != "" {
+		b.WriteString(": ")
+		b.WriteString(r.status)
+	}
+	b.WriteString(r.countSuffix(": "))
+	return b.String()
+}
+
+// countSuffix formats the count after sep, or nothing when nothing was counted
+func (r *Reporter) countSuffix(sep string) string {
+	if r.count == 0 {
+		return ""
+	}
+	if r.status != "" {
+		sep = ", "
+	}
+	return fmt.Sprintf("%s%d %s", sep, r.count, r.unit)
+}
@@ -0,0 +1,124 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock tests move by hand
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestReporter_Lines(t *testing.T) {
+	var out bytes.Buffer
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	r := newReporter(&out, "Fetching template main", false, clock.Now)
+
+	r.Done()
+	if out.Len() != 0 {
+		t.Fatalf("Expected a step finished before any line to stay silent, got %q", out.String())
+	}
+
+	r = newReporter(&out, "Fetching template main", false, clock.Now)
+	r.Status("Receiving objects:  45% (450/1000)")
+	r.tick()
+	r.Status("Resolving deltas: 100% (10/10), done.")
+	r.tick()
+	clock.now = clock.now.Add(12 * time.Second)
+	r.Done()
+	r.Done()
+
+	want := "Fetching template main: Receiving objects:  45% (450/1000)\n" +
+		"Fetching template main: Resolving deltas: 100% (10/10), done.\n" +
+		"Fetching template main: done in 12s\n"
+	if out.String() != want {
+		t.Errorf("Output = %q, want %q", out.String(), want)
+	}
+
+	// Nothing is written once the step is done
+	r.tick()
+	if out.String() != want {
+		t.Errorf("Expected no output after Done, got %q", out.String())
+	}
+}
+
+func TestReporter_Count(t *testing.T) {
+	var out bytes.Buffer
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	r := newReporter(&out, "Copying template files", false, clock.Now)
+
+	for i := 0; i < 3; i++ {
+		r.Increment("files")
+	}
+	r.tick()
+	r.Done()
+
+	want := "Copying template files: 3 files\nCopying template files: done in 0s, 3 files\n"
+	if out.String() != want {
+		t.Errorf("Output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestReporter_Live(t *testing.T) {
+	var out bytes.Buffer
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	r := newReporter(&out, "Fetching template main", true, clock.Now)
+
+	r.Status("Receiving objects:  45% (450/1000)")
+	r.tick()
+	r.tick()
+	r.Done()
+
+	lines := strings.Split(out.String(), "\r\033[K")
+	if len(lines) != 4 || lines[0] != "" || lines[3] != "" {
+		t.Fatalf("Expected two redraws and a cleared line, got %q", out.String())
+	}
+	if lines[1] == lines[2] {
+		t.Errorf("Expected the spinner to turn between redraws, got %q twice", lines[1])
+	}
+	if !strings.HasSuffix(lines[1], " Fetching template main: Receiving objects:  45% (450/1000)") {
+		t.Errorf("Redrawn line = %q", lines[1])
+	}
+}
+
+func TestStart(t *testing.T) {
+	t.Cleanup(func() { Setup(nil, false) })
+
+	Setup(nil, false)
+	var off *Reporter = Start("Fetching")
+	if off != nil {
+		t.Fatal("Expected no reporter while progress is off")
+	}
+	// A nil reporter ignores every call
+	off.Status("status")
+	off.Increment("files")
+	off.Done()
+
+	var out bytes.Buffer
+	Setup(&out, true)
+	first := Start("Fetching template main")
+	if first == nil {
+		t.Fatal("Expected a reporter while progress is on")
+	}
+	if second := Start("Fetching template ccr"); second != nil {
+		second.Done()
+		t.Error("Expected a second live step not to be shown while the first draws the line")
+	}
+	first.Done()
+	if next := Start("Copying template files"); next == nil {
+		t.Error("Expected a live step once the previous one is done")
+	} else {
+		next.Done()
+	}
+
+	Setup(&out, false)
+	a, b := Start("Fetching template main"), Start("Fetching template ccr")
+	if a == nil || b == nil {
+		t.Error("Expected concurrent steps to be reported as plain lines")
+	}
+	a.Done()
+	b.Done()
+}
@@ -0,0 +1,324 @@
+// Package cache keeps clones of template repositories between runs, so
+// installing the same template into many projects only fetches it once.
+//
+// Each repository URL gets its own directory holding a bare clone and one
+// checked-out tree per commit. The bare clone mirrors every branch and tag, so
+// templates that share a repository and differ only in branch (like the
+// built-in ones) share one clone, which is fetched rather than cloned again:
+//
+//	<cache dir>/<url hash>/repo.git/
+//	<cache dir>/<url hash>/trees/<commit>/
+//	<cache dir>/<url hash>/url
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
+)
+
+const (
+	bareDirName  = "repo.git"
+	treesDirName = "trees"
+	urlFileName  = "url"
+)
+
+// Service manages the template clone cache. It is safe for concurrent use;
+// checkouts of the same repository are serialized.
+type Service struct {
+	gitService *git.Service
+	dir        string
+
+	mu        sync.Mutex
+	repoLocks map[string]*sync.Mutex
+}
+
+// New creates a new cache service rooted at the user cache directory. The
+// cache is disabled when no cache directory can be determined.
+func New() *Service {
+	dir, err := config.GetUserCacheDir()
+	if err != nil {
+		dir = ""
+	}
+	return &Service{
+		gitService: git.New(),
+		dir:        dir,
+		repoLocks:  make(map[string]*sync.Mutex),
+	}
+}
+
+// Dir returns the cache directory, or "" when the cache is disabled
+func (s *Service) Dir() string {
+	return s.dir
+}
+
+// Enabled reports whether clones can be cached
+func (s *Service) Enabled() bool {
+	return s.dir != ""
+}
+
+// Checkout returns a cached working tree of opts.Commit, or of the head of
+// opts.Branch (a branch or tag; the remote's default branch if empty) when no
+// commit is given, along with the commit it holds. The repository is only
+// fetched when the commit is not cached yet or a branch head is wanted; if that
+// fetch fails for a branch head, the cached head is used. opts.Depth is
+// ignored: the cached clone keeps full history so any later pin can be served
+// from it.
+//
+// Cloning and fetching stop when ctx is done. The returned tree is shared with
+// later runs and must not be modified.
+func (s *Service) Checkout(ctx context.Context, opts git.CloneOptions) (string, string, error) {
+	return s.checkout(ctx, opts, false)
+}
+
+// CheckoutCached returns a working tree like Checkout without contacting the
+// repository, for working offline: a branch head is the one last fetched, and
+// a repository or commit that is not cached yet is a NOT_CACHED error.
+func (s *Service) CheckoutCached(opts git.CloneOptions) (string, string, error) {
+	return s.checkout(context.Background(), opts, true)
+}
+
+// checkout implements Checkout, or CheckoutCached when offline is set
+func (s *Service) checkout(ctx context.Context, opts git.CloneOptions, offline bool) (string, string, error) {
+	if !s.Enabled() {
+		return "", "", models.NewAppError(models.ErrorCodeInvalidConfiguration, "No cache directory available", nil)
+	}
+
+	repoDir := s.repoDir(opts.URL)
+	bareDir := filepath.Join(repoDir, bareDirName)
+
+	unlock := s.lockRepo(repoDir)
+	defer unlock()
+
+	fetched := false
+	if _, err := os.Stat(bareDir); os.IsNotExist(err) {
+		if offline {
+			return "", "", notCached(opts.URL, "")
+		}
+		if err := s.cloneBare(ctx, repoDir, opts); err != nil {
+			return "", "", err
+		}
+		fetched = true
+	}
+
+	commit := opts.Commit
+	if commit == "" {
+		if !fetched && !offline {
+			if err := s.gitService.Fetch(ctx, bareDir, opts.Retry); err != nil && opts.Notify != nil && ctx.Err() == nil {
+				opts.Notify(fmt.Sprintf("Could not update cached clone of %s, using the cached branch head: %v", opts.URL, err))
+			}
+		}
+		ref := "HEAD"
+		if opts.Branch != "" {
+			ref = "refs/heads/" + opts.Branch
+		}
+		resolved, err := s.gitService.ResolveCommit(bareDir, ref)
+		if err != nil && opts.Branch != "" {
+			// Like git clone --branch, a tag of that name will do
+			resolved, err = s.gitService.ResolveCommit(bareDir, "refs/tags/"+opts.Branch)
+		}
+		if err != nil {
+			return "", "", err
+		}
+		commit = resolved
+	} else if s.gitService.IsValidCommit(bareDir, commit) != nil {
+		if fetched {
+			return "", "", s.gitService.IsValidCommit(bareDir, commit)
+		}
+		if offline {
+			return "", "", notCached(opts.URL, commit)
+		}
+		if err := s.gitService.Fetch(ctx, bareDir, opts.Retry); err != nil {
+			return "", "", err
+		}
+		if err := s.gitService.IsValidCommit(bareDir, commit); err != nil {
+			return "", "", err
+		}
+	}
+
+	// Trees are keyed by the full hash, however the commit was written
+	commit, err := s.gitService.ResolveCommit(bareDir, commit)
+	if err != nil {
+		return "", "", err
+	}
+
+	treeDir := filepath.Join(repoDir, treesDirName, commit)
+	if _, err := os.Stat(filepath.Join(treeDir, ".git")); err == nil {
+		return treeDir, commit, nil
+	}
+	if err := s.checkoutTree(ctx, bareDir, treeDir, commit); err != nil {
+		return "", "", err
+	}
+	return treeDir, commit, nil
+}
+
+// Refresh fetches every branch and tag of the cached clone of url, cloning it
+// first when it is not cached yet, and reports whether it was cloned. Trees
+// already checked out are kept, since they are keyed by commit.
+func (s *Service) Refresh(ctx context.Context, url string, retry git.RetryOptions) (bool, error) {
+	if !s.Enabled() {
+		return false, models.NewAppError(models.ErrorCodeInvalidConfiguration, "No cache directory available", nil)
+	}
+
+	repoDir := s.repoDir(url)
+	bareDir := filepath.Join(repoDir, bareDirName)
+
+	unlock := s.lockRepo(repoDir)
+	defer unlock()
+
+	if _, err := os.Stat(bareDir); os.IsNotExist(err) {
+		if err := s.cloneBare(ctx, repoDir, git.CloneOptions{URL: url, Retry: retry}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if err := s.gitService.Fetch(ctx, bareDir, retry); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// notCached reports that a repository, or one of its commits when commit is
+// set, is missing from the cache
+func notCached(url, commit string) error {
+	what := url
+	if commit != "" {
+		what = fmt.Sprintf("commit %s of %s", commit, url)
+	}
+	return models.NewAppError(models.ErrorCodeNotCached, fmt.Sprintf("No cached clone of %s", what), nil)
+}
+
+// Clean removes the whole cache and returns the number of bytes it freed
+func (s *Service) Clean() (int64, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+
+	size, err := s.Size()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(s.dir); err != nil {
+		return 0, models.NewFileSystemError(models.ErrorCodeFileSystemError, s.dir, err)
+	}
+	return size, nil
+}
+
+// Size returns the disk space used by the cache in bytes
+func (s *Service) Size() (int64, error) {
+	var size int64
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, models.NewFileSystemError(models.ErrorCodeFileSystemError, s.dir, err)
+	}
+	return size, nil
+}
+
+// lockRepo serializes work on one cached repository within this process, so
+// concurrent checkouts do not clone or fetch it twice
+func (s *Service) lockRepo(repoDir string) func() {
+	s.mu.Lock()
+	lock, ok := s.repoLocks[repoDir]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.repoLocks[repoDir] = lock
+	}
+	s.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// repoDir returns the cache directory for a repository URL. Unlike
+// Template.CacheKey it leaves out the commit and branch, so that every
+// revision of a repository is fetched into the one clone.
+func (s *Service) repoDir(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])[:16])
+}
+
+// cloneBare creates the bare clone for a repository. It is cloned next to its
+// final location and renamed into place, so an interrupted clone never looks
+// like a complete one.
+func (s *Service) cloneBare(ctx context.Context, repoDir string, opts git.CloneOptions) error {
+	if err := os.MkdirAll(repoDir, config.DirPermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, repoDir, err)
+	}
+
+	tempDir, err := os.MkdirTemp(repoDir, bareDirName+".tmp-")
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, repoDir, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := s.gitService.CloneInto(ctx, tempDir, git.CloneOptions{URL: opts.URL, Bare: true, Retry: opts.Retry, Progress: opts.Progress}); err != nil {
+		return fmt.Errorf("failed to clone repository into cache: %w", err)
+	}
+
+	bareDir := filepath.Join(repoDir, bareDirName)
+	if err := os.Rename(tempDir, bareDir); err != nil {
+		if _, statErr := os.Stat(bareDir); statErr == nil {
+			return nil // Another run cached it first
+		}
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, bareDir, err)
+	}
+
+	// Record the URL so the cache directory can be identified by hand
+	_ = os.WriteFile(filepath.Join(repoDir, urlFileName), []byte(opts.URL+"\n"), config.FilePermissions)
+	return nil
+}
+
+// checkoutTree checks commit out of the bare clone into treeDir, going through
+// a temporary directory like cloneBare
+func (s *Service) checkoutTree(ctx context.Context, bareDir, treeDir, commit string) error {
+	treesDir := filepath.Dir(treeDir)
+	if err := os.MkdirAll(treesDir, config.DirPermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, treesDir, err)
+	}
+
+	tempDir, err := os.MkdirTemp(treesDir, commit+".tmp-")
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, treesDir, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := s.gitService.CloneInto(ctx, tempDir, git.CloneOptions{URL: bareDir, Commit: commit}); err != nil {
+		return fmt.Errorf("failed to check out cached commit %s: %w", commit, err)
+	}
+
+	// A stale or partial tree from an older run is replaced
+	if err := os.RemoveAll(treeDir); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, treeDir, err)
+	}
+	if err := os.Rename(tempDir, treeDir); err != nil {
+		if _, statErr := os.Stat(filepath.Join(treeDir, ".git")); statErr == nil {
+			return nil // Another run checked it out first
+		}
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, treeDir, err)
+	}
+	return nil
+}
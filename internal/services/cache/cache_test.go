@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
+)
+
+// newTestService returns a cache service rooted in a temporary directory
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Git not available, skipping cache tests")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	return New()
+}
+
+// commitFile writes file.txt in a repository and commits it, returning the hash
+func commitFile(t *testing.T, repoDir, content string) string {
+	t.Helper()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); os.IsNotExist(err) {
+		run("init", "-b", "main")
+		run("config", "user.email", "test@example.com")
+		run("config", "user.name", "Test User")
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "file.txt"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	run("add", "file.txt")
+	run("commit", "-m", content)
+	return run("rev-parse", "HEAD")
+}
+
+func readTree(t *testing.T, treeDir string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(treeDir, "file.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read cached tree: %v", err)
+	}
+	return string(content)
+}
+
+func TestService_Checkout(t *testing.T) {
+	service := newTestService(t)
+	repoDir := t.TempDir()
+	first := commitFile(t, repoDir, "one")
+	second := commitFile(t, repoDir, "two")
+	url := "file://" + repoDir
+
+	// A pinned commit is cloned once and then served from the cache
+	tree, commit, err := service.Checkout(context.Background(), git.CloneOptions{URL: url, Branch: "main", Commit: first})
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if commit != first || readTree(t, tree) != "one" {
+		t.Errorf("Checkout() = %s at %s, want file 'one' at %s", tree, commit, first)
+	}
+	if !strings.HasPrefix(tree, service.Dir()) {
+		t.Errorf("Checkout() tree %s is outside the cache %s", tree, service.Dir())
+	}
+
+	again, _, err := service.Checkout(context.Background(), git.CloneOptions{URL: url, Commit: first[:10]})
+	if err != nil || again != tree {
+		t.Errorf("Second Checkout() = %s, %v; want cached %s", again, err, tree)
+	}
+
+	// A branch head is fetched, picking up new commits
+	head, commit, err := service.Checkout(context.Background(), git.CloneOptions{URL: url, Branch: "main"})
+	if err != nil {
+		t.Fatalf("Checkout() of branch head error = %v", err)
+	}
+	if commit != second || readTree(t, head) != "two" {
+		t.Errorf("Checkout() of branch head = %s, want %s", commit, second)
+	}
+
+	third := commitFile(t, repoDir, "three")
+	if _, commit, err = service.Checkout(context.Background(), git.CloneOptions{URL: url, Branch: "main"}); err != nil || commit != third {
+		t.Errorf("Checkout() after a new commit = %s, %v; want %s", commit, err, third)
+	}
+
+	// A pinned commit missing from the cache is fetched
+	fourth := commitFile(t, repoDir, "four")
+	if tree, _, err := service.Checkout(context.Background(), git.CloneOptions{URL: url, Commit: fourth}); err != nil || readTree(t, tree) != "four" {
+		t.Errorf("Checkout() of an uncached commit error = %v", err)
+	}
+
+	if _, _, err := service.Checkout(context.Background(), git.CloneOptions{URL: url, Commit: strings.Repeat("0", 40)}); err == nil {
+		t.Error("Expected an error for a commit the repository does not have")
+	}
+
+	// A tag can be given in place of a branch
+	tag := exec.Command("git", "tag", "v1", first)
+	tag.Dir = repoDir
+	if output, err := tag.CombinedOutput(); err != nil {
+		t.Fatalf("git tag failed: %v\n%s", err, output)
+	}
+	if tree, commit, err := service.Checkout(context.Background(), git.CloneOptions{URL: url, Branch: "v1"}); err != nil || commit != first || readTree(t, tree) != "one" {
+		t.Errorf("Checkout() of a tag = %s, %v; want %s", commit, err, first)
+	}
+}
+
+func TestService_CheckoutCached(t *testing.T) {
+	service := newTestService(t)
+	repoDir := t.TempDir()
+	first := commitFile(t, repoDir, "one")
+	url := "file://" + repoDir
+
+	if _, _, err := service.CheckoutCached(git.CloneOptions{URL: url, Commit: first}); !models.IsErrorCode(err, models.ErrorCodeNotCached) {
+		t.Fatalf("CheckoutCached() of an uncached repository = %v, want %s", err, models.ErrorCodeNotCached)
+	}
+
+	if _, _, err := service.Checkout(context.Background(), git.CloneOptions{URL: url, Branch: "main"}); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	second := commitFile(t, repoDir, "two")
+
+	// A cached commit is served, even when only its tree has to be checked out
+	if tree, commit, err := service.CheckoutCached(git.CloneOptions{URL: url, Commit: first}); err != nil || commit != first || readTree(t, tree) != "one" {
+		t.Errorf("CheckoutCached() = %s, %v; want %s", commit, err, first)
+	}
+
+	// The branch head stays at the last fetch, and newer commits are not fetched
+	if _, commit, err := service.CheckoutCached(git.CloneOptions{URL: url, Branch: "main"}); err != nil || commit != first {
+		t.Errorf("CheckoutCached() of branch head = %s, %v; want the cached %s", commit, err, first)
+	}
+	if _, _, err := service.CheckoutCached(git.CloneOptions{URL: url, Commit: second}); !models.IsErrorCode(err, models.ErrorCodeNotCached) {
+		t.Errorf("CheckoutCached() of an uncached commit = %v, want %s", err, models.ErrorCodeNotCached)
+	}
+}
+
+func TestService_Refresh(t *testing.T) {
+	service := newTestService(t)
+	repoDir := t.TempDir()
+	first := commitFile(t, repoDir, "one")
+	url := "file://" + repoDir
+
+	if cloned, err := service.Refresh(context.Background(), url, git.RetryOptions{}); err != nil || !cloned {
+		t.Fatalf("Refresh() of an uncached repository = %v, %v; want a clone", cloned, err)
+	}
+	if _, commit, err := service.CheckoutCached(git.CloneOptions{URL: url, Branch: "main"}); err != nil || commit != first {
+		t.Errorf("CheckoutCached() after Refresh() = %s, %v; want %s", commit, err, first)
+	}
+
+	// One clone serves every branch, and a refresh picks up new ones
+	branch := exec.Command("git", "checkout", "-b", "other")
+	branch.Dir = repoDir
+	if output, err := branch.CombinedOutput(); err != nil {
+		t.Fatalf("git checkout failed: %v\n%s", err, output)
+	}
+	second := commitFile(t, repoDir, "two")
+
+	if cloned, err := service.Refresh(context.Background(), url, git.RetryOptions{}); err != nil || cloned {
+		t.Fatalf("Refresh() of a cached repository = %v, %v; want a fetch", cloned, err)
+	}
+	if tree, commit, err := service.CheckoutCached(git.CloneOptions{URL: url, Branch: "other"}); err != nil || commit != second || readTree(t, tree) != "two" {
+		t.Errorf("CheckoutCached() of a new branch = %s, %v; want %s", commit, err, second)
+	}
+	if _, commit, err := service.CheckoutCached(git.CloneOptions{URL: url, Branch: "main"}); err != nil || commit != first {
+		t.Errorf("CheckoutCached() of main = %s, %v; want %s", commit, err, first)
+	}
+}
+
+func TestService_Clean(t *testing.T) {
+	service := newTestService(t)
+	repoDir := t.TempDir()
+	commit := commitFile(t, repoDir, "one")
+
+	if _, _, err := service.Checkout(context.Background(), git.CloneOptions{URL: "file://" + repoDir, Commit: commit}); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	freed, err := service.Clean()
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if freed == 0 {
+		t.Error("Expected Clean() to report the space it freed")
+	}
+	if _, err := os.Stat(service.Dir()); !os.IsNotExist(err) {
+		t.Errorf("Expected the cache directory to be removed, got %v", err)
+	}
+
+	// Cleaning an empty cache is not an error
+	if freed, err := service.Clean(); err != nil || freed != 0 {
+		t.Errorf("Clean() of an empty cache = %d, %v", freed, err)
+	}
+}
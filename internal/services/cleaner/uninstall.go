@@ -0,0 +1,301 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// UninstallResult reports what an uninstall removed and kept, with paths
+// relative to the target directory
+type UninstallResult struct {
+	// Installed files and symlinks that were deleted
+	Removed []string `json:"removed"`
+
+	// Installed files changed since installation; kept unless forced
+	Modified []string `json:"modified"`
+
+	// Recorded files that were already gone
+	Missing []string `json:"missing"`
+
+	// Directories removed because uninstalling left them empty
+	PrunedDirectories []string `json:"pruned_directories"`
+
+	// Whether strategic hooks were removed from settings.json
+	CleanedSettings bool `json:"cleaned_settings"`
+
+	// Whether the lock file was removed; it is kept while modified files remain
+	RemovedLock bool `json:"removed_lock"`
+
+	// Template taken out of the lock when uninstalling one of several; it stays
+	// recorded, with only its kept files, while modified files remain
+	RemovedTemplate string `json:"removed_template,omitempty"`
+
+	// Files another installed template also ships, left in place and now
+	// recorded under that template
+	HandedOff []state.FileHandoff `json:"handed_off,omitempty"`
+
+	// Issues encountered
+	Warnings []string `json:"warnings"`
+}
+
+// Uninstall deletes exactly the files recorded in the lock file's manifests,
+// for every installed template, and prunes the directories this leaves empty.
+// Files modified since installation are kept unless force is set; while any
+// remain, the lock file is kept too so the uninstall can be finished later.
+func (s *Service) Uninstall(targetDir string, force bool) (*UninstallResult, error) {
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		return nil, models.NewAppError(models.ErrorCodeFileSystemError, "Failed to read lock file", err)
+	}
+	if lock == nil {
+		return nil, models.NewAppError(
+			models.ErrorCodeNotInstalled,
+			fmt.Sprintf("No lock file found in %s; use clean to remove an installation the CLI did not record", targetDir),
+			nil,
+		)
+	}
+	files := lock.AllFiles()
+	if len(files) == 0 {
+		return nil, models.NewAppError(
+			models.ErrorCodeNotInstalled,
+			"The lock file has no record of installed files (installed by an older version); use clean instead",
+			nil,
+		)
+	}
+
+	result := &UninstallResult{}
+	removedDirs := make(map[string]struct{})
+
+	if _, err := removeRecords(targetDir, files, force, result, removedDirs); err != nil {
+		return result, err
+	}
+
+	kept := len(result.Modified) > 0 && !force
+	if !kept {
+		for _, name := range []string{config.TemplateInfoFile, config.LockFileName} {
+			path := filepath.Join(targetDir, config.StrategicClaudeBasicDir, name)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return result, models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+			}
+			removedDirs[filepath.Dir(path)] = struct{}{}
+		}
+		result.RemovedLock = true
+
+		// History kept by --keep-git is not in any manifest but goes with the install
+		for _, entry := range lock.Templates {
+			if !entry.KeepGit {
+				continue
+			}
+			gitDir := filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.KeptGitDir)
+			if err := os.RemoveAll(gitDir); err != nil {
+				return result, models.NewFileSystemError(models.ErrorCodeFileSystemError, gitDir, err)
+			}
+			removedDirs[filepath.Dir(gitDir)] = struct{}{}
+			break
+		}
+
+		// The installer merged strategic hooks into settings.json; take them back out
+		settingsPath := filepath.Join(targetDir, config.ClaudeDir, config.ClaudeSettingsFile)
+		if _, err := os.Stat(settingsPath); err == nil {
+			if err := s.settingsService.CleanSettings(targetDir); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to clean settings.json: %v", err))
+			} else {
+				result.CleanedSettings = true
+				removedDirs[filepath.Dir(settingsPath)] = struct{}{}
+			}
+		}
+	}
+
+	pruned, err := pruneEmptyDirectories(targetDir, removedDirs)
+	result.PrunedDirectories = pruned
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// UninstallTemplate removes one of several layered templates: the files only
+// it installed are deleted as Uninstall would, while files another installed
+// template also ships stay in place and are recorded under the last of those.
+// The framework itself, settings.json hooks included, stays for the templates
+// that remain. Uninstalling the only installed template is a full Uninstall.
+func (s *Service) UninstallTemplate(targetDir, templateID string, force bool) (*UninstallResult, error) {
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		return nil, models.NewAppError(models.ErrorCodeFileSystemError, "Failed to read lock file", err)
+	}
+	if lock == nil {
+		return nil, models.NewAppError(
+			models.ErrorCodeNotInstalled,
+			fmt.Sprintf("No lock file found in %s; use clean to remove an installation the CLI did not record", targetDir),
+			nil,
+		)
+	}
+	index := lock.Index(templateID)
+	if index < 0 {
+		return nil, models.NewAppError(
+			models.ErrorCodeNotInstalled,
+			fmt.Sprintf("Template '%s' is not installed in %s; installed: %s", templateID, targetDir, strings.Join(lock.TemplateIDs(), ", ")),
+			nil,
+		)
+	}
+	if len(lock.Templates) == 1 {
+		return s.Uninstall(targetDir, force)
+	}
+	if len(lock.Templates[index].Files) == 0 {
+		return nil, models.NewAppError(
+			models.ErrorCodeNotInstalled,
+			fmt.Sprintf("The lock file has no record of the files template '%s' installed (installed by an older version)", templateID),
+			nil,
+		)
+	}
+
+	removed, handoffs := lock.Remove(templateID)
+	result := &UninstallResult{HandedOff: handoffs}
+	removedDirs := make(map[string]struct{})
+
+	kept, err := removeRecords(targetDir, removed.Files, force, result, removedDirs)
+	if err != nil {
+		return result, err
+	}
+
+	if len(kept) > 0 {
+		// Still recorded, in its old place, so the uninstall can be finished later
+		removed.Files = kept
+		lock.Templates = slices.Insert(lock.Templates, index, *removed)
+	} else {
+		result.RemovedTemplate = templateID
+		if index == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"%s still describes '%s'; it is rewritten the next time a template is installed without --add",
+				config.TemplateInfoFile, templateID))
+		}
+	}
+	if len(handoffs) > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"Files left to other templates keep the content '%s' installed; run 'update --force' to restore their own copies", templateID))
+	}
+
+	if err := state.WriteLock(targetDir, lock); err != nil {
+		return result, models.NewAppError(models.ErrorCodeFileSystemError, "Failed to write lock file", err)
+	}
+
+	pruned, err := pruneEmptyDirectories(targetDir, removedDirs)
+	result.PrunedDirectories = pruned
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// removeRecords deletes the recorded files that are still installed, adding
+// them to result and their directories to removedDirs. Modified files are only
+// deleted when forced; the records of those kept are returned.
+func removeRecords(targetDir string, records []state.FileRecord, force bool, result *UninstallResult, removedDirs map[string]struct{}) ([]state.FileRecord, error) {
+	var kept []state.FileRecord
+	for _, record := range records {
+		path := filepath.Join(targetDir, filepath.FromSlash(record.Path))
+
+		modified, err := record.Modified(path)
+		if os.IsNotExist(err) {
+			result.Missing = append(result.Missing, record.Path)
+			continue
+		}
+		if err != nil {
+			return kept, models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+		}
+
+		if modified {
+			result.Modified = append(result.Modified, record.Path)
+			if !force {
+				kept = append(kept, record)
+				continue
+			}
+		}
+
+		if err := removeRecorded(path); err != nil {
+			if modified {
+				// A forced removal can still meet a path that became a directory
+				result.Warnings = append(result.Warnings, err.Error())
+				continue
+			}
+			return kept, err
+		}
+		result.Removed = append(result.Removed, record.Path)
+		removedDirs[filepath.Dir(path)] = struct{}{}
+	}
+	return kept, nil
+}
+
+// removeRecorded deletes one installed file or symlink. Directories are never
+// removed here, even if one now sits where a file was installed.
+func removeRecorded(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+	if info.IsDir() {
+		return models.NewAppError(
+			models.ErrorCodeFileSystemError,
+			fmt.Sprintf("Not removing %s: it is now a directory", path),
+			nil,
+		)
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsPermission(err) {
+			return models.NewFileSystemError(models.ErrorCodePermissionDenied, path, err)
+		}
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+	return nil
+}
+
+// pruneEmptyDirectories removes each of dirs, and then its parents, while they
+// are empty, stopping at targetDir. It returns the removed directories relative
+// to targetDir, deepest first.
+func pruneEmptyDirectories(targetDir string, dirs map[string]struct{}) ([]string, error) {
+	candidates := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		candidates = append(candidates, dir)
+	}
+	// Deepest first, so children are removed before their parents are checked
+	sort.Slice(candidates, func(i, j int) bool {
+		return strings.Count(candidates[i], string(filepath.Separator)) > strings.Count(candidates[j], string(filepath.Separator))
+	})
+
+	var pruned []string
+	targetDir = filepath.Clean(targetDir)
+	for _, dir := range candidates {
+		for dir != targetDir && strings.HasPrefix(dir, targetDir+string(filepath.Separator)) {
+			entries, err := os.ReadDir(dir)
+			if os.IsNotExist(err) {
+				dir = filepath.Dir(dir)
+				continue // Already pruned through another path
+			}
+			if err != nil {
+				return pruned, models.NewFileSystemError(models.ErrorCodeFileSystemError, dir, err)
+			}
+			if len(entries) > 0 {
+				break
+			}
+			if err := os.Remove(dir); err != nil {
+				return pruned, models.NewFileSystemError(models.ErrorCodeFileSystemError, dir, err)
+			}
+			rel, _ := filepath.Rel(targetDir, dir)
+			pruned = append(pruned, rel)
+			dir = filepath.Dir(dir)
+		}
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,287 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// setupRecordedInstallation writes an installation with a manifest: an untouched
+// file, a file edited after installing, a framework symlink, and a user file
+// the manifest does not know about
+func setupRecordedInstallation(t *testing.T) string {
+	t.Helper()
+	targetDir := t.TempDir()
+	strategic := config.StrategicClaudeBasicDir
+
+	write := func(rel, content string) {
+		path := filepath.Join(targetDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", rel, err)
+		}
+	}
+	hash := func(rel string) string {
+		sum, err := state.HashFile(filepath.Join(targetDir, rel))
+		if err != nil {
+			t.Fatalf("HashFile() error = %v", err)
+		}
+		return sum
+	}
+
+	write(strategic+"/core/README.md", "# Core\n")
+	write(strategic+"/core/commands/plan.md", "# Plan\n")
+	planHash := hash(strategic + "/core/commands/plan.md")
+	write(strategic+"/core/commands/plan.md", "# Plan, edited\n")
+	write(strategic+"/thoughts/notes.md", "my notes\n")
+	write(strategic+"/"+config.TemplateInfoFile, "{}\n")
+
+	link := filepath.Join(targetDir, config.ClaudeDir, "commands", "strategic")
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		t.Fatalf("Failed to create .claude/commands: %v", err)
+	}
+	linkTarget := "../../" + strategic + "/core/commands"
+	if err := os.Symlink(linkTarget, link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	lock := &state.Lock{Templates: []state.TemplateLock{
+		{
+			TemplateID: "main",
+			Files: []state.FileRecord{
+				{Path: config.ClaudeDir + "/commands/strategic", Link: linkTarget},
+				{Path: strategic + "/core/README.md", SHA256: hash(strategic + "/core/README.md")},
+				{Path: strategic + "/core/gone.md", SHA256: planHash},
+			},
+		},
+		{
+			// A layered template's files are removed too
+			TemplateID: "ccr",
+			Files: []state.FileRecord{
+				{Path: strategic + "/core/commands/plan.md", SHA256: planHash},
+			},
+		},
+	}}
+	if err := state.WriteLock(targetDir, lock); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+	return targetDir
+}
+
+func TestUninstall(t *testing.T) {
+	strategic := config.StrategicClaudeBasicDir
+
+	tests := []struct {
+		name        string
+		force       bool
+		wantRemoved int
+		wantExists  map[string]bool
+	}{
+		{
+			name:        "keeps modified files and the lock",
+			wantRemoved: 2,
+			wantExists: map[string]bool{
+				strategic + "/core/README.md":         false,
+				strategic + "/core/commands/plan.md":  true,
+				strategic + "/thoughts/notes.md":      true,
+				strategic + "/" + config.LockFileName: true,
+				config.ClaudeDir:                      false,
+			},
+		},
+		{
+			name:        "force removes modified files",
+			force:       true,
+			wantRemoved: 3,
+			wantExists: map[string]bool{
+				strategic + "/core":                       false,
+				strategic + "/thoughts/notes.md":          true,
+				strategic + "/" + config.LockFileName:     false,
+				strategic + "/" + config.TemplateInfoFile: false,
+				config.ClaudeDir + "/commands/strategic":  false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetDir := setupRecordedInstallation(t)
+
+			result, err := New().Uninstall(targetDir, tt.force)
+			if err != nil {
+				t.Fatalf("Uninstall() error = %v", err)
+			}
+
+			if len(result.Removed) != tt.wantRemoved {
+				t.Errorf("Removed = %v, want %d entries", result.Removed, tt.wantRemoved)
+			}
+			if len(result.Modified) != 1 || result.Modified[0] != strategic+"/core/commands/plan.md" {
+				t.Errorf("Modified = %v, want the edited plan.md", result.Modified)
+			}
+			if len(result.Missing) != 1 {
+				t.Errorf("Missing = %v, want the already deleted file", result.Missing)
+			}
+			if result.RemovedLock != tt.force {
+				t.Errorf("RemovedLock = %v, want %v", result.RemovedLock, tt.force)
+			}
+
+			for rel, want := range tt.wantExists {
+				_, err := os.Lstat(filepath.Join(targetDir, rel))
+				if got := err == nil; got != want {
+					t.Errorf("%s exists = %v, want %v", rel, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestUninstall_NoManifest(t *testing.T) {
+	targetDir := t.TempDir()
+	if _, err := New().Uninstall(targetDir, false); !models.IsErrorCode(err, models.ErrorCodeNotInstalled) {
+		t.Errorf("Uninstall() without a lock error = %v, want not installed", err)
+	}
+
+	if err := state.WriteLock(targetDir, &state.Lock{Templates: []state.TemplateLock{{TemplateID: "main"}}}); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+	if _, err := New().Uninstall(targetDir, false); !models.IsErrorCode(err, models.ErrorCodeNotInstalled) {
+		t.Errorf("Uninstall() with a lock lacking files error = %v, want not installed", err)
+	}
+}
+
+func TestUninstallTemplate(t *testing.T) {
+	strategic := config.StrategicClaudeBasicDir
+	commands := strategic + "/core/commands/"
+
+	setup := func(t *testing.T) string {
+		t.Helper()
+		targetDir := t.TempDir()
+		records := make(map[string]state.FileRecord)
+		for rel, content := range map[string]string{
+			commands + "base.md":   "base\n",
+			commands + "plan.md":   "layer\n",
+			commands + "layer.md":  "layer\n",
+			commands + "edited.md": "layer\n",
+		} {
+			path := filepath.Join(targetDir, rel)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", rel, err)
+			}
+			sum, err := state.HashFile(path)
+			if err != nil {
+				t.Fatalf("HashFile() error = %v", err)
+			}
+			records[rel] = state.FileRecord{Path: rel, SHA256: sum}
+		}
+		if err := os.WriteFile(filepath.Join(targetDir, commands+"edited.md"), []byte("mine\n"), 0644); err != nil {
+			t.Fatalf("Failed to edit file: %v", err)
+		}
+
+		shared := records[commands+"plan.md"]
+		shared.SharedWith = []string{"main"}
+		lock := &state.Lock{Templates: []state.TemplateLock{
+			{TemplateID: "main", Files: []state.FileRecord{records[commands+"base.md"]}},
+			{TemplateID: "ccr", Files: []state.FileRecord{
+				records[commands+"edited.md"],
+				records[commands+"layer.md"],
+				shared,
+			}},
+		}}
+		if err := state.WriteLock(targetDir, lock); err != nil {
+			t.Fatalf("WriteLock() error = %v", err)
+		}
+		return targetDir
+	}
+
+	tests := []struct {
+		name         string
+		force        bool
+		wantRemoved  int
+		wantTemplate string
+		wantLock     string
+		wantExists   map[string]bool
+	}{
+		{
+			name:        "keeps modified files recorded under the template",
+			wantRemoved: 1,
+			wantLock:    "main,ccr",
+			wantExists: map[string]bool{
+				commands + "base.md":   true,
+				commands + "plan.md":   true,
+				commands + "layer.md":  false,
+				commands + "edited.md": true,
+			},
+		},
+		{
+			name:         "force removes the template from the lock",
+			force:        true,
+			wantRemoved:  2,
+			wantTemplate: "ccr",
+			wantLock:     "main",
+			wantExists: map[string]bool{
+				commands + "base.md":   true,
+				commands + "plan.md":   true,
+				commands + "layer.md":  false,
+				commands + "edited.md": false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetDir := setup(t)
+
+			result, err := New().UninstallTemplate(targetDir, "ccr", tt.force)
+			if err != nil {
+				t.Fatalf("UninstallTemplate() error = %v", err)
+			}
+
+			if len(result.Removed) != tt.wantRemoved {
+				t.Errorf("Removed = %v, want %d entries", result.Removed, tt.wantRemoved)
+			}
+			if result.RemovedTemplate != tt.wantTemplate || result.RemovedLock {
+				t.Errorf("RemovedTemplate = %q, RemovedLock = %v; want %q and false", result.RemovedTemplate, result.RemovedLock, tt.wantTemplate)
+			}
+			want := state.FileHandoff{Path: commands + "plan.md", TemplateID: "main"}
+			if len(result.HandedOff) != 1 || result.HandedOff[0] != want {
+				t.Errorf("HandedOff = %+v, want plan.md to main", result.HandedOff)
+			}
+
+			for rel, want := range tt.wantExists {
+				_, err := os.Lstat(filepath.Join(targetDir, rel))
+				if got := err == nil; got != want {
+					t.Errorf("%s exists = %v, want %v", rel, got, want)
+				}
+			}
+
+			lock, err := state.ReadLock(targetDir)
+			if err != nil || lock == nil {
+				t.Fatalf("ReadLock() = %v, %v", lock, err)
+			}
+			if got := strings.Join(lock.TemplateIDs(), ","); got != tt.wantLock {
+				t.Errorf("Lock templates = %s, want %s", got, tt.wantLock)
+			}
+			if main := lock.Find("main"); len(main.Files) != 2 || main.Files[1].Path != commands+"plan.md" {
+				t.Errorf("main files = %+v, want base.md and the handed off plan.md", main.Files)
+			}
+		})
+	}
+}
+
+func TestUninstallTemplate_NotInstalled(t *testing.T) {
+	targetDir := setupRecordedInstallation(t)
+
+	_, err := New().UninstallTemplate(targetDir, "missing", false)
+	if !models.IsErrorCode(err, models.ErrorCodeNotInstalled) || !strings.Contains(err.Error(), "installed: main, ccr") {
+		t.Errorf("UninstallTemplate(missing) error = %v, want not installed listing the templates", err)
+	}
+}
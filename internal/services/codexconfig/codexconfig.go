@@ -2,6 +2,7 @@ package codexconfig
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
@@ -104,7 +105,7 @@ func (s *Service) RemoveCodexConfig(targetDir string) error {
 	for _, backupFile := range matches {
 		if err := os.Remove(backupFile); err != nil {
 			// Log warning but continue
-			fmt.Printf("Warning: Failed to remove backup file %s: %v\n", backupFile, err)
+			slog.Warn("Failed to remove backup file", "path", backupFile, "error", err)
 		}
 	}
 
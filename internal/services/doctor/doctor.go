@@ -0,0 +1,186 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// Check is the outcome of one environment check
+type Check struct {
+	Name   string
+	Passed bool
+	Detail string // What was found
+	Hint   string // How to fix a failed check
+}
+
+// Report collects the results of every check
+type Report struct {
+	Checks []Check
+}
+
+// Failed returns the number of checks that did not pass
+func (r *Report) Failed() int {
+	failed := 0
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Service checks that the environment can install templates
+type Service struct {
+	gitService *git.Service
+	repoURL    string // Repository probed for network reachability
+}
+
+// New creates a new doctor service instance
+func New() *Service {
+	return &Service{
+		gitService: git.New(),
+		repoURL:    config.DefaultRepoURL,
+	}
+}
+
+// Run performs every check against targetDir and returns the results in display order
+func (s *Service) Run(targetDir string) *Report {
+	report := &Report{}
+
+	gitCheck := s.checkGit()
+	report.Checks = append(report.Checks, gitCheck)
+
+	if gitCheck.Passed {
+		report.Checks = append(report.Checks, s.checkNetwork())
+	} else {
+		report.Checks = append(report.Checks, Check{
+			Name:   "Network",
+			Detail: fmt.Sprintf("skipped reaching %s because git is unavailable", s.repoURL),
+			Hint:   "Install git, then run doctor again",
+		})
+	}
+
+	report.Checks = append(report.Checks, s.checkRegistry()...)
+	report.Checks = append(report.Checks, s.checkWritable(targetDir))
+
+	return report
+}
+
+// checkGit verifies git is on PATH and reports its version
+func (s *Service) checkGit() Check {
+	check := Check{Name: "Git"}
+
+	if err := s.gitService.ValidateGitInstalled(); err != nil {
+		check.Detail = "git not found in PATH"
+		check.Hint = "Install git (https://git-scm.com/downloads) and make sure it is on your PATH"
+		return check
+	}
+
+	version, err := s.gitService.Version()
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = "Check that the git binary on your PATH runs"
+		return check
+	}
+
+	check.Passed = true
+	check.Detail = "git " + version
+	return check
+}
+
+// checkNetwork verifies the default template repository can be reached
+func (s *Service) checkNetwork() Check {
+	check := Check{Name: "Network"}
+
+	if err := s.gitService.CheckRemote(s.repoURL); err != nil {
+		check.Detail = err.Error()
+		check.Hint = "Check your internet connection, proxy settings (HTTPS_PROXY), and git credentials"
+		return check
+	}
+
+	check.Passed = true
+	check.Detail = "reached " + s.repoURL
+	return check
+}
+
+// checkRegistry validates every registered template, one check per template
+func (s *Service) checkRegistry() []Check {
+	all := templates.ListTemplates()
+	if len(all) == 0 {
+		return []Check{{
+			Name:   "Template registry",
+			Detail: "no templates registered",
+			Hint:   "Check the --registry file, or remove it to use the built-in templates",
+		}}
+	}
+
+	checks := make([]Check, 0, len(all))
+	for _, template := range all {
+		check := Check{Name: fmt.Sprintf("Template %s", template.ID)}
+		if err := template.IsValid(); err != nil {
+			check.Detail = err.Error()
+			check.Hint = "Fix this entry in your template registry file"
+		} else {
+			check.Passed = true
+			check.Detail = template.RepoURL
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkWritable verifies files can be created in targetDir. A directory that
+// does not exist yet is checked through its closest existing parent, since
+// init creates it.
+func (s *Service) checkWritable(targetDir string) Check {
+	check := Check{Name: "Target directory"}
+
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = "Pass a valid directory path"
+		return check
+	}
+
+	dir := absTarget
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				check.Detail = fmt.Sprintf("%s is not a directory", dir)
+				check.Hint = "Choose a directory as the install target"
+				return check
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			check.Detail = fmt.Sprintf("no existing parent for %s", absTarget)
+			check.Hint = "Pass a valid directory path"
+			return check
+		}
+		dir = parent
+	}
+
+	probe, err := os.CreateTemp(dir, ".strategic-claude-doctor-")
+	if err != nil {
+		check.Detail = fmt.Sprintf("cannot write to %s: %v", dir, err)
+		check.Hint = "Fix the directory permissions or choose another target"
+		return check
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	check.Passed = true
+	if dir == absTarget {
+		check.Detail = fmt.Sprintf("%s is writable", absTarget)
+	} else {
+		check.Detail = fmt.Sprintf("%s does not exist yet; %s is writable", absTarget, dir)
+	}
+	return check
+}
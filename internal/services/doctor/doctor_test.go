@@ -0,0 +1,96 @@
+package doctor
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// initRemote creates an empty local git repository to probe instead of the network
+func initRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func TestService_Run(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	remote := initRemote(t)
+	valid := templates.Template{ID: "local", Name: "Local", RepoURL: t.TempDir()}
+
+	tests := []struct {
+		name       string
+		registry   map[string]templates.Template
+		repoURL    string
+		target     func(t *testing.T) string
+		wantFailed []string
+	}{
+		{
+			name:     "healthy environment",
+			registry: map[string]templates.Template{"local": valid},
+			repoURL:  remote,
+			target:   func(t *testing.T) string { return t.TempDir() },
+		},
+		{
+			name:     "missing target is checked through its parent",
+			registry: map[string]templates.Template{"local": valid},
+			repoURL:  remote,
+			target:   func(t *testing.T) string { return filepath.Join(t.TempDir(), "new", "project") },
+		},
+		{
+			name: "invalid registry entry",
+			registry: map[string]templates.Template{
+				"local":  valid,
+				"broken": {ID: "broken", Name: "Broken"},
+			},
+			repoURL:    remote,
+			target:     func(t *testing.T) string { return t.TempDir() },
+			wantFailed: []string{"Template broken"},
+		},
+		{
+			name:       "unreachable repository",
+			registry:   map[string]templates.Template{"local": valid},
+			repoURL:    filepath.Join(t.TempDir(), "missing"),
+			target:     func(t *testing.T) string { return t.TempDir() },
+			wantFailed: []string{"Network"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates.Registry.Set(tt.registry)
+			service := New()
+			service.repoURL = tt.repoURL
+
+			report := service.Run(tt.target(t))
+
+			var failed []string
+			for _, check := range report.Checks {
+				if !check.Passed {
+					failed = append(failed, check.Name)
+					if check.Hint == "" {
+						t.Errorf("Failed check %q has no hint", check.Name)
+					}
+				}
+			}
+
+			if strings.Join(failed, ",") != strings.Join(tt.wantFailed, ",") {
+				t.Errorf("Failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+			if report.Failed() != len(tt.wantFailed) {
+				t.Errorf("Failed() = %d, want %d", report.Failed(), len(tt.wantFailed))
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package filesystem
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// ExcludeMatcher matches paths against gitignore-style exclusion patterns.
+// Paths are relative to the template repository root and use either separator.
+//
+// Supported syntax:
+//   - a pattern without a slash (README.md, *.log) matches a name at any depth
+//   - a leading slash (/README.md) or a slash in the middle (docs/*.md) anchors
+//     the pattern to the repository root
+//   - a trailing slash (.github/) matches directories only
+//   - ** matches any number of directories (**/fixtures, docs/**)
+//   - a leading ! re-includes a path excluded by an earlier pattern
+//   - blank lines and lines starting with # are ignored
+//
+// As in gitignore, the last matching pattern wins, and nothing inside an
+// excluded directory can be re-included.
+type ExcludeMatcher struct {
+	rules []excludeRule
+}
+
+// excludeRule is one parsed pattern
+type excludeRule struct {
+	segments []string
+	anchored bool
+	dirOnly  bool
+	negate   bool
+}
+
+// NewExcludeMatcher parses patterns into a matcher
+func NewExcludeMatcher(patterns []string) (*ExcludeMatcher, error) {
+	matcher := &ExcludeMatcher{}
+
+	for _, raw := range patterns {
+		pattern := strings.TrimSpace(raw)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		rule := excludeRule{}
+		if negated, ok := strings.CutPrefix(pattern, "!"); ok {
+			rule.negate = true
+			pattern = negated
+		}
+		if trimmed, ok := strings.CutSuffix(pattern, "/"); ok {
+			rule.dirOnly = true
+			pattern = trimmed
+		}
+		if trimmed, ok := strings.CutPrefix(pattern, "/"); ok {
+			rule.anchored = true
+			pattern = trimmed
+		}
+		if strings.Contains(pattern, "/") {
+			rule.anchored = true
+		}
+
+		if pattern == "" {
+			return nil, models.NewAppError(
+				models.ErrorCodeValidationFailed,
+				fmt.Sprintf("Invalid exclude pattern '%s': pattern is empty", raw),
+				nil,
+			)
+		}
+
+		rule.segments = strings.Split(pattern, "/")
+		for _, segment := range rule.segments {
+			if segment == "**" {
+				continue
+			}
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, models.NewAppError(
+					models.ErrorCodeValidationFailed,
+					fmt.Sprintf("Invalid exclude pattern '%s'", raw),
+					err,
+				)
+			}
+		}
+
+		matcher.rules = append(matcher.rules, rule)
+	}
+
+	return matcher, nil
+}
+
+// Match reports whether rel, or any directory containing it, is excluded.
+// isDir tells whether rel itself is a directory.
+func (m *ExcludeMatcher) Match(rel string, isDir bool) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+
+	parts := strings.Split(strings.Trim(filepath.ToSlash(filepath.Clean(rel)), "/"), "/")
+	for i := 1; i <= len(parts); i++ {
+		if m.matchLevel(parts[:i], i < len(parts) || isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLevel applies every rule to one path, the last match deciding
+func (m *ExcludeMatcher) matchLevel(parts []string, isDir bool) bool {
+	excluded := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.matches(parts) {
+			excluded = !rule.negate
+		}
+	}
+	return excluded
+}
+
+// matches reports whether the rule's pattern matches the path
+func (r excludeRule) matches(parts []string) bool {
+	if !r.anchored {
+		ok, _ := path.Match(r.segments[0], parts[len(parts)-1])
+		return ok
+	}
+	return matchSegments(r.segments, parts)
+}
+
+// matchSegments matches pattern segments against path segments, letting **
+// stand for zero or more directories
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
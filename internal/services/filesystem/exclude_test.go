@@ -0,0 +1,94 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+func TestExcludeMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{name: "name at root", patterns: []string{"README.md"}, path: "README.md", want: true},
+		{name: "name at any depth", patterns: []string{"README.md"}, path: "docs/guide/README.md", want: true},
+		{name: "anchored name at root", patterns: []string{"/README.md"}, path: "README.md", want: true},
+		{name: "anchored name not nested", patterns: []string{"/README.md"}, path: "docs/README.md", want: false},
+		{name: "glob", patterns: []string{"*.log"}, path: "logs/debug.log", want: true},
+		{name: "directory pattern", patterns: []string{".github/"}, path: ".github", isDir: true, want: true},
+		{name: "directory pattern skips file", patterns: []string{".github/"}, path: ".github", want: false},
+		{name: "inside excluded directory", patterns: []string{".github/"}, path: ".github/workflows/ci.yml", want: true},
+		{name: "slash anchors pattern", patterns: []string{"docs/*.md"}, path: "docs/intro.md", want: true},
+		{name: "slash anchored not nested", patterns: []string{"docs/*.md"}, path: "other/docs/intro.md", want: false},
+		{name: "leading double star", patterns: []string{"**/fixtures"}, path: "a/b/fixtures/data.json", want: true},
+		{name: "trailing double star", patterns: []string{"examples/**"}, path: "examples/one/main.go", want: true},
+		{name: "middle double star", patterns: []string{"a/**/z.md"}, path: "a/z.md", want: true},
+		{name: "negation re-includes", patterns: []string{"*.md", "!keep.md"}, path: "keep.md", want: false},
+		{name: "last match wins", patterns: []string{"!keep.md", "*.md"}, path: "keep.md", want: true},
+		{name: "negation cannot re-include inside excluded directory", patterns: []string{"docs/", "!docs/keep.md"}, path: "docs/keep.md", want: true},
+		{name: "comments and blanks ignored", patterns: []string{"# README.md", "  "}, path: "README.md", want: false},
+		{name: "windows separators", patterns: []string{"/docs/"}, path: filepath.Join("docs", "a.md"), want: true},
+		{name: "no patterns", path: "README.md", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := NewExcludeMatcher(tt.patterns)
+			if err != nil {
+				t.Fatalf("NewExcludeMatcher() error = %v", err)
+			}
+			if got := matcher.Match(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewExcludeMatcher_InvalidPattern(t *testing.T) {
+	for _, pattern := range []string{"[", "/", "!"} {
+		if _, err := NewExcludeMatcher([]string{pattern}); !models.IsErrorCode(err, models.ErrorCodeValidationFailed) {
+			t.Errorf("NewExcludeMatcher(%q) error = %v, want validation error", pattern, err)
+		}
+	}
+}
+
+func TestService_CopyDirectoryFiltered(t *testing.T) {
+	service := New()
+	sourceDir := t.TempDir()
+	for _, rel := range []string{"keep.md", "skip.log", filepath.Join("ci", "build.yml"), filepath.Join("docs", "guide.md")} {
+		path := filepath.Join(sourceDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content\n"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	matcher, err := NewExcludeMatcher([]string{"*.log", "ci/"})
+	if err != nil {
+		t.Fatalf("NewExcludeMatcher() error = %v", err)
+	}
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	err = service.CopyDirectoryFiltered(sourceDir, destDir, func(path string, info os.FileInfo) bool {
+		rel, _ := filepath.Rel(sourceDir, path)
+		return matcher.Match(rel, info.IsDir())
+	})
+	if err != nil {
+		t.Fatalf("CopyDirectoryFiltered() error = %v", err)
+	}
+
+	for rel, want := range map[string]bool{"keep.md": true, filepath.Join("docs", "guide.md"): true, "skip.log": false, "ci": false} {
+		_, err := os.Stat(filepath.Join(destDir, rel))
+		if got := err == nil; got != want {
+			t.Errorf("%s copied = %v, want %v", rel, got, want)
+		}
+	}
+}
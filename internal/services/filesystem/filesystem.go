@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -356,11 +357,31 @@ func (s *Service) CopyFile(sourcePath, destPath string) error {
 		return models.NewFileSystemError(models.ErrorCodePermissionDenied, destPath, err)
 	}
 
+	slog.Debug("Copied file", "from", sourcePath, "to", destPath)
 	return nil
 }
 
+// SkipFunc reports whether a path found while copying should be left out.
+// Skipping a directory skips everything inside it.
+type SkipFunc func(path string, info os.FileInfo) bool
+
 // CopyDirectory copies an entire directory tree
 func (s *Service) CopyDirectory(sourcePath, destPath string) error {
+	return s.CopyDirectoryFiltered(sourcePath, destPath, nil)
+}
+
+// CopyDirectoryFiltered copies a directory tree, leaving out the paths for which
+// skip returns true. A nil skip copies everything. Symlinks are copied as written.
+func (s *Service) CopyDirectoryFiltered(sourcePath, destPath string, skip SkipFunc) error {
+	return s.copyTree(sourcePath, sourcePath, destPath, skip, LinkPolicy{}, nil, nil)
+}
+
+// copyTree copies the directory at walkPath to destPath. Paths are passed to
+// skip as if they were under sourcePath, which differs from walkPath when a
+// directory link is being dereferenced; walked collects the directories copied
+// so far, for copyLink's loop check. Regular files are copied on pool when it
+// is not nil; the caller waits for it.
+func (s *Service) copyTree(sourcePath, walkPath, destPath string, skip SkipFunc, links LinkPolicy, walked []string, pool *copyPool) error {
 	if sourcePath == "" || destPath == "" {
 		return models.NewAppError(
 			models.ErrorCodeValidationFailed,
@@ -370,7 +391,7 @@ func (s *Service) CopyDirectory(sourcePath, destPath string) error {
 	}
 
 	// Get source directory info
-	sourceInfo, err := os.Stat(sourcePath)
+	sourceInfo, err := os.Stat(walkPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return models.NewFileSystemError(models.ErrorCodeDirectoryNotFound, sourcePath, err)
@@ -397,23 +418,37 @@ func (s *Service) CopyDirectory(sourcePath, destPath string) error {
 		return models.NewFileSystemError(models.ErrorCodePermissionDenied, destPath, err)
 	}
 
+	if realPath, err := filepath.EvalSymlinks(walkPath); err == nil {
+		walked = append(walked[:len(walked):len(walked)], realPath)
+	}
+
 	// Walk through source directory
-	return filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
+	return filepath.Walk(walkPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := pool.Err(); err != nil {
+			return err
+		}
 
 		// Skip root directory (already created)
-		if path == sourcePath {
+		if path == walkPath {
 			return nil
 		}
 
 		// Calculate relative path
-		relPath, err := filepath.Rel(sourcePath, path)
+		relPath, err := filepath.Rel(walkPath, path)
 		if err != nil {
 			return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
 		}
 
+		if skip != nil && skip(filepath.Join(sourcePath, relPath), info) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		destItemPath := filepath.Join(destPath, relPath)
 
 		switch {
@@ -425,17 +460,12 @@ func (s *Service) CopyDirectory(sourcePath, destPath string) error {
 			}
 		case info.Mode()&os.ModeSymlink != 0:
 			// Handle symlinks
-			linkTarget, err := os.Readlink(path)
-			if err != nil {
-				return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
-			}
-			err = os.Symlink(linkTarget, destItemPath)
-			if err != nil {
-				return models.NewFileSystemError(models.ErrorCodeSymlinkCreationFailed, destItemPath, err)
+			if err := s.copyLink(path, destItemPath, skip, links, walked, pool); err != nil {
+				return err
 			}
 		default:
 			// Copy regular file
-			if err := s.CopyFile(path, destItemPath); err != nil {
+			if err := pool.copy(s, path, destItemPath); err != nil {
 				return err
 			}
 		}
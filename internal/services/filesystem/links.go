@@ -0,0 +1,149 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// LinkPolicy says how the symlinks in a template checkout are copied
+type LinkPolicy struct {
+	// Root is the template checkout. Every link must resolve inside it, and
+	// absolute links are rewritten relative to the link so they keep working
+	// once installed. An empty Root copies links as written.
+	Root string
+
+	// Dereference copies what each link points to instead of recreating the
+	// link. Links found inside a dereferenced directory are dereferenced too.
+	Dereference bool
+}
+
+// CopyDirectoryWithLinks copies a directory tree like CopyDirectoryFiltered,
+// handling the symlinks in it according to links
+func (s *Service) CopyDirectoryWithLinks(sourcePath, destPath string, skip SkipFunc, links LinkPolicy) error {
+	return s.CopyDirectoryConcurrent(sourcePath, destPath, skip, links, 1)
+}
+
+// CopyDirectoryConcurrent copies a directory tree like CopyDirectoryWithLinks,
+// copying up to jobs files at once. Directories and symlinks are created in
+// walk order, before the files inside them; the first failure stops the copy
+// and is returned once the files already started have finished.
+func (s *Service) CopyDirectoryConcurrent(sourcePath, destPath string, skip SkipFunc, links LinkPolicy, jobs int) error {
+	pool := newCopyPool(s, jobs)
+	return pool.Wait(s.copyTree(sourcePath, sourcePath, destPath, skip, links, nil, pool))
+}
+
+// copyLink copies the symlink at path to destPath according to links. skip
+// and walked apply when a directory link is dereferenced: walked holds the
+// directories already being copied, which the link must not lead back into.
+// Dereferenced files are copied on pool when it is not nil.
+func (s *Service) copyLink(path, destPath string, skip SkipFunc, links LinkPolicy, walked []string, pool *copyPool) error {
+	linkTarget, err := os.Readlink(path)
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+
+	if links.Root != "" {
+		resolved, err := links.resolve(path, linkTarget)
+		if err != nil {
+			return err
+		}
+
+		if links.Dereference {
+			return s.copyLinkTarget(path, resolved, destPath, skip, links, walked, pool)
+		}
+
+		if filepath.IsAbs(linkTarget) {
+			linkDir, err := filepath.EvalSymlinks(filepath.Dir(path))
+			if err != nil {
+				return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+			}
+			if linkTarget, err = filepath.Rel(linkDir, resolved); err != nil {
+				return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), config.DirPermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, destPath, err)
+	}
+	if err := os.Symlink(linkTarget, destPath); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeSymlinkCreationFailed, destPath, err)
+	}
+	return nil
+}
+
+// copyLinkTarget copies the file or directory that the link at path resolves
+// to as destPath
+func (s *Service) copyLinkTarget(path, resolved, destPath string, skip SkipFunc, links LinkPolicy, walked []string, pool *copyPool) error {
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return models.NewAppError(
+			models.ErrorCodeSymlinkInvalid,
+			fmt.Sprintf("Cannot dereference symlink %s: its target does not exist", path),
+			err,
+		).WithContext("path", path)
+	}
+
+	if !info.IsDir() {
+		return pool.copy(s, resolved, destPath)
+	}
+
+	realDir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+	for _, dir := range append(walked[:len(walked):len(walked)], realDir) {
+		if isWithin(resolved, dir) {
+			return models.NewAppError(
+				models.ErrorCodeSymlinkInvalid,
+				fmt.Sprintf("Cannot dereference symlink %s: it loops back to a directory being copied", path),
+				nil,
+			).WithContext("path", path)
+		}
+	}
+
+	return s.copyTree(path, resolved, destPath, skip, links, walked, pool)
+}
+
+// resolve returns where the link at path with the given target points, with
+// symlinks evaluated where it exists, and checks that it stays inside Root
+func (links LinkPolicy) resolve(path, linkTarget string) (string, error) {
+	root, err := filepath.EvalSymlinks(links.Root)
+	if err != nil {
+		return "", models.NewFileSystemError(models.ErrorCodeFileSystemError, links.Root, err)
+	}
+
+	resolved := linkTarget
+	if !filepath.IsAbs(resolved) {
+		linkDir, err := filepath.EvalSymlinks(filepath.Dir(path))
+		if err != nil {
+			return "", models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+		}
+		resolved = filepath.Join(linkDir, resolved)
+	}
+	if real, err := filepath.EvalSymlinks(resolved); err == nil {
+		resolved = real
+	} else {
+		resolved = filepath.Clean(resolved) // Dangling, so only its path can be checked
+	}
+
+	if !isWithin(root, resolved) {
+		return "", models.NewAppError(
+			models.ErrorCodeSymlinkInvalid,
+			fmt.Sprintf("Symlink %s points outside the template to %s", path, linkTarget),
+			nil,
+		).WithContext("path", path).WithContext("target", linkTarget)
+	}
+	return resolved, nil
+}
+
+// isWithin reports whether path is root or inside it
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
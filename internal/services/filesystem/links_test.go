@@ -0,0 +1,192 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+func TestService_CopyDirectoryWithLinks(t *testing.T) {
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatalf("Failed to write file outside the template: %v", err)
+	}
+
+	// root/framework is copied; root/shared is inside the template but not copied
+	setup := func(t *testing.T, links map[string]func(root string) string) string {
+		t.Helper()
+		root := t.TempDir()
+		for _, dir := range []string{"framework/docs", "shared"} {
+			if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+				t.Fatalf("Failed to create %s: %v", dir, err)
+			}
+		}
+		for name, content := range map[string]string{"framework/docs/guide.md": "guide", "shared/notes.md": "notes"} {
+			if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+		for name, target := range links {
+			if err := os.Symlink(target(root), filepath.Join(root, "framework", name)); err != nil {
+				t.Fatalf("Failed to create symlink %s: %v", name, err)
+			}
+		}
+		return root
+	}
+
+	tests := []struct {
+		name        string
+		link        func(root string) string
+		dereference bool
+		wantTarget  string // Link target expected in the copy
+		wantContent string // Content expected through the copied link
+		wantErr     bool
+	}{
+		{
+			name:        "relative link is recreated",
+			link:        func(root string) string { return "docs/guide.md" },
+			wantTarget:  "docs/guide.md",
+			wantContent: "guide",
+		},
+		{
+			name:       "relative link outside the copied directory",
+			link:       func(root string) string { return "../shared/notes.md" },
+			wantTarget: "../shared/notes.md",
+		},
+		{
+			name:        "absolute link is made relative",
+			link:        func(root string) string { return filepath.Join(root, "framework", "docs", "guide.md") },
+			wantTarget:  "docs/guide.md",
+			wantContent: "guide",
+		},
+		{
+			name: "relative link outside the template",
+			link: func(root string) string {
+				rel, _ := filepath.Rel(filepath.Join(root, "framework"), filepath.Join(outside, "secret.txt"))
+				return rel
+			},
+			wantErr: true,
+		},
+		{
+			name:    "absolute link outside the template",
+			link:    func(root string) string { return filepath.Join(outside, "secret.txt") },
+			wantErr: true,
+		},
+		{
+			name:    "dangling link outside the template",
+			link:    func(root string) string { return "/nonexistent/file" },
+			wantErr: true,
+		},
+		{
+			name:        "dereferenced relative link is copied",
+			link:        func(root string) string { return "../shared/notes.md" },
+			dereference: true,
+			wantContent: "notes",
+		},
+		{
+			name:        "dereferenced absolute link is copied",
+			link:        func(root string) string { return filepath.Join(root, "framework", "docs", "guide.md") },
+			dereference: true,
+			wantContent: "guide",
+		},
+		{
+			name:        "dereferencing stays inside the template",
+			link:        func(root string) string { return filepath.Join(outside, "secret.txt") },
+			dereference: true,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := setup(t, map[string]func(string) string{"link.md": tt.link})
+
+			destDir := filepath.Join(t.TempDir(), "framework")
+			links := LinkPolicy{Root: root, Dereference: tt.dereference}
+			err := New().CopyDirectoryWithLinks(filepath.Join(root, "framework"), destDir, nil, links)
+			if tt.wantErr {
+				if !models.IsErrorCode(err, models.ErrorCodeSymlinkInvalid) {
+					t.Fatalf("Expected invalid symlink error, got %v", err)
+				}
+				if _, err := os.Lstat(filepath.Join(destDir, "link.md")); !os.IsNotExist(err) {
+					t.Error("Expected the link not to be copied")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CopyDirectoryWithLinks() error = %v", err)
+			}
+
+			copied := filepath.Join(destDir, "link.md")
+			info, err := os.Lstat(copied)
+			if err != nil {
+				t.Fatalf("Failed to stat copied link: %v", err)
+			}
+			if tt.dereference {
+				if !info.Mode().IsRegular() {
+					t.Fatalf("Expected a regular file, got mode %v", info.Mode())
+				}
+			} else {
+				target, err := os.Readlink(copied)
+				if err != nil {
+					t.Fatalf("Expected a symlink: %v", err)
+				}
+				if target != filepath.FromSlash(tt.wantTarget) {
+					t.Errorf("Link target = %s, want %s", target, tt.wantTarget)
+				}
+			}
+
+			if tt.wantContent != "" {
+				data, err := os.ReadFile(copied)
+				if err != nil {
+					t.Fatalf("Failed to read through copied link: %v", err)
+				}
+				if string(data) != tt.wantContent {
+					t.Errorf("Content = %q, want %q", data, tt.wantContent)
+				}
+			}
+		})
+	}
+}
+
+func TestService_CopyDirectoryWithLinks_DereferenceDirectory(t *testing.T) {
+	root := t.TempDir()
+	framework := filepath.Join(root, "framework")
+	if err := os.MkdirAll(filepath.Join(root, "shared", "nested"), 0755); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	if err := os.MkdirAll(framework, 0755); err != nil {
+		t.Fatalf("Failed to create framework: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "shared", "nested", "a.md"), []byte("a"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Symlink("../shared", filepath.Join(framework, "shared")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	destDir := filepath.Join(t.TempDir(), "framework")
+	links := LinkPolicy{Root: root, Dereference: true}
+	if err := New().CopyDirectoryWithLinks(framework, destDir, nil, links); err != nil {
+		t.Fatalf("CopyDirectoryWithLinks() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(destDir, "shared", "nested", "a.md"))
+	if err != nil || string(data) != "a" {
+		t.Fatalf("Expected the linked directory to be copied, got %q, %v", data, err)
+	}
+	if info, err := os.Lstat(filepath.Join(destDir, "shared")); err != nil || !info.IsDir() {
+		t.Errorf("Expected shared to be a directory, got %v, %v", info, err)
+	}
+
+	// A link back to a directory being copied would never finish
+	if err := os.Symlink("..", filepath.Join(root, "shared", "nested", "loop")); err != nil {
+		t.Fatalf("Failed to create loop symlink: %v", err)
+	}
+	err = New().CopyDirectoryWithLinks(framework, filepath.Join(t.TempDir(), "framework"), nil, links)
+	if !models.IsErrorCode(err, models.ErrorCodeSymlinkInvalid) {
+		t.Errorf("Expected invalid symlink error for a loop, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// ResolveWithin joins rel to root and checks that the result stays inside
+// root: rel must be a relative path without "..", and no symlink already on
+// disk between root and the result may lead elsewhere. The last element is not
+// followed, since it is what gets replaced.
+func ResolveWithin(root, rel string) (string, error) {
+	if !filepath.IsLocal(rel) {
+		return "", traversalError(root, rel, "it is not a relative path inside the directory")
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", models.NewFileSystemError(models.ErrorCodeFileSystemError, root, err)
+	}
+
+	path := filepath.Join(root, rel)
+
+	// Resolve the deepest parent that exists; the rest is created below it
+	parent := filepath.Dir(path)
+	for {
+		if _, err := os.Lstat(parent); err == nil {
+			break
+		}
+		next := filepath.Dir(parent)
+		if next == parent {
+			break
+		}
+		parent = next
+	}
+	realParent, err := filepath.EvalSymlinks(parent)
+	if err != nil {
+		return "", models.NewFileSystemError(models.ErrorCodeFileSystemError, parent, err)
+	}
+	if !isWithin(realRoot, realParent) {
+		return "", traversalError(root, rel, fmt.Sprintf("%s leads to %s", parent, realParent))
+	}
+
+	return path, nil
+}
+
+// checkLinkWithin checks that the symlink at linkPath, once it is at dest,
+// points inside root
+func checkLinkWithin(root, linkPath, dest string) error {
+	linkTarget, err := os.Readlink(linkPath)
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, linkPath, err)
+	}
+
+	resolved := linkTarget
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(dest), linkTarget)
+	}
+	resolved = filepath.Clean(resolved)
+
+	inside := isWithin(filepath.Clean(root), resolved)
+	if realRoot, err := filepath.EvalSymlinks(root); err == nil && !inside {
+		inside = isWithin(realRoot, resolved) // An absolute target may use the resolved root
+	}
+	if !inside {
+		return traversalError(root, dest, fmt.Sprintf("it is a symlink to %s", linkTarget))
+	}
+	return nil
+}
+
+// traversalError reports a path that would be written outside root
+func traversalError(root, rel, reason string) error {
+	return models.NewAppError(
+		models.ErrorCodePathTraversal,
+		fmt.Sprintf("Refusing to write %s outside %s: %s", rel, root, reason),
+		nil,
+	).WithContext("path", rel).WithContext("root", root)
+}
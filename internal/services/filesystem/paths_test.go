@@ -0,0 +1,145 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+func TestResolveWithin(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "framework", "core", "a.md"), "a")
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	if err := os.Symlink("framework", filepath.Join(root, "alias")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		rel     string
+		wantErr bool
+	}{
+		{name: "existing file", rel: "framework/core/a.md"},
+		{name: "new nested file", rel: "framework/new/dir/b.md"},
+		{name: "dot segments that stay inside", rel: "framework/core/../core/a.md"},
+		{name: "symlinked parent inside the root", rel: "alias/core/a.md"},
+		{name: "the symlink itself is replaced, not followed", rel: "escape"},
+		{name: "parent traversal", rel: "../../etc/passwd", wantErr: true},
+		{name: "traversal after a directory", rel: "framework/../../outside.md", wantErr: true},
+		{name: "absolute path", rel: "/etc/passwd", wantErr: true},
+		{name: "empty path", rel: "", wantErr: true},
+		{name: "symlinked parent outside the root", rel: "escape/evil.md", wantErr: true},
+		{name: "missing directories under an escaping symlink", rel: "escape/a/b/evil.md", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ResolveWithin(root, filepath.FromSlash(tt.rel))
+			if tt.wantErr {
+				if !models.IsErrorCode(err, models.ErrorCodePathTraversal) {
+					t.Errorf("ResolveWithin(%q) error = %v, want %s", tt.rel, err, models.ErrorCodePathTraversal)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveWithin(%q) error = %v", tt.rel, err)
+			}
+			if want := filepath.Join(root, filepath.FromSlash(tt.rel)); path != want {
+				t.Errorf("ResolveWithin(%q) = %s, want %s", tt.rel, path, want)
+			}
+		})
+	}
+}
+
+func TestTransaction_RejectsTraversal(t *testing.T) {
+	service := New()
+	outside := t.TempDir()
+	sourceDir := t.TempDir()
+	writeTestFile(t, filepath.Join(sourceDir, "file.md"), "content")
+
+	t.Run("staging outside the target", func(t *testing.T) {
+		targetDir := t.TempDir()
+		tx, err := service.BeginTransaction(targetDir)
+		if err != nil {
+			t.Fatalf("BeginTransaction() error = %v", err)
+		}
+		defer tx.Rollback()
+
+		checks := map[string]error{
+			"StageFile":      tx.StageFile(filepath.Join(sourceDir, "file.md"), filepath.Join("..", "..", "evil.md")),
+			"StageDirectory": tx.StageDirectory(sourceDir, filepath.Join("framework", "..", "..", "evil")),
+			"StageRemoval":   tx.StageRemoval(filepath.Join("..", filepath.Base(outside))),
+		}
+		for name, err := range checks {
+			if !models.IsErrorCode(err, models.ErrorCodePathTraversal) {
+				t.Errorf("%s error = %v, want %s", name, err, models.ErrorCodePathTraversal)
+			}
+		}
+		if len(tx.Staged()) != 0 {
+			t.Errorf("Expected nothing staged, got %v", tx.Staged())
+		}
+	})
+
+	t.Run("target directory symlinked outside", func(t *testing.T) {
+		targetDir := t.TempDir()
+		if err := os.Symlink(outside, filepath.Join(targetDir, "framework")); err != nil {
+			t.Fatalf("Failed to create symlink: %v", err)
+		}
+		tx, err := service.BeginTransaction(targetDir)
+		if err != nil {
+			t.Fatalf("BeginTransaction() error = %v", err)
+		}
+		defer tx.Rollback()
+
+		err = tx.StageFile(filepath.Join(sourceDir, "file.md"), filepath.Join("framework", "file.md"))
+		if !models.IsErrorCode(err, models.ErrorCodePathTraversal) {
+			t.Errorf("StageFile() error = %v, want %s", err, models.ErrorCodePathTraversal)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "file.md")); !os.IsNotExist(err) {
+			t.Error("Expected nothing written outside the target")
+		}
+	})
+
+	linkTargets := map[string]string{
+		"relative symlink target": filepath.Join("..", "..", "..", filepath.Base(outside), "secret"),
+		"absolute symlink target": filepath.Join(outside, "secret"),
+	}
+	for name, linkTarget := range linkTargets {
+		t.Run(name, func(t *testing.T) {
+			targetDir := t.TempDir()
+			writeTestFile(t, filepath.Join(targetDir, "framework", "old.md"), "old")
+
+			linkSource := t.TempDir()
+			writeTestFile(t, filepath.Join(linkSource, "file.md"), "content")
+			if err := os.Symlink(linkTarget, filepath.Join(linkSource, "link")); err != nil {
+				t.Fatalf("Failed to create symlink: %v", err)
+			}
+
+			tx, err := service.BeginTransaction(targetDir)
+			if err != nil {
+				t.Fatalf("BeginTransaction() error = %v", err)
+			}
+			defer tx.Rollback()
+
+			// Staged without a link policy, so only Commit stands in the way
+			if err := tx.StageDirectory(linkSource, "framework"); err != nil {
+				t.Fatalf("StageDirectory() error = %v", err)
+			}
+			if err := tx.Commit(); !models.IsErrorCode(err, models.ErrorCodePathTraversal) {
+				t.Fatalf("Commit() error = %v, want %s", err, models.ErrorCodePathTraversal)
+			}
+
+			if got := readTestFile(filepath.Join(targetDir, "framework", "old.md")); got != "old" {
+				t.Errorf("Expected the target untouched, old.md = %q", got)
+			}
+			if _, err := os.Lstat(filepath.Join(targetDir, "framework", "link")); !os.IsNotExist(err) {
+				t.Error("Expected the symlink not to be moved into the target")
+			}
+		})
+	}
+}
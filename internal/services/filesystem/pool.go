@@ -0,0 +1,96 @@
+package filesystem
+
+import (
+	"sync"
+)
+
+// copyPool copies regular files on a bounded number of goroutines while a tree
+// is walked. The walk stays sequential, so each directory is created before the
+// files in it are queued, and symlinks are still handled in walk order. The
+// first failure stops the workers from starting more copies and is returned by
+// Err and Wait.
+type copyPool struct {
+	work chan copyJob
+	wg   sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// copyJob is one file for the pool to copy
+type copyJob struct {
+	source string
+	dest   string
+}
+
+// newCopyPool starts jobs workers copying files with fs. It returns nil, which
+// copies each file as it is found, when jobs is 1 or less.
+func newCopyPool(fs *Service, jobs int) *copyPool {
+	if jobs <= 1 {
+		return nil
+	}
+
+	pool := &copyPool{work: make(chan copyJob, jobs)}
+	for worker := 0; worker < jobs; worker++ {
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			for job := range pool.work {
+				// Drain the queue without copying once a file has failed
+				if pool.Err() != nil {
+					continue
+				}
+				if err := fs.CopyFile(job.source, job.dest); err != nil {
+					pool.fail(err)
+				}
+			}
+		}()
+	}
+	return pool
+}
+
+// copy copies source to dest, on a worker when the pool is not nil. An error
+// from an earlier copy is returned instead of queueing another.
+func (p *copyPool) copy(fs *Service, source, dest string) error {
+	if p == nil {
+		return fs.CopyFile(source, dest)
+	}
+	if err := p.Err(); err != nil {
+		return err
+	}
+	p.work <- copyJob{source: source, dest: dest}
+	return nil
+}
+
+// Err returns the first copy that failed so far, or nil
+func (p *copyPool) Err() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// fail records err unless an earlier copy already failed
+func (p *copyPool) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// Wait stops accepting files, waits for the queued ones to be copied, and
+// returns the first failure, or walkErr when the walk itself failed first
+func (p *copyPool) Wait(walkErr error) error {
+	if p == nil {
+		return walkErr
+	}
+	close(p.work)
+	p.wg.Wait()
+	if walkErr != nil {
+		return walkErr
+	}
+	return p.err
+}
@@ -0,0 +1,114 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree creates dirs directories under root, each holding files small
+// files, and returns the relative paths of the files
+func writeTree(tb testing.TB, root string, dirs, files int) []string {
+	tb.Helper()
+	var paths []string
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(fmt.Sprintf("dir%d", d), "nested")
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			tb.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		for f := 0; f < files; f++ {
+			rel := filepath.Join(dir, fmt.Sprintf("file%d.md", f))
+			if err := os.WriteFile(filepath.Join(root, rel), []byte(rel), 0644); err != nil {
+				tb.Fatalf("Failed to write %s: %v", rel, err)
+			}
+			paths = append(paths, rel)
+		}
+	}
+	return paths
+}
+
+func TestService_CopyDirectoryConcurrent(t *testing.T) {
+	source := t.TempDir()
+	paths := writeTree(t, source, 10, 20)
+	if err := os.Symlink(filepath.Join("dir0", "nested", "file0.md"), filepath.Join(source, "link.md")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	for _, jobs := range []int{0, 1, 4, 32} {
+		t.Run(fmt.Sprintf("jobs=%d", jobs), func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "copy")
+			skipped := filepath.Join("dir9", "nested", "file19.md")
+			skip := func(path string, info os.FileInfo) bool { return path == filepath.Join(source, skipped) }
+
+			if err := New().CopyDirectoryConcurrent(source, dest, skip, LinkPolicy{Root: source}, jobs); err != nil {
+				t.Fatalf("CopyDirectoryConcurrent() error = %v", err)
+			}
+
+			for _, rel := range paths {
+				content, err := os.ReadFile(filepath.Join(dest, rel))
+				if rel == skipped {
+					if !os.IsNotExist(err) {
+						t.Errorf("Expected skipped %s not to be copied, got error %v", rel, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("Expected %s to be copied: %v", rel, err)
+				}
+				if string(content) != rel {
+					t.Errorf("Content of %s = %q, want %q", rel, content, rel)
+				}
+			}
+			if target, err := os.Readlink(filepath.Join(dest, "link.md")); err != nil || target != filepath.Join("dir0", "nested", "file0.md") {
+				t.Errorf("Expected link.md to be recreated, got %q, %v", target, err)
+			}
+		})
+	}
+}
+
+func TestService_CopyDirectoryConcurrent_Failure(t *testing.T) {
+	source := t.TempDir()
+	writeTree(t, source, 4, 50)
+
+	// A directory in the way of one file makes its copy fail on a worker
+	dest := filepath.Join(t.TempDir(), "copy")
+	blocked := filepath.Join(dest, "dir2", "nested", "file7.md")
+	if err := os.MkdirAll(filepath.Join(blocked, "in-the-way"), 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", blocked, err)
+	}
+
+	err := New().CopyDirectoryConcurrent(source, dest, nil, LinkPolicy{}, 8)
+	if err == nil {
+		t.Fatal("Expected an error copying over a directory")
+	}
+	if !strings.Contains(err.Error(), "file7.md") {
+		t.Errorf("Expected the error to name the failed file, got %v", err)
+	}
+}
+
+// Copying 2000 small files, sequentially and on the pool. On a single-CPU
+// Linux sandbox, where the pool can only overlap waits on the disk:
+//
+//	BenchmarkService_CopyDirectoryConcurrent/jobs=1    10    52479850 ns/op
+//	BenchmarkService_CopyDirectoryConcurrent/jobs=8    10    49650593 ns/op
+func BenchmarkService_CopyDirectoryConcurrent(b *testing.B) {
+	service := New()
+	source := b.TempDir()
+	writeTree(b, source, 40, 50)
+
+	for _, jobs := range []int{1, 8} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			tempDir := b.TempDir()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dest := filepath.Join(tempDir, fmt.Sprintf("copy%d", i))
+				if err := service.CopyDirectoryConcurrent(source, dest, nil, LinkPolicy{}, jobs); err != nil {
+					b.Fatalf("CopyDirectoryConcurrent failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,538 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+const (
+	// stagedDirName holds the new content until it is moved into place
+	stagedDirName = "staged"
+
+	// previousDirName holds replaced content until the transaction is closed
+	previousDirName = "previous"
+
+	// snapshotDirName holds copies of paths changed in place after the commit
+	snapshotDirName = "snapshot"
+)
+
+// Transaction stages directories and files inside the target and swaps them
+// into place with renames, keeping whatever they replace so the swap can be
+// rolled back.
+// The staging area lives in the target directory so every rename stays on one
+// filesystem and is atomic.
+type Transaction struct {
+	fs         *Service
+	targetDir  string
+	stagingDir string
+	staged     []string
+	removals   []string
+	links      LinkPolicy
+	jobs       int
+	onStage    func()
+	applied    []appliedMove
+	snapshots  []snapshot
+	done       bool
+}
+
+// appliedMove records a staged path that has been moved into the target
+type appliedMove struct {
+	rel         string
+	hadPrevious bool
+}
+
+// snapshot records a target path copied aside by Snapshot
+type snapshot struct {
+	rel     string
+	existed bool
+}
+
+// BeginTransaction creates a staging area in targetDir for a new transaction
+func (s *Service) BeginTransaction(targetDir string) (*Transaction, error) {
+	stagingDir, err := os.MkdirTemp(targetDir, config.StagingDirPrefix)
+	if err != nil {
+		if os.IsPermission(err) {
+			return nil, models.NewFileSystemError(models.ErrorCodePermissionDenied, targetDir, err)
+		}
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, targetDir, err)
+	}
+
+	return &Transaction{
+		fs:         s,
+		targetDir:  targetDir,
+		stagingDir: stagingDir,
+	}, nil
+}
+
+// SetLinkPolicy sets how symlinks in the staged content are copied. By default
+// they are recreated as written.
+func (tx *Transaction) SetLinkPolicy(links LinkPolicy) {
+	tx.links = links
+}
+
+// SetJobs sets how many files are copied at once while staging a directory.
+// By default, or with 1 or less, they are copied one at a time.
+func (tx *Transaction) SetJobs(jobs int) {
+	tx.jobs = jobs
+}
+
+// SetProgress sets a function called for each file as it is staged, for
+// reporting progress on large templates
+func (tx *Transaction) SetProgress(onStage func()) {
+	tx.onStage = onStage
+}
+
+// StageDirectory copies sourcePath into the staging area; on Commit it replaces
+// rel, a path relative to the target directory
+func (tx *Transaction) StageDirectory(sourcePath, rel string) error {
+	return tx.StageDirectoryFiltered(sourcePath, rel, nil)
+}
+
+// StageDirectoryFiltered stages sourcePath like StageDirectory, leaving out the
+// paths for which skip returns true
+func (tx *Transaction) StageDirectoryFiltered(sourcePath, rel string, skip SkipFunc) error {
+	if tx.done {
+		return models.NewAppError(models.ErrorCodeValidationFailed, "transaction is already finished", nil)
+	}
+	if _, err := ResolveWithin(tx.targetDir, rel); err != nil {
+		return err
+	}
+
+	if tx.onStage != nil {
+		skip = countStaged(skip, tx.onStage)
+	}
+
+	if err := tx.fs.CopyDirectoryConcurrent(sourcePath, tx.StagedPath(rel), skip, tx.links, tx.jobs); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", rel, err)
+	}
+
+	tx.staged = append(tx.staged, rel)
+	return nil
+}
+
+// StageFile copies a single file or symlink into the staging area; on Commit it
+// replaces rel, a path relative to the target directory
+func (tx *Transaction) StageFile(sourcePath, rel string) error {
+	if tx.done {
+		return models.NewAppError(models.ErrorCodeValidationFailed, "transaction is already finished", nil)
+	}
+	if _, err := ResolveWithin(tx.targetDir, rel); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(sourcePath)
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, sourcePath, err)
+	}
+
+	stagedPath := tx.StagedPath(rel)
+	if info.Mode()&os.ModeSymlink != 0 {
+		if err := tx.fs.copyLink(sourcePath, stagedPath, nil, tx.links, nil, nil); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", rel, err)
+		}
+	} else if err := tx.fs.CopyFile(sourcePath, stagedPath); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", rel, err)
+	}
+
+	if tx.onStage != nil {
+		tx.onStage()
+	}
+
+	tx.staged = append(tx.staged, rel)
+	return nil
+}
+
+// countStaged wraps skip to call onStage for every file that is not skipped
+func countStaged(skip SkipFunc, onStage func()) SkipFunc {
+	return func(path string, info os.FileInfo) bool {
+		if skip != nil && skip(path, info) {
+			return true
+		}
+		if !info.IsDir() {
+			onStage()
+		}
+		return false
+	}
+}
+
+// StageRemoval marks rel, a path relative to the target directory, to be
+// removed on Commit. It is set aside like replaced content, so a rollback puts
+// it back.
+func (tx *Transaction) StageRemoval(rel string) error {
+	if tx.done {
+		return models.NewAppError(models.ErrorCodeValidationFailed, "transaction is already finished", nil)
+	}
+	if _, err := ResolveWithin(tx.targetDir, rel); err != nil {
+		return err
+	}
+
+	tx.removals = append(tx.removals, rel)
+	return nil
+}
+
+// Staged returns the target-relative paths staged so far, in staging order
+func (tx *Transaction) Staged() []string {
+	return append([]string(nil), tx.staged...)
+}
+
+// StagedPath returns where the staged copy of rel lives until Commit
+func (tx *Transaction) StagedPath(rel string) string {
+	return filepath.Join(tx.stagingDir, stagedDirName, rel)
+}
+
+// KeepUnchanged compares each staged file with the installed file it would
+// replace, using the SHA-256 the manifest records, and keeps the installed one
+// wherever content and mode match, so repeated installs leave unchanged files
+// and their timestamps alone. Inside a staged directory the installed file is
+// linked into the staging area in place of the copy; a staged file that
+// matches is left out of the commit. Call it once staging is finished.
+func (tx *Transaction) KeepUnchanged() (models.FileChanges, error) {
+	var changes models.FileChanges
+	if tx.done {
+		return changes, models.NewAppError(models.ErrorCodeValidationFailed, "transaction is already finished", nil)
+	}
+
+	staged := make([]string, 0, len(tx.staged))
+	for _, rel := range tx.staged {
+		stagedRoot := tx.StagedPath(rel)
+		info, err := os.Lstat(stagedRoot)
+		if err != nil {
+			return changes, models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedRoot, err)
+		}
+
+		if !info.IsDir() {
+			unchanged, err := sameContent(stagedRoot, filepath.Join(tx.targetDir, rel), info)
+			if err != nil {
+				return changes, err
+			}
+			switch {
+			case unchanged:
+				changes.Unchanged++
+				continue // Nothing to move
+			case info.Mode().IsRegular():
+				changes.Updated++
+			}
+			staged = append(staged, rel)
+			continue
+		}
+
+		err = filepath.Walk(stagedRoot, func(path string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			within, err := filepath.Rel(stagedRoot, path)
+			if err != nil {
+				return err
+			}
+			installed := filepath.Join(tx.targetDir, rel, within)
+			unchanged, err := sameContent(path, installed, info)
+			if err != nil {
+				return err
+			}
+			if !unchanged {
+				changes.Updated++
+				return nil
+			}
+			changes.Unchanged++
+			return keepInstalled(path, installed)
+		})
+		if err != nil {
+			return changes, err
+		}
+		staged = append(staged, rel)
+	}
+
+	tx.staged = staged
+	return changes, nil
+}
+
+// sameContent reports whether the installed file is a regular file with the
+// same mode and SHA-256 as the staged one
+func sameContent(stagedPath, installedPath string, staged os.FileInfo) (bool, error) {
+	installed, err := os.Lstat(installedPath)
+	if err != nil || !staged.Mode().IsRegular() || installed.Mode() != staged.Mode() || installed.Size() != staged.Size() {
+		return false, nil
+	}
+
+	stagedHash, err := state.HashFile(stagedPath)
+	if err != nil {
+		return false, models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedPath, err)
+	}
+	installedHash, err := state.HashFile(installedPath)
+	if err != nil {
+		return false, models.NewFileSystemError(models.ErrorCodeFileSystemError, installedPath, err)
+	}
+	return stagedHash == installedHash, nil
+}
+
+// keepInstalled replaces the staged copy of an unchanged file with a hard link
+// to the installed file, so the commit moves the same file back into place.
+// Where links are not supported the copy stays, taking the installed file's
+// modification time instead.
+func keepInstalled(stagedPath, installedPath string) error {
+	link := stagedPath + ".unchanged"
+	if err := os.Link(installedPath, link); err == nil {
+		if err := os.Rename(link, stagedPath); err != nil {
+			_ = os.Remove(link) // Best effort cleanup; the copy is still staged
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedPath, err)
+		}
+		return nil
+	}
+
+	info, err := os.Stat(installedPath)
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, installedPath, err)
+	}
+	if err := os.Chtimes(stagedPath, info.ModTime(), info.ModTime()); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedPath, err)
+	}
+	return nil
+}
+
+// Commit moves every staged path into the target, setting aside what it
+// replaces, then sets aside the paths staged for removal. If any move fails,
+// the moves already made are rolled back. Nothing is moved if a path would end
+// up outside the target or a staged symlink would point outside it.
+func (tx *Transaction) Commit() error {
+	if tx.done {
+		return models.NewAppError(models.ErrorCodeValidationFailed, "transaction is already finished", nil)
+	}
+	if err := tx.checkContained(); err != nil {
+		return err
+	}
+
+	for _, rel := range tx.staged {
+		if err := tx.apply(rel); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return errors.Join(err, rollbackErr)
+			}
+			return err
+		}
+	}
+	for _, rel := range tx.removals {
+		if err := tx.remove(rel); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return errors.Join(err, rollbackErr)
+			}
+			return err
+		}
+	}
+
+	tx.staged = nil
+	tx.removals = nil
+	return nil
+}
+
+// Snapshot copies rel, a path relative to the target directory, into the
+// staging area as it is now, so a rollback puts it back however it is changed
+// afterwards; a path that does not exist yet is removed instead. It covers the
+// changes made in place around the commit, such as merged settings and created
+// symlinks. A path already snapshotted keeps its first copy.
+func (tx *Transaction) Snapshot(rel string) error {
+	if tx.done {
+		return models.NewAppError(models.ErrorCodeValidationFailed, "transaction is already finished", nil)
+	}
+	if _, err := ResolveWithin(tx.targetDir, rel); err != nil {
+		return err
+	}
+	for _, snap := range tx.snapshots {
+		if snap.rel == rel {
+			return nil
+		}
+	}
+
+	path := filepath.Join(tx.targetDir, rel)
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		tx.snapshots = append(tx.snapshots, snapshot{rel: rel})
+		return nil
+	}
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+
+	copyPath := filepath.Join(tx.stagingDir, snapshotDirName, rel)
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		err = tx.fs.copyLink(path, copyPath, nil, LinkPolicy{}, nil, nil)
+	case info.IsDir():
+		err = tx.fs.CopyDirectory(path, copyPath)
+	default:
+		err = tx.fs.CopyFile(path, copyPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to snapshot %s: %w", rel, err)
+	}
+
+	tx.snapshots = append(tx.snapshots, snapshot{rel: rel, existed: true})
+	return nil
+}
+
+// checkContained checks that every staged and removed path is still inside the
+// target, and that every staged symlink will point inside it once moved there
+func (tx *Transaction) checkContained() error {
+	for _, rel := range tx.removals {
+		if _, err := ResolveWithin(tx.targetDir, rel); err != nil {
+			return err
+		}
+	}
+
+	for _, rel := range tx.staged {
+		if _, err := ResolveWithin(tx.targetDir, rel); err != nil {
+			return err
+		}
+
+		stagedRoot := tx.StagedPath(rel)
+		err := filepath.Walk(stagedRoot, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.Mode()&os.ModeSymlink == 0 {
+				return err
+			}
+			within, err := filepath.Rel(stagedRoot, path)
+			if err != nil {
+				return err
+			}
+			return checkLinkWithin(tx.targetDir, path, filepath.Join(tx.targetDir, rel, within))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remove sets aside one path staged for removal; a path already gone is skipped
+func (tx *Transaction) remove(rel string) error {
+	dest := filepath.Join(tx.targetDir, rel)
+	if _, err := os.Lstat(dest); os.IsNotExist(err) {
+		return nil
+	}
+
+	previous := filepath.Join(tx.stagingDir, previousDirName, rel)
+	if err := os.MkdirAll(filepath.Dir(previous), config.DirPermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, previous, err)
+	}
+	if err := os.Rename(dest, previous); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, dest, err)
+	}
+
+	tx.applied = append(tx.applied, appliedMove{rel: rel, hadPrevious: true})
+	return nil
+}
+
+// apply moves one staged path into the target
+func (tx *Transaction) apply(rel string) error {
+	dest := filepath.Join(tx.targetDir, rel)
+	move := appliedMove{rel: rel}
+
+	if _, err := os.Lstat(dest); err == nil {
+		previous := filepath.Join(tx.stagingDir, previousDirName, rel)
+		if err := os.MkdirAll(filepath.Dir(previous), config.DirPermissions); err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, previous, err)
+		}
+		if err := os.Rename(dest, previous); err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, dest, err)
+		}
+		move.hadPrevious = true
+	}
+
+	if err := tx.moveStaged(rel, dest); err != nil {
+		// Put the original back before reporting the failure
+		if move.hadPrevious {
+			if restoreErr := os.Rename(filepath.Join(tx.stagingDir, previousDirName, rel), dest); restoreErr != nil {
+				return errors.Join(err, models.NewFileSystemError(models.ErrorCodeRestoreFailed, dest, restoreErr))
+			}
+		}
+		return err
+	}
+
+	tx.applied = append(tx.applied, move)
+	return nil
+}
+
+// moveStaged renames the staged copy of rel to dest
+func (tx *Transaction) moveStaged(rel, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), config.DirPermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, filepath.Dir(dest), err)
+	}
+	if err := os.Rename(tx.StagedPath(rel), dest); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, dest, err)
+	}
+	return nil
+}
+
+// Rollback puts back the snapshotted paths, undoes every committed move,
+// restores the replaced content, and removes the staging area. It is safe to
+// call more than once.
+func (tx *Transaction) Rollback() error {
+	if tx.done {
+		return nil
+	}
+
+	var errs []error
+	for i := len(tx.snapshots) - 1; i >= 0; i-- {
+		snap := tx.snapshots[i]
+		dest := filepath.Join(tx.targetDir, snap.rel)
+
+		if err := os.RemoveAll(dest); err != nil {
+			errs = append(errs, models.NewFileSystemError(models.ErrorCodeRestoreFailed, dest, err))
+			continue
+		}
+		if snap.existed {
+			copyPath := filepath.Join(tx.stagingDir, snapshotDirName, snap.rel)
+			if err := os.Rename(copyPath, dest); err != nil {
+				errs = append(errs, models.NewFileSystemError(models.ErrorCodeRestoreFailed, dest, err))
+			}
+		}
+	}
+
+	for i := len(tx.applied) - 1; i >= 0; i-- {
+		move := tx.applied[i]
+		dest := filepath.Join(tx.targetDir, move.rel)
+
+		if err := os.RemoveAll(dest); err != nil {
+			errs = append(errs, models.NewFileSystemError(models.ErrorCodeRestoreFailed, dest, err))
+			continue
+		}
+		if move.hadPrevious {
+			previous := filepath.Join(tx.stagingDir, previousDirName, move.rel)
+			if err := os.Rename(previous, dest); err != nil {
+				errs = append(errs, models.NewFileSystemError(models.ErrorCodeRestoreFailed, dest, err))
+			}
+		}
+	}
+
+	// Keep the staging area if something could not be restored, since it may
+	// hold the only copy of the replaced content
+	if len(errs) > 0 {
+		errs = append(errs, fmt.Errorf("replaced files were left in %s", tx.stagingDir))
+		tx.done = true
+		return errors.Join(errs...)
+	}
+
+	tx.done = true
+	return tx.removeStaging()
+}
+
+// Close finishes a committed transaction by discarding the staging area, the
+// content it replaced, and the snapshots. After Close the transaction can no longer be rolled back.
+func (tx *Transaction) Close() error {
+	if tx.done {
+		return nil
+	}
+	tx.done = true
+	return tx.removeStaging()
+}
+
+// removeStaging deletes the staging area
+func (tx *Transaction) removeStaging() error {
+	if err := os.RemoveAll(tx.stagingDir); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, tx.stagingDir, err)
+	}
+	return nil
+}
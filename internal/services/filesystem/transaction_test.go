@@ -0,0 +1,355 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// writeTestFile creates a file and its parent directories
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+// readTestFile returns a file's content, or "" if it cannot be read
+func readTestFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// assertNoStaging fails the test if a staging directory was left in targetDir
+func assertNoStaging(t *testing.T, targetDir string) {
+	t.Helper()
+	entries, err := os.ReadDir(targetDir)
+	if err != nil {
+		t.Fatalf("Failed to read target directory: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), config.StagingDirPrefix) {
+			t.Errorf("Staging directory %s was left behind", entry.Name())
+		}
+	}
+}
+
+func TestTransaction_CommitAndClose(t *testing.T) {
+	service := New()
+	targetDir := t.TempDir()
+	sourceDir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(targetDir, "framework", "old.md"), "old")
+	writeTestFile(t, filepath.Join(sourceDir, "new.md"), "new")
+
+	tx, err := service.BeginTransaction(targetDir)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	if err := tx.StageDirectory(sourceDir, "framework"); err != nil {
+		t.Fatalf("StageDirectory() error = %v", err)
+	}
+
+	// Nothing changes until commit
+	if got := readTestFile(filepath.Join(targetDir, "framework", "old.md")); got != "old" {
+		t.Errorf("Target changed before commit, old.md = %q", got)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := tx.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := readTestFile(filepath.Join(targetDir, "framework", "new.md")); got != "new" {
+		t.Errorf("new.md = %q, want %q", got, "new")
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "framework", "old.md")); !os.IsNotExist(err) {
+		t.Errorf("Expected old.md to be replaced, stat error = %v", err)
+	}
+	assertNoStaging(t, targetDir)
+
+	// A closed transaction cannot be rolled back
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("Rollback() after Close error = %v", err)
+	}
+	if got := readTestFile(filepath.Join(targetDir, "framework", "new.md")); got != "new" {
+		t.Errorf("Rollback after Close changed the target, new.md = %q", got)
+	}
+}
+
+func TestTransaction_RollbackAfterCommit(t *testing.T) {
+	service := New()
+	targetDir := t.TempDir()
+	sourceDir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(targetDir, "framework", "core", "README.md"), "v1")
+	writeTestFile(t, filepath.Join(sourceDir, "README.md"), "v2")
+
+	tx, err := service.BeginTransaction(targetDir)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	for _, rel := range []string{filepath.Join("framework", "core"), filepath.Join("framework", "templates")} {
+		if err := tx.StageDirectory(sourceDir, rel); err != nil {
+			t.Fatalf("StageDirectory() error = %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	// A later install step fails
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if got := readTestFile(filepath.Join(targetDir, "framework", "core", "README.md")); got != "v1" {
+		t.Errorf("README.md = %q, want restored %q", got, "v1")
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "framework", "templates")); !os.IsNotExist(err) {
+		t.Errorf("Expected newly added directory to be removed, stat error = %v", err)
+	}
+	assertNoStaging(t, targetDir)
+}
+
+func TestTransaction_SnapshotRollback(t *testing.T) {
+	service := New()
+	targetDir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(targetDir, "settings", "settings.json"), "user")
+	if err := os.Symlink("settings", filepath.Join(targetDir, "link")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	tx, err := service.BeginTransaction(targetDir)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	for _, rel := range []string{"settings", "link", "created"} {
+		if err := tx.Snapshot(rel); err != nil {
+			t.Fatalf("Snapshot(%s) error = %v", rel, err)
+		}
+	}
+
+	// Later install steps change the snapshotted paths in place
+	writeTestFile(t, filepath.Join(targetDir, "settings", "settings.json"), "merged")
+	writeTestFile(t, filepath.Join(targetDir, "settings", "added.json"), "added")
+	if err := os.Remove(filepath.Join(targetDir, "link")); err != nil {
+		t.Fatalf("Failed to remove symlink: %v", err)
+	}
+	if err := os.Symlink("elsewhere", filepath.Join(targetDir, "link")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	writeTestFile(t, filepath.Join(targetDir, "created", "file.md"), "new")
+
+	// Snapshotting again keeps the first copy
+	if err := tx.Snapshot("settings"); err != nil {
+		t.Fatalf("Snapshot() again error = %v", err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if got := readTestFile(filepath.Join(targetDir, "settings", "settings.json")); got != "user" {
+		t.Errorf("settings.json = %q, want restored %q", got, "user")
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "settings", "added.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected added.json to be removed, stat error = %v", err)
+	}
+	if got, err := os.Readlink(filepath.Join(targetDir, "link")); err != nil || got != "settings" {
+		t.Errorf("link = %q, %v, want restored %q", got, err, "settings")
+	}
+	if _, err := os.Lstat(filepath.Join(targetDir, "created")); !os.IsNotExist(err) {
+		t.Errorf("Expected a path that did not exist to be removed, stat error = %v", err)
+	}
+	assertNoStaging(t, targetDir)
+}
+
+func TestTransaction_CommitFailureRollsBack(t *testing.T) {
+	service := New()
+	targetDir := t.TempDir()
+	sourceDir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(targetDir, "first", "file.md"), "original")
+	writeTestFile(t, filepath.Join(sourceDir, "file.md"), "replacement")
+
+	// A regular file where the second directory's parent should be makes its move fail
+	writeTestFile(t, filepath.Join(targetDir, "blocker"), "not a directory")
+
+	tx, err := service.BeginTransaction(targetDir)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	if err := tx.StageDirectory(sourceDir, "first"); err != nil {
+		t.Fatalf("StageDirectory() error = %v", err)
+	}
+	if err := tx.StageDirectory(sourceDir, filepath.Join("blocker", "second")); err != nil {
+		t.Fatalf("StageDirectory() error = %v", err)
+	}
+
+	if err := tx.Commit(); err == nil {
+		t.Fatal("Expected Commit() to fail")
+	}
+
+	if got := readTestFile(filepath.Join(targetDir, "first", "file.md")); got != "original" {
+		t.Errorf("file.md = %q, want restored %q", got, "original")
+	}
+	assertNoStaging(t, targetDir)
+}
+
+func TestTransaction_SetProgress(t *testing.T) {
+	service := New()
+	targetDir := t.TempDir()
+	sourceDir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(sourceDir, "a.md"), "a")
+	writeTestFile(t, filepath.Join(sourceDir, "nested", "b.md"), "b")
+	writeTestFile(t, filepath.Join(sourceDir, "skipped.md"), "skipped")
+	writeTestFile(t, filepath.Join(sourceDir, "single.md"), "single")
+
+	tx, err := service.BeginTransaction(targetDir)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	defer func() { _ = tx.Close() }()
+
+	staged := 0
+	tx.SetProgress(func() { staged++ })
+
+	skip := func(path string, info os.FileInfo) bool {
+		return filepath.Base(path) == "skipped.md" || filepath.Base(path) == "single.md"
+	}
+	if err := tx.StageDirectoryFiltered(sourceDir, "framework", skip); err != nil {
+		t.Fatalf("StageDirectoryFiltered() error = %v", err)
+	}
+	if err := tx.StageFile(filepath.Join(sourceDir, "single.md"), "single.md"); err != nil {
+		t.Fatalf("StageFile() error = %v", err)
+	}
+
+	// Directories and skipped files are not counted
+	if staged != 3 {
+		t.Errorf("Progress counted %d files, want 3", staged)
+	}
+}
+
+func TestTransaction_KeepUnchanged(t *testing.T) {
+	service := New()
+	targetDir := t.TempDir()
+	sourceDir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(targetDir, "framework", "same.md"), "same")
+	writeTestFile(t, filepath.Join(targetDir, "framework", "changed.md"), "v1")
+	writeTestFile(t, filepath.Join(targetDir, "single.md"), "single")
+	writeTestFile(t, filepath.Join(sourceDir, "framework", "same.md"), "same")
+	writeTestFile(t, filepath.Join(sourceDir, "framework", "changed.md"), "v2")
+	writeTestFile(t, filepath.Join(sourceDir, "framework", "new.md"), "new")
+	writeTestFile(t, filepath.Join(sourceDir, "single.md"), "single")
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, rel := range []string{filepath.Join("framework", "same.md"), "single.md"} {
+		if err := os.Chtimes(filepath.Join(targetDir, rel), past, past); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+
+	tx, err := service.BeginTransaction(targetDir)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	if err := tx.StageDirectory(filepath.Join(sourceDir, "framework"), "framework"); err != nil {
+		t.Fatalf("StageDirectory() error = %v", err)
+	}
+	if err := tx.StageFile(filepath.Join(sourceDir, "single.md"), "single.md"); err != nil {
+		t.Fatalf("StageFile() error = %v", err)
+	}
+
+	changes, err := tx.KeepUnchanged()
+	if err != nil {
+		t.Fatalf("KeepUnchanged() error = %v", err)
+	}
+	if want := (models.FileChanges{Unchanged: 2, Updated: 2}); changes != want {
+		t.Errorf("KeepUnchanged() = %+v, want %+v", changes, want)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := tx.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for rel, want := range map[string]string{
+		filepath.Join("framework", "same.md"):    "same",
+		filepath.Join("framework", "changed.md"): "v2",
+		filepath.Join("framework", "new.md"):     "new",
+		"single.md":                              "single",
+	} {
+		if got := readTestFile(filepath.Join(targetDir, rel)); got != want {
+			t.Errorf("%s = %q, want %q", rel, got, want)
+		}
+	}
+
+	// Unchanged files keep their timestamps
+	for _, rel := range []string{filepath.Join("framework", "same.md"), "single.md"} {
+		info, err := os.Stat(filepath.Join(targetDir, rel))
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if !info.ModTime().Equal(past) {
+			t.Errorf("%s modified at %v, want unchanged %v", rel, info.ModTime(), past)
+		}
+	}
+	assertNoStaging(t, targetDir)
+}
+
+func TestTransaction_KeepUnchangedRollback(t *testing.T) {
+	service := New()
+	targetDir := t.TempDir()
+	sourceDir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(targetDir, "framework", "same.md"), "same")
+	writeTestFile(t, filepath.Join(targetDir, "framework", "changed.md"), "v1")
+	writeTestFile(t, filepath.Join(sourceDir, "same.md"), "same")
+	writeTestFile(t, filepath.Join(sourceDir, "changed.md"), "v2")
+
+	tx, err := service.BeginTransaction(targetDir)
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	if err := tx.StageDirectory(sourceDir, "framework"); err != nil {
+		t.Fatalf("StageDirectory() error = %v", err)
+	}
+	if _, err := tx.KeepUnchanged(); err != nil {
+		t.Fatalf("KeepUnchanged() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	if got := readTestFile(filepath.Join(targetDir, "framework", "same.md")); got != "same" {
+		t.Errorf("same.md = %q, want %q", got, "same")
+	}
+	if got := readTestFile(filepath.Join(targetDir, "framework", "changed.md")); got != "v1" {
+		t.Errorf("changed.md = %q, want restored %q", got, "v1")
+	}
+	assertNoStaging(t, targetDir)
+}
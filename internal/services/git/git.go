@@ -1,9 +1,16 @@
 package git
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +18,23 @@ import (
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
 )
 
+// commandWaitDelay is how long a cancelled git command's children (such as ssh)
+// may hold its output open before it is abandoned
+const commandWaitDelay = 2 * time.Second
+
+// command builds a git command run in dir, or the current directory when dir
+// is empty, and logs it at debug level
+func command(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	if dir == "" {
+		slog.Debug("Running git " + strings.Join(args, " "))
+	} else {
+		slog.Debug("Running git "+strings.Join(args, " "), "dir", dir)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	return cmd
+}
+
 // Service handles git operations for the Strategic Claude Basic CLI
 type Service struct {
 	timeout time.Duration
@@ -36,13 +60,242 @@ func (s *Service) ValidateGitInstalled() error {
 	return nil
 }
 
+// Version returns the installed git version, e.g. "2.43.0"
+func (s *Service) Version() (string, error) {
+	output, err := commandOutput(command(context.Background(), "", "--version"))
+	if err != nil {
+		return "", models.NewAppError(
+			models.ErrorCodeGitNotFound,
+			"Failed to run git --version",
+			err,
+		)
+	}
+	version := strings.TrimSpace(string(output))
+	return strings.TrimPrefix(version, "git version "), nil
+}
+
+// CheckRemote verifies that a repository URL can be reached by listing its
+// refs, without cloning anything. It fails after the service timeout.
+func (s *Service) CheckRemote(url string) error {
+	_, err := s.lsRemote(url, []string{"--heads"})
+	return err
+}
+
+// CheckRemoteRef verifies that ref names a branch or tag of the repository at
+// url, listing its refs without cloning anything
+func (s *Service) CheckRemoteRef(url, ref string) error {
+	output, err := s.lsRemote(url, []string{"--heads", "--tags"}, "refs/heads/"+ref, "refs/tags/"+ref)
+	if err != nil {
+		return err
+	}
+
+	// ls-remote patterns match any trailing path segments, so the ref must be
+	// matched exactly
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && (fields[1] == "refs/heads/"+ref || fields[1] == "refs/tags/"+ref) {
+			return nil
+		}
+	}
+	return models.NewAppError(
+		models.ErrorCodeGitRefNotFound,
+		fmt.Sprintf("Branch or tag %s not found in %s", ref, url),
+		nil,
+	)
+}
+
+// RemoteBranchCommit returns the full hash of the commit at the tip of branch
+// in the repository at url, listing its refs without cloning anything
+func (s *Service) RemoteBranchCommit(url, branch string) (string, error) {
+	output, err := s.lsRemote(url, []string{"--heads"}, "refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[1] == "refs/heads/"+branch {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", models.NewAppError(
+		models.ErrorCodeGitRefNotFound,
+		fmt.Sprintf("Branch %s not found in %s", branch, url),
+		nil,
+	)
+}
+
+// CheckRemoteCommit verifies that the repository at url has commit, a full
+// hash. A commit at the tip of a branch or tag is found by listing refs; any
+// other is fetched on its own, without history, into a scratch repository.
+func (s *Service) CheckRemoteCommit(url, commit string) error {
+	output, err := s.lsRemote(url, nil)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && strings.EqualFold(fields[0], commit) {
+			return nil
+		}
+	}
+
+	tempDir, err := s.createTempDir()
+	if err != nil {
+		return models.NewAppError(models.ErrorCodeFileSystemError, "Failed to create temporary directory", err)
+	}
+	defer func() { _ = s.CleanupTempDir(tempDir) }()
+
+	if output, err := commandCombinedOutput(command(context.Background(), tempDir, "init", "--bare", "--quiet")); err != nil {
+		return models.NewAppError(
+			models.ErrorCodeGitError,
+			fmt.Sprintf("Failed to create scratch repository: %s", strings.TrimSpace(string(output))),
+			err,
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := command(ctx, tempDir, "fetch", "--quiet", "--depth", "1", url, commit)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = commandWaitDelay
+
+	if err := runCommand(cmd); err != nil {
+		output := strings.TrimSpace(stderr.String())
+		switch {
+		case ctx.Err() != nil:
+			return contextError(ctx, "fetching "+commit+" from "+url, err)
+		case isAuthFailure(output):
+			return models.NewAppError(
+				models.ErrorCodeGitAuthFailed,
+				fmt.Sprintf("Authentication failed for %s", url),
+				err,
+			)
+		case isNetworkFailure(output):
+			return models.NewAppError(
+				models.ErrorCodeNetworkError,
+				fmt.Sprintf("Network error fetching from %s: %s", url, output),
+				err,
+			)
+		default:
+			// The server refuses a commit it does not have ("not our ref")
+			return models.NewAppError(
+				models.ErrorCodeGitCommitNotFound,
+				fmt.Sprintf("Commit %s not found in %s", commit, url),
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// lsRemote runs git ls-remote with options on the repository at url, listing
+// the refs matching patterns (every ref when none are given), and returns its
+// output. It fails after the service timeout.
+func (s *Service) lsRemote(url string, options []string, patterns ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	args := append(append([]string{"ls-remote"}, options...), url)
+	var stdout, stderr bytes.Buffer
+	cmd := command(ctx, "", append(args, patterns...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := runCommand(cmd); err != nil {
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			return "", models.NewAppError(
+				models.ErrorCodeNetworkTimeout,
+				fmt.Sprintf("Timed out after %s reaching %s", s.timeout, url),
+				err,
+			)
+		case isAuthFailure(stderr.String()):
+			return "", models.NewAppError(
+				models.ErrorCodeGitAuthFailed,
+				fmt.Sprintf("Authentication failed for %s", url),
+				err,
+			)
+		default:
+			return "", models.NewAppError(
+				models.ErrorCodeNetworkError,
+				fmt.Sprintf("Failed to reach %s: %s", url, strings.TrimSpace(stderr.String())),
+				err,
+			)
+		}
+	}
+
+	return stdout.String(), nil
+}
+
+// CloneOptions describes how a repository should be cloned
+type CloneOptions struct {
+	// Repository URL to clone
+	URL string
+
+	// Branch to clone (empty for the remote's default branch)
+	Branch string
+
+	// Commit to check out after cloning (empty to stay at the branch head)
+	Commit string
+
+	// Depth limits history for a shallow clone (0 clones full history)
+	Depth int
+
+	// Bare clones the repository without a working tree; Commit is ignored
+	Bare bool
+
+	// Sparse lists sparse checkout patterns, in gitignore syntax relative to
+	// the repository root. When set, the clone is partial: it fetches only the
+	// blobs of the files the patterns select and checks out only those, along
+	// with every directory of the tree. Ignored for bare clones.
+	Sparse []string
+
+	// Submodules checks out the repository's submodules, and theirs in turn,
+	// at the commits it records. Ignored for bare clones.
+	Submodules bool
+
+	// Retry controls how a clone that fails on the network is retried
+	Retry RetryOptions
+
+	// Notify receives notices about fallbacks taken during the clone (optional)
+	Notify func(message string)
+
+	// Progress receives each line of git's progress output, such as
+	// "Receiving objects:  45% (450/1000)", while cloning (optional)
+	Progress func(line string)
+}
+
+// partial reports whether the clone fetches only the blobs Sparse selects
+func (o CloneOptions) partial() bool {
+	return len(o.Sparse) > 0 && !o.Bare
+}
+
+// notify reports a notice if a handler is configured
+func (o CloneOptions) notify(format string, args ...interface{}) {
+	if o.Notify != nil {
+		o.Notify(fmt.Sprintf(format, args...))
+	}
+}
+
 // CloneRepository clones a git repository to a temporary directory and checks out a specific commit
 func (s *Service) CloneRepository(url, commit string) (string, error) {
 	return s.CloneRepositoryWithBranch(url, "", commit)
 }
 
-// CloneRepositoryWithBranch clones a git repository with optional branch specification and checks out a specific commit
+// CloneRepositoryWithBranch clones a git repository with optional branch specification and checks out a specific commit.
+// An empty commit leaves the clone at the head of the branch.
 func (s *Service) CloneRepositoryWithBranch(url, branch, commit string) (string, error) {
+	return s.CloneWithOptions(context.Background(), CloneOptions{URL: url, Branch: branch, Commit: commit})
+}
+
+// CloneWithOptions clones a repository into a new temporary directory according to opts.
+// Shallow clones that cannot reach the pinned commit are deepened, falling back to a full clone.
+// The clone stops when ctx is done, and the temporary directory is removed.
+func (s *Service) CloneWithOptions(ctx context.Context, opts CloneOptions) (string, error) {
 	if err := s.ValidateGitInstalled(); err != nil {
 		return "", err
 	}
@@ -56,31 +309,349 @@ func (s *Service) CloneRepositoryWithBranch(url, branch, commit string) (string,
 		)
 	}
 
-	// Attempt clone with retries for network issues
-	var cloneErr error
-	for attempt := 1; attempt <= 3; attempt++ {
-		cloneErr = s.cloneWithRetry(url, branch, tempDir, attempt)
-		if cloneErr == nil {
-			break
+	if err := s.cloneInto(ctx, tempDir, opts); err != nil {
+		_ = s.CleanupTempDir(tempDir) // Best effort cleanup
+		return "", err
+	}
+
+	return tempDir, nil
+}
+
+// CloneInto clones a repository into dir, which must not exist or be empty,
+// according to opts. The clone stops when ctx is done.
+func (s *Service) CloneInto(ctx context.Context, dir string, opts CloneOptions) error {
+	if err := s.ValidateGitInstalled(); err != nil {
+		return err
+	}
+	return s.cloneInto(ctx, dir, opts)
+}
+
+// cloneInto clones and checks out opts.Commit in tempDir. A partial clone
+// that fails, as it can with a git or server too old for one, is made again
+// in full.
+func (s *Service) cloneInto(ctx context.Context, tempDir string, opts CloneOptions) error {
+	err := s.cloneCheckout(ctx, tempDir, opts)
+	if err != nil && opts.partial() && ctx.Err() == nil &&
+		(models.IsErrorCode(err, models.ErrorCodeGitCloneError) || models.IsErrorCode(err, models.ErrorCodeGitCheckoutError)) {
+		opts.notify("Partial clone of %s failed, falling back to a full clone: %v", opts.URL, err)
+		opts.Sparse = nil
+		err = s.cloneCheckout(ctx, tempDir, opts)
+	}
+	if err != nil || !opts.Submodules || opts.Bare {
+		return err
+	}
+	return s.updateSubmodules(ctx, tempDir, opts)
+}
+
+// cloneCheckout makes one attempt at what cloneInto does
+func (s *Service) cloneCheckout(ctx context.Context, tempDir string, opts CloneOptions) error {
+	if err := s.cloneWithRetries(ctx, opts, tempDir, opts.Depth); err != nil {
+		return err
+	}
+
+	// An empty commit tracks the branch head
+	if opts.Commit == "" || opts.Bare {
+		if opts.partial() {
+			return s.sparseCheckout(ctx, tempDir, "HEAD", opts.Sparse)
 		}
+		return nil
+	}
 
-		if attempt < 3 {
-			time.Sleep(time.Second * time.Duration(attempt))
+	if opts.Depth > 0 && s.IsValidCommit(tempDir, opts.Commit) != nil {
+		if err := s.reachShallowCommit(ctx, tempDir, opts); err != nil {
+			if ctx.Err() != nil {
+				return contextError(ctx, "cloning "+opts.URL, err)
+			}
+			opts.notify("Shallow clone could not reach commit %s, falling back to a full clone", opts.Commit)
+
+			if err := s.resetDir(tempDir); err != nil {
+				return err
+			}
+			if err := s.cloneWithRetries(ctx, opts, tempDir, 0); err != nil {
+				return err
+			}
 		}
 	}
 
-	if cloneErr != nil {
-		_ = s.CleanupTempDir(tempDir) // Best effort cleanup
-		return "", cloneErr
+	// A commit missing from the full history is reported as such, not as a failed checkout
+	if err := s.IsValidCommit(tempDir, opts.Commit); err != nil {
+		return err
+	}
+
+	// A partial clone has nothing checked out yet, so the commit's files are
+	// read into place first and checking it out only moves HEAD
+	if opts.partial() {
+		if err := s.sparseCheckout(ctx, tempDir, opts.Commit, opts.Sparse); err != nil {
+			return err
+		}
 	}
 
 	// Checkout specific commit
-	if err := s.checkoutCommit(tempDir, commit); err != nil {
-		_ = s.CleanupTempDir(tempDir) // Best effort cleanup
-		return "", err
+	return s.checkoutCommit(ctx, tempDir, opts.Commit)
+}
+
+// submodule is a submodule declared in a checkout's .gitmodules
+type submodule struct {
+	name string
+	path string
+	url  string
+}
+
+// updateSubmodules checks out the submodules of the commit checked out in
+// repoPath, recursively. They are fetched one at a time, each retried like the
+// clone when it fails on the network, so a failure names the submodule.
+func (s *Service) updateSubmodules(ctx context.Context, repoPath string, opts CloneOptions) error {
+	submodules, err := s.listSubmodules(ctx, repoPath)
+	if err != nil {
+		return err
 	}
 
-	return tempDir, nil
+	for _, sub := range submodules {
+		what := fmt.Sprintf("fetching submodule %s from %s", sub.path, sub.url)
+		attempts, err := withRetries(ctx, opts.Retry, what, func() error {
+			return s.updateSubmodule(ctx, repoPath, sub)
+		})
+		if err == nil {
+			continue
+		}
+		if !isTransient(err) || ctx.Err() != nil {
+			return err
+		}
+		return models.NewAppError(
+			models.ErrorCodeGitCloneError,
+			fmt.Sprintf("Failed to fetch submodule %s from %s after %d attempts", sub.path, sub.url, attempts),
+			err,
+		)
+	}
+	return nil
+}
+
+// listSubmodules returns the submodules the checkout in repoPath declares, in
+// the order of its .gitmodules; none when it has no .gitmodules
+func (s *Service) listSubmodules(ctx context.Context, repoPath string) ([]submodule, error) {
+	if _, err := os.Stat(filepath.Join(repoPath, ".gitmodules")); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	// Each entry is "submodule.<name>.<key>\n<value>"
+	output, err := commandOutput(command(ctx, repoPath, "config", "--file", ".gitmodules", "--null", "--get-regexp", `^submodule\..*\.(path|url)$`))
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil // No path or url keys
+		}
+		return nil, models.NewAppError(models.ErrorCodeGitError, "Failed to read .gitmodules", err).WithContext("path", repoPath)
+	}
+
+	var submodules []submodule
+	index := make(map[string]int)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		key, value, ok := strings.Cut(entry, "\n")
+		if !ok {
+			continue
+		}
+		name, field, ok := cutLast(strings.TrimPrefix(key, "submodule."), ".")
+		if !ok {
+			continue
+		}
+		i, seen := index[name]
+		if !seen {
+			i = len(submodules)
+			index[name] = i
+			submodules = append(submodules, submodule{name: name})
+		}
+		if field == "path" {
+			submodules[i].path = value
+		} else {
+			submodules[i].url = value
+		}
+	}
+
+	// Without a path there is nothing to check out
+	declared := submodules[:0]
+	for _, sub := range submodules {
+		if sub.path != "" {
+			declared = append(declared, sub)
+		}
+	}
+	return declared, nil
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// updateSubmodule makes one attempt at checking out a submodule and its own
+// submodules, starting over from whatever an earlier attempt left behind
+func (s *Service) updateSubmodule(ctx context.Context, repoPath string, sub submodule) error {
+	for _, dir := range []string{filepath.Join(repoPath, ".git", "modules", filepath.FromSlash(sub.name)), filepath.Join(repoPath, filepath.FromSlash(sub.path))} {
+		if err := s.emptyDir(dir); err != nil {
+			return err
+		}
+	}
+
+	var stderr bytes.Buffer
+	cmd := command(ctx, repoPath, "submodule", "update", "--init", "--recursive", "--", sub.path)
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = commandWaitDelay
+
+	if err := runCommand(cmd); err != nil {
+		output := cloneErrorOutput(stderr.String())
+		switch {
+		case ctx.Err() != nil:
+			return contextError(ctx, "fetching submodule "+sub.path, err)
+		case isAuthFailure(output):
+			return models.NewAppError(
+				models.ErrorCodeGitAuthFailed,
+				fmt.Sprintf("Authentication failed for submodule %s from %s; credentials may be missing (check ssh-agent, SSH keys, or GIT_SSH_COMMAND)", sub.path, sub.url),
+				err,
+			)
+		case isNetworkFailure(output):
+			return models.NewAppError(
+				models.ErrorCodeNetworkError,
+				fmt.Sprintf("Network error fetching submodule %s from %s: %s", sub.path, sub.url, output),
+				err,
+			)
+		default:
+			return models.NewAppError(
+				models.ErrorCodeGitCloneError,
+				fmt.Sprintf("Failed to check out submodule %s from %s: %s", sub.path, sub.url, output),
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// sparseCheckout checks out the files of rev that patterns select into the
+// working tree of a clone made without a checkout, fetching their blobs if
+// the clone is partial. Every directory of rev's tree is created, empty or
+// not, so the checkout has the layout of a full one.
+func (s *Service) sparseCheckout(ctx context.Context, repoPath, rev string, patterns []string) error {
+	fail := func(err error, output []byte) error {
+		if ctx.Err() != nil {
+			return contextError(ctx, "checking out "+rev, err)
+		}
+		return models.NewAppError(
+			models.ErrorCodeGitCheckoutError,
+			fmt.Sprintf("Failed to check out %s sparsely: %s", rev, strings.TrimSpace(string(output))),
+			err,
+		)
+	}
+
+	if output, err := commandCombinedOutput(command(ctx, repoPath, "config", "core.sparseCheckout", "true")); err != nil {
+		return fail(err, output)
+	}
+	sparseFile := filepath.Join(repoPath, ".git", "info", "sparse-checkout")
+	if err := os.MkdirAll(filepath.Dir(sparseFile), config.DirPermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, filepath.Dir(sparseFile), err)
+	}
+	if err := os.WriteFile(sparseFile, []byte(strings.Join(patterns, "\n")+"\n"), config.FilePermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, sparseFile, err)
+	}
+
+	if output, err := commandCombinedOutput(command(ctx, repoPath, "read-tree", "-mu", rev)); err != nil {
+		return fail(err, output)
+	}
+
+	// Listing trees needs no blobs
+	output, err := commandOutput(command(ctx, repoPath, "ls-tree", "-r", "-d", "-z", "--name-only", rev))
+	if err != nil {
+		return fail(err, output)
+	}
+	for _, dir := range strings.Split(string(output), "\x00") {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(repoPath, filepath.FromSlash(dir))
+		if err := os.MkdirAll(path, config.DirPermissions); err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+		}
+	}
+	return nil
+}
+
+// reachShallowCommit tries to make a commit available in a shallow clone, first by
+// fetching it directly and then by fetching the full history
+func (s *Service) reachShallowCommit(ctx context.Context, repoPath string, opts CloneOptions) error {
+	fetch := command(ctx, repoPath, "fetch", "--depth", strconv.Itoa(opts.Depth), "origin", opts.Commit)
+	if runCommand(fetch) == nil && s.IsValidCommit(repoPath, opts.Commit) == nil {
+		return nil
+	}
+
+	opts.notify("Commit %s is not reachable at depth %d, deepening clone", opts.Commit, opts.Depth)
+
+	unshallow := command(ctx, repoPath, "fetch", "--unshallow", "origin")
+	if err := runCommand(unshallow); err != nil {
+		return models.NewAppError(
+			models.ErrorCodeGitCloneError,
+			"Failed to deepen shallow clone",
+			err,
+		)
+	}
+
+	return s.IsValidCommit(repoPath, opts.Commit)
+}
+
+// resetDir empties a temporary directory so it can be cloned into again
+func (s *Service) resetDir(path string) error {
+	if err := s.CleanupTempDir(path); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path, config.DirPermissions); err != nil {
+		return models.NewAppError(
+			models.ErrorCodeFileSystemError,
+			"Failed to recreate temporary directory",
+			err,
+		)
+	}
+	return nil
+}
+
+// cloneWithRetries clones into tempDir, retrying transient network failures
+func (s *Service) cloneWithRetries(ctx context.Context, opts CloneOptions, tempDir string, depth int) error {
+	branchInfo := ""
+	if opts.Branch != "" {
+		branchInfo = fmt.Sprintf(" (branch: %s)", opts.Branch)
+	}
+
+	attempts, err := withRetries(ctx, opts.Retry, "cloning "+opts.URL, func() error {
+		// A failed attempt may leave a partial clone behind
+		if err := s.emptyDir(tempDir); err != nil {
+			return err
+		}
+		return s.cloneWithRetry(ctx, opts, tempDir, depth)
+	})
+	if err == nil || !isTransient(err) || ctx.Err() != nil {
+		return err
+	}
+	return models.NewAppError(
+		models.ErrorCodeGitCloneError,
+		fmt.Sprintf("Failed to clone repository %s%s after %d attempts", opts.URL, branchInfo, attempts),
+		err,
+	)
+}
+
+// emptyDir removes everything inside dir, which need not exist
+func (s *Service) emptyDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, dir, err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+		}
+	}
+	return nil
 }
 
 // CleanupTempDir removes the temporary directory and its contents
@@ -119,49 +690,207 @@ func (s *Service) createTempDir() (string, error) {
 	return tempDir, nil
 }
 
-// cloneWithRetry performs a git clone operation with error handling
-func (s *Service) cloneWithRetry(url, branch, tempDir string, attempt int) error {
-	var cmd *exec.Cmd
-
+// cloneWithRetry performs a single git clone attempt with error handling
+func (s *Service) cloneWithRetry(ctx context.Context, opts CloneOptions, tempDir string, depth int) error {
+	url, branch := opts.URL, opts.Branch
+	args := []string{"clone"}
+	if opts.Bare {
+		args = append(args, "--bare")
+	}
+	if depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(depth))
+	}
+	if opts.partial() {
+		// Blobs are fetched by the sparse checkout, only for the files it selects
+		args = append(args, "--filter=blob:none", "--no-checkout")
+	}
 	if branch != "" {
 		// Clone specific branch
-		cmd = exec.Command("git", "clone", "-b", branch, url, tempDir)
-	} else {
-		// Clone default branch
-		cmd = exec.Command("git", "clone", url, tempDir)
+		args = append(args, "-b", branch)
 	}
+	if opts.Progress != nil {
+		// Git only reports progress to a terminal unless asked
+		args = append(args, "--progress")
+	}
+	args = append(args, url, tempDir)
 
+	// The environment is inherited, so ssh-agent, credential helpers and
+	// GIT_SSH_COMMAND (e.g. a CI deploy key) apply to the clone
+	var stderr bytes.Buffer
+	cmd := command(ctx, "", args...)
 	cmd.Stdout = nil // Suppress output
-	cmd.Stderr = nil
+	cmd.Stderr = &stderr
+	if opts.Progress != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, &progressWriter{report: opts.Progress})
+	}
+	cmd.WaitDelay = commandWaitDelay
 
-	err := cmd.Run()
+	err := runCommand(cmd)
 	if err != nil {
-		if attempt == 3 { // Last attempt, return detailed error
+		output := cloneErrorOutput(stderr.String())
+		switch {
+		case ctx.Err() != nil:
+			return contextError(ctx, "cloning "+url, err)
+		case isAuthFailure(output):
+			// Retrying will not fix missing credentials
+			return models.NewAppError(
+				models.ErrorCodeGitAuthFailed,
+				fmt.Sprintf("Authentication failed for %s; credentials may be missing (check ssh-agent, SSH keys, or GIT_SSH_COMMAND)", url),
+				err,
+			)
+		case isNetworkFailure(output):
+			return models.NewAppError(
+				models.ErrorCodeNetworkError,
+				fmt.Sprintf("Network error cloning %s: %s", url, output),
+				err,
+			)
+		default:
 			branchInfo := ""
 			if branch != "" {
 				branchInfo = fmt.Sprintf(" (branch: %s)", branch)
 			}
 			return models.NewAppError(
 				models.ErrorCodeGitCloneError,
-				fmt.Sprintf("Failed to clone repository %s%s after %d attempts", url, branchInfo, attempt),
+				fmt.Sprintf("Failed to clone repository %s%s: %s", url, branchInfo, output),
 				err,
 			)
 		}
-		return err // Will be retried
 	}
 
+	// Servers without partial clone support send everything, with a warning
+	if opts.partial() && strings.Contains(stderr.String(), "filtering not recognized by server") {
+		opts.notify("%s does not support partial clones; every file was fetched", url)
+	}
 	return nil
 }
 
+// cloneErrorOutput returns git clone's stderr without its progress lines
+func cloneErrorOutput(stderr string) string {
+	var lines []string
+	for _, line := range strings.FieldsFunc(stderr, isLineBreak) {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "Cloning into") && !isProgressLine(line) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "; ")
+}
+
+// progressPhases start the lines git writes to report progress, after an
+// optional "remote: " prefix
+var progressPhases = []string{"Enumerating", "Counting", "Compressing", "Total", "Receiving", "Resolving", "Unpacking", "Updating files"}
+
+// isProgressLine reports whether a line of git's stderr is progress output
+func isProgressLine(line string) bool {
+	line = strings.TrimPrefix(line, "remote: ")
+	for _, phase := range progressPhases {
+		if strings.HasPrefix(line, phase) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLineBreak reports whether r ends a line of git's stderr; progress lines
+// are redrawn with a carriage return
+func isLineBreak(r rune) bool {
+	return r == '\n' || r == '\r'
+}
+
+// progressWriter passes each complete progress line git writes to report
+type progressWriter struct {
+	report  func(line string)
+	partial []byte
+}
+
+// Write splits git's stderr into lines, reporting the progress lines
+func (w *progressWriter) Write(p []byte) (int, error) {
+	data := append(w.partial, p...)
+	for {
+		end := bytes.IndexAny(data, "\r\n")
+		if end < 0 {
+			break
+		}
+		if line := strings.TrimSpace(string(data[:end])); isProgressLine(line) {
+			w.report(line)
+		}
+		data = data[end+1:]
+	}
+	w.partial = append(w.partial[:0], data...)
+	return len(p), nil
+}
+
+// authFailureMarkers are git and ssh stderr fragments that indicate missing or rejected credentials
+var authFailureMarkers = []string{
+	"permission denied (publickey",
+	"authentication failed",
+	"could not read username",
+	"could not read password",
+	"host key verification failed",
+	"terminal prompts disabled",
+	"repository not found",
+}
+
+// isAuthFailure reports whether git's stderr describes an authentication problem
+func isAuthFailure(stderr string) bool {
+	lower := strings.ToLower(stderr)
+	for _, marker := range authFailureMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// networkFailureMarkers are git and ssh stderr fragments that indicate a
+// dropped or unreachable connection, which is worth retrying
+var networkFailureMarkers = []string{
+	"could not resolve host",
+	"could not resolve hostname",
+	"temporary failure in name resolution",
+	"connection timed out",
+	"operation timed out",
+	"connection reset",
+	"connection refused",
+	"connection closed",
+	"broken pipe",
+	"network is unreachable",
+	"no route to host",
+	"the remote end hung up unexpectedly",
+	"early eof",
+	"rpc failed",
+	"unexpected disconnect",
+	"gnutls_handshake",
+	"ssl_read",
+	"ssl_connect",
+	"http 429",
+	"error: 500",
+	"error: 502",
+	"error: 503",
+	"error: 504",
+}
+
+// isNetworkFailure reports whether git's stderr describes a transient network problem
+func isNetworkFailure(stderr string) bool {
+	lower := strings.ToLower(stderr)
+	for _, marker := range networkFailureMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
 // checkoutCommit checks out a specific commit in the cloned repository
-func (s *Service) checkoutCommit(repoPath, commit string) error {
-	cmd := exec.Command("git", "checkout", commit)
-	cmd.Dir = repoPath
+func (s *Service) checkoutCommit(ctx context.Context, repoPath, commit string) error {
+	cmd := command(ctx, repoPath, "checkout", commit)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
-	err := cmd.Run()
+	err := runCommand(cmd)
 	if err != nil {
+		if ctx.Err() != nil {
+			return contextError(ctx, "checking out commit "+commit, err)
+		}
 		return models.NewAppError(
 			models.ErrorCodeGitCheckoutError,
 			fmt.Sprintf("Failed to checkout commit %s", commit),
@@ -177,40 +906,232 @@ func (s *Service) GetRepoInfo(repoPath string) (map[string]string, error) {
 	info := make(map[string]string)
 
 	// Get current commit hash
-	cmd := exec.Command("git", "rev-parse", "HEAD")
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+	commit, err := s.GetHeadCommit(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	info["commit"] = commit
+
+	// Get remote URL
+	cmd := command(context.Background(), repoPath, "config", "--get", "remote.origin.url")
+	output, err := commandOutput(cmd)
 	if err != nil {
 		return nil, models.NewAppError(
+			models.ErrorCodeGitError,
+			"Failed to get remote URL",
+			err,
+		)
+	}
+	info["remote_url"] = strings.TrimSpace(string(output))
+
+	return info, nil
+}
+
+// GetHeadCommit returns the commit hash currently checked out in the repository
+func (s *Service) GetHeadCommit(repoPath string) (string, error) {
+	cmd := command(context.Background(), repoPath, "rev-parse", "HEAD")
+	output, err := commandOutput(cmd)
+	if err != nil {
+		return "", models.NewAppError(
 			models.ErrorCodeGitError,
 			"Failed to get current commit hash",
 			err,
 		)
 	}
-	info["commit"] = strings.TrimSpace(string(output))
+	return strings.TrimSpace(string(output)), nil
+}
 
-	// Get remote URL
-	cmd = exec.Command("git", "config", "--get", "remote.origin.url")
-	cmd.Dir = repoPath
-	output, err = cmd.Output()
+// ExecutableFiles returns the slash-separated paths that the tree at HEAD
+// records as executable (mode 100755). A checkout may not carry the bit, for
+// example where core.fileMode is off, so the tree is the authority.
+func (s *Service) ExecutableFiles(repoPath string) (map[string]bool, error) {
+	cmd := command(context.Background(), repoPath, "ls-tree", "-r", "-z", "HEAD")
+	output, err := commandOutput(cmd)
 	if err != nil {
 		return nil, models.NewAppError(
 			models.ErrorCodeGitError,
-			"Failed to get remote URL",
+			"Failed to list repository file modes",
+			err,
+		).WithContext("path", repoPath)
+	}
+
+	executable := make(map[string]bool)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		// Each entry is "<mode> <type> <object>\t<path>"
+		meta, path, ok := strings.Cut(entry, "\t")
+		if ok && strings.HasPrefix(meta, "100755 ") {
+			executable[path] = true
+		}
+	}
+	return executable, nil
+}
+
+// VerifyHeadCommit checks that the repository HEAD is exactly the expected
+// commit, which may be written in either case
+func (s *Service) VerifyHeadCommit(repoPath, expected string) error {
+	actual, err := s.GetHeadCommit(repoPath)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(actual, expected) {
+		return models.NewAppError(
+			models.ErrorCodeGitCommitMismatch,
+			fmt.Sprintf("Cloned commit %s does not match pinned commit %s", actual, expected),
+			nil,
+		).WithContext("expected", expected).WithContext("actual", actual)
+	}
+
+	return nil
+}
+
+// CommitInfo describes a single commit
+type CommitInfo struct {
+	Hash       string
+	AuthorDate time.Time
+	Subject    string // First line of the commit message
+}
+
+// GetCommitInfo resolves commit in the repository to its full hash, author date, and subject
+func (s *Service) GetCommitInfo(repoPath, commit string) (*CommitInfo, error) {
+	cmd := command(context.Background(), repoPath, "log", "-1", "--format="+commitInfoFormat, commit, "--")
+	output, err := commandOutput(cmd)
+	if err != nil {
+		return nil, models.NewAppError(
+			models.ErrorCodeGitCommitNotFound,
+			fmt.Sprintf("Commit %s not found in repository", commit),
 			err,
 		)
 	}
-	info["remote_url"] = strings.TrimSpace(string(output))
 
-	return info, nil
+	return parseCommitInfo(strings.TrimRight(string(output), "\n"), commit)
+}
+
+// AdoptGitDir configures gitDir, the git directory of a clone moved into a
+// project, to use workTree (relative to gitDir) as its working tree and
+// originURL as its remote. Only the files under subdir, a slash-separated path
+// relative to workTree, belong to it: the index marks every other file
+// skip-worktree and sparse checkout keeps later checkouts to subdir, so git run
+// through gitDir never writes, deletes, or reports the project's own files.
+func (s *Service) AdoptGitDir(gitDir, workTree, subdir, originURL string) error {
+	run := func(stdin string, args ...string) (string, error) {
+		// Paths on stdin are resolved against the directory git runs in
+		cmd := command(context.Background(), filepath.Join(gitDir, workTree), append([]string{"--git-dir", gitDir}, args...)...)
+		cmd.Stdin = strings.NewReader(stdin)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := commandOutput(cmd)
+		if err != nil {
+			return "", models.NewAppError(
+				models.ErrorCodeGitError,
+				fmt.Sprintf("Failed to configure git directory %s: %s", gitDir, strings.TrimSpace(stderr.String())),
+				err,
+			)
+		}
+		return string(output), nil
+	}
+
+	for _, setting := range [][2]string{
+		{"core.worktree", workTree},
+		{"core.sparseCheckout", "true"},
+		// Otherwise git clears skip-worktree from the project files it finds on disk
+		{"sparse.expectFilesOutsideOfPatterns", "true"},
+		{"status.showUntrackedFiles", "no"},
+		{"remote.origin.url", originURL},
+	} {
+		if _, err := run("", "config", setting[0], setting[1]); err != nil {
+			return err
+		}
+	}
+
+	sparseFile := filepath.Join(gitDir, "info", "sparse-checkout")
+	if err := os.MkdirAll(filepath.Dir(sparseFile), config.DirPermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, filepath.Dir(sparseFile), err)
+	}
+	if err := os.WriteFile(sparseFile, []byte("/"+subdir+"/\n"), config.FilePermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, sparseFile, err)
+	}
+
+	listed, err := run("", "ls-files", "-z")
+	if err != nil {
+		return err
+	}
+	var outside []string
+	for _, path := range strings.Split(listed, "\x00") {
+		if path != "" && !strings.HasPrefix(path, subdir+"/") {
+			outside = append(outside, path)
+		}
+	}
+	if len(outside) == 0 {
+		return nil
+	}
+	_, err = run(strings.Join(outside, "\x00")+"\x00", "update-index", "--skip-worktree", "-z", "--stdin")
+	return err
+}
+
+// RecentCommits returns up to limit commits reachable from ref in the
+// repository, newest first, such as the recent history of a branch
+func (s *Service) RecentCommits(repoPath, ref string, limit int) ([]CommitInfo, error) {
+	cmd := command(context.Background(), repoPath, "log", "-n", strconv.Itoa(limit), "--format="+commitInfoFormat, ref, "--")
+	output, err := commandOutput(cmd)
+	if err != nil {
+		return nil, models.NewAppError(
+			models.ErrorCodeGitRefNotFound,
+			fmt.Sprintf("Failed to list commits of %s", ref),
+			err,
+		)
+	}
+
+	var commits []CommitInfo
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		info, err := parseCommitInfo(line, ref)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, *info)
+	}
+	return commits, nil
+}
+
+// commitInfoFormat is the git log format parseCommitInfo reads
+const commitInfoFormat = "%H%x00%aI%x00%s"
+
+// parseCommitInfo reads one line of git log output in commitInfoFormat;
+// commit names what was asked for, for error messages
+func parseCommitInfo(line, commit string) (*CommitInfo, error) {
+	fields := strings.SplitN(line, "\x00", 3)
+	if len(fields) != 3 {
+		return nil, models.NewAppError(
+			models.ErrorCodeGitError,
+			fmt.Sprintf("Unexpected git log output for commit %s", commit),
+			nil,
+		)
+	}
+
+	date, err := time.Parse(time.RFC3339, fields[1])
+	if err != nil {
+		return nil, models.NewAppError(
+			models.ErrorCodeGitError,
+			fmt.Sprintf("Failed to parse author date of commit %s", commit),
+			err,
+		)
+	}
+
+	return &CommitInfo{
+		Hash:       fields[0],
+		AuthorDate: date,
+		Subject:    fields[2],
+	}, nil
 }
 
 // IsValidCommit checks if a commit hash exists in the repository
 func (s *Service) IsValidCommit(repoPath, commit string) error {
-	cmd := exec.Command("git", "cat-file", "-e", commit)
-	cmd.Dir = repoPath
+	cmd := command(context.Background(), repoPath, "cat-file", "-e", commit)
 
-	err := cmd.Run()
+	err := runCommand(cmd)
 	if err != nil {
 		return models.NewAppError(
 			models.ErrorCodeGitCommitNotFound,
@@ -221,3 +1142,82 @@ func (s *Service) IsValidCommit(repoPath, commit string) error {
 
 	return nil
 }
+
+// Fetch updates every branch and tag of a clone from its origin, including a
+// bare mirror whose branches are the remote's own. Transient network failures
+// are retried according to retry, and the fetch stops when ctx is done.
+func (s *Service) Fetch(ctx context.Context, repoPath string, retry RetryOptions) error {
+	_, err := withRetries(ctx, retry, "fetching "+repoPath, func() error {
+		return s.fetchOnce(ctx, repoPath)
+	})
+	return err
+}
+
+// fetchOnce performs a single fetch attempt for Fetch
+func (s *Service) fetchOnce(ctx context.Context, repoPath string) error {
+	var stderr bytes.Buffer
+	cmd := command(ctx, repoPath, "fetch", "--prune", "--tags", "origin", "+refs/heads/*:refs/heads/*")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = commandWaitDelay
+
+	if err := runCommand(cmd); err != nil {
+		output := strings.TrimSpace(stderr.String())
+		switch {
+		case ctx.Err() != nil:
+			return contextError(ctx, "fetching "+repoPath, err)
+		case isAuthFailure(output):
+			return models.NewAppError(
+				models.ErrorCodeGitAuthFailed,
+				fmt.Sprintf("Authentication failed fetching %s", repoPath),
+				err,
+			)
+		case isNetworkFailure(output):
+			return models.NewAppError(
+				models.ErrorCodeNetworkError,
+				fmt.Sprintf("Network error fetching %s: %s", repoPath, output),
+				err,
+			)
+		default:
+			return models.NewAppError(
+				models.ErrorCodeGitError,
+				fmt.Sprintf("Failed to fetch %s: %s", repoPath, output),
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// ResolveCommit returns the full hash of the commit a branch, tag, or
+// abbreviated hash names in the repository
+func (s *Service) ResolveCommit(repoPath, ref string) (string, error) {
+	cmd := command(context.Background(), repoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	output, err := commandOutput(cmd)
+	if err != nil {
+		return "", models.NewAppError(
+			models.ErrorCodeGitCommitNotFound,
+			fmt.Sprintf("Ref %s not found in repository", ref),
+			err,
+		)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// contextError describes a git operation stopped because ctx is done: a
+// timeout when its deadline passed, a cancellation otherwise
+func contextError(ctx context.Context, what string, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return models.NewAppError(
+			models.ErrorCodeNetworkTimeout,
+			fmt.Sprintf("Timed out %s", what),
+			err,
+		)
+	}
+	return models.NewAppError(
+		models.ErrorCodeUserCancelled,
+		fmt.Sprintf("Cancelled %s", what),
+		err,
+	)
+}
@@ -1,11 +1,15 @@
 package git
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
@@ -342,3 +346,705 @@ func TestService_FileSystemErrors(t *testing.T) {
 		_ = err
 	}
 }
+
+// initHistoryRepo creates a repository with the given number of commits and
+// returns its path and the commit hashes, oldest first
+func initHistoryRepo(t *testing.T, commits int) (string, []string) {
+	t.Helper()
+
+	repoDir := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+
+	var hashes []string
+	for i := 0; i < commits; i++ {
+		if err := os.WriteFile(filepath.Join(repoDir, "file.txt"), []byte(strings.Repeat("x", i+1)), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		run("add", "file.txt")
+		run("commit", "-m", fmt.Sprintf("commit %d", i+1))
+		hashes = append(hashes, run("rev-parse", "HEAD"))
+	}
+
+	return repoDir, hashes
+}
+
+func TestService_CloneWithOptions_Shallow(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not available, skipping clone tests")
+	}
+
+	repoDir, hashes := initHistoryRepo(t, 3)
+	url := "file://" + repoDir
+
+	tests := []struct {
+		name   string
+		commit string
+		depth  int
+	}{
+		{name: "branch tip at depth 1", commit: hashes[2], depth: 1},
+		{name: "older commit at depth 1", commit: hashes[0], depth: 1},
+		{name: "older commit with full clone", commit: hashes[0], depth: 0},
+		{name: "branch head without pin", commit: "", depth: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notices []string
+			tempDir, err := service.CloneWithOptions(context.Background(), CloneOptions{
+				URL:    url,
+				Branch: "main",
+				Commit: tt.commit,
+				Depth:  tt.depth,
+				Notify: func(msg string) { notices = append(notices, msg) },
+			})
+			if err != nil {
+				t.Fatalf("CloneWithOptions() error = %v", err)
+			}
+			defer func() { _ = service.CleanupTempDir(tempDir) }()
+
+			want := tt.commit
+			if want == "" {
+				want = hashes[2]
+			}
+			head, err := service.GetHeadCommit(tempDir)
+			if err != nil {
+				t.Fatalf("GetHeadCommit() error = %v", err)
+			}
+			if head != want {
+				t.Errorf("HEAD = %s, want %s (notices: %v)", head, want, notices)
+			}
+		})
+	}
+}
+
+func TestService_CloneWithOptions_Partial(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not available, skipping clone tests")
+	}
+
+	repoDir, hashes := initHistoryRepo(t, 2)
+	run := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	for _, file := range []string{"keep/a.txt", "skip/b.txt", "skip/sub/c.txt"} {
+		path := filepath.Join(repoDir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run(repoDir, "add", ".")
+	run(repoDir, "commit", "-m", "directories")
+	run(repoDir, "config", "uploadpack.allowFilter", "true")
+	head := run(repoDir, "rev-parse", "HEAD")
+
+	tests := []struct {
+		name   string
+		commit string
+		depth  int
+	}{
+		{name: "pinned commit at depth 1", commit: head, depth: 1},
+		{name: "branch head with full history", commit: "", depth: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var notices []string
+			tempDir, err := service.CloneWithOptions(context.Background(), CloneOptions{
+				URL:    "file://" + repoDir,
+				Branch: "main",
+				Commit: tt.commit,
+				Depth:  tt.depth,
+				Sparse: []string{"/*", "!/*/", "/keep/"},
+				Notify: func(msg string) { notices = append(notices, msg) },
+			})
+			if err != nil {
+				t.Fatalf("CloneWithOptions() error = %v", err)
+			}
+			defer func() { _ = service.CleanupTempDir(tempDir) }()
+
+			if got, err := service.GetHeadCommit(tempDir); err != nil || got != head {
+				t.Errorf("HEAD = %s, %v; want %s (notices: %v)", got, err, head, notices)
+			}
+			for _, file := range []string{"file.txt", "keep/a.txt"} {
+				if _, err := os.Stat(filepath.Join(tempDir, filepath.FromSlash(file))); err != nil {
+					t.Errorf("Expected %s to be checked out: %v", file, err)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(tempDir, "skip", "b.txt")); !os.IsNotExist(err) {
+				t.Errorf("Expected skip/b.txt to be left out of the checkout")
+			}
+			if info, err := os.Stat(filepath.Join(tempDir, "skip", "sub")); err != nil || !info.IsDir() {
+				t.Errorf("Expected the skip/sub directory to be created, got %v", err)
+			}
+			if missing := run(tempDir, "rev-list", "--objects", "--missing=print", "HEAD"); !strings.Contains(missing, "?") {
+				t.Errorf("Expected the blobs outside the sparse patterns not to be fetched (notices: %v)", notices)
+			}
+		})
+	}
+
+	t.Run("pinned older commit", func(t *testing.T) {
+		tempDir, err := service.CloneWithOptions(context.Background(), CloneOptions{
+			URL:    "file://" + repoDir,
+			Branch: "main",
+			Commit: hashes[0],
+			Depth:  1,
+			Sparse: []string{"/*", "!/*/", "/keep/"},
+		})
+		if err != nil {
+			t.Fatalf("CloneWithOptions() error = %v", err)
+		}
+		defer func() { _ = service.CleanupTempDir(tempDir) }()
+
+		if got, err := service.GetHeadCommit(tempDir); err != nil || got != hashes[0] {
+			t.Errorf("HEAD = %s, %v; want %s", got, err, hashes[0])
+		}
+		if data, err := os.ReadFile(filepath.Join(tempDir, "file.txt")); err != nil || string(data) != "x" {
+			t.Errorf("file.txt = %q, %v; want the first commit's content", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(tempDir, "keep")); !os.IsNotExist(err) {
+			t.Errorf("Expected the first commit to have no keep directory")
+		}
+	})
+}
+
+func TestService_CloneWithOptions_Submodules(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not available, skipping clone tests")
+	}
+	// Git refuses submodules from local paths unless allowed
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	run := func(dir string, args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	sharedDir, _ := initHistoryRepo(t, 1)
+	repoDir, _ := initHistoryRepo(t, 1)
+	run(repoDir, "submodule", "add", "file://"+sharedDir, "agents/shared")
+	run(repoDir, "commit", "-m", "add shared agents")
+	head := run(repoDir, "rev-parse", "HEAD")
+
+	clone := func(t *testing.T, url string, submodules bool) (string, error) {
+		t.Helper()
+		tempDir, err := service.CloneWithOptions(context.Background(), CloneOptions{
+			URL:        url,
+			Branch:     "main",
+			Commit:     head,
+			Depth:      1,
+			Submodules: submodules,
+			Retry:      RetryOptions{Attempts: 1},
+		})
+		if err == nil {
+			t.Cleanup(func() { _ = service.CleanupTempDir(tempDir) })
+		}
+		return tempDir, err
+	}
+
+	t.Run("left out by default", func(t *testing.T) {
+		tempDir, err := clone(t, "file://"+repoDir, false)
+		if err != nil {
+			t.Fatalf("CloneWithOptions() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(tempDir, "agents", "shared", "file.txt")); !os.IsNotExist(err) {
+			t.Errorf("Expected the submodule not to be checked out, got %v", err)
+		}
+	})
+
+	t.Run("checked out", func(t *testing.T) {
+		tempDir, err := clone(t, "file://"+repoDir, true)
+		if err != nil {
+			t.Fatalf("CloneWithOptions() error = %v", err)
+		}
+		if data, err := os.ReadFile(filepath.Join(tempDir, "agents", "shared", "file.txt")); err != nil || string(data) != "x" {
+			t.Errorf("Submodule file = %q, %v; want it checked out", data, err)
+		}
+	})
+
+	t.Run("failure names the submodule", func(t *testing.T) {
+		brokenDir, _ := initHistoryRepo(t, 1)
+		run(brokenDir, "submodule", "add", "file://"+sharedDir, "vendor/gone")
+		run(brokenDir, "config", "--file", ".gitmodules", "submodule.vendor/gone.url", "file://"+filepath.Join(t.TempDir(), "missing"))
+		run(brokenDir, "commit", "-am", "point the submodule nowhere")
+		head = run(brokenDir, "rev-parse", "HEAD")
+
+		_, err := clone(t, "file://"+brokenDir, true)
+		if !models.IsErrorCode(err, models.ErrorCodeGitCloneError) || !strings.Contains(err.Error(), "submodule vendor/gone") {
+			t.Errorf("Expected a %s naming vendor/gone, got %v", models.ErrorCodeGitCloneError, err)
+		}
+	})
+}
+
+func TestService_CloneWithOptions_UnknownCommit(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not available, skipping clone tests")
+	}
+
+	repoDir, _ := initHistoryRepo(t, 2)
+
+	var notices []string
+	_, err := service.CloneWithOptions(context.Background(), CloneOptions{
+		URL:    "file://" + repoDir,
+		Branch: "main",
+		Commit: strings.Repeat("a", 40),
+		Depth:  1,
+		Notify: func(msg string) { notices = append(notices, msg) },
+	})
+	if err == nil {
+		t.Fatal("Expected error for a commit that does not exist")
+	}
+	if !models.IsErrorCode(err, models.ErrorCodeGitCommitNotFound) {
+		t.Errorf("Expected %s, got %v", models.ErrorCodeGitCommitNotFound, err)
+	}
+	if len(notices) == 0 {
+		t.Error("Expected a notice when falling back from the shallow clone")
+	}
+}
+
+func TestService_CloneWithOptions_Progress(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not available, skipping clone tests")
+	}
+
+	repoDir, _ := initHistoryRepo(t, 2)
+
+	var lines []string
+	tempDir, err := service.CloneWithOptions(context.Background(), CloneOptions{
+		URL:      "file://" + repoDir,
+		Branch:   "main",
+		Progress: func(line string) { lines = append(lines, line) },
+	})
+	if err != nil {
+		t.Fatalf("CloneWithOptions() error = %v", err)
+	}
+	defer func() { _ = service.CleanupTempDir(tempDir) }()
+
+	if len(lines) == 0 {
+		t.Fatal("Expected git's progress lines to be reported")
+	}
+	for _, line := range lines {
+		if !isProgressLine(line) {
+			t.Errorf("Reported %q, which is not a progress line", line)
+		}
+	}
+}
+
+func TestSetOutputStream(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not available, skipping clone tests")
+	}
+
+	repoDir, hashes := initHistoryRepo(t, 2)
+
+	var streamed bytes.Buffer
+	SetOutputStream(&streamed)
+	t.Cleanup(func() { SetOutputStream(nil) })
+
+	var lines []string
+	tempDir, err := service.CloneWithOptions(context.Background(), CloneOptions{
+		URL:      "file://" + repoDir,
+		Branch:   "main",
+		Progress: func(line string) { lines = append(lines, line) },
+	})
+	if err != nil {
+		t.Fatalf("CloneWithOptions() error = %v", err)
+	}
+	defer func() { _ = service.CleanupTempDir(tempDir) }()
+
+	// git's output is copied, and still captured as before
+	output := streamed.String()
+	if !strings.Contains(output, "$ git clone") || !strings.Contains(output, "Cloning into") {
+		t.Errorf("Streamed output = %q, want the clone command and git's messages", output)
+	}
+	if len(lines) == 0 {
+		t.Error("Expected progress lines to be reported while streaming")
+	}
+	head, err := service.GetHeadCommit(tempDir)
+	if err != nil || head != hashes[1] {
+		t.Errorf("GetHeadCommit() = %s, %v; want %s", head, err, hashes[1])
+	}
+	if !strings.Contains(streamed.String(), hashes[1]) {
+		t.Error("Expected the output of rev-parse to be streamed")
+	}
+
+	SetOutputStream(nil)
+	streamed.Reset()
+	if _, err := service.GetHeadCommit(tempDir); err != nil {
+		t.Fatalf("GetHeadCommit() error = %v", err)
+	}
+	if streamed.Len() != 0 {
+		t.Errorf("Streamed %q after turning streaming off", streamed.String())
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	var lines []string
+	w := &progressWriter{report: func(line string) { lines = append(lines, line) }}
+
+	// Lines arrive split across writes and redrawn with carriage returns
+	for _, chunk := range []string{
+		"Cloning into 'repo'...\nremote: Counting obj",
+		"ects:  50% (1/2)   \rremote: Counting objects: 100% (2/2), done.\n",
+		"Receiving objects:  50% (1/2)\rReceiving objects: 100% (2/2), done.\n",
+		"warning: something else\nResolving del",
+	} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	want := []string{
+		"remote: Counting objects:  50% (1/2)",
+		"remote: Counting objects: 100% (2/2), done.",
+		"Receiving objects:  50% (1/2)",
+		"Receiving objects: 100% (2/2), done.",
+	}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("Reported %q, want %q", lines, want)
+	}
+}
+
+func TestCloneErrorOutput(t *testing.T) {
+	stderr := "Cloning into 'repo'...\nremote: Counting objects:  50% (1/2)\rremote: Counting objects: 100% (2/2), done.\n" +
+		"fatal: Remote branch nope not found in upstream origin\n"
+	if got, want := cloneErrorOutput(stderr), "fatal: Remote branch nope not found in upstream origin"; got != want {
+		t.Errorf("cloneErrorOutput() = %q, want %q", got, want)
+	}
+}
+
+func TestService_CheckRemoteRefAndCommit(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not available, skipping remote tests")
+	}
+
+	repoDir, hashes := initHistoryRepo(t, 3)
+	url := "file://" + repoDir
+	tag := exec.Command("git", "tag", "v1", hashes[0])
+	tag.Dir = repoDir
+	if output, err := tag.CombinedOutput(); err != nil {
+		t.Fatalf("git tag failed: %v\n%s", err, output)
+	}
+
+	refs := []struct {
+		ref  string
+		want bool
+	}{
+		{"main", true},
+		{"v1", true},
+		{"ain", false}, // ls-remote alone would match the trailing part of main
+		{"missing", false},
+	}
+	for _, tt := range refs {
+		err := service.CheckRemoteRef(url, tt.ref)
+		if tt.want && err != nil {
+			t.Errorf("CheckRemoteRef(%s) error = %v", tt.ref, err)
+		}
+		if !tt.want && !models.IsErrorCode(err, models.ErrorCodeGitRefNotFound) {
+			t.Errorf("CheckRemoteRef(%s) = %v, want %s", tt.ref, err, models.ErrorCodeGitRefNotFound)
+		}
+	}
+
+	// The branch tip, a tagged commit, and a commit no ref points at
+	for _, commit := range []string{hashes[2], hashes[0], hashes[1]} {
+		if err := service.CheckRemoteCommit(url, commit); err != nil {
+			t.Errorf("CheckRemoteCommit(%s) error = %v", commit, err)
+		}
+	}
+	if err := service.CheckRemoteCommit(url, strings.Repeat("a", 40)); !models.IsErrorCode(err, models.ErrorCodeGitCommitNotFound) {
+		t.Errorf("CheckRemoteCommit() of a missing commit = %v, want %s", err, models.ErrorCodeGitCommitNotFound)
+	}
+
+	if err := service.CheckRemoteRef("file://"+filepath.Join(t.TempDir(), "missing"), "main"); !models.IsErrorCode(err, models.ErrorCodeNetworkError) {
+		t.Errorf("CheckRemoteRef() of a missing repository = %v, want %s", err, models.ErrorCodeNetworkError)
+	}
+
+	if tip, err := service.RemoteBranchCommit(url, "main"); err != nil || tip != hashes[2] {
+		t.Errorf("RemoteBranchCommit(main) = %s, %v; want %s", tip, err, hashes[2])
+	}
+	// Tags are not branches, and only whole names match
+	for _, branch := range []string{"v1", "ain"} {
+		if _, err := service.RemoteBranchCommit(url, branch); !models.IsErrorCode(err, models.ErrorCodeGitRefNotFound) {
+			t.Errorf("RemoteBranchCommit(%s) error = %v, want %s", branch, err, models.ErrorCodeGitRefNotFound)
+		}
+	}
+}
+
+func TestIsAuthFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   bool
+	}{
+		{
+			name:   "ssh key rejected",
+			stderr: "git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.",
+			want:   true,
+		},
+		{
+			name:   "https without credentials",
+			stderr: "fatal: could not read Username for 'https://github.com': terminal prompts disabled",
+			want:   true,
+		},
+		{
+			name:   "unknown host key",
+			stderr: "Host key verification failed.\nfatal: Could not read from remote repository.",
+			want:   true,
+		},
+		{
+			name:   "private repo hidden from anonymous user",
+			stderr: "remote: Repository not found.\nfatal: repository 'https://github.com/org/private.git/' not found",
+			want:   true,
+		},
+		{
+			name:   "network failure",
+			stderr: "fatal: unable to access 'https://github.com/org/repo.git/': Could not resolve host: github.com",
+			want:   false,
+		},
+		{
+			name:   "missing branch",
+			stderr: "fatal: Remote branch nope not found in upstream origin",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAuthFailure(tt.stderr); got != tt.want {
+				t.Errorf("isAuthFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_VerifyHeadCommit(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not installed, skipping test")
+	}
+
+	repoDir, hashes := initHistoryRepo(t, 2)
+
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "head", expected: hashes[1]},
+		{name: "head in uppercase", expected: strings.ToUpper(hashes[1])},
+		{name: "older commit", expected: hashes[0], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.VerifyHeadCommit(repoDir, tt.expected)
+			if tt.wantErr {
+				if !models.IsErrorCode(err, models.ErrorCodeGitCommitMismatch) {
+					t.Errorf("Expected commit mismatch error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("VerifyHeadCommit() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestService_GetCommitInfo(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not installed, skipping test")
+	}
+
+	repoDir, hashes := initHistoryRepo(t, 2)
+
+	tests := []struct {
+		name        string
+		commit      string
+		wantHash    string
+		wantSubject string
+		wantErr     bool
+	}{
+		{name: "full hash", commit: hashes[0], wantHash: hashes[0], wantSubject: "commit 1"},
+		{name: "short hash", commit: hashes[1][:8], wantHash: hashes[1], wantSubject: "commit 2"},
+		{name: "ref", commit: "HEAD", wantHash: hashes[1], wantSubject: "commit 2"},
+		{name: "unknown commit", commit: strings.Repeat("0", 40), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := service.GetCommitInfo(repoDir, tt.commit)
+			if tt.wantErr {
+				if !models.IsErrorCode(err, models.ErrorCodeGitCommitNotFound) {
+					t.Errorf("Expected commit not found error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetCommitInfo() error = %v", err)
+			}
+			if info.Hash != tt.wantHash || info.Subject != tt.wantSubject {
+				t.Errorf("GetCommitInfo() = %+v, want hash %s subject %q", info, tt.wantHash, tt.wantSubject)
+			}
+			if info.AuthorDate.IsZero() {
+				t.Error("Expected author date to be set")
+			}
+		})
+	}
+}
+
+func TestService_RecentCommits(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not installed, skipping test")
+	}
+
+	repoDir, hashes := initHistoryRepo(t, 3)
+
+	tests := []struct {
+		name       string
+		ref        string
+		limit      int
+		wantHashes []string
+		wantErr    bool
+	}{
+		{name: "newest first", ref: "HEAD", limit: 10, wantHashes: []string{hashes[2], hashes[1], hashes[0]}},
+		{name: "limited", ref: "HEAD", limit: 2, wantHashes: []string{hashes[2], hashes[1]}},
+		{name: "from an older commit", ref: hashes[1], limit: 10, wantHashes: []string{hashes[1], hashes[0]}},
+		{name: "unknown ref", ref: "no-such-branch", limit: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, err := service.RecentCommits(repoDir, tt.ref, tt.limit)
+			if tt.wantErr {
+				if !models.IsErrorCode(err, models.ErrorCodeGitRefNotFound) {
+					t.Errorf("Expected ref not found error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecentCommits() error = %v", err)
+			}
+			if len(commits) != len(tt.wantHashes) {
+				t.Fatalf("RecentCommits() returned %d commits, want %d", len(commits), len(tt.wantHashes))
+			}
+			for i, commit := range commits {
+				if commit.Hash != tt.wantHashes[i] {
+					t.Errorf("commit %d = %s, want %s", i, commit.Hash, tt.wantHashes[i])
+				}
+				if commit.Subject == "" || commit.AuthorDate.IsZero() {
+					t.Errorf("commit %d is missing its subject or date: %+v", i, commit)
+				}
+			}
+		})
+	}
+}
+
+func TestService_ExecutableFiles(t *testing.T) {
+	service := New()
+	if err := service.ValidateGitInstalled(); err != nil {
+		t.Skip("Git not installed, skipping test")
+	}
+
+	repoDir, _ := initHistoryRepo(t, 1)
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	// The tree marks the hook executable even though the file on disk is not
+	hookPath := filepath.Join(repoDir, "hooks", "run hook.sh")
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		t.Fatalf("Failed to create hooks directory: %v", err)
+	}
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+	run("add", "hooks")
+	run("update-index", "--chmod=+x", "hooks/run hook.sh")
+	run("commit", "-m", "add hook")
+
+	executable, err := service.ExecutableFiles(repoDir)
+	if err != nil {
+		t.Fatalf("ExecutableFiles() error = %v", err)
+	}
+	if !executable["hooks/run hook.sh"] {
+		t.Errorf("Expected hooks/run hook.sh to be executable, got %v", executable)
+	}
+	if executable["file.txt"] {
+		t.Error("Expected file.txt not to be executable")
+	}
+
+	if _, err := service.ExecutableFiles(t.TempDir()); !models.IsErrorCode(err, models.ErrorCodeGitError) {
+		t.Errorf("Expected git error outside a repository, got %v", err)
+	}
+}
+
+func TestService_CloneWithOptions_Timeout(t *testing.T) {
+	tempRoot := t.TempDir()
+	t.Setenv("TMPDIR", tempRoot)
+	// A transport that never answers stands in for a hung server
+	t.Setenv("GIT_SSH_COMMAND", "sh -c 'sleep 30' --")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	service := New()
+	_, err := service.CloneWithOptions(ctx, CloneOptions{URL: "ssh://git@example.invalid/repo.git"})
+	if !models.IsErrorCode(err, models.ErrorCodeNetworkTimeout) {
+		t.Fatalf("CloneWithOptions() error = %v, want %s", err, models.ErrorCodeNetworkTimeout)
+	}
+	if !strings.Contains(err.Error(), "example.invalid") {
+		t.Errorf("timeout error %q does not name the URL", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("CloneWithOptions() took %s after its deadline", elapsed)
+	}
+
+	entries, err := os.ReadDir(tempRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("temporary clone directory left behind: %v", entries)
+	}
+}
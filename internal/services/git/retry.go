@@ -0,0 +1,68 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// RetryOptions controls how network steps are retried
+type RetryOptions struct {
+	// Attempts is the most times a step is tried (0 uses config.DefaultGitRetries)
+	Attempts int
+
+	// OnRetry receives a message before each retry (optional)
+	OnRetry func(message string)
+}
+
+// Backoff between attempts doubles from retryBaseDelay up to retryMaxDelay.
+// They are variables so tests can shorten them.
+var (
+	retryBaseDelay = config.GitRetryBaseDelay
+	retryMaxDelay  = config.GitRetryMaxDelay
+)
+
+// withRetries runs step until it succeeds, fails with an error that is not
+// transient, has been tried the configured number of times, or ctx is done. It
+// returns the number of attempts made and the last error.
+func withRetries(ctx context.Context, retry RetryOptions, what string, step func() error) (int, error) {
+	attempts := retry.Attempts
+	if attempts <= 0 {
+		attempts = config.DefaultGitRetries
+	}
+
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := step()
+		if err == nil || !isTransient(err) || attempt >= attempts {
+			return attempt, err
+		}
+		if ctx.Err() != nil {
+			return attempt, contextError(ctx, what, err)
+		}
+
+		if retry.OnRetry != nil {
+			retry.OnRetry(fmt.Sprintf("Attempt %d/%d failed %s, retrying in %s: %v", attempt, attempts, what, delay, err))
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, contextError(ctx, what, err)
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+// isTransient reports whether an error comes from a dropped or timed-out
+// connection, as opposed to failures such as bad credentials or a missing
+// repository that retrying cannot fix
+func isTransient(err error) bool {
+	return models.IsErrorCode(err, models.ErrorCodeNetworkError) ||
+		models.IsErrorCode(err, models.ErrorCodeNetworkTimeout)
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+func TestWithRetries(t *testing.T) {
+	oldBase, oldMax := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 2*time.Millisecond
+	defer func() { retryBaseDelay, retryMaxDelay = oldBase, oldMax }()
+
+	networkErr := models.NewAppError(models.ErrorCodeNetworkError, "connection reset", nil)
+	authErr := models.NewAppError(models.ErrorCodeGitAuthFailed, "authentication failed", nil)
+
+	tests := []struct {
+		name         string
+		attempts     int
+		failures     []error // Errors returned by successive attempts before succeeding
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "succeeds first time",
+			attempts:     3,
+			wantAttempts: 1,
+		},
+		{
+			name:         "recovers from a dropped connection",
+			attempts:     3,
+			failures:     []error{networkErr, networkErr},
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after the configured attempts",
+			attempts:     2,
+			failures:     []error{networkErr, networkErr, networkErr},
+			wantAttempts: 2,
+			wantErr:      networkErr,
+		},
+		{
+			name:         "does not retry authentication failures",
+			attempts:     3,
+			failures:     []error{authErr},
+			wantAttempts: 1,
+			wantErr:      authErr,
+		},
+		{
+			name:         "zero attempts uses the default",
+			attempts:     0,
+			failures:     []error{networkErr, networkErr},
+			wantAttempts: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, retries := 0, 0
+			retry := RetryOptions{
+				Attempts: tt.attempts,
+				OnRetry:  func(string) { retries++ },
+			}
+
+			attempts, err := withRetries(context.Background(), retry, "test", func() error {
+				calls++
+				if calls <= len(tt.failures) {
+					return tt.failures[calls-1]
+				}
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("withRetries() error = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts || calls != tt.wantAttempts {
+				t.Errorf("withRetries() made %d attempts (%d calls), want %d", attempts, calls, tt.wantAttempts)
+			}
+			if retries != tt.wantAttempts-1 {
+				t.Errorf("OnRetry called %d times, want %d", retries, tt.wantAttempts-1)
+			}
+		})
+	}
+}
+
+func TestIsNetworkFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   bool
+	}{
+		{
+			name:   "unresolvable host",
+			stderr: "fatal: unable to access 'https://github.com/org/repo.git/': Could not resolve host: github.com",
+			want:   true,
+		},
+		{
+			name:   "dropped connection",
+			stderr: "error: RPC failed; curl 56 GnuTLS recv error (-54): Error in the pull function.\nfatal: early EOF",
+			want:   true,
+		},
+		{
+			name:   "ssh connection refused",
+			stderr: "ssh: connect to host github.com port 22: Connection refused\nfatal: Could not read from remote repository.",
+			want:   true,
+		},
+		{
+			name:   "missing branch",
+			stderr: "fatal: Remote branch nope not found in upstream origin",
+			want:   false,
+		},
+		{
+			name:   "not a repository",
+			stderr: "fatal: repository '/tmp/nope' does not exist",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNetworkFailure(tt.stderr); got != tt.want {
+				t.Errorf("isNetworkFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// stream receives a copy of every git command's output as it runs; nil keeps
+// the output captured only
+var (
+	streamMu sync.Mutex
+	stream   io.Writer
+)
+
+// SetOutputStream makes every git command copy its stdout and stderr to w as
+// it runs, each command introduced by a "$ git ..." line, for debugging clone
+// problems with git's own messages. Output is still captured and checked as
+// usual. Passing nil turns streaming off.
+func SetOutputStream(w io.Writer) {
+	streamMu.Lock()
+	defer streamMu.Unlock()
+	stream = w
+}
+
+// streamWriter writes to the stream, one write at a time, since templates are
+// fetched concurrently and stdout and stderr are copied from separate pipes
+type streamWriter struct{}
+
+func (streamWriter) Write(p []byte) (int, error) {
+	streamMu.Lock()
+	defer streamMu.Unlock()
+	if stream == nil {
+		return len(p), nil
+	}
+	// A failing terminal must not fail the git command
+	_, _ = stream.Write(p)
+	return len(p), nil
+}
+
+// streaming reports whether git output is copied to a stream, announcing cmd
+// on it when it is
+func streaming(cmd *exec.Cmd) bool {
+	streamMu.Lock()
+	defer streamMu.Unlock()
+	if stream == nil {
+		return false
+	}
+	fmt.Fprintf(stream, "$ git %s\n", strings.Join(cmd.Args[1:], " "))
+	return true
+}
+
+// tee adds the stream to a command's output writer, which may be nil
+func tee(w io.Writer) io.Writer {
+	if w == nil {
+		return streamWriter{}
+	}
+	return io.MultiWriter(w, streamWriter{})
+}
+
+// runCommand runs cmd like cmd.Run, copying its output to the stream
+func runCommand(cmd *exec.Cmd) error {
+	if streaming(cmd) {
+		cmd.Stdout = tee(cmd.Stdout)
+		cmd.Stderr = tee(cmd.Stderr)
+	}
+	return cmd.Run()
+}
+
+// commandOutput runs cmd like cmd.Output, copying its output to the stream. As with
+// cmd.Output, an exit error carries stderr unless the caller captures it.
+func commandOutput(cmd *exec.Cmd) ([]byte, error) {
+	if !streaming(cmd) {
+		return cmd.Output()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = tee(&stdout)
+	captureStderr := cmd.Stderr == nil
+	if captureStderr {
+		cmd.Stderr = tee(&stderr)
+	} else {
+		cmd.Stderr = tee(cmd.Stderr)
+	}
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if captureStderr && errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// commandCombinedOutput runs cmd like cmd.CombinedOutput, copying its output to the stream
+func commandCombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	if !streaming(cmd) {
+		return cmd.CombinedOutput()
+	}
+
+	var combined bytes.Buffer
+	// One writer for both, so exec copies them through a single pipe
+	w := tee(&combined)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := cmd.Run()
+	return combined.Bytes(), err
+}
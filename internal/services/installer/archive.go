@@ -0,0 +1,443 @@
+package installer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// ArchivePath returns where the source archive of the template's pinned
+// commit lives in archiveDir, named by the template's CacheKey, or "" when the
+// template is not installed through an archive: no directory was given, the
+// template is a plain local directory, or it has no pinned commit to name the
+// archive by. Archives named by the commit alone, as earlier versions wrote
+// them, are still used when there is none under the key.
+func ArchivePath(template templates.Template, archiveDir string) string {
+	if archiveDir == "" || !template.IsPinned() || (template.IsLocal() && !template.IsLocalGitRepo()) {
+		return ""
+	}
+	archive := filepath.Join(archiveDir, template.CacheKey()+".tar")
+	if _, err := os.Stat(archive); os.IsNotExist(err) {
+		legacy := filepath.Join(archiveDir, strings.ToLower(template.Commit)+".tar")
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return archive
+}
+
+// archivedSource extracts the archive at archive into a temporary source
+// directory. It returns nil, with no error, when there is no archive to use:
+// it is missing, unreadable, or its files do not match the template's
+// TreeHash. A bad archive is removed so the next fetch replaces it.
+func (s *Service) archivedSource(archive string, template templates.Template, installConfig models.InstallConfig) *templateSource {
+	if _, err := os.Stat(archive); err != nil {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "strategic-claude-archive-*")
+	if err != nil {
+		slog.Warn("Failed to create a directory for the template archive", "error", err)
+		return nil
+	}
+	discard := func(reason string, err error) *templateSource {
+		_ = os.RemoveAll(dir) // Best effort cleanup
+		slog.Warn("Ignoring template archive; fetching the template instead", "archive", archive, "reason", reason, "error", err)
+		_ = os.Remove(archive)
+		return nil
+	}
+
+	if err := extractArchive(archive, dir); err != nil {
+		return discard("unreadable", err)
+	}
+	if !installConfig.SkipVerify {
+		if err := verifyTreeHash(dir, template); err != nil {
+			return discard("tree hash mismatch", err)
+		}
+	}
+
+	slog.Info("Using template archive", "template", template.ID, "archive", archive)
+	return &templateSource{
+		Dir:         dir,
+		Commit:      template.Commit,
+		fromArchive: true,
+		cleanup: func() error {
+			return os.RemoveAll(dir)
+		},
+	}
+}
+
+// writeArchive saves the checkout in sourceDir as the archive at archive,
+// leaving out what the default exclude patterns do, such as .git, so that it
+// holds every file an install of the commit can read. Entries are in path
+// order with fixed times and owners, so the same commit always archives to the
+// same bytes. Executable bits come from the git tree, as on install.
+func (s *Service) writeArchive(sourceDir, archive string) error {
+	filter, err := newFileFilter(nil, config.GetDefaultExcludePatterns())
+	if err != nil {
+		return err
+	}
+	executable, err := s.gitService.ExecutableFiles(sourceDir)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(archive), config.DirPermissions); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, filepath.Dir(archive), err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(archive), "."+filepath.Base(archive)+"-*")
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, filepath.Dir(archive), err)
+	}
+	defer os.Remove(file.Name()) // Fails harmlessly once renamed
+
+	tw := tar.NewWriter(file)
+	err = filepath.WalkDir(sourceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == sourceDir {
+			return err
+		}
+		rel, err := filepath.Rel(sourceDir, p)
+		if err != nil {
+			return err
+		}
+		if filter.Skip(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := filepath.ToSlash(rel)
+		header := &tar.Header{Name: name, ModTime: time.Unix(0, 0), Format: tar.FormatPAX}
+		switch {
+		case d.IsDir():
+			header.Typeflag, header.Name, header.Mode = tar.TypeDir, name+"/", 0755
+		case d.Type()&os.ModeSymlink != 0:
+			header.Typeflag, header.Mode = tar.TypeSymlink, 0777
+			if header.Linkname, err = os.Readlink(p); err != nil {
+				return err
+			}
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			header.Typeflag, header.Mode, header.Size = tar.TypeReg, 0644, info.Size()
+			if executable[name] || info.Mode()&0111 != 0 {
+				header.Mode = 0755
+			}
+		default:
+			return nil // Sockets, devices, and the like are never installed
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, archive, err)
+	}
+
+	if err := os.Rename(file.Name(), archive); err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, archive, err)
+	}
+	return nil
+}
+
+// archiveEntry is one directory, file, or symbolic link in an archive
+type archiveEntry struct {
+	name     string      // Slash-separated path, without a trailing slash
+	mode     fs.FileMode // Type bits and permissions
+	linkname string      // Where a symbolic link points
+	open     func() (io.ReadCloser, error)
+}
+
+// walkArchive calls fn for each entry of the tar, gzip-compressed tar, or zip
+// archive at archive, in archive order, telling the formats apart by their
+// first bytes. Global headers, such as the commit git archive records, are
+// skipped; hard links, devices, and the like are reported as errors.
+func walkArchive(archive string, fn func(archiveEntry) error) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	magic := make([]byte, 4)
+	n, _ := io.ReadFull(file, magic)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var r io.Reader = file
+	switch {
+	case n == 4 && string(magic) == "PK\x03\x04":
+		return walkZip(file, fn)
+	case n >= 2 && magic[0] == 0x1f && magic[1] == 0x8b:
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		entry := archiveEntry{
+			name:     archiveName(header.Name),
+			mode:     header.FileInfo().Mode(),
+			linkname: header.Linkname,
+			open:     func() (io.ReadCloser, error) { return io.NopCloser(tr), nil },
+		}
+		switch header.Typeflag {
+		case tar.TypeDir, tar.TypeSymlink, tar.TypeReg:
+			if entry.name == "." {
+				continue // The directory being unpacked into
+			}
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			return fmt.Errorf("entry %q has unsupported type %q", header.Name, header.Typeflag)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// walkZip calls fn for each entry of the zip archive in file
+func walkZip(file *os.File, fn func(archiveEntry) error) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		entry := archiveEntry{
+			name: archiveName(f.Name),
+			mode: f.Mode(),
+			open: func() (io.ReadCloser, error) { return f.Open() },
+		}
+		switch {
+		case entry.name == ".":
+			continue // The directory being unpacked into
+		case entry.mode.IsDir(), entry.mode.IsRegular():
+		case entry.mode&fs.ModeSymlink != 0:
+			// A zip stores where a symbolic link points as its content
+			if entry.linkname, err = readLinkname(f); err != nil {
+				return fmt.Errorf("entry %q: %w", f.Name, err)
+			}
+		default:
+			return fmt.Errorf("entry %q has unsupported type %s", f.Name, entry.mode.Type())
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveName cleans an entry's path, so "./core/" and "core" are alike;
+// paths that leave the archive still do after cleaning
+func archiveName(name string) string {
+	return path.Clean(strings.TrimSuffix(name, "/"))
+}
+
+// readLinkname reads where a zipped symbolic link points
+func readLinkname(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	target, err := io.ReadAll(io.LimitReader(rc, 4096))
+	return string(target), err
+}
+
+// checkEntry rejects an entry whose path would leave the directory the
+// archive is unpacked into, whether it is absolute, climbs out with "..", or
+// sits inside a symbolic link unpacked before it. links collects the
+// archive's symbolic links as they are checked.
+func checkEntry(entry archiveEntry, links map[string]bool) error {
+	if !filepath.IsLocal(filepath.FromSlash(entry.name)) {
+		return fmt.Errorf("entry %q is outside the archive", entry.name)
+	}
+	for parent := path.Dir(entry.name); parent != "."; parent = path.Dir(parent) {
+		if links[parent] {
+			return fmt.Errorf("entry %q is inside the symbolic link %q", entry.name, parent)
+		}
+	}
+	if entry.mode&fs.ModeSymlink != 0 {
+		links[entry.name] = true
+	}
+	return nil
+}
+
+// checkArchive reads through an archive given with --archive before anything
+// is unpacked, checking every entry with checkEntry and that it holds an
+// exported template: a framework directory at its top level, or inside its
+// only top-level directory, as in the archives git forges offer for download.
+// It returns the directory that holds the template, "" for the top level.
+func checkArchive(archive string) (string, error) {
+	links := make(map[string]bool)
+	tops := make(map[string]bool)
+	framework := make(map[string]bool)
+	err := walkArchive(archive, func(entry archiveEntry) error {
+		if err := checkEntry(entry, links); err != nil {
+			return err
+		}
+		parts := strings.Split(entry.name, "/")
+		tops[parts[0]] = true
+		// The framework directory counts wherever it is listed, or anything below it
+		if !entry.mode.IsDir() {
+			parts = parts[:len(parts)-1]
+		}
+		switch {
+		case len(parts) >= 1 && parts[0] == config.StrategicClaudeBasicDir:
+			framework[""] = true
+		case len(parts) >= 2 && parts[1] == config.StrategicClaudeBasicDir:
+			framework[parts[0]] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return "", models.NewAppError(models.ErrorCodePathTraversal, fmt.Sprintf("archive %s is unsafe to unpack", archive), err)
+	}
+
+	switch {
+	case framework[""]:
+		return "", nil
+	case len(tops) == 1:
+		for top := range tops {
+			if framework[top] {
+				return top, nil
+			}
+		}
+	}
+	return "", models.NewAppError(
+		models.ErrorCodeValidationFailed,
+		fmt.Sprintf("archive %s holds no %s directory at its top level or in a single top-level directory", archive, config.StrategicClaudeBasicDir),
+		nil,
+	)
+}
+
+// extractArchive unpacks an archive into dir, which must be empty, checking
+// each entry with checkEntry before it is written
+func extractArchive(archive, dir string) error {
+	links := make(map[string]bool)
+	return walkArchive(archive, func(entry archiveEntry) error {
+		if err := checkEntry(entry, links); err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(entry.name))
+
+		switch {
+		case entry.mode.IsDir():
+			return os.MkdirAll(target, config.DirPermissions)
+		case entry.mode&fs.ModeSymlink != 0:
+			if err := os.MkdirAll(filepath.Dir(target), config.DirPermissions); err != nil {
+				return err
+			}
+			return os.Symlink(entry.linkname, target)
+		default:
+			rc, err := entry.open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			return extractFile(rc, target, entry.mode.Perm())
+		}
+	})
+}
+
+// importedSource unpacks the archive given with --archive into a temporary
+// source directory, once checkArchive has found it safe and holding a
+// template. The source records the archive's digest in place of a commit.
+func (s *Service) importedSource(archive string) (*templateSource, error) {
+	root, err := checkArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := state.HashFile(archive)
+	if err != nil {
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, archive, err)
+	}
+
+	dir, err := os.MkdirTemp("", "strategic-claude-archive-*")
+	if err != nil {
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, os.TempDir(), err)
+	}
+	if err := extractArchive(archive, dir); err != nil {
+		_ = os.RemoveAll(dir) // Best effort cleanup
+		return nil, models.NewAppError(models.ErrorCodeFileSystemError, fmt.Sprintf("failed to unpack archive %s", archive), err)
+	}
+
+	slog.Info("Installing from archive", "archive", archive, "digest", templates.TreeHashPrefix+digest)
+	return &templateSource{
+		Dir:           filepath.Join(dir, filepath.FromSlash(root)),
+		archiveDigest: templates.TreeHashPrefix + digest,
+		fromArchive:   true,
+		cleanup: func() error {
+			return os.RemoveAll(dir)
+		},
+	}, nil
+}
+
+// extractFile writes the current archive entry to a new file at target
+func extractFile(r io.Reader, target string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), config.DirPermissions); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
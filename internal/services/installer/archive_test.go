@@ -0,0 +1,396 @@
+package installer
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestPrepareSource_Archive(t *testing.T) {
+	service := New()
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	if err := os.WriteFile(filepath.Join(sourceDir, hookScript), []byte("#!/bin/sh\necho done\n"), 0755); err != nil {
+		t.Fatalf("Failed to write hook script: %v", err)
+	}
+	template := templates.Template{
+		ID:      "local",
+		Name:    "Local",
+		RepoURL: sourceDir,
+		Branch:  "main",
+		Commit:  initGitTemplate(t, sourceDir),
+	}
+	expected, err := TreeHash(sourceDir, template)
+	if err != nil {
+		t.Fatalf("TreeHash() error = %v", err)
+	}
+	template.TreeHash = expected
+
+	installConfig := models.InstallConfig{NoCache: true, ArchiveDir: t.TempDir()}
+	archive := filepath.Join(installConfig.ArchiveDir, template.CacheKey()+".tar")
+
+	prepare := func() *templateSource {
+		t.Helper()
+		source, err := service.prepareSource(template, installConfig)
+		if err != nil {
+			t.Fatalf("prepareSource() error = %v", err)
+		}
+		t.Cleanup(func() { _ = source.Cleanup() })
+		return source
+	}
+
+	// The first install fetches with git and archives the commit
+	if source := prepare(); source.fromArchive {
+		t.Fatalf("Expected the first source to be fetched, not unpacked")
+	}
+	first, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("Expected an archive of the commit: %v", err)
+	}
+
+	t.Run("installs from the archive without git", func(t *testing.T) {
+		t.Setenv("PATH", "")
+
+		source := prepare()
+		if !source.fromArchive {
+			t.Fatalf("Expected the source to be unpacked from the archive")
+		}
+		if actual, err := TreeHash(source.Dir, template); err != nil || actual != expected {
+			t.Errorf("Unpacked tree hash = %s, %v; want %s", actual, err, expected)
+		}
+		if _, err := os.Stat(filepath.Join(source.Dir, ".git")); !os.IsNotExist(err) {
+			t.Errorf("Expected the archive to leave out .git")
+		}
+		info, err := os.Stat(filepath.Join(source.Dir, hookScript))
+		if err != nil || info.Mode().Perm()&0111 == 0 {
+			t.Errorf("Unpacked hook script = %v, %v; want executable", info, err)
+		}
+	})
+
+	t.Run("replaces an unreadable archive", func(t *testing.T) {
+		if err := os.WriteFile(archive, []byte("not a tar file"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if source := prepare(); source.fromArchive {
+			t.Fatalf("Expected the unreadable archive to be ignored")
+		}
+		// The same commit always archives to the same bytes
+		if rewritten, err := os.ReadFile(archive); err != nil || string(rewritten) != string(first) {
+			t.Errorf("Expected the archive to be written again identically, got error %v", err)
+		}
+	})
+
+	t.Run("uses an archive named by the commit", func(t *testing.T) {
+		legacy := filepath.Join(installConfig.ArchiveDir, template.Commit+".tar")
+		if err := os.Rename(archive, legacy); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = os.Rename(legacy, archive) })
+
+		if path := ArchivePath(template, installConfig.ArchiveDir); path != legacy {
+			t.Errorf("ArchivePath() = %s, want %s", path, legacy)
+		}
+		if source := prepare(); !source.fromArchive {
+			t.Errorf("Expected the source to be unpacked from the commit-named archive")
+		}
+	})
+
+	t.Run("discards an archive not matching the tree hash", func(t *testing.T) {
+		mismatched := template
+		mismatched.TreeHash = templates.TreeHashPrefix + strings.Repeat("0", 64)
+		source, err := service.prepareSource(mismatched, installConfig)
+		if err != nil {
+			t.Fatalf("prepareSource() error = %v", err)
+		}
+		t.Cleanup(func() { _ = source.Cleanup() })
+		if source.fromArchive {
+			t.Errorf("Expected the mismatched archive to be ignored")
+		}
+		if _, err := os.Stat(archive); !os.IsNotExist(err) {
+			t.Errorf("Expected the mismatched archive to be removed and not rewritten")
+		}
+	})
+}
+
+func TestExtractArchive_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []tar.Header
+		wantErr string
+	}{
+		{
+			name:    "path outside",
+			headers: []tar.Header{{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0644}},
+			wantErr: "outside the archive",
+		},
+		{
+			name:    "absolute path",
+			headers: []tar.Header{{Name: "/etc/evil", Typeflag: tar.TypeReg, Mode: 0644}},
+			wantErr: "outside the archive",
+		},
+		{
+			name: "through a symbolic link",
+			headers: []tar.Header{
+				{Name: "link", Typeflag: tar.TypeSymlink, Linkname: os.TempDir(), Mode: 0777},
+				{Name: "link/evil", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+			wantErr: "inside the symbolic link",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "bad.tar")
+			file, err := os.Create(archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tw := tar.NewWriter(file)
+			for _, header := range tt.headers {
+				if err := tw.WriteHeader(&header); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			file.Close()
+
+			err = extractArchive(archive, t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("extractArchive() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// packTemplate writes the files in sourceDir to a tar.gz or zip archive,
+// chosen by the extension of archive, with every path below prefix
+func packTemplate(t *testing.T, sourceDir, archive, prefix string) {
+	t.Helper()
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var add func(rel string, info os.FileInfo, content []byte) error
+	var finish func() error
+	if strings.HasSuffix(archive, ".zip") {
+		zw := zip.NewWriter(file)
+		add = func(rel string, info os.FileInfo, content []byte) error {
+			header, err := zip.FileInfoHeader(info)
+			if err != nil {
+				return err
+			}
+			header.Name = rel
+			if info.IsDir() {
+				header.Name += "/"
+			}
+			w, err := zw.CreateHeader(header)
+			if err == nil {
+				_, err = w.Write(content)
+			}
+			return err
+		}
+		finish = zw.Close
+	} else {
+		gz := gzip.NewWriter(file)
+		tw := tar.NewWriter(gz)
+		add = func(rel string, info os.FileInfo, content []byte) error {
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = rel
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			_, err = tw.Write(content)
+			return err
+		}
+		finish = func() error {
+			if err := tw.Close(); err != nil {
+				return err
+			}
+			return gz.Close()
+		}
+	}
+
+	err = filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == sourceDir {
+			return err
+		}
+		rel, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		var content []byte
+		if info.Mode().IsRegular() {
+			if content, err = os.ReadFile(path); err != nil {
+				return err
+			}
+		}
+		return add(prefix+filepath.ToSlash(rel), info, content)
+	})
+	if err == nil {
+		err = finish()
+	}
+	if err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+}
+
+func TestInstall_Archive(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		prefix  string
+	}{
+		{name: "gzip-compressed tar in a top-level directory", archive: "claude-template.tar.gz", prefix: "claude-template-1.0/"},
+		{name: "zip at the top level", archive: "claude-template.zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceDir := createLocalTemplate(t)
+			keepEmptyDirs(t, sourceDir)
+			archive := filepath.Join(t.TempDir(), tt.archive)
+			packTemplate(t, sourceDir, archive, tt.prefix)
+
+			targetDir := t.TempDir()
+			installConfig := models.InstallConfig{
+				TargetDir:     targetDir,
+				TemplateID:    "claude-template",
+				Archive:       archive,
+				SkipConfirm:   true,
+				NoBackup:      true,
+				GitignoreMode: "track",
+			}
+			if err := installConfig.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if err := New().Install(installConfig); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+
+			readme := filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+			if got, err := os.ReadFile(readme); err != nil || string(got) != "# Core\n" {
+				t.Errorf("README.md = %q, %v; want the archive's copy", got, err)
+			}
+
+			digest, err := state.HashFile(archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lock, err := state.ReadLock(targetDir)
+			if err != nil {
+				t.Fatalf("ReadLock() error = %v", err)
+			}
+			entry := lock.Find("claude-template")
+			if entry == nil || entry.ArchiveDigest != templates.TreeHashPrefix+digest || entry.Commit != "" || entry.RepoURL != archive {
+				t.Errorf("Lock entry = %+v, want the archive %s with digest %s and no commit", entry, archive, digest)
+			}
+		})
+	}
+}
+
+func TestCheckArchive(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  []tar.Header
+		wantRoot string
+		wantCode models.ErrorCode
+	}{
+		{
+			name: "top level",
+			headers: []tar.Header{
+				{Name: "./", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: "./.strategic-claude-basic/core/README.md", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+		},
+		{
+			name: "single top-level directory",
+			headers: []tar.Header{
+				{Name: "pax_global_header", Typeflag: tar.TypeXGlobalHeader},
+				{Name: "template-v1/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: "template-v1/.strategic-claude-basic/", Typeflag: tar.TypeDir, Mode: 0755},
+			},
+			wantRoot: "template-v1",
+		},
+		{
+			name: "no framework directory",
+			headers: []tar.Header{
+				{Name: "docs/README.md", Typeflag: tar.TypeReg, Mode: 0644},
+				{Name: ".strategic-claude-basic", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+			wantCode: models.ErrorCodeValidationFailed,
+		},
+		{
+			name: "several top-level directories",
+			headers: []tar.Header{
+				{Name: "a/.strategic-claude-basic/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: "b/.strategic-claude-basic/", Typeflag: tar.TypeDir, Mode: 0755},
+			},
+			wantCode: models.ErrorCodeValidationFailed,
+		},
+		{
+			name: "path traversal",
+			headers: []tar.Header{
+				{Name: ".strategic-claude-basic/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: ".strategic-claude-basic/../../evil", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+			wantCode: models.ErrorCodePathTraversal,
+		},
+		{
+			name: "hard link",
+			headers: []tar.Header{
+				{Name: ".strategic-claude-basic/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: ".strategic-claude-basic/passwd", Typeflag: tar.TypeLink, Linkname: "/etc/passwd"},
+			},
+			wantCode: models.ErrorCodePathTraversal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "template.tar")
+			file, err := os.Create(archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tw := tar.NewWriter(file)
+			for _, header := range tt.headers {
+				if err := tw.WriteHeader(&header); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			file.Close()
+
+			root, err := checkArchive(archive)
+			if tt.wantCode != "" {
+				var appErr *models.AppError
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantCode {
+					t.Errorf("checkArchive() error = %v, want code %s", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil || root != tt.wantRoot {
+				t.Errorf("checkArchive() = %q, %v; want %q", root, err, tt.wantRoot)
+			}
+		})
+	}
+}
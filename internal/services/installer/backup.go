@@ -0,0 +1,109 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// CreateBackup saves the given target-relative paths under backupPath, keeping
+// their relative layout so a failed or unwanted install can be undone by hand.
+// Framework content is copied and stays in place until the installer replaces it;
+// entries occupying a symlink location are moved out of the way so the symlink
+// can be created.
+func (s *Service) CreateBackup(targetDir, backupPath string, paths []string) error {
+	linkPaths := requiredLinkPaths()
+
+	for _, rel := range paths {
+		sourcePath := filepath.Join(targetDir, rel)
+		info, err := os.Lstat(sourcePath)
+		if os.IsNotExist(err) {
+			continue // Nothing to backup
+		}
+		if err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, sourcePath, err)
+		}
+
+		destPath := filepath.Join(backupPath, rel)
+		if err := os.MkdirAll(filepath.Dir(destPath), config.DirPermissions); err != nil {
+			return models.NewFileSystemError(models.ErrorCodeBackupFailed, filepath.Dir(destPath), err)
+		}
+
+		switch {
+		case linkPaths[rel]:
+			if err := os.Rename(sourcePath, destPath); err != nil {
+				return models.NewFileSystemError(models.ErrorCodeBackupFailed, sourcePath, err)
+			}
+		case info.IsDir():
+			if err := s.filesystemService.CopyDirectory(sourcePath, destPath); err != nil {
+				return fmt.Errorf("failed to create backup: %w", err)
+			}
+		default:
+			if err := s.filesystemService.CopyFile(sourcePath, destPath); err != nil {
+				return fmt.Errorf("failed to create backup: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// analyzeLinkConflicts marks symlink locations in .claude and .codex that hold
+// something other than the expected framework symlink, such as a user's own
+// directory, as files the installation will replace
+func (s *Service) analyzeLinkConflicts(plan *models.InstallationPlan) {
+	links := requiredLinkTargets()
+	for _, rel := range sortedKeys(links) {
+		target := links[rel]
+		fullPath := filepath.Join(plan.TargetDir, rel)
+
+		info, err := os.Lstat(fullPath)
+		if err != nil {
+			continue // Nothing there yet
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			if existing, err := os.Readlink(fullPath); err == nil && existing == target {
+				continue // Already the framework symlink
+			}
+		}
+
+		plan.WillReplace = append(plan.WillReplace, rel)
+	}
+}
+
+// requiredLinkTargets maps the target-relative path of every framework symlink to
+// the link target it should have
+func requiredLinkTargets() map[string]string {
+	links := make(map[string]string)
+	for path, target := range config.GetRequiredSymlinks() {
+		links[filepath.Join(config.ClaudeDir, path)] = target
+	}
+	for path, target := range config.GetCodexRequiredSymlinks() {
+		links[filepath.Join(config.CodexDir, path)] = target
+	}
+	return links
+}
+
+// sortedKeys returns the keys of a path map in lexical order
+func sortedKeys(paths map[string]string) []string {
+	keys := make([]string, 0, len(paths))
+	for key := range paths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// requiredLinkPaths returns the target-relative paths of every framework symlink
+func requiredLinkPaths() map[string]bool {
+	paths := make(map[string]bool)
+	for path := range requiredLinkTargets() {
+		paths[path] = true
+	}
+	return paths
+}
@@ -0,0 +1,112 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_BacksUpConflictingClaudeContent(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	// A user directory sits where the framework symlink goes
+	targetDir := t.TempDir()
+	userAgents := filepath.Join(config.ClaudeDir, config.AgentsDir, "strategic")
+	userFile := filepath.Join(targetDir, userAgents, "my-agent.md")
+	if err := os.MkdirAll(filepath.Dir(userFile), 0755); err != nil {
+		t.Fatalf("Failed to create user directory: %v", err)
+	}
+	if err := os.WriteFile(userFile, []byte("mine\n"), 0644); err != nil {
+		t.Fatalf("Failed to write user file: %v", err)
+	}
+
+	service := New()
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		GitignoreMode: "track",
+	}
+
+	plan, err := service.AnalyzeInstallation(installConfig)
+	if err != nil {
+		t.Fatalf("AnalyzeInstallation() error = %v", err)
+	}
+	if !plan.BackupRequired || plan.BackupDir == "" {
+		t.Fatalf("Expected a backup for conflicting .claude content, plan = %+v", plan)
+	}
+
+	installConfig.BackupDir = plan.BackupDir
+	if err := service.Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	if !strings.HasPrefix(filepath.Base(plan.BackupDir), config.BackupDirPrefix) {
+		t.Errorf("Backup directory %s should use the %s prefix", plan.BackupDir, config.BackupDirPrefix)
+	}
+
+	backedUp, err := os.ReadFile(filepath.Join(plan.BackupDir, userAgents, "my-agent.md"))
+	if err != nil {
+		t.Fatalf("Expected user file under its relative path in the backup: %v", err)
+	}
+	if string(backedUp) != "mine\n" {
+		t.Errorf("Backed up content = %q, want %q", string(backedUp), "mine\n")
+	}
+
+	info, err := os.Lstat(filepath.Join(targetDir, userAgents))
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Expected framework symlink at %s, err = %v", userAgents, err)
+	}
+}
+
+func TestCreateBackup(t *testing.T) {
+	targetDir := t.TempDir()
+	backupDir := filepath.Join(targetDir, config.GetBackupDirName())
+
+	coreFile := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	linkDir := filepath.Join(config.CodexDir, "prompts", "strategic")
+	for _, rel := range []string{coreFile, filepath.Join(linkDir, "prompt.md")} {
+		path := filepath.Join(targetDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content\n"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	paths := []string{
+		filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir),
+		linkDir,
+		filepath.Join(config.ClaudeDir, "missing"),
+	}
+	if err := New().CreateBackup(targetDir, backupDir, paths); err != nil {
+		t.Fatalf("CreateBackup() error = %v", err)
+	}
+
+	// Framework content is copied and left in place
+	for _, path := range []string{filepath.Join(backupDir, coreFile), filepath.Join(targetDir, coreFile)} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to exist: %v", path, err)
+		}
+	}
+
+	// Symlink locations are moved aside
+	if _, err := os.Stat(filepath.Join(backupDir, linkDir, "prompt.md")); err != nil {
+		t.Errorf("Expected moved link directory in backup: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(targetDir, linkDir)); !os.IsNotExist(err) {
+		t.Errorf("Expected link location to be cleared, stat error = %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package installer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// DiffInstalled compares each file recorded in the manifest with its copy in
+// the template at the registry's current commit, rendered with the configured
+// variables as an install would. Only files that differ are returned, in
+// manifest order; symlinks and files the CLI did not install are not compared.
+func (s *Service) DiffInstalled(installConfig models.InstallConfig, files []state.FileRecord) (_ []models.FileDiff, err error) {
+	defer func() { err = models.NewTemplateError(installConfig.TemplateID, err) }()
+	template, err := installConfig.GetTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.prepareSource(template, installConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = source.Cleanup() // Best effort cleanup
+	}()
+
+	// Render a scratch copy so local template directories are never modified
+	renderDir, err := os.MkdirTemp("", "strategic-claude-diff-*")
+	if err != nil {
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, os.TempDir(), err)
+	}
+	defer os.RemoveAll(renderDir)
+
+	for _, record := range files {
+		if record.IsLink() {
+			continue
+		}
+		rel := filepath.FromSlash(record.Path)
+		sourcePath := filepath.Join(source.Dir, rel)
+		if info, err := os.Lstat(sourcePath); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if err := s.filesystemService.CopyFile(sourcePath, filepath.Join(renderDir, rel)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(installConfig.RenderPatterns) > 0 {
+		values, err := s.variableValues(template, installConfig, installConfig.TargetDir)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.variablesService.Render(renderDir, installConfig.RenderPatterns, values, false); err != nil {
+			return nil, err
+		}
+	}
+
+	var diffs []models.FileDiff
+	for _, record := range files {
+		if record.IsLink() {
+			continue
+		}
+		rel := filepath.FromSlash(record.Path)
+
+		diff := models.FileDiff{Path: record.Path}
+		if diff.Template, err = readIfExists(filepath.Join(renderDir, rel)); err != nil {
+			return nil, err
+		}
+		diff.MissingInTemplate = diff.Template == nil
+		if diff.Local, err = readIfExists(filepath.Join(installConfig.TargetDir, rel)); err != nil {
+			return nil, err
+		}
+		diff.MissingLocally = diff.Local == nil
+
+		if diff.MissingInTemplate == diff.MissingLocally && bytes.Equal(diff.Template, diff.Local) {
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// DiffCommits compares the files the template installs at two commits, with
+// no project involved: each commit is installed into a scratch directory as
+// ExportManifest does, and every file that differs between them is returned in
+// path order. The from side is reported as FileDiff.Template and the to side
+// as FileDiff.Local. An empty to compares with the registry's current commit.
+func (s *Service) DiffCommits(installConfig models.InstallConfig, from, to string) (_ []models.FileDiff, err error) {
+	defer func() { err = models.NewTemplateError(installConfig.TemplateID, err) }()
+
+	fromDir, err := s.installCommit(installConfig, from)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(fromDir)
+	toDir, err := s.installCommit(installConfig, to)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(toDir)
+
+	fromFiles, err := listFiles(fromDir)
+	if err != nil {
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, fromDir, err)
+	}
+	toFiles, err := listFiles(toDir)
+	if err != nil {
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, toDir, err)
+	}
+
+	var diffs []models.FileDiff
+	for _, rel := range slices.Compact(slices.Sorted(slices.Values(append(fromFiles, toFiles...)))) {
+		diff := models.FileDiff{Path: filepath.ToSlash(rel)}
+		if diff.Template, err = readIfExists(filepath.Join(fromDir, rel)); err != nil {
+			return nil, err
+		}
+		diff.MissingInTemplate = diff.Template == nil
+		if diff.Local, err = readIfExists(filepath.Join(toDir, rel)); err != nil {
+			return nil, err
+		}
+		diff.MissingLocally = diff.Local == nil
+
+		if diff.MissingInTemplate == diff.MissingLocally && bytes.Equal(diff.Template, diff.Local) {
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// installCommit installs the template at commit, or at its registry pin when
+// commit is empty, into a new scratch directory and returns it. The caller
+// removes the directory.
+func (s *Service) installCommit(installConfig models.InstallConfig, commit string) (string, error) {
+	installConfig.FromCommit = commit
+	template, err := installConfig.GetTemplate()
+	if err != nil {
+		return "", err
+	}
+
+	source, err := s.prepareSource(template, installConfig)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = source.Cleanup() // Best effort cleanup
+	}()
+
+	scratchDir, err := os.MkdirTemp("", "strategic-claude-diff-*")
+	if err != nil {
+		return "", models.NewFileSystemError(models.ErrorCodeFileSystemError, os.TempDir(), err)
+	}
+	if _, err := s.installScratch(source, template, installConfig, scratchDir); err != nil {
+		os.RemoveAll(scratchDir)
+		return "", err
+	}
+	return scratchDir, nil
+}
+
+// readIfExists reads a regular file, returning nil content when there is none
+func readIfExists(path string) ([]byte, error) {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) || (err == nil && !info.Mode().IsRegular()) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+	}
+	if content == nil {
+		content = []byte{} // Empty, but present
+	}
+	return content, nil
+}
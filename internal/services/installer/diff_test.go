@@ -0,0 +1,175 @@
+package installer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestDiffInstalled(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	commands := config.StrategicClaudeBasicDir + "/" + config.CoreDir + "/" + config.CommandsDir
+	readme := config.StrategicClaudeBasicDir + "/" + config.CoreDir + "/README.md"
+	files := map[string]string{
+		commands + "/plan.md":     "# Plan\n",
+		commands + "/research.md": "# Research\n",
+		commands + "/project.md":  "# {{.ProjectName}}\n",
+	}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(sourceDir, filepath.FromSlash(rel)), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", rel, err)
+		}
+	}
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:      targetDir,
+		TemplateID:     "local",
+		SkipConfirm:    true,
+		NoBackup:       true,
+		GitignoreMode:  "track",
+		Variables:      map[string]string{"ProjectName": "Acme"},
+		RenderPatterns: config.GetDefaultRenderPatterns(),
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	// Edit one file locally, delete another, and drop one from the template
+	if err := os.WriteFile(filepath.Join(targetDir, filepath.FromSlash(commands+"/plan.md")), []byte("# My plan\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit plan.md: %v", err)
+	}
+	if err := os.Remove(filepath.Join(targetDir, filepath.FromSlash(commands+"/research.md"))); err != nil {
+		t.Fatalf("Failed to remove research.md: %v", err)
+	}
+	if err := os.Remove(filepath.Join(sourceDir, filepath.FromSlash(readme))); err != nil {
+		t.Fatalf("Failed to remove README from template: %v", err)
+	}
+
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+
+	diffs, err := New().DiffInstalled(installConfig, lock.Templates[0].Files)
+	if err != nil {
+		t.Fatalf("DiffInstalled() error = %v", err)
+	}
+
+	got := make(map[string]models.FileDiff)
+	for _, diff := range diffs {
+		got[diff.Path] = diff
+	}
+	if len(got) != 3 {
+		t.Fatalf("DiffInstalled() = %v, want plan.md, research.md, and README.md", diffs)
+	}
+	if diff := got[commands+"/plan.md"]; string(diff.Local) != "# My plan\n" || string(diff.Template) != "# Plan\n" {
+		t.Errorf("plan.md diff = %q vs %q", diff.Template, diff.Local)
+	}
+	if !got[commands+"/research.md"].MissingLocally {
+		t.Errorf("Expected research.md to be missing locally")
+	}
+	if !got[readme].MissingInTemplate {
+		t.Errorf("Expected README.md to be missing in the template")
+	}
+	// Rendered with the same variables as the install, so it matches
+	if _, ok := got[commands+"/project.md"]; ok {
+		t.Errorf("Expected rendered project.md to match the template")
+	}
+}
+
+func TestDiffCommits(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	commands := config.StrategicClaudeBasicDir + "/" + config.CoreDir + "/" + config.CommandsDir
+	readme := config.StrategicClaudeBasicDir + "/" + config.CoreDir + "/README.md"
+	write := func(dir, rel, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(rel)), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", rel, err)
+		}
+	}
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	write(sourceDir, commands+"/plan.md", "# Plan\n")
+	write(sourceDir, commands+"/project.md", "# {{.ProjectName}}\n")
+	older := initGitTemplate(t, sourceDir)
+
+	// The next commit edits the README, drops plan.md, and adds research.md
+	write(sourceDir, readme, "# Core v2\n")
+	write(sourceDir, commands+"/research.md", "# Research\n")
+	if err := os.Remove(filepath.Join(sourceDir, filepath.FromSlash(commands+"/plan.md"))); err != nil {
+		t.Fatalf("Failed to remove plan.md: %v", err)
+	}
+	if output, err := exec.Command("git", "-C", sourceDir, "add", "-A").CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v\n%s", err, output)
+	}
+	commit := exec.Command("git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "v2")
+	commit.Dir = sourceDir
+	if output, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v\n%s", err, output)
+	}
+	output, err := exec.Command("git", "-C", sourceDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+	newer := strings.TrimSpace(string(output))
+
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, Branch: "main", Commit: newer},
+	})
+	installConfig := models.InstallConfig{
+		TargetDir:      t.TempDir(),
+		TemplateID:     "local",
+		Variables:      map[string]string{"ProjectName": "Acme"},
+		RenderPatterns: config.GetDefaultRenderPatterns(),
+	}
+
+	for _, to := range []string{newer, ""} {
+		diffs, err := New().DiffCommits(installConfig, older, to)
+		if err != nil {
+			t.Fatalf("DiffCommits(%q) error = %v", to, err)
+		}
+
+		var paths []string
+		got := make(map[string]models.FileDiff)
+		for _, diff := range diffs {
+			paths = append(paths, diff.Path)
+			got[diff.Path] = diff
+		}
+		// Sorted by path; the unchanged, rendered project.md is left out
+		want := []string{readme, commands + "/plan.md", commands + "/research.md"}
+		if strings.Join(paths, ",") != strings.Join(want, ",") {
+			t.Fatalf("DiffCommits(%q) paths = %v, want %v", to, paths, want)
+		}
+		if diff := got[readme]; string(diff.Template) != "# Core\n" || string(diff.Local) != "# Core v2\n" {
+			t.Errorf("README.md diff = %q vs %q", diff.Template, diff.Local)
+		}
+		if !got[commands+"/plan.md"].MissingLocally {
+			t.Errorf("Expected plan.md to be missing at the newer commit")
+		}
+		if !got[commands+"/research.md"].MissingInTemplate {
+			t.Errorf("Expected research.md to be missing at the older commit")
+		}
+	}
+
+	if diffs, err := New().DiffCommits(installConfig, newer, newer); err != nil || len(diffs) != 0 {
+		t.Errorf("DiffCommits() of a commit with itself = %v, %v; want no differences", diffs, err)
+	}
+}
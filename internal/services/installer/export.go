@@ -0,0 +1,101 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// ExportManifest returns the manifest a new installation of the template into
+// installConfig.TargetDir would record, without touching the target. The
+// template is fetched and installed into a scratch directory with the same
+// filters, --only subtrees, and variable rendering, so the records can be
+// compared with the files of a lock file. Variables nobody supplied are left
+// as written rather than prompted for, and hooks and scripts are not run.
+func (s *Service) ExportManifest(installConfig models.InstallConfig) (_ []state.FileRecord, err error) {
+	defer func() { err = models.NewTemplateError(installConfig.TemplateID, err) }()
+	template, err := installConfig.GetTemplate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template configuration: %w", err)
+	}
+
+	source, err := s.prepareSource(template, installConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = source.Cleanup() // Best effort cleanup
+	}()
+
+	if !installConfig.SkipVerify && source.unhashable() == "" {
+		if err := verifyTreeHash(source.Dir, template); err != nil {
+			return nil, err
+		}
+	}
+
+	scratchDir, err := os.MkdirTemp("", "strategic-claude-manifest-*")
+	if err != nil {
+		return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, os.TempDir(), err)
+	}
+	defer os.RemoveAll(scratchDir)
+
+	roots, err := s.installScratch(source, template, installConfig, scratchDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The framework symlinks are part of every installation's manifest
+	if err := s.ensureClaudeDirectory(scratchDir); err != nil {
+		return nil, err
+	}
+	if err := s.symlinkService.CreateSymlinks(scratchDir); err != nil {
+		return nil, fmt.Errorf("failed to create symlinks: %w", err)
+	}
+	if err := s.symlinkService.CreateCodexSymlinks(scratchDir); err != nil {
+		return nil, fmt.Errorf("failed to create codex symlinks: %w", err)
+	}
+
+	return buildManifest(scratchDir, roots, nil, nil)
+}
+
+// installScratch installs the template's files from source into scratchDir as
+// a new installation into installConfig.TargetDir would: filtered, limited to
+// the --only subtrees, and with variables rendered but never prompted for. It
+// returns the paths staged at the top of scratchDir.
+func (s *Service) installScratch(source *templateSource, template templates.Template, installConfig models.InstallConfig, scratchDir string) ([]string, error) {
+	tx, err := s.filesystemService.BeginTransaction(scratchDir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Close() // Best effort cleanup
+	}()
+	tx.SetLinkPolicy(filesystem.LinkPolicy{Root: source.Dir, Dereference: installConfig.Dereference})
+
+	filter, err := installFilter(template, installConfig)
+	if err != nil {
+		return nil, err
+	}
+	subtrees, err := onlySubtrees(source.Dir, installConfig.OnlyPaths)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.stageFramework(tx, source.Dir, models.InstallationTypeNew, filter, subtrees); err != nil {
+		return nil, err
+	}
+
+	installConfig.PromptVariable = nil
+	if err := s.renderVariables(tx, template, installConfig, installConfig.TargetDir); err != nil {
+		return nil, fmt.Errorf("failed to render template variables: %w", err)
+	}
+
+	roots := tx.Staged()
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return roots, nil
+}
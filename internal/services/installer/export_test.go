@@ -0,0 +1,67 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestExportManifest(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	commands := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, config.CommandsDir)
+	for name, content := range map[string]string{
+		"plan.md":     "# Plan\n",
+		"project.md":  "# {{.ProjectName}}\n",
+		"draft.md":    "# Draft\n",
+		"scratch.txt": "notes\n",
+	} {
+		if err := os.WriteFile(filepath.Join(commands, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:       targetDir,
+		TemplateID:      "local",
+		SkipConfirm:     true,
+		NoBackup:        true,
+		GitignoreMode:   "track",
+		ExcludePatterns: []string{"draft.md"},
+		Variables:       map[string]string{"ProjectName": "Acme"},
+		RenderPatterns:  config.GetDefaultRenderPatterns(),
+	}
+
+	exported, err := New().ExportManifest(installConfig)
+	if err != nil {
+		t.Fatalf("ExportManifest() error = %v", err)
+	}
+	if entries, err := os.ReadDir(targetDir); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected the target to be left untouched, got %v, %v", entries, err)
+	}
+
+	// The export is exactly what an install records, rendering and filters included
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if !reflect.DeepEqual(exported, lock.Templates[0].Files) {
+		t.Errorf("ExportManifest() = %v\nwant the installed manifest %v", exported, lock.Templates[0].Files)
+	}
+}
@@ -0,0 +1,56 @@
+package installer
+
+import (
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// fileFilter decides which template files are installed. Include patterns
+// select the candidates, when there are any, and exclude patterns then remove
+// files from them, so a file matching both is not installed. Both use the
+// gitignore-style syntax of filesystem.ExcludeMatcher; paths are relative to
+// the template repository root.
+type fileFilter struct {
+	include *filesystem.ExcludeMatcher // nil installs every file
+	exclude *filesystem.ExcludeMatcher
+}
+
+// newFileFilter parses the include and exclude patterns into a filter
+func newFileFilter(include, exclude []string) (fileFilter, error) {
+	var filter fileFilter
+	var err error
+	if len(include) > 0 {
+		if filter.include, err = filesystem.NewExcludeMatcher(include); err != nil {
+			return fileFilter{}, err
+		}
+	}
+	if filter.exclude, err = filesystem.NewExcludeMatcher(exclude); err != nil {
+		return fileFilter{}, err
+	}
+	return filter, nil
+}
+
+// installFilter combines the default exclude patterns with the template's and
+// the user's own, and the template's include patterns with the user's
+func installFilter(template templates.Template, installConfig models.InstallConfig) (fileFilter, error) {
+	exclude := config.GetDefaultExcludePatterns()
+	exclude = append(exclude, template.ExcludePatterns...)
+	exclude = append(exclude, installConfig.ExcludePatterns...)
+
+	var include []string
+	include = append(include, template.IncludePatterns...)
+	include = append(include, installConfig.IncludePatterns...)
+
+	return newFileFilter(include, exclude)
+}
+
+// Skip reports whether rel is left out. Directories are only skipped when
+// excluded, since files inside them may still be included.
+func (f fileFilter) Skip(rel string, isDir bool) bool {
+	if f.exclude.Match(rel, isDir) {
+		return true
+	}
+	return !isDir && f.include != nil && !f.include.Match(rel, false)
+}
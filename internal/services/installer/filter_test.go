@@ -0,0 +1,36 @@
+package installer
+
+import "testing"
+
+func TestFileFilter_Skip(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		rel     string
+		isDir   bool
+		want    bool
+	}{
+		{name: "no patterns", rel: "docs/guide.md", want: false},
+		{name: "excluded", exclude: []string{"*.md"}, rel: "docs/guide.md", want: true},
+		{name: "included", include: []string{"**/*.md"}, rel: "docs/guide.md", want: false},
+		{name: "not included", include: []string{"**/*.md"}, rel: "scripts/run.sh", want: true},
+		{name: "included then excluded", include: []string{"**/*.md"}, exclude: []string{"*.draft.md"}, rel: "docs/plan.draft.md", want: true},
+		{name: "included directory", include: []string{"docs/"}, rel: "docs/nested/run.sh", want: false},
+		{name: "directories are kept for their files", include: []string{"**/*.md"}, rel: "scripts", isDir: true, want: false},
+		{name: "excluded directory", include: []string{"**/*.md"}, exclude: []string{"scripts/"}, rel: "scripts", isDir: true, want: true},
+		{name: "negated include", include: []string{"**/*.md", "!README.md"}, rel: "docs/README.md", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newFileFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("newFileFilter() error = %v", err)
+			}
+			if got := filter.Skip(tt.rel, tt.isDir); got != tt.want {
+				t.Errorf("Skip(%q, %v) = %v, want %v", tt.rel, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
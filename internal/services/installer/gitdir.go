@@ -0,0 +1,62 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// keptGitPath returns the target-relative path of the git directory that
+// --keep-git installs
+func keptGitPath() string {
+	return filepath.Join(config.StrategicClaudeBasicDir, config.KeptGitDir)
+}
+
+// stageGitDir stages the checkout's git directory into the framework directory
+// for --keep-git, set up so the project stays a working tree of the template
+// at the installed commit. It is staged after the framework files, so it is
+// not among the roots the manifest records; where the framework directory is
+// staged whole, the git directory is copied into that staged copy instead.
+func (s *Service) stageGitDir(tx *filesystem.Transaction, source *templateSource, template templates.Template) error {
+	if source.fromArchive || source.Commit == "" {
+		return models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			fmt.Sprintf("Template '%s' was not installed from a git checkout, so there is no history to keep", template.ID),
+			nil,
+		)
+	}
+
+	rel := keptGitPath()
+	gitDir := filepath.Join(source.Dir, ".git")
+	if withinSubtrees(rel, tx.Staged()) {
+		if err := s.filesystemService.CopyDirectory(gitDir, tx.StagedPath(rel)); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", rel, err)
+		}
+	} else if err := tx.StageDirectory(gitDir, rel); err != nil {
+		return err
+	}
+
+	// Clones from the cache point at the cached copy rather than the template
+	originURL := template.RepoURL
+	if template.IsLocal() {
+		localPath, err := template.LocalPath()
+		if err != nil {
+			return err
+		}
+		originURL = localPath
+	}
+	// The working tree is the project root, two levels above the git directory
+	return s.gitService.AdoptGitDir(tx.StagedPath(rel), filepath.Join("..", ".."), config.StrategicClaudeBasicDir, originURL)
+}
+
+// hasKeptGitDir reports whether the framework directory in targetDir holds a
+// git directory kept by --keep-git
+func hasKeptGitDir(targetDir string) bool {
+	info, err := os.Stat(filepath.Join(targetDir, keptGitPath()))
+	return err == nil && info.IsDir()
+}
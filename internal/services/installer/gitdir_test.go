@@ -0,0 +1,86 @@
+package installer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_KeepGit(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	if err := os.WriteFile(filepath.Join(sourceDir, "README.md"), []byte("# Template\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commit := initGitTemplate(t, sourceDir)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, Branch: "main", Commit: commit},
+	})
+
+	targetDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(targetDir, "README.md"), []byte("# My project\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := New().Install(models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		KeepGit:       true,
+		NoCache:       true,
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	gitDir := filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.KeptGitDir)
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"--git-dir", gitDir}, args...)...)
+		cmd.Dir = targetDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	if head := git("rev-parse", "HEAD"); head != commit {
+		t.Errorf("Kept HEAD = %s, want %s", head, commit)
+	}
+	if origin := git("config", "remote.origin.url"); origin != sourceDir {
+		t.Errorf("Kept origin = %s, want %s", origin, sourceDir)
+	}
+	// The template's README.md was never installed, and the project's own is not the template's business
+	if status := git("status", "--porcelain"); status != "" {
+		t.Errorf("Expected a clean kept checkout, got status:\n%s", status)
+	}
+	git("checkout", "--", ".")
+	if data, err := os.ReadFile(filepath.Join(targetDir, "README.md")); err != nil || string(data) != "# My project\n" {
+		t.Errorf("Expected the project README.md to be left alone, got %q, %v", data, err)
+	}
+
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	entry := lock.Find("local")
+	if entry == nil || !entry.KeepGit {
+		t.Fatalf("Expected the lock to record keep_git, got %+v", entry)
+	}
+	for _, record := range entry.Files {
+		if strings.HasPrefix(record.Path, config.StrategicClaudeBasicDir+"/"+config.KeptGitDir+"/") {
+			t.Errorf("Expected the manifest to leave out the kept git directory, got %s", record.Path)
+		}
+	}
+}
@@ -3,12 +3,19 @@ package installer
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/progress"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/cache"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/codexconfig"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
@@ -16,30 +23,40 @@ import (
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/settings"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/status"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/symlink"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/variables"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
 )
 
 // Service provides installation functionality for the Strategic Claude Basic framework
 type Service struct {
-	gitService        *git.Service
-	filesystemService *filesystem.Service
-	statusService     *status.Service
-	symlinkService    *symlink.Service
-	settingsService   *settings.Service
+	gitService         *git.Service
+	cacheService       *cache.Service
+	filesystemService  *filesystem.Service
+	statusService      *status.Service
+	symlinkService     *symlink.Service
+	settingsService    *settings.Service
 	codexConfigService *codexconfig.Service
-	scriptService     *script.Service
+	scriptService      *script.Service
+	variablesService   *variables.Service
+
+	// Sources fetched ahead of time by Prefetch, by template ID
+	prefetchMu sync.Mutex
+	prefetched map[string]*templateSource
 }
 
 // New creates a new installer service instance
 func New() *Service {
 	return &Service{
-		gitService:        git.New(),
-		filesystemService: filesystem.New(),
-		statusService:     status.NewService(),
-		symlinkService:    symlink.New(),
-		settingsService:   settings.New(),
+		gitService:         git.New(),
+		cacheService:       cache.New(),
+		filesystemService:  filesystem.New(),
+		statusService:      status.NewService(),
+		symlinkService:     symlink.New(),
+		settingsService:    settings.New(),
 		codexConfigService: codexconfig.New(),
-		scriptService:     script.New(),
+		scriptService:      script.New(),
+		variablesService:   variables.New(),
 	}
 }
 
@@ -55,18 +72,45 @@ func (s *Service) AnalyzeInstallation(installConfig models.InstallConfig) (*mode
 		)
 	}
 
+	// A missing target is created on install when allowed; nothing is
+	// installed in it yet
+	createTarget := false
 	if _, err := os.Stat(absTarget); os.IsNotExist(err) {
-		return nil, models.NewAppError(
-			models.ErrorCodeDirectoryNotFound,
-			fmt.Sprintf("Target directory does not exist: %s", absTarget),
-			err,
-		)
+		if !installConfig.CreateTarget {
+			return nil, models.NewAppError(
+				models.ErrorCodeDirectoryNotFound,
+				fmt.Sprintf("Target directory does not exist: %s", absTarget),
+				err,
+			)
+		}
+		createTarget = true
 	}
 
 	// Check current installation status
-	currentStatus, err := s.statusService.CheckInstallation(absTarget)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check installation status: %w", err)
+	currentStatus := models.NewStatusInfo(absTarget)
+	var previousLock *state.Lock
+	var lockErr error
+	if !createTarget {
+		currentStatus, err = s.statusService.CheckInstallation(absTarget)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check installation status: %w", err)
+		}
+		previousLock, lockErr = state.ReadLock(absTarget)
+	}
+
+	// Re-running init over an installation needs --force-core (update in
+	// place), --add (layer over it), or --force (start over)
+	if !installConfig.Force && !installConfig.ForceCore && !installConfig.Layer {
+		if err := checkNotInstalled(absTarget, currentStatus, previousLock, lockErr); err != nil {
+			return nil, err
+		}
+	}
+
+	// A new installation scaffolded into another directory goes into an empty one
+	if installConfig.RequireEmpty && !createTarget && !currentStatus.IsInstalled && previousLock == nil {
+		if err := checkTargetEmpty(absTarget); err != nil {
+			return nil, err
+		}
 	}
 
 	// Get template configuration
@@ -75,17 +119,45 @@ func (s *Service) AnalyzeInstallation(installConfig models.InstallConfig) (*mode
 		return nil, fmt.Errorf("failed to get template configuration: %w", err)
 	}
 
+	// A template relying on newer CLI features is refused before anything is touched
+	if err := template.CheckCLIVersion(templates.CLIVersion); err != nil {
+		return nil, models.NewAppError(models.ErrorCodeValidationFailed, err.Error(), nil)
+	}
+
+	// Fail early on local template paths and archives that do not exist
+	if installConfig.Archive != "" {
+		if info, err := os.Stat(template.RepoURL); err != nil || info.IsDir() {
+			return nil, models.NewAppError(
+				models.ErrorCodeInvalidPath,
+				fmt.Sprintf("Archive is not a file: %s", template.RepoURL),
+				err,
+			)
+		}
+	} else if template.IsLocal() {
+		if _, err := s.validateLocalSource(template); err != nil {
+			return nil, err
+		}
+	}
+
 	// Determine installation type
 	installType := s.determineInstallationType(currentStatus, installConfig)
 	plan := models.NewInstallationPlan(absTarget, installType, template)
+	plan.CreateTarget = createTarget
 
 	// Analyze what will be done based on installation type
 	s.analyzeFileOperations(plan, currentStatus)
+	s.analyzeLinkConflicts(plan)
+	if installType == models.InstallationTypeOverwrite && len(installConfig.OnlyPaths) == 0 {
+		plan.WillReplace = append(plan.WillReplace, staleRecordedFiles(absTarget, previousLock)...)
+	}
 
 	// Determine if backup is needed
 	plan.BackupRequired = s.needsBackup(plan, installConfig)
 	if plan.BackupRequired && !installConfig.NoBackup {
-		plan.BackupDir = s.filesystemService.GetBackupPath(absTarget)
+		plan.BackupDir = installConfig.BackupDir
+		if plan.BackupDir == "" {
+			plan.BackupDir = s.filesystemService.GetBackupPath(absTarget)
+		}
 	}
 
 	// Set up directory operations
@@ -100,8 +172,12 @@ func (s *Service) AnalyzeInstallation(installConfig models.InstallConfig) (*mode
 	return plan, nil
 }
 
-// Install performs the complete installation process
-func (s *Service) Install(installConfig models.InstallConfig) error {
+// Install performs the complete installation process. Errors are attributed
+// to the template with models.TemplateError.
+func (s *Service) Install(installConfig models.InstallConfig) (err error) {
+	defer func() { err = models.NewTemplateError(installConfig.TemplateID, err) }()
+	started := time.Now()
+
 	// Analyze what needs to be done
 	plan, err := s.AnalyzeInstallation(installConfig)
 	if err != nil {
@@ -117,9 +193,15 @@ func (s *Service) Install(installConfig models.InstallConfig) error {
 		)
 	}
 
+	if plan.CreateTarget {
+		if err := os.MkdirAll(plan.TargetDir, config.DirPermissions); err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, plan.TargetDir, err)
+		}
+	}
+
 	// Create backup if needed
 	if plan.BackupRequired && !installConfig.NoBackup {
-		if err := s.CreateBackup(plan.TargetDir, plan.BackupDir); err != nil {
+		if err := s.CreateBackup(plan.TargetDir, plan.BackupDir, plan.WillReplace); err != nil {
 			return fmt.Errorf("backup creation failed: %w", err)
 		}
 	}
@@ -130,47 +212,196 @@ func (s *Service) Install(installConfig models.InstallConfig) error {
 		return fmt.Errorf("failed to get template configuration: %w", err)
 	}
 
-	// Clone repository to temporary location using template configuration
-	tempDir, err := s.gitService.CloneRepositoryWithBranch(template.RepoURL, template.Branch, template.Commit)
+	// Fetch template contents (clone remote repositories, read local directories in place)
+	fetchStarted := time.Now()
+	source, err := s.prepareSource(template, installConfig)
 	if err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
+		return err
 	}
+	slog.Debug("Fetched template", "template", template.ID, "took", time.Since(fetchStarted).Round(time.Millisecond))
 	defer func() {
-		if cleanupErr := s.gitService.CleanupTempDir(tempDir); cleanupErr != nil {
-			fmt.Printf("Warning: Failed to cleanup temporary directory: %v\n", cleanupErr)
+		if cleanupErr := source.Cleanup(); cleanupErr != nil {
+			slog.Warn("Failed to cleanup temporary directory", "error", cleanupErr)
 		}
 	}()
+	sourceDir := source.Dir
+
+	// Make sure the files to install are the ones the template declares. A
+	// partial clone lacks the files its tree hash covers, and submodules add
+	// files it does not cover, so those rest on the commit check alone.
+	if reason := source.unhashable(); reason != "" && template.TreeHash != "" {
+		slog.Info("Not checking the tree hash of "+reason, "template", template.ID)
+	} else if !installConfig.SkipVerify {
+		if err := verifyTreeHash(sourceDir, template); err != nil {
+			return err
+		}
+	}
 
 	// Update plan with actual script detection
-	plan.HasPreInstallScript = s.scriptService.ScriptExists(tempDir, config.PreInstallScript)
-	plan.HasPostInstallScript = s.scriptService.ScriptExists(tempDir, config.PostInstallScript)
+	plan.HasPreInstallScript = s.scriptService.ScriptExists(sourceDir, config.PreInstallScript)
+	plan.HasPostInstallScript = s.scriptService.ScriptExists(sourceDir, config.PostInstallScript)
 
 	// Execute pre-install script if it exists
 	if plan.HasPreInstallScript {
-		if err := s.executePreInstallScript(tempDir, plan.TargetDir); err != nil {
+		if err := s.executePreInstallScript(sourceDir, plan.TargetDir); err != nil {
 			return fmt.Errorf("pre-install script failed: %w", err)
 		}
 	}
 
-	// Perform the installation based on type
-	switch plan.InstallationType {
-	case models.InstallationTypeNew:
-		err = s.installNew(tempDir, plan.TargetDir)
-	case models.InstallationTypeUpdate:
-		err = s.InstallCore(tempDir, plan.TargetDir)
-	case models.InstallationTypeOverwrite:
-		err = s.installOverwrite(tempDir, plan.TargetDir)
-	default:
-		err = models.NewAppError(
-			models.ErrorCodeInstallationFailed,
-			fmt.Sprintf("Unknown installation type: %s", plan.InstallationType),
-			nil,
-		)
+	// Stage the framework files inside the target and swap them into place;
+	// any failure from here on puts the previous files back
+	tx, err := s.filesystemService.BeginTransaction(plan.TargetDir)
+	if err != nil {
+		return fmt.Errorf("failed to prepare installation: %w", err)
+	}
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			slog.Warn("Failed to roll back installation", "error", rollbackErr)
+		}
+	}()
+
+	// Template symlinks must stay inside the template, so none can reach
+	// outside the target once installed
+	tx.SetLinkPolicy(filesystem.LinkPolicy{Root: sourceDir, Dereference: installConfig.Dereference})
+
+	// Large templates are thousands of small files, so they are copied in parallel
+	jobs := installConfig.Jobs
+	if jobs == 0 {
+		jobs = runtime.NumCPU()
 	}
+	tx.SetJobs(jobs)
 
+	filter, err := installFilter(template, installConfig)
 	if err != nil {
+		return err
+	}
+
+	subtrees, err := onlySubtrees(sourceDir, installConfig.OnlyPaths)
+	if err != nil {
+		return err
+	}
+
+	// The templates installed so far, which a layered install keeps and a
+	// full or core install replaces
+	previousLock, err := state.ReadLock(plan.TargetDir)
+	if err != nil {
+		previousLock = nil
+	}
+
+	copying := progress.Start("Copying template files")
+	defer copying.Done()
+	tx.SetProgress(func() { copying.Increment("files") })
+
+	// A full reinstall starts over: files the previous installation recorded
+	// outside the framework directory go too, after being backed up
+	fromScratch := plan.InstallationType == models.InstallationTypeOverwrite && len(subtrees) == 0
+
+	var shadowed []string
+	if plan.InstallationType == models.InstallationTypeLayer {
+		shadowed, err = s.stageLayer(tx, sourceDir, plan.TargetDir, template.ID, previousLock, filter, subtrees)
+	} else {
+		err = s.stageFramework(tx, sourceDir, plan.InstallationType, filter, subtrees)
+	}
+	copying.Done()
+	if err != nil {
+		return fmt.Errorf("installation failed: %w", err)
+	}
+
+	if fromScratch {
+		for _, rel := range staleRecordedFiles(plan.TargetDir, previousLock) {
+			if err := tx.StageRemoval(rel); err != nil {
+				return fmt.Errorf("installation failed: %w", err)
+			}
+		}
+	}
+
+	// Git checkouts take file modes from the tree; local directories and
+	// archives already copied theirs from disk
+	if source.Commit != "" && !source.fromArchive {
+		if err := s.applyTreeModes(tx, sourceDir); err != nil {
+			return fmt.Errorf("installation failed: %w", err)
+		}
+	}
+
+	if err := s.renderVariables(tx, template, installConfig, plan.TargetDir); err != nil {
+		return fmt.Errorf("failed to render template variables: %w", err)
+	}
+
+	// Remember what is about to be replaced, for the manifest. A layer only
+	// carries over its own entries; the other templates keep theirs.
+	installedRoots := tx.Staged()
+	var previousFiles, ownFiles []state.FileRecord
+	if previousLock != nil {
+		previousFiles = previousLock.AllFiles()
+		ownFiles = previousFiles
+		if fromScratch {
+			ownFiles = nil // Nothing is carried over
+		}
+		if plan.InstallationType == models.InstallationTypeLayer {
+			ownFiles = nil
+			if entry := previousLock.Find(template.ID); entry != nil {
+				ownFiles = entry.Files
+			}
+		}
+	}
+
+	// Core updates and layers keep local edits to framework files rather than losing them
+	var kept map[string]bool
+	if plan.InstallationType == models.InstallationTypeUpdate || plan.InstallationType == models.InstallationTypeLayer {
+		if kept, err = reconcileModified(tx, plan.TargetDir, previousFiles, installConfig); err != nil {
+			return fmt.Errorf("failed to check for modified files: %w", err)
+		}
+	}
+
+	// Files the template dropped outside the replaced directories are kept or pruned
+	if plan.InstallationType == models.InstallationTypeUpdate {
+		if err := reconcileRemoved(tx, sourceDir, plan.TargetDir, previousFiles, filter, subtrees, installConfig); err != nil {
+			return fmt.Errorf("failed to check for files removed from the template: %w", err)
+		}
+	}
+
+	// The template's history is kept alongside its files when asked for
+	if installConfig.KeepGit {
+		if err := s.stageGitDir(tx, source, template); err != nil {
+			return fmt.Errorf("failed to keep the template's git directory: %w", err)
+		}
+	} else if plan.InstallationType == models.InstallationTypeUpdate && hasKeptGitDir(plan.TargetDir) {
+		if withinSubtrees(keptGitPath(), tx.Staged()) {
+			slog.Warn("Removing the git directory kept by --keep-git; reinstall with --keep-git to keep it")
+		} else {
+			slog.Warn("The git directory kept by --keep-git still points at the previous commit; reinstall with --keep-git to move it too")
+		}
+	}
+
+	// Files the installed copy already matches stay as they are, timestamps included
+	changes, err := tx.KeepUnchanged()
+	if err != nil {
+		return fmt.Errorf("failed to compare with the installed files: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("installation failed: %w", err)
 	}
+	slog.Debug("Installed framework files", "staged", len(installedRoots), "unchanged", changes.Unchanged, "updated", changes.Updated, "took", time.Since(started).Round(time.Millisecond))
+
+	// The steps below change the target in place; snapshot what they touch so
+	// a failure puts it back along with the framework files
+	for _, rel := range rollbackPaths(plan.TargetDir, plan.InstallationType) {
+		if err := tx.Snapshot(rel); err != nil {
+			return fmt.Errorf("installation failed: %w", err)
+		}
+	}
+
+	// Core updates keep user directories, creating any the template added
+	if plan.InstallationType == models.InstallationTypeUpdate {
+		if err := s.filesystemService.PreserveUserContent(plan.TargetDir); err != nil {
+			return fmt.Errorf("failed to preserve user content: %w", err)
+		}
+	}
 
 	// Create .claude directory structure if needed
 	if err := s.ensureClaudeDirectory(plan.TargetDir); err != nil {
@@ -199,77 +430,158 @@ func (s *Service) Install(installConfig models.InstallConfig) error {
 
 	// Execute post-install script if it exists
 	if plan.HasPostInstallScript {
-		if err := s.executePostInstallScript(tempDir, plan.TargetDir); err != nil {
+		if err := s.executePostInstallScript(sourceDir, plan.TargetDir); err != nil {
 			return fmt.Errorf("post-install script failed: %w", err)
 		}
 	}
 
 	// Apply gitignore templates based on mode
-	if err := s.applyGitignoreTemplates(tempDir, plan.TargetDir, installConfig.GitignoreMode); err != nil {
+	if err := s.applyGitignoreTemplates(sourceDir, plan.TargetDir, installConfig.GitignoreMode); err != nil {
 		return fmt.Errorf("failed to apply gitignore templates: %w", err)
 	}
 
-	// Save template metadata
-	if err := s.saveTemplateInfo(plan.TargetDir, template); err != nil {
-		return fmt.Errorf("failed to save template metadata: %w", err)
+	// Save template metadata; it describes the base template, so layers on top
+	// are recorded only in the lock file
+	if plan.InstallationType != models.InstallationTypeLayer {
+		if err := s.saveTemplateInfo(plan.TargetDir, template, source.Commit); err != nil {
+			return fmt.Errorf("failed to save template metadata: %w", err)
+		}
 	}
 
-	// Validate installation
-	if err := s.ValidateInstallation(plan.TargetDir); err != nil {
+	// Validate installation; a partial install is only expected to contain its subtrees
+	if len(subtrees) > 0 {
+		if err := validateSubtrees(plan.TargetDir, subtrees); err != nil {
+			return fmt.Errorf("installation validation failed: %w", err)
+		}
+	} else if err := s.ValidateInstallation(plan.TargetDir); err != nil {
 		return fmt.Errorf("installation validation failed: %w", err)
 	}
 
-	return nil
-}
-
-// InstallCore performs selective core updates (--force-core flag)
-func (s *Service) InstallCore(sourceDir, targetDir string) error {
-	strategicDir := filepath.Join(targetDir, config.StrategicClaudeBasicDir)
-
-	// Ensure target directory exists
-	if err := s.filesystemService.CreateDirectory(strategicDir); err != nil {
+	// The template's own hooks run last. A failing one rolls back what the
+	// installer wrote, but whatever the hooks changed themselves stays.
+	if err := s.runPostInstallHooks(template, plan.TargetDir, installConfig); err != nil {
 		return err
 	}
 
-	// Copy only framework directories (core, guides, templates)
-	sourceStrategicDir := filepath.Join(sourceDir, config.StrategicClaudeBasicDir)
-	if err := s.filesystemService.CopyFrameworkFiles(sourceStrategicDir, strategicDir); err != nil {
-		return fmt.Errorf("failed to copy framework files: %w", err)
+	// Record what was installed once everything else has succeeded
+	files, err := buildManifest(plan.TargetDir, installedRoots, ownFiles, kept)
+	if err != nil {
+		return fmt.Errorf("failed to record installed files: %w", err)
 	}
 
-	// Ensure user directories exist (but don't overwrite them)
-	if err := s.filesystemService.PreserveUserContent(targetDir); err != nil {
-		return fmt.Errorf("failed to preserve user content: %w", err)
+	lock := &state.Lock{}
+	if plan.InstallationType == models.InstallationTypeLayer && previousLock != nil {
+		lock = previousLock
+	} else if previousLock != nil {
+		var dropped []string
+		for _, id := range previousLock.TemplateIDs() {
+			if id != template.ID {
+				dropped = append(dropped, id)
+			}
+		}
+		if len(previousLock.Templates) > 1 && len(dropped) > 0 {
+			if fromScratch {
+				slog.Warn(fmt.Sprintf("Reinstalling from scratch removed the layered templates %s; add them again with 'init --add'",
+					strings.Join(dropped, ", ")))
+			} else {
+				slog.Warn(fmt.Sprintf("The lock file no longer records the layered templates %s; add them again with 'init --add'",
+					strings.Join(dropped, ", ")))
+			}
+		}
+	}
+	// A kept git directory is still recorded while it survives the reinstall
+	keptGit := false
+	if previousLock != nil {
+		if entry := previousLock.Find(template.ID); entry != nil {
+			keptGit = entry.KeepGit
+		}
+	}
+	overlaps := lock.Put(state.TemplateLock{
+		TemplateID:     template.ID,
+		RepoURL:        template.RepoURL,
+		Branch:         template.Branch,
+		Commit:         source.Commit,
+		Tag:            template.Tag,
+		KeepGit:        installConfig.KeepGit || (keptGit && hasKeptGitDir(plan.TargetDir)),
+		CommitOverride: installConfig.FromCommit != "" || installConfig.SelectedCommit != "",
+		Ref:            installConfig.Ref,
+		ArchiveDigest:  source.archiveDigest,
+		Submodules:     installConfig.Submodules,
+		InstalledAt:    time.Now().UTC(),
+		Only:           subtrees,
+		Files:          files,
+	})
+	overlaps = append(overlaps, lock.RecordShadowed(template.ID, shadowed)...)
+	if installConfig.NoLock {
+		if err := s.removeLock(plan.TargetDir, previousLock != nil); err != nil {
+			return fmt.Errorf("failed to remove lock file: %w", err)
+		}
+	} else if err := s.writeLock(plan.TargetDir, lock); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+	if installConfig.OnOverlap != nil {
+		for _, overlap := range overlaps {
+			installConfig.OnOverlap(overlap)
+		}
 	}
 
-	// Process settings.json (merge updated template with existing user settings)
-	if err := s.settingsService.ProcessSettings(targetDir); err != nil {
-		return fmt.Errorf("failed to process settings during core update: %w", err)
+	if installConfig.OnFileChanges != nil {
+		installConfig.OnFileChanges(changes)
 	}
 
-	// Process Codex config.toml (update template if it exists)
-	if err := s.codexConfigService.ProcessCodexConfig(targetDir); err != nil {
-		return fmt.Errorf("failed to process codex config during core update: %w", err)
+	committed = true
+	if err := tx.Close(); err != nil {
+		slog.Warn("Failed to remove staging directory", "error", err)
 	}
 
+	slog.Debug("Installation finished", "template", template.ID, "files", len(files), "took", time.Since(started).Round(time.Millisecond))
 	return nil
 }
 
-// CreateBackup creates a backup of the existing installation
-func (s *Service) CreateBackup(targetDir, backupPath string) error {
-	strategicDir := filepath.Join(targetDir, config.StrategicClaudeBasicDir)
+// stageFramework stages the template's framework files for the installation type:
+// the whole framework directory for new installs and overwrites, and only the
+// framework subdirectories (core, templates) for core updates. When subtrees are
+// given, only those paths are staged, whatever the installation type, and the
+// rest of the target is left alone. Files filter skips are left out.
+func (s *Service) stageFramework(tx *filesystem.Transaction, sourceDir string, installType models.InstallationType, filter fileFilter, subtrees []string) error {
+	sourceStrategicDir := filepath.Join(sourceDir, config.StrategicClaudeBasicDir)
 
-	// Check if strategic-claude-basic directory exists
-	if _, err := os.Stat(strategicDir); os.IsNotExist(err) {
-		return nil // Nothing to backup
+	// Patterns are relative to the repository root, not the staged directory
+	skip := func(path string, info os.FileInfo) bool {
+		rel, err := filepath.Rel(sourceDir, path)
+		return err == nil && filter.Skip(rel, info.IsDir())
 	}
 
-	// Create backup
-	if err := s.filesystemService.BackupDirectory(strategicDir, backupPath); err != nil {
-		return fmt.Errorf("failed to create backup: %w", err)
+	if len(subtrees) > 0 {
+		for _, rel := range subtrees {
+			if err := tx.StageDirectoryFiltered(filepath.Join(sourceDir, rel), rel, skip); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	return nil
+	switch installType {
+	case models.InstallationTypeNew, models.InstallationTypeOverwrite:
+		return tx.StageDirectoryFiltered(sourceStrategicDir, config.StrategicClaudeBasicDir, skip)
+	case models.InstallationTypeUpdate:
+		for _, dir := range config.GetCoreDirectories() {
+			sourcePath := filepath.Join(sourceStrategicDir, dir)
+			if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+				continue // Skip if source doesn't have this directory
+			}
+			if err := tx.StageDirectoryFiltered(sourcePath, filepath.Join(config.StrategicClaudeBasicDir, dir), skip); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return models.NewAppError(
+			models.ErrorCodeInstallationFailed,
+			fmt.Sprintf("Unknown installation type: %s", installType),
+			nil,
+		)
+	}
 }
 
 // ValidateInstallation verifies that the installation was successful
@@ -303,6 +615,14 @@ func (s *Service) ValidateInstallation(targetDir string) error {
 // Helper methods
 
 func (s *Service) determineInstallationType(status *models.StatusInfo, installConfig models.InstallConfig) models.InstallationType {
+	// A layer goes over the installed templates; the first template is a new installation
+	if installConfig.Layer {
+		if status.IsInstalled {
+			return models.InstallationTypeLayer
+		}
+		return models.InstallationTypeNew
+	}
+
 	// If force is set, always do full overwrite
 	if installConfig.Force {
 		return models.InstallationTypeOverwrite
@@ -391,24 +711,6 @@ func (s *Service) analyzeSymlinkOperations(plan *models.InstallationPlan, status
 	}
 }
 
-func (s *Service) installNew(sourceDir, targetDir string) error {
-	sourceStrategicDir := filepath.Join(sourceDir, config.StrategicClaudeBasicDir)
-	targetStrategicDir := filepath.Join(targetDir, config.StrategicClaudeBasicDir)
-
-	// Copy entire .strategic-claude-basic directory
-	return s.filesystemService.CopyDirectory(sourceStrategicDir, targetStrategicDir)
-}
-
-func (s *Service) installOverwrite(sourceDir, targetDir string) error {
-	// Remove existing installation
-	if err := s.filesystemService.RemoveStrategicClaudeBasic(targetDir); err != nil {
-		return err
-	}
-
-	// Install fresh copy
-	return s.installNew(sourceDir, targetDir)
-}
-
 func (s *Service) ensureClaudeDirectory(targetDir string) error {
 	claudeDir := filepath.Join(targetDir, config.ClaudeDir)
 
@@ -429,8 +731,30 @@ func (s *Service) ensureClaudeDirectory(targetDir string) error {
 	return nil
 }
 
+// rollbackPaths lists the target paths changed in place once the framework
+// files are committed: the .claude and .codex directories with their symlinks
+// and settings, the framework's .gitignore and template metadata, and the user
+// directories a core update is about to create
+func rollbackPaths(targetDir string, installType models.InstallationType) []string {
+	paths := []string{
+		config.ClaudeDir,
+		config.CodexDir,
+		filepath.Join(config.StrategicClaudeBasicDir, ".gitignore"),
+		filepath.Join(config.StrategicClaudeBasicDir, config.TemplateInfoFile),
+	}
+	if installType == models.InstallationTypeUpdate {
+		for _, dir := range config.GetUserPreservedDirectories() {
+			rel := filepath.Join(config.StrategicClaudeBasicDir, dir)
+			if _, err := os.Lstat(filepath.Join(targetDir, rel)); os.IsNotExist(err) {
+				paths = append(paths, rel)
+			}
+		}
+	}
+	return paths
+}
+
 // saveTemplateInfo saves template metadata to the installation directory
-func (s *Service) saveTemplateInfo(targetDir string, template templates.Template) error {
+func (s *Service) saveTemplateInfo(targetDir string, template templates.Template, installedCommit string) error {
 	strategicDir := filepath.Join(targetDir, config.StrategicClaudeBasicDir)
 	templateInfoPath := filepath.Join(strategicDir, config.TemplateInfoFile)
 
@@ -438,12 +762,12 @@ func (s *Service) saveTemplateInfo(targetDir string, template templates.Template
 	templateInfo := templates.TemplateInfo{
 		Template:        template,
 		InstalledAt:     time.Now().Format(time.RFC3339),
-		InstalledCommit: template.Commit,
+		InstalledCommit: installedCommit,
 		Metadata:        make(map[string]string),
 	}
 
 	// Add additional metadata
-	templateInfo.Metadata["cli_version"] = "0.1.0" // TODO: Get from build info
+	templateInfo.Metadata["cli_version"] = templates.CLIVersion
 	templateInfo.Metadata["installation_type"] = "cli"
 
 	// Marshal to JSON
@@ -468,6 +792,35 @@ func (s *Service) saveTemplateInfo(targetDir string, template templates.Template
 	return nil
 }
 
+// writeLock stamps the lock with the format version and saves it
+func (s *Service) writeLock(targetDir string, lock *state.Lock) error {
+	lock.Version = state.LockVersion
+
+	if err := state.WriteLock(targetDir, lock); err != nil {
+		return models.NewAppError(
+			models.ErrorCodeFileSystemError,
+			fmt.Sprintf("Failed to write lock file to %s", state.LockPath(targetDir)),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// removeLock deletes the lock file an earlier install left when installing with
+// --no-lock, so the target is reported as unmanaged rather than as that install
+// with stale hashes. Only core updates keep the old lock file; other installs
+// replace the framework directory, and the lock file with it.
+func (s *Service) removeLock(targetDir string, hadLock bool) error {
+	if err := os.Remove(state.LockPath(targetDir)); err != nil && !os.IsNotExist(err) {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, state.LockPath(targetDir), err)
+	}
+	if hadLock {
+		slog.Warn(fmt.Sprintf("Installed without a lock file; %s is no longer managed, so update, verify, and diff will not run there", targetDir))
+	}
+	return nil
+}
+
 // analyzeScriptOperations checks if installation scripts exist in the template
 func (s *Service) analyzeScriptOperations(plan *models.InstallationPlan) {
 	// This will be set after the repository is cloned, but we can initialize it here
@@ -491,7 +844,7 @@ func (s *Service) executePreInstallScript(sourceDir, targetDir string) error {
 	// Clean up script after execution
 	if err := s.scriptService.RemoveScript(targetDir, config.PreInstallScript); err != nil {
 		// Log warning but don't fail installation
-		fmt.Printf("Warning: Failed to remove pre-install script: %v\n", err)
+		slog.Warn("Failed to remove pre-install script", "error", err)
 	}
 
 	return nil
@@ -512,7 +865,38 @@ func (s *Service) executePostInstallScript(sourceDir, targetDir string) error {
 	// Clean up script after execution
 	if err := s.scriptService.RemoveScript(targetDir, config.PostInstallScript); err != nil {
 		// Log warning but don't fail installation
-		fmt.Printf("Warning: Failed to remove post-install script: %v\n", err)
+		slog.Warn("Failed to remove post-install script", "error", err)
+	}
+
+	return nil
+}
+
+// runPostInstallHooks runs the commands the template lists in PostInstall in
+// the target directory, in order, stopping at the first that fails. They only
+// run with RunHooks; otherwise the user is told they were skipped.
+func (s *Service) runPostInstallHooks(template templates.Template, targetDir string, installConfig models.InstallConfig) error {
+	if len(template.PostInstall) == 0 {
+		return nil
+	}
+
+	if !installConfig.RunHooks {
+		slog.Warn(fmt.Sprintf("Template %s defines %d post-install hooks, which were not run; review them with 'info %s' and pass --run-hooks to run them",
+			template.ID, len(template.PostInstall), template.ID))
+		return nil
+	}
+
+	output := installConfig.HookOutput
+	if output == nil {
+		output = io.Discard
+	}
+
+	for i, command := range template.PostInstall {
+		fmt.Fprintf(output, "Running post-install hook %d of %d: %s\n", i+1, len(template.PostInstall), command)
+		started := time.Now()
+		if err := s.scriptService.RunCommand(targetDir, command, output); err != nil {
+			return fmt.Errorf("post-install hook failed, so the installation was rolled back; changes the hooks made were kept: %w", err)
+		}
+		slog.Debug("Ran post-install hook", "template", template.ID, "command", command, "took", time.Since(started).Round(time.Millisecond))
 	}
 
 	return nil
@@ -552,7 +936,7 @@ func (s *Service) applyGitignoreTemplates(sourceDir, targetDir, gitignoreMode st
 			return fmt.Errorf("failed to apply template %s: %w", templateFile, err)
 		}
 
-		fmt.Printf("Applied gitignore template: %s -> %s\n", templateFile, targetFile)
+		slog.Debug(fmt.Sprintf("Applied gitignore template %s to %s", templateFile, targetFile))
 	}
 
 	return nil
@@ -1,8 +1,11 @@
 package installer
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
@@ -182,6 +185,18 @@ func TestDetermineInstallationType(t *testing.T) {
 			installConfig: models.InstallConfig{},
 			expectedType:  models.InstallationTypeOverwrite,
 		},
+		{
+			name:          "layer over an installation",
+			status:        &models.StatusInfo{IsInstalled: true},
+			installConfig: models.InstallConfig{Layer: true},
+			expectedType:  models.InstallationTypeLayer,
+		},
+		{
+			name:          "layer without an installation",
+			status:        &models.StatusInfo{IsInstalled: false},
+			installConfig: models.InstallConfig{Layer: true},
+			expectedType:  models.InstallationTypeNew,
+		},
 	}
 
 	for _, tt := range tests {
@@ -330,3 +345,603 @@ func TestAnalyzeFileOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestInstall_RollsBackOnFailure(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	goodSource := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: goodSource},
+	})
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Initial Install() error = %v", err)
+	}
+
+	// A template without its templates directory fails validation after the files are swapped in
+	brokenSource := createLocalTemplate(t)
+	readme := filepath.Join(brokenSource, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Broken\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(brokenSource, config.StrategicClaudeBasicDir, config.TemplatesDir)); err != nil {
+		t.Fatalf("Failed to remove templates directory: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: brokenSource},
+	})
+
+	installConfig.Force = true
+	if err := New().Install(installConfig); err == nil {
+		t.Fatal("Expected Install() to fail for an invalid template")
+	}
+
+	installed, err := os.ReadFile(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Expected the previous installation to be restored: %v", err)
+	}
+	if string(installed) != "# Core\n" {
+		t.Errorf("README.md = %q, want restored %q", string(installed), "# Core\n")
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.TemplatesDir)); err != nil {
+		t.Errorf("Expected templates directory to be restored: %v", err)
+	}
+
+	entries, err := os.ReadDir(targetDir)
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), config.StagingDirPrefix) {
+			t.Errorf("Staging directory %s was left behind", entry.Name())
+		}
+	}
+}
+
+func TestInstall_RollsBackNewInstallation(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	// A template without its templates directory fails validation after the
+	// symlinks, settings, and metadata are written
+	brokenSource := createLocalTemplate(t)
+	if err := os.RemoveAll(filepath.Join(brokenSource, config.StrategicClaudeBasicDir, config.TemplatesDir)); err != nil {
+		t.Fatalf("Failed to remove templates directory: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: brokenSource},
+	})
+
+	targetDir := t.TempDir()
+	settingsPath := filepath.Join(targetDir, config.ClaudeDir, config.ClaudeSettingsFile)
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		t.Fatalf("Failed to create .claude: %v", err)
+	}
+	if err := os.WriteFile(settingsPath, []byte(`{"user": true}`), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+	if err := New().Install(installConfig); err == nil {
+		t.Fatal("Expected Install() to fail for an invalid template")
+	}
+
+	// The target is left as it was: no framework, no symlinks, settings untouched
+	for _, rel := range []string{config.StrategicClaudeBasicDir, config.CodexDir} {
+		if _, err := os.Lstat(filepath.Join(targetDir, rel)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed, stat error = %v", rel, err)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(targetDir, config.ClaudeDir))
+	if err != nil {
+		t.Fatalf("Expected .claude to be restored: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != config.ClaudeSettingsFile {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf(".claude holds %v, want only %s", names, config.ClaudeSettingsFile)
+	}
+	if data, err := os.ReadFile(settingsPath); err != nil || string(data) != `{"user": true}` {
+		t.Errorf("settings.json = %q, %v, want it untouched", data, err)
+	}
+
+	entries, err = os.ReadDir(targetDir)
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), config.StagingDirPrefix) {
+			t.Errorf("Staging directory %s was left behind", entry.Name())
+		}
+	}
+}
+
+func TestInstall_PostInstallHooks(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, PostInstall: []string{
+			"echo hook ran; echo to stderr >&2",
+			"test -f " + filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md") + " && touch hooked",
+		}},
+	})
+
+	// Hooks are skipped unless asked for
+	targetDir := t.TempDir()
+	var output bytes.Buffer
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+		HookOutput:    &output,
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "hooked")); !os.IsNotExist(err) {
+		t.Error("Expected the hooks not to run without RunHooks")
+	}
+	if output.Len() != 0 {
+		t.Errorf("Expected no hook output, got %q", output.String())
+	}
+
+	// With RunHooks they run in order in the installed target
+	installConfig.Force = true
+	installConfig.RunHooks = true
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() with RunHooks error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "hooked")); err != nil {
+		t.Errorf("Expected the second hook to run after the files were installed: %v", err)
+	}
+	for _, want := range []string{"hook 1 of 2", "hook ran", "to stderr", "hook 2 of 2"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("Expected hook output to contain %q, got %q", want, output.String())
+		}
+	}
+
+	// A failing hook fails the install and puts the previous files back
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Changed\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, PostInstall: []string{"exit 1"}},
+	})
+	if err := New().Install(installConfig); err == nil {
+		t.Fatal("Expected Install() to fail when a hook fails")
+	}
+	installed, err := os.ReadFile(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Expected the previous installation to be restored: %v", err)
+	}
+	if string(installed) != "# Core\n" {
+		t.Errorf("README.md = %q, want restored %q", string(installed), "# Core\n")
+	}
+}
+
+func TestInstall_FailingHookRollsBack(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, PostInstall: []string{"touch hook-output && exit 1"}},
+	})
+
+	targetDir := t.TempDir()
+	projectFile := filepath.Join(targetDir, "main.go")
+	if err := os.WriteFile(projectFile, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write project file: %v", err)
+	}
+
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+		RunHooks:      true,
+	}
+	err := New().Install(installConfig)
+	if err == nil {
+		t.Fatal("Expected Install() to fail when a hook fails")
+	}
+	if !strings.Contains(err.Error(), "rolled back") {
+		t.Errorf("Expected the error to say the installation was rolled back, got %v", err)
+	}
+
+	// Everything the installer wrote is gone; the project and the hook's own
+	// output are left
+	entries, err := os.ReadDir(targetDir)
+	if err != nil {
+		t.Fatalf("Failed to read target: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, ",") != "hook-output,main.go" {
+		t.Errorf("Target holds %v after the failed hook, want [hook-output main.go]", names)
+	}
+}
+
+func TestInstall_ExcludePatterns(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	strategic := config.StrategicClaudeBasicDir
+	for _, rel := range []string{
+		"README.md",
+		filepath.Join(".github", "workflows", "ci.yml"),
+		filepath.Join(strategic, config.CoreDir, "examples", "demo.md"),
+		filepath.Join(strategic, config.CoreDir, "notes.draft.md"),
+	} {
+		path := filepath.Join(sourceDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", rel, err)
+		}
+	}
+
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, ExcludePatterns: []string{"**/examples/"}},
+	})
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:       targetDir,
+		TemplateID:      "local",
+		SkipConfirm:     true,
+		NoBackup:        true,
+		GitignoreMode:   "track",
+		ExcludePatterns: []string{"*.draft.md"},
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		// The default patterns only apply at the repository root
+		{filepath.Join(strategic, config.CoreDir, "README.md"), true},
+		{filepath.Join(strategic, config.CoreDir, "examples"), false},
+		{filepath.Join(strategic, config.CoreDir, "notes.draft.md"), false},
+		{"README.md", false},
+		{".github", false},
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(targetDir, tt.rel))
+		if got := err == nil; got != tt.want {
+			t.Errorf("%s installed = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestInstall_IncludePatterns(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	strategic := config.StrategicClaudeBasicDir
+	for _, rel := range []string{
+		filepath.Join(strategic, config.CoreDir, "commands", "plan.md"),
+		filepath.Join(strategic, config.CoreDir, "commands", "plan.draft.md"),
+		filepath.Join(strategic, config.CoreDir, "hooks", "run.sh"),
+		filepath.Join(strategic, config.TemplatesDir, "issue.tmpl"),
+	} {
+		path := filepath.Join(sourceDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", rel, err)
+		}
+	}
+
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, IncludePatterns: []string{"*.tmpl"}},
+	})
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:       targetDir,
+		TemplateID:      "local",
+		SkipConfirm:     true,
+		NoBackup:        true,
+		GitignoreMode:   "track",
+		IncludePatterns: []string{"**/*.md"},
+		ExcludePatterns: []string{"*.draft.md"},
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		// The user's include patterns add to the template's
+		{filepath.Join(strategic, config.CoreDir, "commands", "plan.md"), true},
+		{filepath.Join(strategic, config.TemplatesDir, "issue.tmpl"), true},
+		// Excludes remove files from the included candidates
+		{filepath.Join(strategic, config.CoreDir, "commands", "plan.draft.md"), false},
+		// Files no include pattern matches are left out, their directories kept
+		{filepath.Join(strategic, config.CoreDir, "hooks", "run.sh"), false},
+		{filepath.Join(strategic, config.CoreDir, "hooks"), true},
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(targetDir, tt.rel))
+		if got := err == nil; got != tt.want {
+			t.Errorf("%s installed = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestInstall_CreatesMissingTarget(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	targetDir := filepath.Join(t.TempDir(), "new", "project")
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+
+	service := New()
+	if _, err := service.AnalyzeInstallation(installConfig); !models.IsErrorCode(err, models.ErrorCodeDirectoryNotFound) {
+		t.Fatalf("AnalyzeInstallation() without CreateTarget error = %v, want %s", err, models.ErrorCodeDirectoryNotFound)
+	}
+
+	installConfig.CreateTarget = true
+	plan, err := service.AnalyzeInstallation(installConfig)
+	if err != nil {
+		t.Fatalf("AnalyzeInstallation() error = %v", err)
+	}
+	if !plan.CreateTarget || plan.InstallationType != models.InstallationTypeNew {
+		t.Errorf("plan CreateTarget = %v, type = %s; want true, %s", plan.CreateTarget, plan.InstallationType, models.InstallationTypeNew)
+	}
+	if _, err := os.Stat(targetDir); !os.IsNotExist(err) {
+		t.Fatalf("AnalyzeInstallation() created the target directory")
+	}
+
+	if err := service.Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.LockFileName)); err != nil {
+		t.Errorf("Expected the lock file inside the created target: %v", err)
+	}
+}
+
+func TestInstall_NonEmptyTarget(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	targetDir := t.TempDir()
+	projectFile := filepath.Join(targetDir, "main.go")
+	if err := os.WriteFile(projectFile, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write project file: %v", err)
+	}
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		RequireEmpty:  true,
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+
+	service := New()
+	if err := service.Install(installConfig); !models.IsErrorCode(err, models.ErrorCodeDirectoryNotEmpty) {
+		t.Fatalf("Install() into a populated target error = %v, want %s", err, models.ErrorCodeDirectoryNotEmpty)
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, config.StrategicClaudeBasicDir)); !os.IsNotExist(err) {
+		t.Fatalf("Install() wrote into the target it refused")
+	}
+
+	// --force only starts an existing installation over; it does not lift the check
+	installConfig.Force = true
+	if err := service.Install(installConfig); !models.IsErrorCode(err, models.ErrorCodeDirectoryNotEmpty) {
+		t.Errorf("Install() with Force into a populated target error = %v, want %s", err, models.ErrorCodeDirectoryNotEmpty)
+	}
+
+	// Without RequireEmpty, as for init in the current directory, the
+	// framework is installed alongside the project's files
+	installConfig.Force = false
+	installConfig.RequireEmpty = false
+	if err := service.Install(installConfig); err != nil {
+		t.Fatalf("Install() into a project directory error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.LockFileName)); err != nil {
+		t.Errorf("Expected the lock file after the install: %v", err)
+	}
+	if data, err := os.ReadFile(projectFile); err != nil || string(data) != "package main\n" {
+		t.Errorf("Expected the project file to be left alone, got %q, %v", data, err)
+	}
+
+	// An installation there can still be updated or reinstalled
+	installConfig.RequireEmpty = true
+	installConfig.ForceCore = true
+	if _, err := service.AnalyzeInstallation(installConfig); err != nil {
+		t.Errorf("AnalyzeInstallation() of a core update error = %v", err)
+	}
+
+	// A git directory and the version file do not make the target populated
+	emptyTarget := t.TempDir()
+	if err := os.Mkdir(filepath.Join(emptyTarget, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(emptyTarget, config.VersionFileName), []byte("local\n"), 0644); err != nil {
+		t.Fatalf("Failed to write version file: %v", err)
+	}
+	installConfig.TargetDir = emptyTarget
+	installConfig.ForceCore = false
+	if _, err := service.AnalyzeInstallation(installConfig); err != nil {
+		t.Errorf("AnalyzeInstallation() with only .git and the version file error = %v", err)
+	}
+}
+
+func TestInstall_MinCLIVersion(t *testing.T) {
+	original, originalVersion := templates.Registry.Snapshot(), templates.CLIVersion
+	t.Cleanup(func() {
+		templates.Registry.Set(original)
+		templates.CLIVersion = originalVersion
+	})
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, MinCLIVersion: "1.4.0"},
+	})
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+
+	// Refused before anything is written to the target
+	templates.CLIVersion = "1.3.0"
+	err := New().Install(installConfig)
+	if !models.IsErrorCode(err, models.ErrorCodeValidationFailed) || !strings.Contains(err.Error(), "upgrade the CLI") {
+		t.Fatalf("Install() with an older CLI error = %v, want an upgrade message", err)
+	}
+	if entries, _ := os.ReadDir(targetDir); len(entries) != 0 {
+		t.Errorf("Expected the target to be left empty, found %v", entries)
+	}
+
+	templates.CLIVersion = "1.4.0"
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	// The installed metadata records the version that passed the check
+	data, err := os.ReadFile(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.TemplateInfoFile))
+	if err != nil {
+		t.Fatalf("Failed to read template info: %v", err)
+	}
+	var info templates.TemplateInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatalf("Failed to parse template info: %v", err)
+	}
+	if info.Metadata["cli_version"] != "1.4.0" {
+		t.Errorf("Template info cli_version = %q, want 1.4.0", info.Metadata["cli_version"])
+	}
+}
+
+func TestInstall_TemplateSymlinks(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	commands := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.CommandsDir)
+	outside := filepath.Join(t.TempDir(), "outside.md")
+	if err := os.WriteFile(outside, []byte("outside\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file outside the template: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		target      func(sourceDir string) string
+		dereference bool
+		wantLink    bool
+		wantErr     bool
+	}{
+		{name: "relative link", target: func(string) string { return "../README.md" }, wantLink: true},
+		{name: "absolute link", target: func(sourceDir string) string {
+			return filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+		}, wantLink: true},
+		{name: "dereferenced link", target: func(string) string { return "../README.md" }, dereference: true},
+		{name: "link outside the template", target: func(string) string { return outside }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceDir := createLocalTemplate(t)
+			if err := os.Symlink(tt.target(sourceDir), filepath.Join(sourceDir, commands, "readme.md")); err != nil {
+				t.Fatalf("Failed to create symlink: %v", err)
+			}
+			templates.Registry.Set(map[string]templates.Template{
+				"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+			})
+
+			targetDir := t.TempDir()
+			err := New().Install(models.InstallConfig{
+				TargetDir:     targetDir,
+				TemplateID:    "local",
+				SkipConfirm:   true,
+				NoBackup:      true,
+				GitignoreMode: "track",
+				Dereference:   tt.dereference,
+			})
+			installed := filepath.Join(targetDir, commands, "readme.md")
+			if tt.wantErr {
+				if !models.IsErrorCode(err, models.ErrorCodeSymlinkInvalid) {
+					t.Fatalf("Install() error = %v, want %s", err, models.ErrorCodeSymlinkInvalid)
+				}
+				if _, err := os.Lstat(installed); !os.IsNotExist(err) {
+					t.Error("Expected nothing to be installed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+
+			info, err := os.Lstat(installed)
+			if err != nil {
+				t.Fatalf("Failed to stat installed link: %v", err)
+			}
+			if isLink := info.Mode()&os.ModeSymlink != 0; isLink != tt.wantLink {
+				t.Errorf("Installed readme.md is a symlink = %v, want %v", isLink, tt.wantLink)
+			}
+			if linkTarget, err := os.Readlink(installed); err == nil && filepath.IsAbs(linkTarget) {
+				t.Errorf("Installed link points at %s, want a path relative to the target", linkTarget)
+			}
+			data, err := os.ReadFile(installed)
+			if err != nil || string(data) != "# Core\n" {
+				t.Errorf("Installed readme.md content = %q, %v; want the core README", data, err)
+			}
+		})
+	}
+}
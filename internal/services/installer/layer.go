@@ -0,0 +1,121 @@
+package installer
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// stageLayer stages a template's framework files one by one over the
+// installation already in place, leaving every other file alone. Templates
+// later in the lock take precedence, so files they installed are not staged.
+// Files the template installed last time but no longer ships are staged for
+// removal unless they were edited since. The files the template ships but
+// left to later templates are returned.
+func (s *Service) stageLayer(tx *filesystem.Transaction, sourceDir, targetDir, templateID string, lock *state.Lock, filter fileFilter, subtrees []string) ([]string, error) {
+	shadowed := laterTemplateFiles(lock, templateID)
+	var skipped []string
+
+	roots := subtrees
+	if len(roots) == 0 {
+		roots = []string{config.StrategicClaudeBasicDir}
+	}
+
+	staged := make(map[string]bool)
+	for _, root := range roots {
+		rootPath := filepath.Join(sourceDir, root)
+		err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) && path == rootPath {
+				return filepath.SkipDir
+			}
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(sourceDir, path)
+			if err != nil {
+				return err
+			}
+			if filter.Skip(rel, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if shadowed[filepath.ToSlash(rel)] {
+				skipped = append(skipped, filepath.ToSlash(rel))
+				return nil
+			}
+
+			staged[filepath.ToSlash(rel)] = true
+			return tx.StageFile(path, rel)
+		})
+		if err != nil {
+			return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, rootPath, err)
+		}
+	}
+
+	if lock == nil {
+		return skipped, nil
+	}
+	entry := lock.Find(templateID)
+	if entry == nil {
+		return skipped, nil
+	}
+
+	for _, record := range entry.Files {
+		rel := filepath.FromSlash(record.Path)
+		if record.IsLink() || staged[record.Path] || !withinSubtrees(rel, roots) {
+			continue
+		}
+
+		localPath := filepath.Join(targetDir, rel)
+		modified, err := record.Modified(localPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, localPath, err)
+		}
+		if modified {
+			continue // Local edits stay, still recorded under the template
+		}
+
+		if err := tx.StageRemoval(rel); err != nil {
+			return nil, err
+		}
+	}
+
+	return skipped, nil
+}
+
+// laterTemplateFiles returns the files recorded for templates that come after
+// templateID in the lock, which a layered install of templateID must not replace
+func laterTemplateFiles(lock *state.Lock, templateID string) map[string]bool {
+	files := make(map[string]bool)
+	if lock == nil {
+		return files
+	}
+
+	index := lock.Index(templateID)
+	if index < 0 {
+		return files // A new layer goes on top of everything
+	}
+
+	for _, entry := range lock.Templates[index+1:] {
+		for _, record := range entry.Files {
+			if !record.IsLink() {
+				files[record.Path] = true
+			}
+		}
+	}
+	return files
+}
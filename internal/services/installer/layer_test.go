@@ -0,0 +1,149 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_LayeredTemplates(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	commands := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.CommandsDir)
+	writeFiles := func(dir string, files map[string]string) {
+		t.Helper()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, commands, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+		}
+	}
+
+	baseDir := createLocalTemplate(t)
+	writeFiles(baseDir, map[string]string{"plan.md": "base\n", "base-only.md": "base\n"})
+	layerDir := createLocalTemplate(t)
+	if err := os.Remove(filepath.Join(layerDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")); err != nil {
+		t.Fatalf("Failed to remove README: %v", err)
+	}
+	writeFiles(layerDir, map[string]string{"plan.md": "layer\n", "extra.md": "layer\n"})
+
+	templates.Registry.Set(map[string]templates.Template{
+		"base":  {ID: "base", Name: "Base", RepoURL: baseDir},
+		"layer": {ID: "layer", Name: "Layer", RepoURL: layerDir},
+	})
+
+	targetDir := t.TempDir()
+	var overlaps []state.FileOverlap
+	install := func(templateID string, layer bool) {
+		t.Helper()
+		overlaps = nil
+		installConfig := models.InstallConfig{
+			TargetDir:     targetDir,
+			TemplateID:    templateID,
+			Layer:         layer,
+			Force:         !layer, // Reinstalling over the layers starts over
+			SkipConfirm:   true,
+			NoBackup:      true,
+			GitignoreMode: "track",
+			OnOverlap:     func(overlap state.FileOverlap) { overlaps = append(overlaps, overlap) },
+		}
+		if err := New().Install(installConfig); err != nil {
+			t.Fatalf("Install(%s) error = %v", templateID, err)
+		}
+	}
+	content := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(targetDir, commands, name))
+		if os.IsNotExist(err) {
+			return ""
+		}
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		return string(data)
+	}
+	readLock := func() *state.Lock {
+		t.Helper()
+		lock, err := state.ReadLock(targetDir)
+		if err != nil || lock == nil {
+			t.Fatalf("ReadLock() = %v, %v", lock, err)
+		}
+		return lock
+	}
+	recorded := func(entry *state.TemplateLock, name string) bool {
+		for _, record := range entry.Files {
+			if record.Path == filepath.ToSlash(filepath.Join(commands, name)) {
+				return true
+			}
+		}
+		return false
+	}
+	sharedWith := func(entry *state.TemplateLock, name string) string {
+		for _, record := range entry.Files {
+			if record.Path == filepath.ToSlash(filepath.Join(commands, name)) {
+				return strings.Join(record.SharedWith, ",")
+			}
+		}
+		return ""
+	}
+
+	install("base", false)
+	install("layer", true)
+
+	// The later template wins the collision, and everything else stays
+	if got := content("plan.md"); got != "layer\n" {
+		t.Errorf("plan.md = %q, want the layer's copy", got)
+	}
+	if content("base-only.md") == "" || content("extra.md") == "" {
+		t.Error("Expected files from both templates to be installed")
+	}
+	if len(overlaps) != 1 || overlaps[0].TemplateID != "base" || !strings.HasSuffix(overlaps[0].Path, "/plan.md") {
+		t.Errorf("Overlaps = %+v, want plan.md from base", overlaps)
+	}
+
+	lock := readLock()
+	if got := strings.Join(lock.TemplateIDs(), ","); got != "base,layer" {
+		t.Fatalf("Lock templates = %s, want base,layer", got)
+	}
+	if recorded(lock.Find("base"), "plan.md") || !recorded(lock.Find("layer"), "plan.md") {
+		t.Error("Expected plan.md to be recorded only for the layer")
+	}
+	if got := sharedWith(lock.Find("layer"), "plan.md"); got != "base" {
+		t.Errorf("plan.md shared with %q, want base", got)
+	}
+
+	// Re-applying the base keeps the later template's files and drops its own stale ones
+	if err := os.Remove(filepath.Join(baseDir, commands, "base-only.md")); err != nil {
+		t.Fatalf("Failed to remove base-only.md: %v", err)
+	}
+	install("base", true)
+	if got := content("plan.md"); got != "layer\n" {
+		t.Errorf("plan.md after re-applying base = %q, want the layer's copy", got)
+	}
+	if content("base-only.md") != "" {
+		t.Error("Expected the file the base no longer ships to be removed")
+	}
+	if len(overlaps) != 1 || overlaps[0].TemplateID != "layer" || !overlaps[0].Shadowed {
+		t.Errorf("Overlaps after re-applying base = %+v, want plan.md kept from layer", overlaps)
+	}
+	lock = readLock()
+	if got := strings.Join(lock.TemplateIDs(), ","); got != "base,layer" {
+		t.Errorf("Lock templates after re-applying base = %s, want base,layer", got)
+	}
+	if got := sharedWith(lock.Find("layer"), "plan.md"); got != "base" {
+		t.Errorf("plan.md shared with %q after re-applying base, want base", got)
+	}
+
+	// A full reinstall records only the template it installs
+	install("base", false)
+	if got := strings.Join(readLock().TemplateIDs(), ","); got != "base" {
+		t.Errorf("Lock templates after reinstalling = %s, want base", got)
+	}
+}
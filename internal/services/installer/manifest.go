@@ -0,0 +1,96 @@
+package installer
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// buildManifest records every file under the installed roots with its content
+// hash, along with the framework symlinks. Entries from the previous manifest
+// outside the roots are kept while their files still exist, so a core update
+// remembers the rest of the original installation. Files in kept carry local
+// edits over from before the install and keep their previous entries.
+func buildManifest(targetDir string, roots []string, previous []state.FileRecord, kept map[string]bool) ([]state.FileRecord, error) {
+	records := make(map[string]state.FileRecord)
+
+	for _, record := range previous {
+		if record.IsLink() || (withinSubtrees(filepath.FromSlash(record.Path), roots) && !kept[record.Path]) {
+			continue // Recorded afresh below
+		}
+		if _, err := os.Lstat(filepath.Join(targetDir, filepath.FromSlash(record.Path))); err != nil {
+			continue
+		}
+		records[record.Path] = record
+	}
+
+	for _, root := range roots {
+		rootPath := filepath.Join(targetDir, root)
+		err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(targetDir, path)
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if rel == keptGitPath() {
+					return filepath.SkipDir // History kept by --keep-git is not framework content
+				}
+				return nil
+			}
+			if isManifestExcluded(rel) {
+				return nil
+			}
+
+			record := state.FileRecord{Path: filepath.ToSlash(rel)}
+			if kept[record.Path] {
+				return nil
+			}
+			if d.Type()&os.ModeSymlink != 0 {
+				if record.Link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			} else if record.SHA256, err = state.HashFile(path); err != nil {
+				return err
+			}
+			records[record.Path] = record
+			return nil
+		})
+		if err != nil {
+			return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, rootPath, err)
+		}
+	}
+
+	// Framework symlinks in .claude and .codex, when they point where expected
+	for rel, target := range requiredLinkTargets() {
+		if existing, err := os.Readlink(filepath.Join(targetDir, rel)); err == nil && existing == target {
+			records[filepath.ToSlash(rel)] = state.FileRecord{Path: filepath.ToSlash(rel), Link: target}
+		}
+	}
+
+	manifest := make([]state.FileRecord, 0, len(records))
+	for _, record := range records {
+		manifest = append(manifest, record)
+	}
+	sort.Slice(manifest, func(i, j int) bool {
+		return manifest[i].Path < manifest[j].Path
+	})
+	return manifest, nil
+}
+
+// isManifestExcluded reports whether a target-relative path is installer
+// metadata, which is rewritten on every install rather than tracked
+func isManifestExcluded(rel string) bool {
+	dir, name := filepath.Split(rel)
+	if filepath.Clean(dir) != config.StrategicClaudeBasicDir {
+		return false
+	}
+	return isInstallMetadata(name) || name == config.LockFileName+".tmp"
+}
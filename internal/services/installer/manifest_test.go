@@ -0,0 +1,76 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_RecordsManifest(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	targetDir := t.TempDir()
+	err := New().Install(models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+
+	records := make(map[string]state.FileRecord)
+	for _, record := range lock.Templates[0].Files {
+		records[record.Path] = record
+	}
+
+	readme := config.StrategicClaudeBasicDir + "/" + config.CoreDir + "/README.md"
+	record, ok := records[readme]
+	if !ok {
+		t.Fatalf("Expected %s in the manifest, got %v", readme, lock.Templates[0].Files)
+	}
+	if modified, err := record.Modified(filepath.Join(targetDir, filepath.FromSlash(readme))); err != nil || modified {
+		t.Errorf("Modified() for a fresh install = %v, %v; want false", modified, err)
+	}
+
+	for _, name := range []string{config.LockFileName, config.TemplateInfoFile} {
+		if _, ok := records[config.StrategicClaudeBasicDir+"/"+name]; ok {
+			t.Errorf("Expected installer metadata %s to be left out of the manifest", name)
+		}
+	}
+
+	links := 0
+	for _, record := range lock.Templates[0].Files {
+		if record.IsLink() {
+			links++
+		}
+	}
+	if links == 0 {
+		t.Errorf("Expected the framework symlinks in the manifest, got %v", lock.Templates[0].Files)
+	}
+
+	if err := os.WriteFile(filepath.Join(targetDir, filepath.FromSlash(readme)), []byte("# Edited\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit README: %v", err)
+	}
+	if modified, err := record.Modified(filepath.Join(targetDir, filepath.FromSlash(readme))); err != nil || !modified {
+		t.Errorf("Modified() after an edit = %v, %v; want true", modified, err)
+	}
+}
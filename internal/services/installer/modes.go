@@ -0,0 +1,44 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
+)
+
+// applyTreeModes makes the staged files that the checkout's git tree records as
+// executable executable, so hook scripts still run after install. Staged files
+// already carry their mode from disk, but a checkout does not always have the
+// bit (core.fileMode off, or a filesystem without it), and the tree is what the
+// template author committed.
+func (s *Service) applyTreeModes(tx *filesystem.Transaction, sourceDir string) error {
+	executable, err := s.gitService.ExecutableFiles(sourceDir)
+	if err != nil {
+		return fmt.Errorf("failed to read file modes: %w", err)
+	}
+
+	for path := range executable {
+		stagedPath := tx.StagedPath(filepath.FromSlash(path))
+		info, err := os.Lstat(stagedPath)
+		if os.IsNotExist(err) {
+			continue // Not installed: outside the framework directory, or excluded
+		}
+		if err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedPath, err)
+		}
+		if !info.Mode().IsRegular() || info.Mode()&0111 != 0 {
+			continue
+		}
+
+		// Whoever can read the file may execute it, as git does for 100755
+		perm := info.Mode().Perm()
+		if err := os.Chmod(stagedPath, perm|(perm&0444)>>2); err != nil {
+			return models.NewFileSystemError(models.ErrorCodePermissionDenied, stagedPath, err)
+		}
+	}
+
+	return nil
+}
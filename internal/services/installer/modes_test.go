@@ -0,0 +1,117 @@
+package installer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+var hookScript = filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.HooksDir, "notify.sh")
+
+func TestInstall_PreservesExecutableBit(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	tests := []struct {
+		name string
+		git  bool
+	}{
+		{name: "local directory"},
+		{name: "git repository", git: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceDir := createLocalTemplate(t)
+			if err := os.WriteFile(filepath.Join(sourceDir, hookScript), []byte("#!/bin/sh\necho done\n"), 0755); err != nil {
+				t.Fatalf("Failed to write hook script: %v", err)
+			}
+
+			template := templates.Template{ID: "hooks", Name: "Hooks", RepoURL: sourceDir}
+			if tt.git {
+				keepEmptyDirs(t, sourceDir)
+				template.Branch = "main"
+				template.Commit = initGitTemplate(t, sourceDir)
+			}
+			templates.Registry.Set(map[string]templates.Template{"hooks": template})
+
+			targetDir := t.TempDir()
+			err := New().Install(models.InstallConfig{
+				TargetDir:     targetDir,
+				TemplateID:    "hooks",
+				SkipConfirm:   true,
+				NoBackup:      true,
+				GitignoreMode: "track",
+			})
+			if err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+
+			info, err := os.Stat(filepath.Join(targetDir, hookScript))
+			if err != nil {
+				t.Fatalf("Failed to stat installed hook script: %v", err)
+			}
+			if info.Mode().Perm()&0111 == 0 {
+				t.Errorf("Installed hook script mode = %v, want executable", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestApplyTreeModes(t *testing.T) {
+	sourceDir := createLocalTemplate(t)
+	readme := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(filepath.Join(sourceDir, hookScript), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatalf("Failed to write hook script: %v", err)
+	}
+	initGitTemplate(t, sourceDir)
+
+	// Record the script as executable in the tree only, as a checkout with
+	// core.fileMode off would
+	for _, args := range [][]string{
+		{"update-index", "--chmod=+x", filepath.ToSlash(hookScript)},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "make hook executable"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = sourceDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	service := New()
+	tx, err := service.filesystemService.BeginTransaction(t.TempDir())
+	if err != nil {
+		t.Fatalf("BeginTransaction() error = %v", err)
+	}
+	t.Cleanup(func() { _ = tx.Rollback() })
+	if err := tx.StageDirectory(filepath.Join(sourceDir, config.StrategicClaudeBasicDir), config.StrategicClaudeBasicDir); err != nil {
+		t.Fatalf("StageDirectory() error = %v", err)
+	}
+
+	if err := service.applyTreeModes(tx, sourceDir); err != nil {
+		t.Fatalf("applyTreeModes() error = %v", err)
+	}
+
+	tests := []struct {
+		path           string
+		wantExecutable bool
+	}{
+		{path: hookScript, wantExecutable: true},
+		{path: readme, wantExecutable: false},
+	}
+	for _, tt := range tests {
+		info, err := os.Stat(tx.StagedPath(tt.path))
+		if err != nil {
+			t.Fatalf("Failed to stat staged %s: %v", tt.path, err)
+		}
+		if got := info.Mode().Perm()&0111 != 0; got != tt.wantExecutable {
+			t.Errorf("%s mode = %v, want executable %v", tt.path, info.Mode().Perm(), tt.wantExecutable)
+		}
+	}
+}
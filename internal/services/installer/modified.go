@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// reconcileModified compares each recorded file the staged template would
+// replace with its hash from the last install. Files edited since then are
+// copied into the staging area so the edits survive the update, unless
+// OverwriteModified is set; an edited file whose template copy also changed is
+// a conflict. Every edited file is reported through OnModifiedFile.
+//
+// It returns the paths whose edits were kept. Their previous manifest entries
+// must stay in place so later updates still see them as edited.
+func reconcileModified(tx *filesystem.Transaction, targetDir string, previous []state.FileRecord, installConfig models.InstallConfig) (map[string]bool, error) {
+	kept := make(map[string]bool)
+	roots := tx.Staged()
+
+	for _, record := range previous {
+		rel := filepath.FromSlash(record.Path)
+		if record.IsLink() || !withinSubtrees(rel, roots) {
+			continue
+		}
+
+		localPath := filepath.Join(targetDir, rel)
+		info, err := os.Lstat(localPath)
+		if err != nil || !info.Mode().IsRegular() {
+			continue // Deleted or replaced by something other than a file; the template's copy wins
+		}
+		modified, err := record.Modified(localPath)
+		if err != nil {
+			return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, localPath, err)
+		}
+		if !modified {
+			continue
+		}
+
+		local, err := os.ReadFile(localPath)
+		if err != nil {
+			return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, localPath, err)
+		}
+
+		stagedPath := tx.StagedPath(rel)
+		upstream, err := os.ReadFile(stagedPath)
+		removed := os.IsNotExist(err)
+		if err != nil && !removed {
+			return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedPath, err)
+		}
+		if !removed && bytes.Equal(local, upstream) {
+			continue // The template made the same change
+		}
+
+		file := models.ModifiedFile{
+			Path:            record.Path,
+			UpstreamRemoved: removed,
+			Local:           local,
+			Upstream:        upstream,
+		}
+		if !removed {
+			upstreamHash, err := state.HashFile(stagedPath)
+			if err != nil {
+				return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedPath, err)
+			}
+			file.Conflict = upstreamHash != record.SHA256
+		} else {
+			file.Conflict = true
+		}
+
+		if installConfig.OverwriteModified {
+			file.Overwritten = true
+		} else {
+			if err := os.MkdirAll(filepath.Dir(stagedPath), 0755); err != nil {
+				return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedPath, err)
+			}
+			if err := os.WriteFile(stagedPath, local, info.Mode().Perm()); err != nil {
+				return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, stagedPath, err)
+			}
+			kept[record.Path] = true
+		}
+
+		if installConfig.OnModifiedFile != nil {
+			installConfig.OnModifiedFile(file)
+		}
+	}
+
+	return kept, nil
+}
@@ -0,0 +1,123 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_CoreUpdateKeepsLocalEdits(t *testing.T) {
+	readme := config.StrategicClaudeBasicDir + "/" + config.CoreDir + "/README.md"
+	plan := config.StrategicClaudeBasicDir + "/" + config.CoreDir + "/" + config.CommandsDir + "/plan.md"
+
+	tests := []struct {
+		name       string
+		overwrite  bool
+		wantReadme string
+		wantPlan   string
+	}{
+		{
+			name:       "keeps edits by default",
+			wantReadme: "# Core, edited\n",
+			wantPlan:   "# Plan, edited\n",
+		},
+		{
+			name:       "overwrite applies the template",
+			overwrite:  true,
+			wantReadme: "# Core\n",
+			wantPlan:   "# Plan v2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := templates.Registry.Snapshot()
+			t.Cleanup(func() { templates.Registry.Set(original) })
+
+			sourceDir := createLocalTemplate(t)
+			templates.Registry.Set(map[string]templates.Template{
+				"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+			})
+			writeFile := func(dir, rel, content string) {
+				if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(rel)), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", rel, err)
+				}
+			}
+			writeFile(sourceDir, plan, "# Plan\n")
+
+			targetDir := t.TempDir()
+			installConfig := models.InstallConfig{
+				TargetDir:     targetDir,
+				TemplateID:    "local",
+				SkipConfirm:   true,
+				NoBackup:      true,
+				GitignoreMode: "track",
+			}
+			if err := New().Install(installConfig); err != nil {
+				t.Fatalf("Initial Install() error = %v", err)
+			}
+			before, err := state.ReadLock(targetDir)
+			if err != nil {
+				t.Fatalf("ReadLock() error = %v", err)
+			}
+
+			// Edit both files locally; only plan.md changes in the template
+			writeFile(targetDir, readme, "# Core, edited\n")
+			writeFile(targetDir, plan, "# Plan, edited\n")
+			writeFile(sourceDir, plan, "# Plan v2\n")
+
+			var modified []models.ModifiedFile
+			installConfig.ForceCore = true
+			installConfig.OverwriteModified = tt.overwrite
+			installConfig.OnModifiedFile = func(file models.ModifiedFile) {
+				modified = append(modified, file)
+			}
+			if err := New().Install(installConfig); err != nil {
+				t.Fatalf("Core update Install() error = %v", err)
+			}
+
+			for rel, want := range map[string]string{readme: tt.wantReadme, plan: tt.wantPlan} {
+				content, err := os.ReadFile(filepath.Join(targetDir, filepath.FromSlash(rel)))
+				if err != nil || string(content) != want {
+					t.Errorf("%s = %q (%v), want %q", rel, content, err, want)
+				}
+			}
+
+			reported := make(map[string]models.ModifiedFile)
+			for _, file := range modified {
+				reported[file.Path] = file
+			}
+			if len(reported) != 2 {
+				t.Fatalf("Reported modified files = %v, want README and plan", modified)
+			}
+			if reported[readme].Conflict || !reported[plan].Conflict {
+				t.Errorf("Conflict flags README = %v, plan = %v; want false, true", reported[readme].Conflict, reported[plan].Conflict)
+			}
+			if string(reported[plan].Upstream) != "# Plan v2\n" || reported[plan].Overwritten != tt.overwrite {
+				t.Errorf("plan report = %+v", reported[plan])
+			}
+
+			// Kept files keep their original hash so the next update sees them as edited again
+			after, err := state.ReadLock(targetDir)
+			if err != nil {
+				t.Fatalf("ReadLock() error = %v", err)
+			}
+			hashes := func(lock *state.Lock) map[string]string {
+				m := make(map[string]string)
+				for _, record := range lock.Templates[0].Files {
+					m[record.Path] = record.SHA256
+				}
+				return m
+			}
+			beforeHashes, afterHashes := hashes(before), hashes(after)
+			if keptBase := afterHashes[plan] == beforeHashes[plan]; keptBase == tt.overwrite {
+				t.Errorf("plan.md manifest hash kept = %v, want %v", keptBase, !tt.overwrite)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_NoLock(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	tests := []struct {
+		name     string
+		existing bool // Installed with a lock file first
+		update   bool // Install with --force-core instead of from scratch
+	}{
+		{name: "new installation"},
+		{name: "over a managed installation", existing: true, update: true},
+		{name: "reinstalling a managed installation", existing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetDir := t.TempDir()
+			installConfig := models.InstallConfig{
+				TargetDir:     targetDir,
+				TemplateID:    "local",
+				SkipConfirm:   true,
+				NoBackup:      true,
+				GitignoreMode: "track",
+			}
+			if tt.existing {
+				if err := New().Install(installConfig); err != nil {
+					t.Fatalf("Initial Install() error = %v", err)
+				}
+				installConfig.ForceCore = tt.update
+				installConfig.Force = !tt.update
+			}
+
+			installConfig.NoLock = true
+			if err := New().Install(installConfig); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+
+			readme := filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+			if _, err := os.Stat(readme); err != nil {
+				t.Errorf("framework file not installed: %v", err)
+			}
+			lock, err := state.ReadLock(targetDir)
+			if err != nil {
+				t.Fatalf("ReadLock() error = %v", err)
+			}
+			if lock != nil {
+				t.Errorf("ReadLock() = %+v, want no lock file", lock)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// PrefetchResult reports how fetching one template's source went
+type PrefetchResult struct {
+	TemplateID string
+	Duration   time.Duration
+	Err        error
+}
+
+// Prefetch fetches the sources of several templates concurrently, running at
+// most jobs fetches at a time, so installing them one after another does not
+// wait on each clone in turn. Later calls to Install for these templates use
+// the fetched sources until Release is called.
+//
+// Results are returned in the order of templateList. Every failure is
+// reported, joined into the returned error, rather than just the first.
+func (s *Service) Prefetch(templateList []templates.Template, installConfig models.InstallConfig, jobs int) ([]PrefetchResult, error) {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	results := make([]PrefetchResult, len(templateList))
+	sources := make([]*templateSource, len(templateList))
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(jobs, len(templateList)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				start := time.Now()
+				source, err := s.prepareSource(templateList[i], installConfig)
+				results[i] = PrefetchResult{TemplateID: templateList[i].ID, Duration: time.Since(start), Err: err}
+				sources[i] = source
+			}
+		}()
+	}
+	for i := range templateList {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	s.prefetchMu.Lock()
+	defer s.prefetchMu.Unlock()
+	if s.prefetched == nil {
+		s.prefetched = make(map[string]*templateSource)
+	}
+
+	var errs []error
+	for i, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("template '%s': %w", result.TemplateID, result.Err))
+			continue
+		}
+		s.prefetched[result.TemplateID] = sources[i]
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// Release cleans up the sources fetched by Prefetch
+func (s *Service) Release() error {
+	s.prefetchMu.Lock()
+	defer s.prefetchMu.Unlock()
+
+	var errs []error
+	for id, source := range s.prefetched {
+		if err := source.Cleanup(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(s.prefetched, id)
+	}
+	return errors.Join(errs...)
+}
+
+// prefetchedSource returns the source Prefetch fetched for a template, if any.
+// The caller must not clean it up; Release does.
+func (s *Service) prefetchedSource(templateID string) (*templateSource, bool) {
+	s.prefetchMu.Lock()
+	defer s.prefetchMu.Unlock()
+
+	source, ok := s.prefetched[templateID]
+	if !ok {
+		return nil, false
+	}
+	return &templateSource{Dir: source.Dir, Commit: source.Commit, partial: source.partial, submodules: source.submodules}, true
+}
@@ -0,0 +1,65 @@
+package installer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestPrefetch(t *testing.T) {
+	firstDir := createLocalTemplate(t)
+	secondDir := createLocalTemplate(t)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	templateList := []templates.Template{
+		{ID: "first", Name: "First", RepoURL: firstDir},
+		{ID: "broken", Name: "Broken", RepoURL: missing},
+		{ID: "second", Name: "Second", RepoURL: secondDir},
+		{ID: "also-broken", Name: "Also broken", RepoURL: missing + "-too"},
+	}
+
+	service := New()
+	results, err := service.Prefetch(templateList, models.InstallConfig{}, 2)
+	t.Cleanup(func() { _ = service.Release() })
+
+	if err == nil {
+		t.Fatal("Expected Prefetch() to report the failed templates")
+	}
+	for _, id := range []string{"'broken'", "'also-broken'"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("Prefetch() error = %v, want it to mention %s", err, id)
+		}
+	}
+
+	if len(results) != len(templateList) {
+		t.Fatalf("Prefetch() returned %d results, want %d", len(results), len(templateList))
+	}
+	for i, result := range results {
+		if result.TemplateID != templateList[i].ID {
+			t.Errorf("results[%d] = %s, want %s (input order)", i, result.TemplateID, templateList[i].ID)
+		}
+		wantErr := strings.Contains(result.TemplateID, "broken")
+		if (result.Err != nil) != wantErr {
+			t.Errorf("results[%d].Err = %v, want error %v", i, result.Err, wantErr)
+		}
+	}
+
+	// Successful fetches are reused until released
+	source, ok := service.prefetchedSource("second")
+	if !ok || source.Dir != secondDir {
+		t.Errorf("prefetchedSource(second) = %v, %v; want %s", source, ok, secondDir)
+	}
+	if _, ok := service.prefetchedSource("broken"); ok {
+		t.Error("Expected no prefetched source for a failed template")
+	}
+
+	if err := service.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, ok := service.prefetchedSource("first"); ok {
+		t.Error("Expected Release() to drop prefetched sources")
+	}
+}
@@ -0,0 +1,159 @@
+package installer
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// PreviewFiles fetches the template into a temporary location and records in the
+// plan, file by file, what the installation would create, overwrite, skip, or
+// remove. Nothing in the target directory is modified.
+func (s *Service) PreviewFiles(installConfig models.InstallConfig, plan *models.InstallationPlan) error {
+	template, err := installConfig.GetTemplate()
+	if err != nil {
+		return fmt.Errorf("failed to get template configuration: %w", err)
+	}
+
+	source, err := s.prepareSource(template, installConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cleanupErr := source.Cleanup(); cleanupErr != nil {
+			slog.Warn("Failed to cleanup temporary directory", "error", cleanupErr)
+		}
+	}()
+
+	plan.HasPreInstallScript = s.scriptService.ScriptExists(source.Dir, config.PreInstallScript)
+	plan.HasPostInstallScript = s.scriptService.ScriptExists(source.Dir, config.PostInstallScript)
+
+	sourceFiles, err := listFiles(filepath.Join(source.Dir, config.StrategicClaudeBasicDir))
+	if err != nil {
+		return models.NewAppError(
+			models.ErrorCodeFileSystemError,
+			"Failed to read template files",
+			err,
+		)
+	}
+
+	targetFiles, err := listFiles(filepath.Join(plan.TargetDir, config.StrategicClaudeBasicDir))
+	if err != nil {
+		return models.NewAppError(
+			models.ErrorCodeFileSystemError,
+			"Failed to read existing installation files",
+			err,
+		)
+	}
+
+	filter, err := installFilter(template, installConfig)
+	if err != nil {
+		return err
+	}
+
+	subtrees, err := onlySubtrees(source.Dir, installConfig.OnlyPaths)
+	if err != nil {
+		return err
+	}
+
+	// replaced reports whether an existing file goes away because its directory is replaced wholesale
+	replaced := func(rel string) bool {
+		if len(subtrees) > 0 {
+			return withinSubtrees(filepath.Join(config.StrategicClaudeBasicDir, rel), subtrees)
+		}
+		return plan.InstallationType == models.InstallationTypeOverwrite ||
+			(plan.InstallationType == models.InstallationTypeUpdate && config.IsCoreFile(filepath.ToSlash(rel)))
+	}
+
+	plan.FileChanges = nil
+	installed := make(map[string]struct{}, len(sourceFiles))
+	targetSet := toSet(targetFiles)
+
+	for _, rel := range sourceFiles {
+		path := filepath.Join(config.StrategicClaudeBasicDir, rel)
+		_, exists := targetSet[rel]
+
+		if filter.Skip(path, false) {
+			// Excluded files are not installed; existing copies in a replaced directory are removed below
+			if !exists || !replaced(rel) {
+				plan.AddFileChange(path, models.FileActionSkip)
+			}
+			continue
+		}
+
+		// Partial installs copy only the selected subtrees, and core updates only framework directories
+		copied := true
+		switch {
+		case len(subtrees) > 0:
+			copied = withinSubtrees(path, subtrees)
+		case plan.InstallationType == models.InstallationTypeUpdate:
+			copied = config.IsCoreFile(filepath.ToSlash(rel))
+		}
+
+		switch {
+		case !copied:
+			plan.AddFileChange(path, models.FileActionSkip)
+		case exists:
+			plan.AddFileChange(path, models.FileActionOverwrite)
+		default:
+			plan.AddFileChange(path, models.FileActionCreate)
+		}
+		installed[rel] = struct{}{}
+	}
+
+	for _, rel := range targetFiles {
+		if _, inSource := installed[rel]; inSource || isInstallMetadata(rel) {
+			continue
+		}
+		if replaced(rel) {
+			plan.AddFileChange(filepath.Join(config.StrategicClaudeBasicDir, rel), models.FileActionRemove)
+		}
+	}
+
+	return nil
+}
+
+// listFiles returns the files under root (everything but directories), relative
+// to root and in lexical order. A missing root yields an empty list.
+func listFiles(root string) ([]string, error) {
+	var files []string
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return files, nil
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	return files, err
+}
+
+// isInstallMetadata reports whether a path is written by the installer itself
+// rather than copied from the template
+func isInstallMetadata(rel string) bool {
+	return rel == config.TemplateInfoFile || rel == config.LockFileName
+}
+
+// toSet indexes a list of paths for membership checks
+func toSet(paths []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		set[path] = struct{}{}
+	}
+	return set
+}
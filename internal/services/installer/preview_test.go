@@ -0,0 +1,114 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestPreviewFiles(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	// The template also ships a user-directory file that core updates leave alone
+	planReadme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.PlanDir, "CLAUDE.md")
+	if err := os.MkdirAll(filepath.Dir(planReadme), 0755); err != nil {
+		t.Fatalf("Failed to create plan directory: %v", err)
+	}
+	if err := os.WriteFile(planReadme, []byte("# Plans\n"), 0644); err != nil {
+		t.Fatalf("Failed to write plan file: %v", err)
+	}
+
+	strategic := config.StrategicClaudeBasicDir
+	coreReadme := filepath.Join(strategic, config.CoreDir, "README.md")
+	staleCore := filepath.Join(strategic, config.CoreDir, "old.md")
+	userPlan := filepath.Join(strategic, config.PlanDir, "CLAUDE.md")
+
+	// installExisting creates a target with an installation that has a stale core
+	// file and an edited user file
+	installExisting := func(t *testing.T) string {
+		targetDir := t.TempDir()
+		for _, rel := range []string{coreReadme, staleCore, userPlan, filepath.Join(strategic, config.LockFileName)} {
+			path := filepath.Join(targetDir, rel)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(path, []byte("existing\n"), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+		}
+		return targetDir
+	}
+
+	tests := []struct {
+		name        string
+		installType models.InstallationType
+		existing    bool
+		want        []models.FileChange
+	}{
+		{
+			name:        "new installation creates every file",
+			installType: models.InstallationTypeNew,
+			want: []models.FileChange{
+				{Path: coreReadme, Action: models.FileActionCreate},
+				{Path: userPlan, Action: models.FileActionCreate},
+			},
+		},
+		{
+			name:        "core update touches only framework files",
+			installType: models.InstallationTypeUpdate,
+			existing:    true,
+			want: []models.FileChange{
+				{Path: coreReadme, Action: models.FileActionOverwrite},
+				{Path: userPlan, Action: models.FileActionSkip},
+				{Path: staleCore, Action: models.FileActionRemove},
+			},
+		},
+		{
+			name:        "overwrite replaces everything",
+			installType: models.InstallationTypeOverwrite,
+			existing:    true,
+			want: []models.FileChange{
+				{Path: coreReadme, Action: models.FileActionOverwrite},
+				{Path: userPlan, Action: models.FileActionOverwrite},
+				{Path: staleCore, Action: models.FileActionRemove},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetDir := t.TempDir()
+			if tt.existing {
+				targetDir = installExisting(t)
+			}
+
+			plan := models.NewInstallationPlan(targetDir, tt.installType, templates.Registry.Snapshot()["local"])
+			installConfig := models.InstallConfig{TargetDir: targetDir, TemplateID: "local"}
+			if err := New().PreviewFiles(installConfig, plan); err != nil {
+				t.Fatalf("PreviewFiles() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(plan.FileChanges, tt.want) {
+				t.Errorf("FileChanges = %v, want %v", plan.FileChanges, tt.want)
+			}
+
+			// A preview never writes to the target
+			if !tt.existing {
+				if _, err := os.Stat(filepath.Join(targetDir, strategic)); !os.IsNotExist(err) {
+					t.Errorf("Expected target to be untouched, stat error = %v", err)
+				}
+			}
+		})
+	}
+}
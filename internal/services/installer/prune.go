@@ -0,0 +1,57 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// reconcileRemoved finds the recorded files a core update leaves in place that
+// the template no longer ships. The staged directories replace their old
+// copies wholesale, so only files outside them, in the installed subtrees or
+// the framework directory, can outlive the template. With Prune set, those not
+// edited since the last install are staged for removal; the rest are kept.
+// Every one is reported through OnRemovedFile.
+func reconcileRemoved(tx *filesystem.Transaction, sourceDir, targetDir string, previous []state.FileRecord, filter fileFilter, subtrees []string, installConfig models.InstallConfig) error {
+	scope := subtrees
+	if len(scope) == 0 {
+		scope = []string{config.StrategicClaudeBasicDir}
+	}
+	staged := tx.Staged()
+
+	for _, record := range previous {
+		rel := filepath.FromSlash(record.Path)
+		if record.IsLink() || !withinSubtrees(rel, scope) || withinSubtrees(rel, staged) {
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(sourceDir, rel)); err == nil && !filter.Skip(rel, false) {
+			continue // Still shipped
+		}
+
+		localPath := filepath.Join(targetDir, rel)
+		modified, err := record.Modified(localPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, localPath, err)
+		}
+
+		file := models.RemovedFile{Path: record.Path, Modified: modified}
+		if installConfig.Prune && !modified {
+			if err := tx.StageRemoval(rel); err != nil {
+				return err
+			}
+			file.Pruned = true
+		}
+		if installConfig.OnRemovedFile != nil {
+			installConfig.OnRemovedFile(file)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_CoreUpdateRemovedUpstream(t *testing.T) {
+	guide := config.StrategicClaudeBasicDir + "/guides/old.md"
+	edited := config.StrategicClaudeBasicDir + "/guides/edited.md"
+	seed := config.StrategicClaudeBasicDir + "/" + config.PlanDir + "/README.md"
+	kept := config.StrategicClaudeBasicDir + "/guides/kept.md"
+
+	tests := []struct {
+		name        string
+		prune       bool
+		wantPresent map[string]bool
+	}{
+		{
+			name:        "reports and keeps by default",
+			wantPresent: map[string]bool{guide: true, edited: true, seed: true, kept: true},
+		},
+		{
+			name:        "prune deletes unedited files",
+			prune:       true,
+			wantPresent: map[string]bool{guide: false, edited: true, seed: false, kept: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := templates.Registry.Snapshot()
+			t.Cleanup(func() { templates.Registry.Set(original) })
+
+			sourceDir := createLocalTemplate(t)
+			templates.Registry.Set(map[string]templates.Template{
+				"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+			})
+			writeFile := func(dir, rel, content string) {
+				path := filepath.Join(dir, filepath.FromSlash(rel))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", rel, err)
+				}
+			}
+			for _, rel := range []string{guide, edited, seed, kept} {
+				writeFile(sourceDir, rel, "# "+rel+"\n")
+			}
+
+			targetDir := t.TempDir()
+			installConfig := models.InstallConfig{
+				TargetDir:     targetDir,
+				TemplateID:    "local",
+				SkipConfirm:   true,
+				NoBackup:      true,
+				GitignoreMode: "track",
+			}
+			if err := New().Install(installConfig); err != nil {
+				t.Fatalf("Initial Install() error = %v", err)
+			}
+
+			// The template drops all but kept.md, and one dropped file was edited
+			for _, rel := range []string{guide, edited, seed} {
+				if err := os.Remove(filepath.Join(sourceDir, filepath.FromSlash(rel))); err != nil {
+					t.Fatalf("Failed to remove %s from the template: %v", rel, err)
+				}
+			}
+			writeFile(targetDir, edited, "# Mine\n")
+
+			removed := make(map[string]models.RemovedFile)
+			installConfig.ForceCore = true
+			installConfig.Prune = tt.prune
+			installConfig.OnRemovedFile = func(file models.RemovedFile) {
+				removed[file.Path] = file
+			}
+			if err := New().Install(installConfig); err != nil {
+				t.Fatalf("Core update Install() error = %v", err)
+			}
+
+			if len(removed) != 3 || !removed[edited].Modified || removed[edited].Pruned ||
+				removed[guide].Pruned != tt.prune || removed[seed].Pruned != tt.prune {
+				t.Errorf("Reported removed files = %v", removed)
+			}
+			for rel, want := range tt.wantPresent {
+				_, err := os.Stat(filepath.Join(targetDir, filepath.FromSlash(rel)))
+				if got := err == nil; got != want {
+					t.Errorf("%s present = %v, want %v", rel, got, want)
+				}
+			}
+
+			// Pruned files drop out of the manifest; kept ones stay recorded
+			lock, err := state.ReadLock(targetDir)
+			if err != nil {
+				t.Fatalf("ReadLock() error = %v", err)
+			}
+			recorded := make(map[string]bool)
+			for _, record := range lock.AllFiles() {
+				recorded[record.Path] = true
+			}
+			for rel, want := range tt.wantPresent {
+				if recorded[rel] != want {
+					t.Errorf("%s recorded = %v, want %v", rel, recorded[rel], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// checkNotInstalled refuses to install over an existing installation, found by
+// its lock file or its framework directory and symlinks, when the caller has
+// not said what to do with it: update it in place, layer over it, or start over
+func checkNotInstalled(targetDir string, status *models.StatusInfo, lock *state.Lock, lockErr error) error {
+	if !status.IsInstalled && lock == nil && lockErr == nil {
+		return nil
+	}
+
+	installed := ""
+	if lock != nil && len(lock.Templates) > 0 {
+		installed = fmt.Sprintf(" (templates: %s)", strings.Join(lock.TemplateIDs(), ", "))
+	}
+	return models.NewAppError(
+		models.ErrorCodeAlreadyInstalled,
+		fmt.Sprintf("Strategic Claude Basic is already installed in %s%s; run 'update' to move it to the template's current commit, or 'init --force' to back it up and reinstall from scratch",
+			targetDir, installed),
+		nil,
+	).WithContext("target_dir", targetDir)
+}
+
+// checkTargetEmpty refuses a new installation into a directory that already
+// holds files. A git directory and the version file pinning the template to
+// install do not count.
+func checkTargetEmpty(targetDir string) error {
+	entries, err := os.ReadDir(targetDir)
+	if err != nil {
+		return models.NewFileSystemError(models.ErrorCodeFileSystemError, targetDir, err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == ".git" || entry.Name() == config.VersionFileName {
+			continue
+		}
+		return models.NewAppError(
+			models.ErrorCodeDirectoryNotEmpty,
+			fmt.Sprintf("Target directory %s is not empty (found %s); use 'init --allow-non-empty' to install into it anyway", targetDir, entry.Name()),
+			nil,
+		).WithContext("target_dir", targetDir)
+	}
+	return nil
+}
+
+// staleRecordedFiles returns the files a previous installation recorded outside
+// the framework directory that are still there, such as files layered templates
+// placed in .claude. A reinstall from scratch replaces the framework directory as
+// a whole and removes these, so nothing of the old installation is left behind.
+func staleRecordedFiles(targetDir string, lock *state.Lock) []string {
+	if lock == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var stale []string
+	for _, record := range lock.AllFiles() {
+		rel := filepath.FromSlash(record.Path)
+		if record.IsLink() || seen[rel] || withinSubtrees(rel, []string{config.StrategicClaudeBasicDir}) {
+			continue // Symlinks are recreated; the framework directory is replaced
+		}
+		seen[rel] = true
+		if _, err := os.Lstat(filepath.Join(targetDir, rel)); err != nil {
+			continue
+		}
+		stale = append(stale, rel)
+	}
+	sort.Strings(stale)
+	return stale
+}
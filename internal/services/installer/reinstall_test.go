@@ -0,0 +1,142 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_RefusesExistingInstallation(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Initial Install() error = %v", err)
+	}
+
+	if _, err := New().AnalyzeInstallation(installConfig); !models.IsErrorCode(err, models.ErrorCodeAlreadyInstalled) {
+		t.Errorf("AnalyzeInstallation() over an installation error = %v, want %s", err, models.ErrorCodeAlreadyInstalled)
+	}
+	if err := New().Install(installConfig); !models.IsErrorCode(err, models.ErrorCodeAlreadyInstalled) {
+		t.Errorf("Install() over an installation error = %v, want %s", err, models.ErrorCodeAlreadyInstalled)
+	}
+
+	// A lock file alone marks the target as installed
+	lockOnly := t.TempDir()
+	if err := state.WriteLock(lockOnly, &state.Lock{Templates: []state.TemplateLock{{TemplateID: "local"}}}); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+	lockConfig := installConfig
+	lockConfig.TargetDir = lockOnly
+	if _, err := New().AnalyzeInstallation(lockConfig); !models.IsErrorCode(err, models.ErrorCodeAlreadyInstalled) {
+		t.Errorf("AnalyzeInstallation() over a lock file error = %v, want %s", err, models.ErrorCodeAlreadyInstalled)
+	}
+
+	for name, flags := range map[string]func(*models.InstallConfig){
+		"force":      func(c *models.InstallConfig) { c.Force = true },
+		"force-core": func(c *models.InstallConfig) { c.ForceCore = true },
+		"add":        func(c *models.InstallConfig) { c.Layer = true },
+	} {
+		allowed := installConfig
+		flags(&allowed)
+		if _, err := New().AnalyzeInstallation(allowed); err != nil {
+			t.Errorf("%s: AnalyzeInstallation() error = %v", name, err)
+		}
+	}
+}
+
+func TestInstall_ForceStartsOver(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Initial Install() error = %v", err)
+	}
+
+	// An earlier install recorded a file outside the framework directory
+	stale := filepath.Join("docs", "extra.md")
+	if err := os.MkdirAll(filepath.Join(targetDir, "docs"), 0755); err != nil {
+		t.Fatalf("Failed to create docs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetDir, stale), []byte("extra\n"), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", stale, err)
+	}
+	lock, err := state.ReadLock(targetDir)
+	if err != nil || lock == nil {
+		t.Fatalf("ReadLock() = %v, %v", lock, err)
+	}
+	entry := lock.Find("local")
+	entry.Files = append(entry.Files, state.FileRecord{Path: filepath.ToSlash(stale), SHA256: "0"})
+	entry.Ref = "old-branch"
+	if err := state.WriteLock(targetDir, lock); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+
+	installConfig.Force = true
+	installConfig.NoBackup = false
+	service := New()
+	plan, err := service.AnalyzeInstallation(installConfig)
+	if err != nil {
+		t.Fatalf("AnalyzeInstallation() error = %v", err)
+	}
+	if plan.InstallationType != models.InstallationTypeOverwrite || !plan.BackupRequired {
+		t.Fatalf("plan type = %s, backup = %v; want %s with a backup", plan.InstallationType, plan.BackupRequired, models.InstallationTypeOverwrite)
+	}
+	installConfig.BackupDir = plan.BackupDir
+	if err := service.Install(installConfig); err != nil {
+		t.Fatalf("Install() with Force error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(targetDir, stale)); !os.IsNotExist(err) {
+		t.Errorf("Expected %s from the previous installation to be removed", stale)
+	}
+	for _, rel := range []string{stale, filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md")} {
+		if _, err := os.Stat(filepath.Join(plan.BackupDir, rel)); err != nil {
+			t.Errorf("Expected %s in the backup: %v", rel, err)
+		}
+	}
+
+	lock, err = state.ReadLock(targetDir)
+	if err != nil || lock == nil {
+		t.Fatalf("ReadLock() after Force = %v, %v", lock, err)
+	}
+	if len(lock.Templates) != 1 || lock.Templates[0].Ref != "" {
+		t.Errorf("Expected a fresh lock entry for local only, got %+v", lock.Templates)
+	}
+	for _, record := range lock.AllFiles() {
+		if record.Path == filepath.ToSlash(stale) {
+			t.Errorf("Expected %s to be dropped from the lock", stale)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package installer
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/filesystem"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// renderVariables substitutes template variables in the staged files before they
+// are moved into place. Values come from the built-in defaults, then the
+// template's own defaults, then --set, then prompts for anything still missing
+// (or, with --prompt, for anything --set did not supply).
+// Environment variables in --set values are expanded here, once; defaults and
+// prompted values are used as given.
+func (s *Service) renderVariables(tx *filesystem.Transaction, template templates.Template, installConfig models.InstallConfig, targetDir string) error {
+	if len(installConfig.RenderPatterns) == 0 {
+		return nil
+	}
+
+	values, err := s.variableValues(template, installConfig, targetDir)
+	if err != nil {
+		return err
+	}
+
+	// Ask for variables the templates use but nobody supplied; with
+	// PromptVariables, also for those with a default, and for every variable
+	// the template declares
+	if installConfig.PromptVariable != nil {
+		var names []string
+		if installConfig.PromptVariables {
+			names = slices.Sorted(maps.Keys(template.Variables))
+		}
+		for _, rel := range renderRoots(tx) {
+			referenced, err := s.variablesService.Referenced(tx.StagedPath(rel), installConfig.RenderPatterns)
+			if err != nil {
+				return err
+			}
+			names = append(names, referenced...)
+		}
+
+		asked := make(map[string]bool)
+		for _, name := range names {
+			if asked[name] {
+				continue
+			}
+			asked[name] = true
+			if _, set := installConfig.Variables[name]; set {
+				continue
+			}
+			current, ok := values[name]
+			if ok && !installConfig.PromptVariables {
+				continue
+			}
+
+			variable := template.Variables[name]
+			variable.Default = current
+			value, err := installConfig.PromptVariable(name, variable)
+			if err != nil {
+				return fmt.Errorf("failed to read value for %s: %w", name, err)
+			}
+			if value != "" {
+				values[name] = value
+			}
+		}
+	}
+
+	var rendered, skipped, missing []string
+	for _, rel := range renderRoots(tx) {
+		result, err := s.variablesService.Render(tx.StagedPath(rel), installConfig.RenderPatterns, values, installConfig.StrictVariables)
+		if err != nil {
+			return err
+		}
+		for _, file := range result.Rendered {
+			rendered = append(rendered, filepath.Join(rel, file))
+		}
+		for _, file := range result.Skipped {
+			skipped = append(skipped, filepath.Join(rel, file))
+		}
+		missing = append(missing, result.Missing...)
+	}
+
+	if len(rendered) > 0 {
+		slog.Info(fmt.Sprintf("Rendered template variables in %d file(s)", len(rendered)), "files", strings.Join(rendered, ", "))
+	}
+	for _, file := range skipped {
+		slog.Warn(fmt.Sprintf("Left %s unrendered; its placeholders are not valid template syntax", file))
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		slog.Warn(fmt.Sprintf("No value for template variables %s; placeholders were left as written", strings.Join(slices.Compact(missing), ", ")))
+	}
+
+	return nil
+}
+
+// variableValues returns the built-in defaults for targetDir overridden by the
+// template's defaults and then the --set values, with the environment variables
+// --set values reference expanded. An unset one is left empty, or is an error
+// under --strict.
+func (s *Service) variableValues(template templates.Template, installConfig models.InstallConfig, targetDir string) (map[string]string, error) {
+	values := s.variablesService.Defaults(targetDir)
+	maps.Copy(values, template.VariableDefaults())
+	for _, name := range slices.Sorted(maps.Keys(installConfig.Variables)) {
+		value, unset := s.variablesService.ExpandEnv(installConfig.Variables[name], os.LookupEnv)
+		if len(unset) > 0 {
+			if installConfig.StrictVariables {
+				return nil, models.NewAppError(
+					models.ErrorCodeValidationFailed,
+					fmt.Sprintf("Environment variables used by template variable %s are not set: %s", name, strings.Join(unset, ", ")),
+					nil,
+				).WithContext("variable", name)
+			}
+			slog.Warn(fmt.Sprintf("Environment variables used by template variable %s are not set: %s; they were left empty", name, strings.Join(unset, ", ")))
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// renderRoots returns the staged directories to render. A layered install
+// stages single files, which are rendered together from the staged framework
+// directory so patterns see the same paths as in a full install.
+func renderRoots(tx *filesystem.Transaction) []string {
+	var roots []string
+	files := false
+	for _, rel := range tx.Staged() {
+		if info, err := os.Lstat(tx.StagedPath(rel)); err == nil && !info.IsDir() {
+			files = true
+			continue
+		}
+		roots = append(roots, rel)
+	}
+	if files {
+		roots = append(roots, config.StrategicClaudeBasicDir)
+	}
+	return roots
+}
@@ -0,0 +1,279 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_RendersVariables(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# {{.ProjectName}} by {{.Team}}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	targetDir := filepath.Join(t.TempDir(), "demo")
+	if err := os.Mkdir(targetDir, 0755); err != nil {
+		t.Fatalf("Failed to create target: %v", err)
+	}
+
+	var prompted []string
+	installConfig := models.InstallConfig{
+		TargetDir:      targetDir,
+		TemplateID:     "local",
+		SkipConfirm:    true,
+		GitignoreMode:  "track",
+		RenderPatterns: config.GetDefaultRenderPatterns(),
+		PromptVariable: func(name string, variable templates.Variable) (string, error) {
+			prompted = append(prompted, name)
+			return "platform", nil
+		},
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	installed, err := os.ReadFile(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read installed README: %v", err)
+	}
+	if string(installed) != "# demo by platform\n" {
+		t.Errorf("README.md = %q, want %q", string(installed), "# demo by platform\n")
+	}
+	if len(prompted) != 1 || prompted[0] != "Team" {
+		t.Errorf("Prompted for %v, want only Team", prompted)
+	}
+
+	// The template source itself is never rewritten
+	source, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatalf("Failed to read source README: %v", err)
+	}
+	if string(source) != "# {{.ProjectName}} by {{.Team}}\n" {
+		t.Errorf("Source README was modified: %q", string(source))
+	}
+}
+
+func TestInstall_TemplateVariableDefaults(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# {{.Team}} uses {{.Editor}}, {{.Owner}} owns it\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, Variables: map[string]templates.Variable{
+			"Team":   {Default: "platform"},
+			"Editor": {Default: "vim"},
+		}},
+	})
+
+	var prompted []string
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:      targetDir,
+		TemplateID:     "local",
+		SkipConfirm:    true,
+		GitignoreMode:  "track",
+		RenderPatterns: config.GetDefaultRenderPatterns(),
+		Variables:      map[string]string{"Editor": "emacs"},
+		PromptVariable: func(name string, variable templates.Variable) (string, error) {
+			prompted = append(prompted, name)
+			return "ops", nil
+		},
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	// --set overrides a template default, and only variables without either are prompted for
+	installed, err := os.ReadFile(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read installed README: %v", err)
+	}
+	want := "# platform uses emacs, ops owns it\n"
+	if string(installed) != want {
+		t.Errorf("README.md = %q, want %q", string(installed), want)
+	}
+	if len(prompted) != 1 || prompted[0] != "Owner" {
+		t.Errorf("Prompted for %v, want only Owner", prompted)
+	}
+
+	// Under --strict the defaults count as values
+	strictTarget := t.TempDir()
+	installConfig.TargetDir = strictTarget
+	installConfig.PromptVariable = nil
+	installConfig.StrictVariables = true
+	installConfig.Variables = map[string]string{"Owner": "ops"}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Strict Install() with defaults error = %v", err)
+	}
+}
+
+func TestInstall_PromptVariables(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# {{.ProjectName}}: {{.Team}} with {{.Editor}}, {{.Owner}}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, Variables: map[string]templates.Variable{
+			"Team":    {Default: "platform", Description: "Team that owns the project"},
+			"Editor":  {Default: "vim"},
+			"License": {Default: "MIT"},
+		}},
+	})
+
+	targetDir := filepath.Join(t.TempDir(), "demo")
+	if err := os.Mkdir(targetDir, 0755); err != nil {
+		t.Fatalf("Failed to create target: %v", err)
+	}
+
+	prompted := make(map[string]templates.Variable)
+	var order []string
+	installConfig := models.InstallConfig{
+		TargetDir:       targetDir,
+		TemplateID:      "local",
+		SkipConfirm:     true,
+		GitignoreMode:   "track",
+		RenderPatterns:  config.GetDefaultRenderPatterns(),
+		Variables:       map[string]string{"Editor": "emacs"},
+		PromptVariables: true,
+		PromptVariable: func(name string, variable templates.Variable) (string, error) {
+			prompted[name] = variable
+			order = append(order, name)
+			if name == "Owner" {
+				return "ops", nil
+			}
+			return variable.Default, nil
+		},
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	// Declared variables come first, then the ones only the files use; --set values are never asked for
+	if got := strings.Join(order, ","); got != "License,Team,Owner,ProjectName" {
+		t.Errorf("Prompted for %s, want License,Team,Owner,ProjectName", got)
+	}
+	if team := prompted["Team"]; team.Default != "platform" || team.Description != "Team that owns the project" {
+		t.Errorf("Team prompt = %+v, want its default and description", team)
+	}
+	if project := prompted["ProjectName"]; project.Default != "demo" {
+		t.Errorf("ProjectName prompt = %+v, want the built-in default", project)
+	}
+
+	installed, err := os.ReadFile(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read installed README: %v", err)
+	}
+	if want := "# demo: platform with emacs, ops\n"; string(installed) != want {
+		t.Errorf("README.md = %q, want %q", string(installed), want)
+	}
+}
+
+func TestInstall_StrictVariables(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("module {{.GoModule}}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:       targetDir,
+		TemplateID:      "local",
+		SkipConfirm:     true,
+		GitignoreMode:   "track",
+		RenderPatterns:  config.GetDefaultRenderPatterns(),
+		StrictVariables: true,
+	}
+	if err := New().Install(installConfig); err == nil {
+		t.Fatal("Expected strict install to fail with an unset variable")
+	}
+
+	// Nothing is moved into place when rendering fails
+	if _, err := os.Stat(filepath.Join(targetDir, config.StrategicClaudeBasicDir)); !os.IsNotExist(err) {
+		t.Errorf("Expected no installation after a strict failure, stat error = %v", err)
+	}
+}
+
+func TestInstall_ExpandsEnvironmentInVariables(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# {{.Team}} ({{.Module}}) costs {{.Price}}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	t.Setenv("SCB_TEST_TEAM", "platform")
+	t.Setenv("SCB_TEST_MODULE", "example.com/$SCB_TEST_TEAM")
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:      targetDir,
+		TemplateID:     "local",
+		SkipConfirm:    true,
+		GitignoreMode:  "track",
+		RenderPatterns: config.GetDefaultRenderPatterns(),
+		Variables: map[string]string{
+			"Team":   "team-${SCB_TEST_TEAM}",
+			"Module": "$SCB_TEST_MODULE",
+			"Price":  "$$5",
+		},
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	installed, err := os.ReadFile(filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read installed README: %v", err)
+	}
+	// An expanded value is not expanded a second time
+	want := "# team-platform (example.com/$SCB_TEST_TEAM) costs $5\n"
+	if string(installed) != want {
+		t.Errorf("README.md = %q, want %q", string(installed), want)
+	}
+
+	// Under --strict an unset environment variable fails the install
+	strictTarget := t.TempDir()
+	installConfig.TargetDir = strictTarget
+	installConfig.StrictVariables = true
+	installConfig.Variables = map[string]string{"Team": "$SCB_TEST_UNSET", "Module": "m", "Price": "p"}
+	err = New().Install(installConfig)
+	if !models.IsErrorCode(err, models.ErrorCodeValidationFailed) {
+		t.Fatalf("Install() with an unset environment variable error = %v, want %s", err, models.ErrorCodeValidationFailed)
+	}
+	if _, err := os.Stat(filepath.Join(strictTarget, config.StrategicClaudeBasicDir)); !os.IsNotExist(err) {
+		t.Errorf("Expected no installation after a strict failure, stat error = %v", err)
+	}
+}
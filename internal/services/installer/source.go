@@ -0,0 +1,486 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/progress"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// templateSource is a directory holding the template contents to install from
+type templateSource struct {
+	// Dir is the root of the template checkout
+	Dir string
+
+	// Commit is the commit that was checked out (empty for plain local directories)
+	Commit string
+
+	// cleanup removes the directory once installation is done (nil for sources
+	// that are read in place, such as local template directories)
+	cleanup func() error
+
+	// fromArchive is set for sources unpacked from a template archive, whose
+	// files already carry the modes of the git tree
+	fromArchive bool
+
+	// partial is set for partial clones, which check out only the files the
+	// install selects, so the tree hash cannot be checked or the tree archived
+	partial bool
+
+	// submodules is set for clones with their submodules checked out, whose
+	// files the tree hash does not cover
+	submodules bool
+
+	// archiveDigest is the SHA-256 of the archive given with --archive that
+	// the source was unpacked from, recorded in place of a commit
+	archiveDigest string
+}
+
+// unhashable describes why the source cannot be checked against the
+// template's tree hash or archived, or returns "" when it can
+func (src *templateSource) unhashable() string {
+	switch {
+	case src.partial:
+		return "a partial clone"
+	case src.submodules:
+		return "a clone with submodules"
+	}
+	return ""
+}
+
+// Cleanup releases the source directory if it was created for this installation
+func (src *templateSource) Cleanup() error {
+	if src.cleanup == nil {
+		return nil
+	}
+	return src.cleanup()
+}
+
+// prepareSource makes the template contents available on disk. An archive
+// given with --archive is unpacked; sources fetched by Prefetch are reused;
+// plain local directories are used in place; a pinned commit with an archive
+// in ArchiveDir is unpacked from it without git;
+// everything else is cloned with git and, unless verification is skipped,
+// checked against the template's pinned commit, then archived when ArchiveDir
+// is set.
+func (s *Service) prepareSource(template templates.Template, installConfig models.InstallConfig) (*templateSource, error) {
+	if installConfig.Archive != "" {
+		return s.importedSource(installConfig.Archive)
+	}
+	if source, ok := s.prefetchedSource(template.ID); ok {
+		return source, nil
+	}
+
+	// Archives leave out .git, which --keep-git needs, and the submodules
+	archive := ArchivePath(template, installConfig.ArchiveDir)
+	if archive == "" || installConfig.KeepGit || installConfig.Submodules {
+		return s.fetchSource(template, installConfig)
+	}
+	if source := s.archivedSource(archive, template, installConfig); source != nil {
+		return source, nil
+	}
+
+	source, err := s.fetchSource(template, installConfig)
+	if err != nil {
+		return nil, err
+	}
+	// A rejected archive is gone, so a commit-named one is replaced under the key
+	archive = ArchivePath(template, installConfig.ArchiveDir)
+
+	// Only a checkout that passed verification is archived for later installs
+	if !installConfig.SkipVerify && source.unhashable() == "" {
+		if err := verifyTreeHash(source.Dir, template); err != nil {
+			return source, nil // Install reports the mismatch
+		}
+		if err := s.writeArchive(source.Dir, archive); err != nil {
+			slog.Warn("Failed to write template archive", "archive", archive, "error", err)
+		} else {
+			slog.Info("Wrote template archive", "template", template.ID, "archive", archive)
+		}
+	}
+	return source, nil
+}
+
+// fetchSource makes the template contents available on disk without an
+// archive, as prepareSource describes
+func (s *Service) fetchSource(template templates.Template, installConfig models.InstallConfig) (*templateSource, error) {
+	repoURL := template.RepoURL
+	sparse := sparsePatterns(template, installConfig)
+	if installConfig.PartialClone && sparse == nil {
+		slog.Warn("Nothing limits the install to part of the template, so --partial-clone fetches every file", "template", template.ID)
+	}
+
+	if template.IsLocal() {
+		localPath, err := s.validateLocalSource(template)
+		if err != nil {
+			return nil, err
+		}
+
+		if !template.IsLocalGitRepo() {
+			return &templateSource{Dir: localPath}, nil
+		}
+
+		// Local git checkouts are still cloned so the pinned commit is honoured
+		repoURL = localPath
+	} else if installConfig.Offline || (sparse == nil && !installConfig.Submodules && !installConfig.NoCache && s.cacheService.Enabled()) {
+		return s.cachedSource(template, installConfig)
+	}
+
+	ctx, cancel := gitContext(installConfig)
+	defer cancel()
+
+	fetching := progress.Start("Fetching template " + template.ID)
+	tempDir, err := s.gitService.CloneWithOptions(ctx, git.CloneOptions{
+		URL:        repoURL,
+		Branch:     template.CheckoutRef(),
+		Commit:     template.PinnedCommit(),
+		Depth:      cloneDepth(installConfig),
+		Sparse:     sparse,
+		Submodules: installConfig.Submodules,
+		Retry:      retryOptions(installConfig),
+		Notify: func(message string) {
+			slog.Info(message)
+		},
+		Progress: gitProgress(fetching),
+	})
+	fetching.Done()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone repository%s: %w", timeoutNote(err, installConfig), err)
+	}
+
+	source := &templateSource{
+		Dir:        tempDir,
+		Commit:     template.Commit,
+		partial:    sparse != nil,
+		submodules: installConfig.Submodules,
+		cleanup: func() error {
+			return s.gitService.CleanupTempDir(tempDir)
+		},
+	}
+
+	// Make sure the pinned commit is what was actually checked out
+	if !installConfig.SkipVerify && !template.FollowBranch && template.Tag == "" {
+		if err := s.gitService.VerifyHeadCommit(tempDir, template.Commit); err != nil {
+			_ = source.Cleanup() // Best effort cleanup
+			return nil, err
+		}
+	}
+
+	// Record which commit the tag resolved to
+	if template.Tag != "" {
+		commit, err := s.resolveTag(tempDir, template, installConfig)
+		if err != nil {
+			_ = source.Cleanup() // Best effort cleanup
+			return nil, err
+		}
+		source.Commit = commit
+	}
+
+	// Record which commit the branch head resolved to
+	if template.FollowBranch {
+		commit, err := s.gitService.GetHeadCommit(tempDir)
+		if err != nil {
+			_ = source.Cleanup() // Best effort cleanup
+			return nil, fmt.Errorf("failed to resolve branch %s: %w", template.Branch, err)
+		}
+		source.Commit = commit
+	}
+
+	return source, nil
+}
+
+// CommitBranch returns the branch, or ref, whose recent commits RecentCommits
+// lists for the install. Templates pinned to a commit have no branch head to
+// choose below, so only those following their branch, or installed at a ref,
+// qualify.
+func (s *Service) CommitBranch(installConfig models.InstallConfig) (string, error) {
+	template, err := installConfig.GetTemplate()
+	if err != nil {
+		return "", err
+	}
+	if template.IsLocal() && !template.IsLocalGitRepo() {
+		return "", models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			fmt.Sprintf("Template '%s' is a plain local directory with no commits to choose from", template.ID),
+			nil,
+		)
+	}
+	if template.Tag != "" {
+		return "", models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			fmt.Sprintf("Template '%s' is pinned to tag %s; commits can only be chosen on a branch the template follows or one given with --ref", template.ID, template.Tag),
+			nil,
+		)
+	}
+	if !template.FollowBranch {
+		return "", models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			fmt.Sprintf("Template '%s' is pinned to commit %s; commits can only be chosen on a branch the template follows or one given with --ref", template.ID, template.Commit),
+			nil,
+		)
+	}
+	return template.Branch, nil
+}
+
+// ResolveCommit returns the commit an install with installConfig would check
+// out, without installing anything. A commit pinned in the registry or given
+// with --from-commit is returned as is; for a followed branch, a ref, or a tag
+// the template is fetched, from the cache when it holds it, to see which
+// commit that points to now.
+func (s *Service) ResolveCommit(installConfig models.InstallConfig) (_ string, err error) {
+	defer func() { err = models.NewTemplateError(installConfig.TemplateID, err) }()
+	template, err := installConfig.GetTemplate()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case installConfig.Archive != "":
+		return "", models.NewAppError(models.ErrorCodeInvalidConfiguration, "A template archive has no commit to resolve", nil)
+	case template.IsLocal() && !template.IsLocalGitRepo():
+		return "", models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			fmt.Sprintf("Template '%s' is a plain local directory with no commit to resolve", template.ID),
+			nil,
+		)
+	}
+	if commit := template.PinnedCommit(); commit != "" && template.Tag == "" {
+		return commit, nil
+	}
+
+	source, err := s.prepareSource(template, installConfig)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = source.Cleanup() // Best effort cleanup
+	}()
+	return source.Commit, nil
+}
+
+// cloneDepth returns how deep to clone: always the full history when the git
+// directory is kept, so it can be used to diff and rebase against the template
+func cloneDepth(installConfig models.InstallConfig) int {
+	if installConfig.KeepGit {
+		return 0
+	}
+	return installConfig.CloneDepth
+}
+
+// sparsePatterns returns the sparse checkout patterns of a partial clone for
+// installConfig: the --only directories, or else the include patterns, along
+// with the files at the repository root, such as install scripts. It returns
+// nil, for a full clone, without --partial-clone or when nothing limits the
+// install to part of the template.
+func sparsePatterns(template templates.Template, installConfig models.InstallConfig) []string {
+	if !installConfig.PartialClone {
+		return nil
+	}
+
+	var selected []string
+	for _, only := range installConfig.OnlyPaths {
+		selected = append(selected, "/"+strings.Trim(filepath.ToSlash(filepath.Clean(only)), "/")+"/")
+	}
+	if len(selected) == 0 {
+		selected = append(selected, template.IncludePatterns...)
+		selected = append(selected, installConfig.IncludePatterns...)
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+	return append([]string{"/*", "!/*/"}, selected...)
+}
+
+// RecentCommits fetches the template like an install would and returns up to
+// limit of the latest commits on its branch, newest first, to pick one to
+// install with SelectedCommit
+func (s *Service) RecentCommits(installConfig models.InstallConfig, limit int) ([]git.CommitInfo, error) {
+	if _, err := s.CommitBranch(installConfig); err != nil {
+		return nil, err
+	}
+	template, err := installConfig.GetTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	// A shallow clone must reach back far enough to list the commits
+	if installConfig.CloneDepth > 0 && installConfig.CloneDepth < limit {
+		installConfig.CloneDepth = limit
+	}
+
+	source, err := s.prepareSource(template, installConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = source.Cleanup() // Best effort cleanup
+	}()
+
+	return s.gitService.RecentCommits(source.Dir, source.Commit, limit)
+}
+
+// cachedSource serves a remote template from the clone cache, and only from
+// what is already cached when offline. The cached tree is shared between runs,
+// so it is never cleaned up here.
+func (s *Service) cachedSource(template templates.Template, installConfig models.InstallConfig) (*templateSource, error) {
+	var treeDir, commit string
+	var err error
+	if installConfig.Offline {
+		if !s.cacheService.Enabled() {
+			return nil, models.NewAppError(models.ErrorCodeNotCached, fmt.Sprintf("Offline and no cache for template %s: no cache directory is available", template.ID), nil)
+		}
+		treeDir, commit, err = s.cacheService.CheckoutCached(git.CloneOptions{
+			URL:    template.RepoURL,
+			Branch: template.CheckoutRef(),
+			Commit: template.PinnedCommit(),
+		})
+		if models.IsErrorCode(err, models.ErrorCodeNotCached) {
+			return nil, models.NewAppError(models.ErrorCodeNotCached, fmt.Sprintf("Offline and no cache for template %s", template.ID), err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached repository: %w", err)
+		}
+	} else {
+		ctx, cancel := gitContext(installConfig)
+		defer cancel()
+
+		fetching := progress.Start("Fetching template " + template.ID)
+		defer fetching.Done()
+
+		treeDir, commit, err = s.cacheService.Checkout(ctx, git.CloneOptions{
+			URL:    template.RepoURL,
+			Branch: template.CheckoutRef(),
+			Commit: template.PinnedCommit(),
+			Retry:  retryOptions(installConfig),
+			Notify: func(message string) {
+				slog.Info(message)
+			},
+			Progress: gitProgress(fetching),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch repository%s: %w", timeoutNote(err, installConfig), err)
+		}
+	}
+
+	switch {
+	case template.FollowBranch:
+	case template.Tag != "":
+		if commit, err = s.resolveTag(treeDir, template, installConfig); err != nil {
+			return nil, err
+		}
+	case !installConfig.SkipVerify:
+		if err := s.gitService.VerifyHeadCommit(treeDir, template.Commit); err != nil {
+			return nil, err
+		}
+	}
+
+	return &templateSource{Dir: treeDir, Commit: commit}, nil
+}
+
+// resolveTag returns the commit the template's tag names in the checkout in
+// dir and, unless verification is skipped, makes sure it is the commit that
+// was checked out: git takes a branch of the same name over the tag.
+func (s *Service) resolveTag(dir string, template templates.Template, installConfig models.InstallConfig) (string, error) {
+	if installConfig.SkipVerify {
+		return s.gitService.GetHeadCommit(dir)
+	}
+
+	commit, err := s.gitService.ResolveCommit(dir, "refs/tags/"+template.Tag)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tag %s: %w", template.Tag, err)
+	}
+	if err := s.gitService.VerifyHeadCommit(dir, commit); err != nil {
+		return "", fmt.Errorf("checkout does not match tag %s: %w", template.Tag, err)
+	}
+	return commit, nil
+}
+
+// gitProgress passes git's progress lines to reporter, or returns nil so git
+// is not asked for them when progress is off
+func gitProgress(reporter *progress.Reporter) func(line string) {
+	if reporter == nil {
+		return nil
+	}
+	return reporter.Status
+}
+
+// gitContext bounds the git work of fetching one template by GitTimeout; zero
+// means no limit
+func gitContext(installConfig models.InstallConfig) (context.Context, context.CancelFunc) {
+	if installConfig.GitTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), installConfig.GitTimeout)
+}
+
+// timeoutNote names the limit a git timeout error ran into, for error messages
+func timeoutNote(err error, installConfig models.InstallConfig) string {
+	if !models.IsErrorCode(err, models.ErrorCodeNetworkTimeout) || installConfig.GitTimeout <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" within %s", installConfig.GitTimeout)
+}
+
+// retryOptions configures retries of template clones and fetches, reporting
+// each retry in verbose mode
+func retryOptions(installConfig models.InstallConfig) git.RetryOptions {
+	return git.RetryOptions{
+		Attempts: installConfig.Retries,
+		OnRetry: func(message string) {
+			slog.Debug(message)
+		},
+	}
+}
+
+// validateLocalSource checks that a local template path exists and looks like a template
+func (s *Service) validateLocalSource(template templates.Template) (string, error) {
+	localPath, err := template.LocalPath()
+	if err != nil {
+		return "", models.NewAppError(
+			models.ErrorCodeInvalidPath,
+			fmt.Sprintf("Failed to resolve template path: %s", template.RepoURL),
+			err,
+		)
+	}
+
+	info, err := os.Stat(localPath)
+	if os.IsNotExist(err) {
+		return "", models.NewAppError(
+			models.ErrorCodeDirectoryNotFound,
+			fmt.Sprintf("Template path does not exist: %s", localPath),
+			err,
+		)
+	}
+	if err != nil {
+		return "", models.NewAppError(
+			models.ErrorCodeFileSystemError,
+			fmt.Sprintf("Failed to access template path: %s", localPath),
+			err,
+		)
+	}
+	if !info.IsDir() {
+		return "", models.NewAppError(
+			models.ErrorCodeInvalidPath,
+			fmt.Sprintf("Template path is not a directory: %s", localPath),
+			nil,
+		)
+	}
+
+	frameworkDir := filepath.Join(localPath, config.StrategicClaudeBasicDir)
+	if _, err := os.Stat(frameworkDir); os.IsNotExist(err) {
+		return "", models.NewAppError(
+			models.ErrorCodeDirectoryNotFound,
+			fmt.Sprintf("Template path %s does not contain a %s directory", localPath, config.StrategicClaudeBasicDir),
+			err,
+		)
+	}
+
+	return localPath, nil
+}
@@ -0,0 +1,662 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// createLocalTemplate builds a minimal template directory on disk
+func createLocalTemplate(t *testing.T) string {
+	t.Helper()
+	sourceDir := t.TempDir()
+
+	dirs := []string{
+		filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.AgentsDir),
+		filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.CommandsDir),
+		filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.HooksDir),
+		filepath.Join(config.StrategicClaudeBasicDir, config.TemplatesDir),
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(sourceDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Core\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+
+	return sourceDir
+}
+
+func TestPrepareSource_LocalDirectory(t *testing.T) {
+	service := New()
+	sourceDir := createLocalTemplate(t)
+
+	source, err := service.prepareSource(templates.Template{
+		ID:      "local",
+		Name:    "Local",
+		RepoURL: "file://" + sourceDir,
+	}, models.InstallConfig{})
+	if err != nil {
+		t.Fatalf("prepareSource() error = %v", err)
+	}
+
+	if source.Dir != sourceDir {
+		t.Errorf("Expected source dir %s, got %s", sourceDir, source.Dir)
+	}
+
+	if err := source.Cleanup(); err != nil {
+		t.Errorf("Cleanup() error = %v", err)
+	}
+
+	// Local directories must never be removed by cleanup
+	if _, err := os.Stat(sourceDir); err != nil {
+		t.Errorf("Local template directory was removed: %v", err)
+	}
+}
+
+func TestPrepareSource_LocalErrors(t *testing.T) {
+	service := New()
+	missing := filepath.Join(t.TempDir(), "does-not-exist")
+
+	tests := []struct {
+		name     string
+		repoURL  string
+		wantCode models.ErrorCode
+	}{
+		{
+			name:     "missing path",
+			repoURL:  missing,
+			wantCode: models.ErrorCodeDirectoryNotFound,
+		},
+		{
+			name:     "directory without framework",
+			repoURL:  t.TempDir(),
+			wantCode: models.ErrorCodeDirectoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.prepareSource(templates.Template{ID: "local", Name: "Local", RepoURL: tt.repoURL}, models.InstallConfig{})
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			var appErr *models.AppError
+			if !errors.As(err, &appErr) || appErr.Code != tt.wantCode {
+				t.Errorf("Expected error code %s, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestInstall_LocalTemplate(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		GitignoreMode: "track",
+	}
+
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	installed := filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if _, err := os.Stat(installed); err != nil {
+		t.Errorf("Expected core file to be installed: %v", err)
+	}
+
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if lock == nil || lock.Templates[0].TemplateID != "local" || lock.Templates[0].RepoURL != sourceDir {
+		t.Errorf("Unexpected lock after install: %+v", lock)
+	}
+}
+
+// initGitTemplate turns a local template directory into a git repository with one commit
+func initGitTemplate(t *testing.T, dir string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	run := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init", "-q", "-b", "main")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	return run("rev-parse", "HEAD")
+}
+
+// keepEmptyDirs adds a .gitkeep to each empty directory of a template made by
+// createLocalTemplate, since git does not track empty directories
+func keepEmptyDirs(t *testing.T, sourceDir string) {
+	t.Helper()
+	for _, dir := range []string{
+		filepath.Join(config.CoreDir, config.AgentsDir),
+		filepath.Join(config.CoreDir, config.CommandsDir),
+		filepath.Join(config.CoreDir, config.HooksDir),
+		config.TemplatesDir,
+	} {
+		keep := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, dir, ".gitkeep")
+		if err := os.WriteFile(keep, nil, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", keep, err)
+		}
+	}
+}
+
+func TestInstall_FromCommit(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	older := initGitTemplate(t, sourceDir)
+
+	readme := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(filepath.Join(sourceDir, readme), []byte("# Core v2\n"), 0644); err != nil {
+		t.Fatalf("Failed to update README: %v", err)
+	}
+	commit := exec.Command("git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-am", "v2")
+	commit.Dir = sourceDir
+	if output, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v\n%s", err, output)
+	}
+	rev := exec.Command("git", "rev-parse", "HEAD")
+	rev.Dir = sourceDir
+	output, err := rev.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+	pinned := strings.TrimSpace(string(output))
+
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir, Branch: "main", Commit: pinned},
+	})
+	install := func(targetDir, fromCommit string) error {
+		return New().Install(models.InstallConfig{
+			TargetDir:     targetDir,
+			TemplateID:    "local",
+			FromCommit:    fromCommit,
+			SkipConfirm:   true,
+			NoBackup:      true,
+			GitignoreMode: "track",
+		})
+	}
+
+	targetDir := t.TempDir()
+	if err := install(targetDir, older); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(targetDir, readme))
+	if err != nil || string(data) != "# Core\n" {
+		t.Errorf("Expected the older README, got %q, %v", data, err)
+	}
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	entry := lock.Find("local")
+	if entry == nil || entry.Commit != older || !entry.CommitOverride {
+		t.Errorf("Expected the lock to record override of %s, got %+v", older, entry)
+	}
+
+	err = install(t.TempDir(), strings.Repeat("a", 40))
+	if !models.IsErrorCode(err, models.ErrorCodeGitCommitNotFound) {
+		t.Errorf("Expected %s for a missing commit, got %v", models.ErrorCodeGitCommitNotFound, err)
+	}
+}
+
+func TestInstall_Submodules(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+	// Git refuses submodules from local paths unless allowed
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	sharedDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sharedDir, "reviewer.md"), []byte("# Reviewer\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	initGitTemplate(t, sharedDir)
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	initGitTemplate(t, sourceDir)
+
+	// The tree hash pins the template without its submodules, which a plain
+	// clone leaves as empty directories
+	template := templates.Template{ID: "local", Name: "Local", RepoURL: sourceDir, Branch: "main"}
+	treeHash, err := TreeHash(sourceDir, template)
+	if err != nil {
+		t.Fatalf("TreeHash() error = %v", err)
+	}
+	shared := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.AgentsDir, "shared")
+	for _, args := range [][]string{{"submodule", "add", "-q", sharedDir, filepath.ToSlash(shared)}, {"commit", "-q", "-m", "add shared agents"}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = sourceDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	rev := exec.Command("git", "rev-parse", "HEAD")
+	rev.Dir = sourceDir
+	output, err := rev.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+
+	template.Commit, template.TreeHash = strings.TrimSpace(string(output)), treeHash
+	templates.Registry.Set(map[string]templates.Template{"local": template})
+
+	install := func(targetDir string, submodules bool) error {
+		return New().Install(models.InstallConfig{
+			TargetDir:     targetDir,
+			TemplateID:    "local",
+			Submodules:    submodules,
+			SkipConfirm:   true,
+			NoBackup:      true,
+			GitignoreMode: "track",
+		})
+	}
+
+	t.Run("left out by default", func(t *testing.T) {
+		targetDir := t.TempDir()
+		if err := install(targetDir, false); err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(targetDir, shared, "reviewer.md")); !os.IsNotExist(err) {
+			t.Errorf("Expected the submodule's files not to be installed, got %v", err)
+		}
+	})
+
+	t.Run("included", func(t *testing.T) {
+		targetDir := t.TempDir()
+		if err := install(targetDir, true); err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+		if data, err := os.ReadFile(filepath.Join(targetDir, shared, "reviewer.md")); err != nil || string(data) != "# Reviewer\n" {
+			t.Errorf("Submodule file = %q, %v; want it installed", data, err)
+		}
+		if _, err := os.Lstat(filepath.Join(targetDir, shared, ".git")); !os.IsNotExist(err) {
+			t.Errorf("Expected the submodule's .git not to be installed, got %v", err)
+		}
+		lock, err := state.ReadLock(targetDir)
+		if err != nil {
+			t.Fatalf("ReadLock() error = %v", err)
+		}
+		if entry := lock.Find("local"); entry == nil || !entry.Submodules {
+			t.Errorf("Expected the lock to record the submodules, got %+v", entry)
+		}
+	})
+}
+
+func TestInstall_Ref(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	tagged := initGitTemplate(t, sourceDir)
+	for _, args := range [][]string{
+		{"tag", "v1"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "later"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = sourceDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	templates.Registry.Set(map[string]templates.Template{})
+	targetDir := t.TempDir()
+	err := New().Install(models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    filepath.Base(sourceDir),
+		RepoURL:       sourceDir,
+		Ref:           "v1",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	entry := lock.Find(filepath.Base(sourceDir))
+	if entry == nil || entry.Ref != "v1" || entry.Commit != tagged || entry.RepoURL != sourceDir {
+		t.Errorf("Expected the lock to record v1 at %s from %s, got %+v", tagged, sourceDir, entry)
+	}
+}
+
+func TestInstall_Tag(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	tagged := initGitTemplate(t, sourceDir)
+	for _, args := range [][]string{
+		{"tag", "-a", "v1", "-m", "v1"},
+		{"commit", "-q", "--allow-empty", "-m", "later"},
+		// A branch named like a tag is what git clones for it
+		{"branch", "v2"},
+		{"tag", "-a", "v2", "-m", "v2", tagged},
+	} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = sourceDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	templates.Registry.Set(map[string]templates.Template{
+		"tagged":   {ID: "tagged", Name: "Tagged", RepoURL: sourceDir, Branch: "main", Tag: "v1"},
+		"shadowed": {ID: "shadowed", Name: "Shadowed", RepoURL: sourceDir, Branch: "main", Tag: "v2"},
+	})
+
+	for _, depth := range []int{0, 1} {
+		t.Run(fmt.Sprintf("depth %d", depth), func(t *testing.T) {
+			installConfig := models.InstallConfig{
+				TargetDir:     t.TempDir(),
+				TemplateID:    "tagged",
+				SkipConfirm:   true,
+				NoBackup:      true,
+				NoCache:       true,
+				CloneDepth:    depth,
+				GitignoreMode: "track",
+			}
+			if err := New().Install(installConfig); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+
+			lock, err := state.ReadLock(installConfig.TargetDir)
+			if err != nil {
+				t.Fatalf("ReadLock() error = %v", err)
+			}
+			if entry := lock.Find("tagged"); entry == nil || entry.Tag != "v1" || entry.Commit != tagged {
+				t.Errorf("Expected the lock to record v1 at %s, got %+v", tagged, entry)
+			}
+
+			installConfig.TargetDir = t.TempDir()
+			installConfig.TemplateID = "shadowed"
+			if err := New().Install(installConfig); !models.IsErrorCode(err, models.ErrorCodeGitCommitMismatch) {
+				t.Errorf("Install() of a tag shadowed by a branch error = %v, want %s", err, models.ErrorCodeGitCommitMismatch)
+			}
+		})
+	}
+}
+
+func TestInstall_SelectedCommit(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	older := initGitTemplate(t, sourceDir)
+
+	readme := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(filepath.Join(sourceDir, readme), []byte("# Core v2\n"), 0644); err != nil {
+		t.Fatalf("Failed to update README: %v", err)
+	}
+	commit := exec.Command("git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-am", "v2")
+	commit.Dir = sourceDir
+	if output, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v\n%s", err, output)
+	}
+
+	templates.Registry.Set(map[string]templates.Template{
+		"local":  {ID: "local", Name: "Local", RepoURL: sourceDir, Branch: "main", Commit: templates.HeadCommit, FollowBranch: true},
+		"pinned": {ID: "pinned", Name: "Pinned", RepoURL: sourceDir, Branch: "main", Commit: older},
+	})
+	installConfig := models.InstallConfig{
+		TargetDir:     t.TempDir(),
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		NoCache:       true,
+		CloneDepth:    1,
+		GitignoreMode: "track",
+	}
+
+	service := New()
+	if branch, err := service.CommitBranch(installConfig); err != nil || branch != "main" {
+		t.Fatalf("CommitBranch() = %q, %v; want main", branch, err)
+	}
+	commits, err := service.RecentCommits(installConfig, 10)
+	if err != nil {
+		t.Fatalf("RecentCommits() error = %v", err)
+	}
+	if len(commits) != 2 || commits[1].Hash != older || commits[0].Subject != "v2" {
+		t.Fatalf("RecentCommits() = %+v, want v2 then %s", commits, older)
+	}
+
+	installConfig.SelectedCommit = older
+	if err := service.Install(installConfig); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(installConfig.TargetDir, readme))
+	if err != nil || string(data) != "# Core\n" {
+		t.Errorf("Expected the selected commit's README, got %q, %v", data, err)
+	}
+	lock, err := state.ReadLock(installConfig.TargetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if entry := lock.Find("local"); entry == nil || entry.Commit != older || !entry.CommitOverride {
+		t.Errorf("Expected the lock to record override of %s, got %+v", older, entry)
+	}
+
+	// A pinned template has no branch head to choose below
+	pinnedConfig := installConfig
+	pinnedConfig.TemplateID = "pinned"
+	pinnedConfig.SelectedCommit = ""
+	if _, err := service.CommitBranch(pinnedConfig); !models.IsErrorCode(err, models.ErrorCodeInvalidConfiguration) {
+		t.Errorf("CommitBranch() for a pinned template error = %v, want %s", err, models.ErrorCodeInvalidConfiguration)
+	}
+	pinnedConfig.Ref = "main"
+	if _, err := service.CommitBranch(pinnedConfig); err != nil {
+		t.Errorf("CommitBranch() for a pinned template with a ref error = %v", err)
+	}
+}
+
+func TestResolveCommit(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	older := initGitTemplate(t, sourceDir)
+
+	readme := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(filepath.Join(sourceDir, readme), []byte("# Core v2\n"), 0644); err != nil {
+		t.Fatalf("Failed to update README: %v", err)
+	}
+	commit := exec.Command("git", "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-am", "v2")
+	commit.Dir = sourceDir
+	if output, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v\n%s", err, output)
+	}
+	rev := exec.Command("git", "rev-parse", "HEAD")
+	rev.Dir = sourceDir
+	output, err := rev.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+	head := strings.TrimSpace(string(output))
+
+	templates.Registry.Set(map[string]templates.Template{
+		"following": {ID: "following", Name: "Following", RepoURL: sourceDir, Branch: "main", Commit: templates.HeadCommit, FollowBranch: true},
+		"pinned":    {ID: "pinned", Name: "Pinned", RepoURL: sourceDir, Branch: "main", Commit: older},
+		"plain":     {ID: "plain", Name: "Plain", RepoURL: createLocalTemplate(t), Branch: "main", Commit: templates.HeadCommit, FollowBranch: true},
+	})
+
+	tests := []struct {
+		name       string
+		templateID string
+		fromCommit string
+		ref        string
+		want       string
+	}{
+		{name: "following branch", templateID: "following", want: head},
+		{name: "pinned", templateID: "pinned", want: older},
+		{name: "from commit", templateID: "following", fromCommit: older, want: older},
+		{name: "ref", templateID: "pinned", ref: "main", want: head},
+	}
+
+	service := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.ResolveCommit(models.InstallConfig{
+				TargetDir:  t.TempDir(),
+				TemplateID: tt.templateID,
+				FromCommit: tt.fromCommit,
+				Ref:        tt.ref,
+				NoCache:    true,
+			})
+			if err != nil {
+				t.Fatalf("ResolveCommit() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveCommit() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// A plain local directory has no commit at all
+	_, err = service.ResolveCommit(models.InstallConfig{TargetDir: t.TempDir(), TemplateID: "plain"})
+	if !models.IsErrorCode(err, models.ErrorCodeInvalidConfiguration) {
+		t.Errorf("ResolveCommit() for a plain directory error = %v, want %s", err, models.ErrorCodeInvalidConfiguration)
+	}
+}
+
+func TestPrepareSource_FollowBranch(t *testing.T) {
+	service := New()
+	sourceDir := createLocalTemplate(t)
+	head := initGitTemplate(t, sourceDir)
+
+	source, err := service.prepareSource(templates.Template{
+		ID:           "local",
+		Name:         "Local",
+		RepoURL:      sourceDir,
+		Branch:       "main",
+		Commit:       templates.HeadCommit,
+		FollowBranch: true,
+	}, models.InstallConfig{})
+	if err != nil {
+		t.Fatalf("prepareSource() error = %v", err)
+	}
+	defer source.Cleanup()
+
+	if source.Commit != head {
+		t.Errorf("Expected resolved commit %s, got %s", head, source.Commit)
+	}
+	if source.Dir == sourceDir {
+		t.Error("Expected local git repository to be cloned, not used in place")
+	}
+}
+
+func TestPrepareSource_VerifyCommit(t *testing.T) {
+	service := New()
+	sourceDir := createLocalTemplate(t)
+	head := initGitTemplate(t, sourceDir)
+
+	template := templates.Template{
+		ID:      "local",
+		Name:    "Local",
+		RepoURL: sourceDir,
+		Branch:  "main",
+		Commit:  head,
+	}
+
+	source, err := service.prepareSource(template, models.InstallConfig{})
+	if err != nil {
+		t.Fatalf("prepareSource() with matching commit error = %v", err)
+	}
+	_ = source.Cleanup()
+
+	// Abbreviated pins check out fine but are not an exact match
+	template.Commit = head[:12]
+	if _, err := service.prepareSource(template, models.InstallConfig{}); !models.IsErrorCode(err, models.ErrorCodeGitCommitMismatch) {
+		t.Errorf("Expected commit mismatch error, got %v", err)
+	}
+
+	source, err = service.prepareSource(template, models.InstallConfig{SkipVerify: true})
+	if err != nil {
+		t.Fatalf("prepareSource() with verification skipped error = %v", err)
+	}
+	_ = source.Cleanup()
+}
+
+func TestPrepareSource_Offline(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	service := New()
+
+	// A remote template that was never cached fails without contacting it
+	remote := templates.Template{
+		ID:      "remote",
+		Name:    "Remote",
+		RepoURL: "https://example.invalid/remote.git",
+		Branch:  "main",
+		Commit:  "1234567890abcdef1234567890abcdef12345678",
+	}
+	_, err := service.prepareSource(remote, models.InstallConfig{Offline: true})
+	if !models.IsErrorCode(err, models.ErrorCodeNotCached) || !strings.Contains(err.Error(), "Offline and no cache for template remote") {
+		t.Errorf("Expected a NOT_CACHED error naming the template, got %v", err)
+	}
+
+	// Local templates need no network
+	sourceDir := createLocalTemplate(t)
+	head := initGitTemplate(t, sourceDir)
+	source, err := service.prepareSource(templates.Template{
+		ID:      "local",
+		Name:    "Local",
+		RepoURL: sourceDir,
+		Branch:  "main",
+		Commit:  head,
+	}, models.InstallConfig{Offline: true})
+	if err != nil {
+		t.Fatalf("prepareSource() of a local template offline error = %v", err)
+	}
+	_ = source.Cleanup()
+}
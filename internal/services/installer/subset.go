@@ -0,0 +1,91 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// onlySubtrees checks the --only paths against the template checkout and
+// returns them cleaned and sorted, dropping any path nested in another
+func onlySubtrees(sourceDir string, only []string) ([]string, error) {
+	cleaned := make([]string, 0, len(only))
+	for _, raw := range only {
+		rel := filepath.Clean(raw)
+
+		info, err := os.Stat(filepath.Join(sourceDir, rel))
+		if err != nil || !info.IsDir() {
+			return nil, models.NewAppError(
+				models.ErrorCodeInvalidConfiguration,
+				fmt.Sprintf("--only path '%s' is not a directory in the template; available top-level directories: %s",
+					raw, strings.Join(topLevelDirs(sourceDir), ", ")),
+				err,
+			)
+		}
+
+		if rel != config.StrategicClaudeBasicDir && !strings.HasPrefix(rel, config.StrategicClaudeBasicDir+string(filepath.Separator)) {
+			return nil, models.NewAppError(
+				models.ErrorCodeInvalidConfiguration,
+				fmt.Sprintf("--only path '%s' is outside %s; only framework files are installed", raw, config.StrategicClaudeBasicDir),
+				nil,
+			)
+		}
+
+		cleaned = append(cleaned, rel)
+	}
+
+	sort.Strings(cleaned)
+	subtrees := make([]string, 0, len(cleaned))
+	for _, rel := range cleaned {
+		if len(subtrees) > 0 && withinSubtrees(rel, subtrees[len(subtrees)-1:]) {
+			continue // Already covered by its parent
+		}
+		subtrees = append(subtrees, rel)
+	}
+
+	return subtrees, nil
+}
+
+// validateSubtrees checks that every subtree of a partial install is in place
+func validateSubtrees(targetDir string, subtrees []string) error {
+	for _, rel := range subtrees {
+		path := filepath.Join(targetDir, rel)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return models.NewFileSystemError(models.ErrorCodeInstallationFailed, path, err)
+		}
+	}
+	return nil
+}
+
+// topLevelDirs lists the directories at the root of the template checkout,
+// along with the framework directories, for error messages
+func topLevelDirs(sourceDir string) []string {
+	var dirs []string
+	for _, root := range []string{"", config.StrategicClaudeBasicDir} {
+		entries, err := os.ReadDir(filepath.Join(sourceDir, root))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && entry.Name() != ".git" {
+				dirs = append(dirs, filepath.Join(root, entry.Name()))
+			}
+		}
+	}
+	return dirs
+}
+
+// withinSubtrees reports whether rel is one of subtrees or inside one of them
+func withinSubtrees(rel string, subtrees []string) bool {
+	for _, subtree := range subtrees {
+		if rel == subtree || strings.HasPrefix(rel, subtree+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
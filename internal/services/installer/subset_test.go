@@ -0,0 +1,205 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestOnlySubtrees(t *testing.T) {
+	sourceDir := createLocalTemplate(t)
+	core := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir)
+	commands := filepath.Join(core, config.CommandsDir)
+	if err := os.MkdirAll(filepath.Join(sourceDir, ".github"), 0755); err != nil {
+		t.Fatalf("Failed to create .github: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		only    []string
+		want    []string
+		wantErr string
+	}{
+		{name: "single subtree", only: []string{commands + "/"}, want: []string{commands}},
+		{name: "nested paths collapse", only: []string{commands, core}, want: []string{core}},
+		{name: "missing path lists directories", only: []string{filepath.Join(core, "missing")}, wantErr: "available top-level directories: .github, .strategic-claude-basic, .strategic-claude-basic/core"},
+		{name: "outside framework", only: []string{".github"}, wantErr: "outside .strategic-claude-basic"},
+		{name: "file is not a subtree", only: []string{filepath.Join(core, "README.md")}, wantErr: "is not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := onlySubtrees(sourceDir, tt.only)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("onlySubtrees() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("onlySubtrees() error = %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("onlySubtrees() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstall_OnlySubtrees(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+
+	commands := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.CommandsDir)
+	if err := os.WriteFile(filepath.Join(sourceDir, commands, "plan.md"), []byte("# Plan\n"), 0644); err != nil {
+		t.Fatalf("Failed to write command: %v", err)
+	}
+
+	// Layer the commands onto an existing installation that has a local edit elsewhere
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Initial Install() error = %v", err)
+	}
+	readme := filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Edited\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit README: %v", err)
+	}
+
+	installConfig.Force = true
+	installConfig.OnlyPaths = []string{commands}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Install() with --only error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(targetDir, commands, "plan.md")); err != nil {
+		t.Errorf("Expected selected subtree to be installed: %v", err)
+	}
+	content, err := os.ReadFile(readme)
+	if err != nil || string(content) != "# Edited\n" {
+		t.Errorf("Expected files outside the subtree to be untouched, got %q (%v)", content, err)
+	}
+
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if strings.Join(lock.Templates[0].Only, ",") != commands {
+		t.Errorf("lock.Templates[0].Only = %v, want [%s]", lock.Templates[0].Only, commands)
+	}
+}
+
+func TestSparsePatterns(t *testing.T) {
+	commands := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.CommandsDir)
+	root := []string{"/*", "!/*/"}
+
+	tests := []struct {
+		name          string
+		template      templates.Template
+		installConfig models.InstallConfig
+		want          []string
+	}{
+		{
+			name:          "without --partial-clone",
+			installConfig: models.InstallConfig{OnlyPaths: []string{commands}},
+		},
+		{
+			name:          "nothing selected",
+			installConfig: models.InstallConfig{PartialClone: true},
+		},
+		{
+			name:          "only directories",
+			installConfig: models.InstallConfig{PartialClone: true, OnlyPaths: []string{commands + "/"}},
+			want:          append(root, "/.strategic-claude-basic/core/commands/"),
+		},
+		{
+			name:          "include patterns",
+			template:      templates.Template{IncludePatterns: []string{"*.md"}},
+			installConfig: models.InstallConfig{PartialClone: true, IncludePatterns: []string{"hooks/"}},
+			want:          append(root, "*.md", "hooks/"),
+		},
+		{
+			name:          "only directories win over include patterns",
+			installConfig: models.InstallConfig{PartialClone: true, OnlyPaths: []string{commands}, IncludePatterns: []string{"*.md"}},
+			want:          append(root, "/.strategic-claude-basic/core/commands/"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sparsePatterns(tt.template, tt.installConfig)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("sparsePatterns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstall_PartialClone(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	keepEmptyDirs(t, sourceDir)
+	commands := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.CommandsDir)
+	if err := os.WriteFile(filepath.Join(sourceDir, commands, "plan.md"), []byte("# Plan\n"), 0644); err != nil {
+		t.Fatalf("Failed to write command: %v", err)
+	}
+	template := templates.Template{
+		ID:      "remote",
+		Name:    "Remote",
+		RepoURL: "file://" + sourceDir,
+		Branch:  "main",
+		Commit:  initGitTemplate(t, sourceDir),
+	}
+	// The hash covers files a partial clone of the commands never fetches
+	treeHash, err := TreeHash(sourceDir, template)
+	if err != nil {
+		t.Fatalf("TreeHash() error = %v", err)
+	}
+	template.TreeHash = treeHash
+	templates.Registry.Set(map[string]templates.Template{"remote": template})
+
+	targetDir := t.TempDir()
+	err = New().Install(models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "remote",
+		OnlyPaths:     []string{commands},
+		PartialClone:  true,
+		NoCache:       true,
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	if content, err := os.ReadFile(filepath.Join(targetDir, commands, "plan.md")); err != nil || string(content) != "# Plan\n" {
+		t.Errorf("Expected the selected command to be installed, got %q (%v)", content, err)
+	}
+	lock, err := state.ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if entry := lock.Find("remote"); entry == nil || entry.Commit != template.Commit {
+		t.Errorf("Expected the lock to record commit %s, got %+v", template.Commit, entry)
+	}
+}
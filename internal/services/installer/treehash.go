@@ -0,0 +1,108 @@
+package installer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+// TreeHash computes the hash a template's TreeHash field pins: the framework
+// directory of a checkout, less the default and template exclude patterns and limited to the
+// template's include patterns, if any.
+// Every file contributes a line with its mode, its content hash (or symlink
+// target), and its slash-separated path, in path order, so the result depends
+// only on the files that would be installed and not on the repository history,
+// the filesystem, or the user's own --include, --exclude, and --only flags. A checkout
+// without a framework directory hashes as an empty tree.
+func TreeHash(sourceDir string, template templates.Template) (string, error) {
+	filter, err := newFileFilter(template.IncludePatterns, append(config.GetDefaultExcludePatterns(), template.ExcludePatterns...))
+	if err != nil {
+		return "", err
+	}
+
+	root := filepath.Join(sourceDir, config.StrategicClaudeBasicDir)
+	var lines []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && path == root {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		if filter.Skip(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		var mode, content string
+		switch {
+		case d.Type()&os.ModeSymlink != 0:
+			mode = "120000"
+			if content, err = os.Readlink(path); err != nil {
+				return err
+			}
+		default:
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			mode = "100644"
+			if info.Mode()&0111 != 0 {
+				mode = "100755"
+			}
+			if content, err = state.HashFile(path); err != nil {
+				return err
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s\n", mode, content, filepath.ToSlash(rel)))
+		return nil
+	})
+	if err != nil {
+		return "", models.NewFileSystemError(models.ErrorCodeFileSystemError, root, err)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "")))
+	return templates.TreeHashPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// verifyTreeHash checks a checkout against the template's declared tree hash,
+// if it has one
+func verifyTreeHash(sourceDir string, template templates.Template) error {
+	if template.TreeHash == "" {
+		return nil
+	}
+
+	actual, err := TreeHash(sourceDir, template)
+	if err != nil {
+		return fmt.Errorf("failed to compute tree hash: %w", err)
+	}
+
+	if !strings.EqualFold(actual, template.TreeHash) {
+		return models.NewAppError(
+			models.ErrorCodeTreeHashMismatch,
+			fmt.Sprintf("Template files hash to %s, not the expected %s", actual, template.TreeHash),
+			nil,
+		).WithContext("expected", template.TreeHash).WithContext("actual", actual)
+	}
+
+	return nil
+}
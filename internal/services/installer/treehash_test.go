@@ -0,0 +1,125 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestTreeHash(t *testing.T) {
+	sourceDir := createLocalTemplate(t)
+	template := templates.Template{ID: "local", Name: "Local", RepoURL: sourceDir, ExcludePatterns: []string{"*.draft.md"}}
+	readme := filepath.Join(sourceDir, config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+
+	hash := func() string {
+		t.Helper()
+		got, err := TreeHash(sourceDir, template)
+		if err != nil {
+			t.Fatalf("TreeHash() error = %v", err)
+		}
+		return got
+	}
+
+	base := hash()
+	if base != hash() {
+		t.Fatalf("TreeHash() is not deterministic")
+	}
+
+	// Files that are never installed do not count
+	excluded := []string{
+		"README.md",
+		filepath.Join(".github", "workflows", "ci.yml"),
+		filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "notes.draft.md"),
+	}
+	for _, rel := range excluded {
+		path := filepath.Join(sourceDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", rel, err)
+		}
+	}
+	if got := hash(); got != base {
+		t.Errorf("TreeHash() with excluded files = %s, want %s", got, base)
+	}
+
+	if err := os.Chmod(readme, 0755); err != nil {
+		t.Fatalf("Failed to chmod README: %v", err)
+	}
+	executable := hash()
+	if executable == base {
+		t.Errorf("TreeHash() did not change when a file became executable")
+	}
+
+	if err := os.WriteFile(readme, []byte("# Changed\n"), 0755); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	if got := hash(); got == executable {
+		t.Errorf("TreeHash() did not change when a file's content changed")
+	}
+
+	// With include patterns only the files they select count
+	template.IncludePatterns = []string{"*.tmpl"}
+	included := hash()
+	if err := os.WriteFile(readme, []byte("# Changed again\n"), 0755); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	if got := hash(); got != included {
+		t.Errorf("TreeHash() changed for a file no include pattern selects")
+	}
+}
+
+func TestInstall_TreeHash(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	template := templates.Template{ID: "local", Name: "Local", RepoURL: sourceDir}
+	expected, err := TreeHash(sourceDir, template)
+	if err != nil {
+		t.Fatalf("TreeHash() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		treeHash string
+		wantErr  bool
+	}{
+		{"matching hash", expected, false},
+		{"mismatched hash", templates.TreeHashPrefix + "0000000000000000000000000000000000000000000000000000000000000000", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template.TreeHash = tt.treeHash
+			templates.Registry.Set(map[string]templates.Template{"local": template})
+
+			targetDir := t.TempDir()
+			err := New().Install(models.InstallConfig{
+				TargetDir:     targetDir,
+				TemplateID:    "local",
+				SkipConfirm:   true,
+				NoBackup:      true,
+				GitignoreMode: "track",
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Install() error = %v", err)
+				}
+				return
+			}
+
+			if !models.IsErrorCode(err, models.ErrorCodeTreeHashMismatch) {
+				t.Fatalf("Install() error = %v, want %s", err, models.ErrorCodeTreeHashMismatch)
+			}
+			if _, err := os.Stat(filepath.Join(targetDir, config.StrategicClaudeBasicDir)); !os.IsNotExist(err) {
+				t.Errorf("Install() wrote the framework despite the mismatch")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+)
+
+func TestInstall_CoreUpdateKeepsUnchangedFiles(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+
+	sourceDir := createLocalTemplate(t)
+	templates.Registry.Set(map[string]templates.Template{
+		"local": {ID: "local", Name: "Local", RepoURL: sourceDir},
+	})
+	readme := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, "README.md")
+	plan := filepath.Join(config.StrategicClaudeBasicDir, config.CoreDir, config.CommandsDir, "plan.md")
+	if err := os.WriteFile(filepath.Join(sourceDir, plan), []byte("# Plan\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	targetDir := t.TempDir()
+	installConfig := models.InstallConfig{
+		TargetDir:     targetDir,
+		TemplateID:    "local",
+		SkipConfirm:   true,
+		NoBackup:      true,
+		GitignoreMode: "track",
+	}
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Initial Install() error = %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(filepath.Join(targetDir, readme), past, past); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, plan), []byte("# Plan v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var changes models.FileChanges
+	installConfig.ForceCore = true
+	installConfig.OnFileChanges = func(written models.FileChanges) { changes = written }
+	if err := New().Install(installConfig); err != nil {
+		t.Fatalf("Update Install() error = %v", err)
+	}
+
+	if changes.Updated != 1 || changes.Unchanged == 0 {
+		t.Errorf("OnFileChanges() got %+v, want 1 updated and the rest unchanged", changes)
+	}
+	info, err := os.Stat(filepath.Join(targetDir, readme))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(past) {
+		t.Errorf("README.md modified at %v, want unchanged %v", info.ModTime(), past)
+	}
+	if got, _ := os.ReadFile(filepath.Join(targetDir, plan)); string(got) != "# Plan v2\n" {
+		t.Errorf("plan.md = %q, want the template's new copy", got)
+	}
+}
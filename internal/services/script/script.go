@@ -1,7 +1,9 @@
 package script
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -102,6 +104,43 @@ func (s *Service) ExecuteScript(targetDir, scriptName string) error {
 	return nil
 }
 
+// RunCommand runs a shell command in the target directory, writing what it
+// prints on stdout and stderr to output as it runs
+func (s *Service) RunCommand(targetDir, command string, output io.Writer) error {
+	if targetDir == "" || command == "" {
+		return models.NewAppError(
+			models.ErrorCodeValidationFailed,
+			"Target directory and command cannot be empty",
+			nil,
+		)
+	}
+
+	if output == nil {
+		output = io.Discard
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = targetDir
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	if err := cmd.Run(); err != nil {
+		appErr := models.NewAppError(
+			models.ErrorCodeInstallationFailed,
+			fmt.Sprintf("Command failed: %s", command),
+			err,
+		).WithContext("target_dir", targetDir)
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			appErr = appErr.WithContext("exit_code", exitErr.ExitCode())
+		}
+		return appErr
+	}
+
+	return nil
+}
+
 // RemoveScript removes a script from the target directory
 func (s *Service) RemoveScript(targetDir, scriptName string) error {
 	if targetDir == "" || scriptName == "" {
@@ -1,9 +1,14 @@
 package script
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
 )
 
 func TestService_ScriptExists(t *testing.T) {
@@ -232,3 +237,62 @@ func TestService_GetScriptPath(t *testing.T) {
 		})
 	}
 }
+
+func TestService_RunCommand(t *testing.T) {
+	service := New()
+	targetDir := t.TempDir()
+
+	tests := []struct {
+		name       string
+		command    string
+		wantErr    bool
+		wantOutput []string
+	}{
+		{
+			name:       "stdout and stderr are captured",
+			command:    "echo out; echo err >&2",
+			wantOutput: []string{"out", "err"},
+		},
+		{
+			name:       "runs in the target directory",
+			command:    "touch created && pwd",
+			wantOutput: []string{targetDir},
+		},
+		{
+			name:       "non-zero exit fails",
+			command:    "echo failing; exit 3",
+			wantErr:    true,
+			wantOutput: []string{"failing"},
+		},
+		{
+			name:    "empty command",
+			command: "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := service.RunCommand(targetDir, tt.command, &output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(output.String(), want) {
+					t.Errorf("Expected output to contain %q, got %q", want, output.String())
+				}
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(targetDir, "created")); err != nil {
+		t.Errorf("Expected the command to create a file in the target directory: %v", err)
+	}
+
+	err := service.RunCommand(targetDir, "exit 3", nil)
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) || appErr.Context["exit_code"] != 3 {
+		t.Errorf("Expected an error with exit code 3, got %v", err)
+	}
+}
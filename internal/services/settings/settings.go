@@ -1,6 +1,7 @@
 package settings
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -20,8 +21,34 @@ func New() *Service {
 	return &Service{}
 }
 
+// SettingsPlan is how merging the installed template's hook registrations
+// would change .claude/settings.json
+type SettingsPlan struct {
+	SettingsPath string // The project's settings.json
+	BackupPath   string // Where the current file is copied before it is replaced; empty when there is none
+	Before       []byte // The current file, nil when there is none
+	After        []byte // The file after the merge
+}
+
+// Changed reports whether the merge changes the settings file
+func (p *SettingsPlan) Changed() bool {
+	return p.Before == nil || !bytes.Equal(p.Before, p.After)
+}
+
 // ProcessSettings is the main entry point for managing .claude/settings.json
 func (s *Service) ProcessSettings(targetDir string) error {
+	plan, err := s.PlanSettings(targetDir)
+	if err != nil || plan == nil {
+		return err
+	}
+	return s.ApplySettings(plan)
+}
+
+// PlanSettings works out how merging the hooks in the installed template's
+// settings template into .claude/settings.json would change it, without
+// writing anything. The user's own hooks and permissions are kept. It returns
+// nil when the template provides no settings template.
+func (s *Service) PlanSettings(targetDir string) (*SettingsPlan, error) {
 	strategicDir := filepath.Join(targetDir, config.StrategicClaudeBasicDir)
 	claudeDir := filepath.Join(targetDir, config.ClaudeDir)
 	settingsPath := filepath.Join(claudeDir, config.ClaudeSettingsFile)
@@ -30,27 +57,30 @@ func (s *Service) ProcessSettings(targetDir string) error {
 	// Check if template exists
 	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
 		// Template doesn't exist, nothing to do
-		return nil
+		return nil, nil
 	}
 
 	// Load template settings
 	templateSettings, err := s.loadTemplate(templatePath)
 	if err != nil {
-		return fmt.Errorf("failed to load settings template: %w", err)
+		return nil, fmt.Errorf("failed to load settings template: %w", err)
 	}
 
+	plan := &SettingsPlan{SettingsPath: settingsPath}
+
 	// Handle existing settings
 	var existingSettings *models.ClaudeSettings
 	if _, err := os.Stat(settingsPath); err == nil {
-		// Backup existing settings
-		if err := s.backupExistingSettings(settingsPath); err != nil {
-			return fmt.Errorf("failed to backup existing settings: %w", err)
+		plan.Before, err = os.ReadFile(settingsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load existing settings: %w", err)
 		}
+		plan.BackupPath = backupPath(settingsPath)
 
 		// Load existing settings
 		existingSettings, err = s.loadExistingSettings(settingsPath)
 		if err != nil {
-			return fmt.Errorf("failed to load existing settings: %w", err)
+			return nil, fmt.Errorf("failed to load existing settings: %w", err)
 		}
 	}
 
@@ -60,8 +90,28 @@ func (s *Service) ProcessSettings(targetDir string) error {
 	// Update hook paths to point to strategic directory
 	s.updateStrategicHookPaths(mergedSettings)
 
-	// Write merged settings
-	if err := s.writeSettings(settingsPath, mergedSettings); err != nil {
+	plan.After, err = json.MarshalIndent(mergedSettings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+
+	return plan, nil
+}
+
+// ApplySettings writes the merged settings of plan, backing up the current
+// settings file first
+func (s *Service) ApplySettings(plan *SettingsPlan) error {
+	if plan.BackupPath != "" {
+		if err := os.WriteFile(plan.BackupPath, plan.Before, config.FilePermissions); err != nil {
+			return fmt.Errorf("failed to backup existing settings: %w", err)
+		}
+	}
+
+	// Ensure parent directory exists
+	if err := os.MkdirAll(filepath.Dir(plan.SettingsPath), config.DirPermissions); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	if err := os.WriteFile(plan.SettingsPath, plan.After, config.FilePermissions); err != nil {
 		return fmt.Errorf("failed to write settings: %w", err)
 	}
 
@@ -70,12 +120,6 @@ func (s *Service) ProcessSettings(targetDir string) error {
 
 // backupExistingSettings creates a timestamped backup of existing settings
 func (s *Service) backupExistingSettings(settingsPath string) error {
-	timestamp := time.Now().Format("20060102-150405")
-	backupPath := filepath.Join(
-		filepath.Dir(settingsPath),
-		config.SettingsBackupPrefix+timestamp+".json",
-	)
-
 	// Read existing file
 	data, err := os.ReadFile(settingsPath)
 	if err != nil {
@@ -83,7 +127,16 @@ func (s *Service) backupExistingSettings(settingsPath string) error {
 	}
 
 	// Write backup
-	return os.WriteFile(backupPath, data, config.FilePermissions)
+	return os.WriteFile(backupPath(settingsPath), data, config.FilePermissions)
+}
+
+// backupPath returns the timestamped path a settings file is backed up to
+func backupPath(settingsPath string) string {
+	timestamp := time.Now().Format("20060102-150405")
+	return filepath.Join(
+		filepath.Dir(settingsPath),
+		config.SettingsBackupPrefix+timestamp+".json",
+	)
 }
 
 // loadTemplate loads the settings template from the framework
@@ -158,13 +211,21 @@ func (s *Service) mergeHooks(templateHooks *models.HooksSection, existing *model
 	return result
 }
 
-// mergeHookType merges hooks for a specific hook type (PreToolUse, PostToolUse, etc.)
+// mergeHookType merges hooks for a specific hook type (PreToolUse, PostToolUse, etc.).
+// Matchers keep the order they first appear in, the existing ones first.
 func (s *Service) mergeHookType(templateMatchers []models.HookMatcher, existingMatchers []models.HookMatcher) []models.HookMatcher {
 	matcherMap := make(map[string][]models.HookEntry)
+	var order []string
+	add := func(matcher string, hooks []models.HookEntry) {
+		if _, seen := matcherMap[matcher]; !seen {
+			order = append(order, matcher)
+		}
+		matcherMap[matcher] = append(matcherMap[matcher], hooks...)
+	}
 
 	// Add existing hooks first to preserve user customizations
 	for _, matcher := range existingMatchers {
-		matcherMap[matcher.Matcher] = append(matcherMap[matcher.Matcher], matcher.Hooks...)
+		add(matcher.Matcher, matcher.Hooks)
 	}
 
 	// Add template hooks, avoiding duplicates
@@ -172,18 +233,19 @@ func (s *Service) mergeHookType(templateMatchers []models.HookMatcher, existingM
 		existing := matcherMap[templateMatcher.Matcher]
 
 		// Add template hooks that don't already exist
+		var added []models.HookEntry
 		for _, templateHook := range templateMatcher.Hooks {
-			if !s.hookExists(existing, templateHook) {
-				existing = append(existing, templateHook)
+			if !s.hookExists(existing, templateHook) && !s.hookExists(added, templateHook) {
+				added = append(added, templateHook)
 			}
 		}
-		matcherMap[templateMatcher.Matcher] = existing
+		add(templateMatcher.Matcher, added)
 	}
 
 	// Convert back to slice format
 	var result []models.HookMatcher
-	for matcher, hooks := range matcherMap {
-		if len(hooks) > 0 {
+	for _, matcher := range order {
+		if hooks := matcherMap[matcher]; len(hooks) > 0 {
 			result = append(result, models.HookMatcher{
 				Matcher: matcher,
 				Hooks:   hooks,
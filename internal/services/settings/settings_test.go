@@ -699,3 +699,76 @@ func checkStrategicHookPaths(t *testing.T, hooks *models.HooksSection) {
 		checkHookTypePaths(hooks.Notification, "Notification")
 	}
 }
+
+func TestService_PlanSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	templatePath := filepath.Join(tempDir, config.StrategicClaudeBasicDir, config.SettingsTemplateFile)
+	settingsPath := filepath.Join(tempDir, config.ClaudeDir, config.ClaudeSettingsFile)
+	writeJSON := func(path string, settings *models.ClaudeSettings) {
+		t.Helper()
+		data, _ := json.MarshalIndent(settings, "", "  ")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	service := New()
+	if plan, err := service.PlanSettings(tempDir); err != nil || plan != nil {
+		t.Fatalf("PlanSettings() without a settings template = %v, %v; want nil", plan, err)
+	}
+
+	writeJSON(templatePath, &models.ClaudeSettings{Hooks: &models.HooksSection{
+		PreToolUse: []models.HookMatcher{
+			{Matcher: "Bash", Hooks: []models.HookEntry{{Type: "command", Command: "/usr/bin/python3 $CLAUDE_PROJECT_DIR/.claude/hooks/block-skip-hooks.py"}}},
+			{Matcher: "Write", Hooks: []models.HookEntry{{Type: "command", Command: "/usr/bin/python3 $CLAUDE_PROJECT_DIR/.claude/hooks/check-write.py"}}},
+		},
+	}})
+	userSettings := &models.ClaudeSettings{Hooks: &models.HooksSection{
+		PreToolUse: []models.HookMatcher{
+			{Matcher: "Bash", Hooks: []models.HookEntry{{Type: "command", Command: "./my-hook.sh"}}},
+		},
+	}}
+	writeJSON(settingsPath, userSettings)
+	before, _ := os.ReadFile(settingsPath)
+
+	plan, err := service.PlanSettings(tempDir)
+	if err != nil {
+		t.Fatalf("PlanSettings() error = %v", err)
+	}
+	if !plan.Changed() || plan.BackupPath == "" || string(plan.Before) != string(before) {
+		t.Fatalf("Expected a change backing up the current settings, got %+v", plan)
+	}
+	if after, _ := os.ReadFile(settingsPath); string(after) != string(before) {
+		t.Errorf("Expected PlanSettings not to write settings.json")
+	}
+
+	var merged models.ClaudeSettings
+	if err := json.Unmarshal(plan.After, &merged); err != nil {
+		t.Fatalf("Merged settings are not valid JSON: %v", err)
+	}
+	// The user's matcher comes first and keeps its hook, followed by the template's
+	pre := merged.Hooks.PreToolUse
+	if len(pre) != 2 || pre[0].Matcher != "Bash" || pre[1].Matcher != "Write" ||
+		len(pre[0].Hooks) != 2 || pre[0].Hooks[0].Command != "./my-hook.sh" {
+		t.Errorf("Unexpected merged PreToolUse hooks: %+v", pre)
+	}
+
+	if err := service.ApplySettings(plan); err != nil {
+		t.Fatalf("ApplySettings() error = %v", err)
+	}
+	if backup, err := os.ReadFile(plan.BackupPath); err != nil || string(backup) != string(before) {
+		t.Errorf("Expected the previous settings in the backup, got %q, %v", backup, err)
+	}
+
+	// Merging again finds every hook already registered
+	again, err := service.PlanSettings(tempDir)
+	if err != nil {
+		t.Fatalf("PlanSettings() error = %v", err)
+	}
+	if again.Changed() {
+		t.Errorf("Expected no change once the hooks are registered, got:\n%s", again.After)
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
 )
@@ -72,6 +74,22 @@ func (s *Service) CheckInstallation(targetDir string) (*models.StatusInfo, error
 		}
 	}
 
+	// Load the lock file and compare against the registry
+	if status.StrategicClaudeDir {
+		lock, err := state.ReadLock(absTarget)
+		if err != nil {
+			// Reported separately so a bad lock does not fail installation validation
+			status.LockError = err.Error()
+		} else if lock == nil {
+			status.Unmanaged = true
+		} else {
+			status.Lock = lock
+			for i := range lock.Templates {
+				status.VersionChecks = append(status.VersionChecks, s.CompareWithRegistry(&lock.Templates[i]))
+			}
+		}
+	}
+
 	// Validate symlinks
 	s.validateSymlinks(status)
 	s.validateCodexSymlinks(status)
@@ -306,6 +324,66 @@ func (s *Service) GetStatusSummary(status *models.StatusInfo) string {
 	return "Strategic Claude Basic is installed and configured correctly"
 }
 
+// CompareWithRegistry reports how a locked template's commit relates to the registry's current commit
+func (s *Service) CompareWithRegistry(lock *state.TemplateLock) *models.VersionCheck {
+	check := &models.VersionCheck{
+		TemplateID:      lock.TemplateID,
+		Branch:          lock.Branch,
+		Ref:             lock.Ref,
+		InstalledCommit: lock.Commit,
+		InstalledTag:    lock.Tag,
+	}
+
+	template, err := templates.GetTemplate(lock.TemplateID)
+	if err != nil && lock.Ref != "" {
+		// Installed straight from a repository with --repo-url, so there is no registry commit
+		check.State = models.VersionStateOverridden
+		return check
+	}
+	if err != nil {
+		check.State = models.VersionStateTemplateMissing
+		if suggestion, ok := templates.SuggestTemplate(lock.TemplateID); ok {
+			check.SuggestedID = suggestion.ID
+		}
+		return check
+	}
+
+	check.TemplateName = template.Name
+	check.Branch = template.Branch
+	if template.IsPinned() {
+		check.RegistryCommit = template.Commit
+	}
+	if template.Tag != "" {
+		check.RegistryTag = template.Tag
+	}
+
+	switch {
+	case template.Tag != "":
+		// A tag names one commit, so the installation is current when it was
+		// resolved from the same tag; any other registry pin means an update
+		switch {
+		case lock.Tag == template.Tag:
+			check.State = models.VersionStateCurrent
+		case lock.CommitOverride || lock.Ref != "":
+			check.State = models.VersionStateOverridden
+		default:
+			check.State = models.VersionStateBehind
+		}
+	case lock.Commit == "" || check.RegistryCommit == "":
+		check.State = models.VersionStateUnknown
+	case strings.EqualFold(lock.Commit, check.RegistryCommit):
+		check.State = models.VersionStateCurrent
+	case lock.CommitOverride || lock.Ref != "":
+		// A commit chosen on purpose may be newer or older, so it is not behind
+		check.State = models.VersionStateOverridden
+	default:
+		// Registry pins only move forward with releases, so a differing commit is behind
+		check.State = models.VersionStateBehind
+	}
+
+	return check
+}
+
 // loadTemplateInfo loads template metadata from the installation directory
 func (s *Service) loadTemplateInfo(targetDir string) (*templates.TemplateInfo, error) {
 	strategicDir := filepath.Join(targetDir, config.StrategicClaudeBasicDir)
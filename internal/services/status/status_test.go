@@ -7,6 +7,8 @@ import (
 
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
 )
 
 // createTestDirectory creates a temporary directory structure for testing
@@ -501,3 +503,162 @@ func TestService_detectStrategicClaudeBasic(t *testing.T) {
 		})
 	}
 }
+
+func TestService_CompareWithRegistry(t *testing.T) {
+	service := NewService()
+
+	mainTemplate, err := templates.GetTemplate("main")
+	if err != nil {
+		t.Fatalf("Failed to get main template: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		lock      state.TemplateLock
+		wantState models.VersionState
+	}{
+		{
+			name:      "installed commit matches registry",
+			lock:      state.TemplateLock{TemplateID: "main", Commit: mainTemplate.Commit},
+			wantState: models.VersionStateCurrent,
+		},
+		{
+			name:      "installed commit differs from registry",
+			lock:      state.TemplateLock{TemplateID: "main", Commit: "1111111111111111111111111111111111111111"},
+			wantState: models.VersionStateBehind,
+		},
+		{
+			name:      "installed commit chosen with --from-commit",
+			lock:      state.TemplateLock{TemplateID: "main", Commit: "1111111111111111111111111111111111111111", CommitOverride: true},
+			wantState: models.VersionStateOverridden,
+		},
+		{
+			name:      "installed at a branch with --ref",
+			lock:      state.TemplateLock{TemplateID: "main", Commit: "1111111111111111111111111111111111111111", Ref: "feature/agents"},
+			wantState: models.VersionStateOverridden,
+		},
+		{
+			name:      "repository installed with --repo-url",
+			lock:      state.TemplateLock{TemplateID: "claude-template", Commit: mainTemplate.Commit, Ref: "v1.2.0"},
+			wantState: models.VersionStateOverridden,
+		},
+		{
+			name:      "no installed commit recorded",
+			lock:      state.TemplateLock{TemplateID: "main"},
+			wantState: models.VersionStateUnknown,
+		},
+		{
+			name:      "template removed from registry",
+			lock:      state.TemplateLock{TemplateID: "mian", Commit: mainTemplate.Commit},
+			wantState: models.VersionStateTemplateMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := service.CompareWithRegistry(&tt.lock)
+			if check.State != tt.wantState {
+				t.Errorf("CompareWithRegistry() state = %s, want %s", check.State, tt.wantState)
+			}
+			if tt.wantState == models.VersionStateTemplateMissing && check.SuggestedID != "main" {
+				t.Errorf("Expected suggestion 'main', got %q", check.SuggestedID)
+			}
+		})
+	}
+}
+
+func TestService_CompareWithRegistry_DistantSuggestion(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+	templates.Registry.Set(map[string]templates.Template{
+		"documentation": {ID: "documentation", Name: "Documentation", RepoURL: "https://example.com/repo.git", Branch: "main"},
+		"legacy-docs":   {ID: "legacy-docs", Name: "Legacy", RepoURL: "https://example.com/repo.git", Branch: "legacy", Deprecated: true},
+	})
+
+	// The closest active template is suggested however many edits away it is
+	check := NewService().CompareWithRegistry(&state.TemplateLock{TemplateID: "docs", Commit: "1111111111111111111111111111111111111111"})
+	if check.State != models.VersionStateTemplateMissing {
+		t.Fatalf("CompareWithRegistry() state = %s, want %s", check.State, models.VersionStateTemplateMissing)
+	}
+	if check.SuggestedID != "documentation" {
+		t.Errorf("Expected suggestion 'documentation', got %q", check.SuggestedID)
+	}
+}
+
+func TestService_CompareWithRegistry_Tag(t *testing.T) {
+	original := templates.Registry.Snapshot()
+	t.Cleanup(func() { templates.Registry.Set(original) })
+	templates.Registry.Set(map[string]templates.Template{
+		"tagged": {ID: "tagged", Name: "Tagged", RepoURL: "https://example.com/repo.git", Branch: "main", Tag: "v1.2.0"},
+	})
+	commit := "1111111111111111111111111111111111111111"
+
+	tests := []struct {
+		name      string
+		lock      state.TemplateLock
+		wantState models.VersionState
+	}{
+		{"installed at the registry tag", state.TemplateLock{TemplateID: "tagged", Commit: commit, Tag: "v1.2.0"}, models.VersionStateCurrent},
+		{"installed at an older tag", state.TemplateLock{TemplateID: "tagged", Commit: commit, Tag: "v1.1.0"}, models.VersionStateBehind},
+		{"installed at a commit chosen instead", state.TemplateLock{TemplateID: "tagged", Commit: commit, CommitOverride: true}, models.VersionStateOverridden},
+		{"installed at a commit before the tag pin", state.TemplateLock{TemplateID: "tagged", Commit: commit}, models.VersionStateBehind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewService().CompareWithRegistry(&tt.lock)
+			if check.State != tt.wantState {
+				t.Errorf("CompareWithRegistry() state = %s, want %s", check.State, tt.wantState)
+			}
+			if check.RegistryTag != "v1.2.0" || check.InstalledTag != tt.lock.Tag {
+				t.Errorf("CompareWithRegistry() tags = %q installed, %q registry; want %q, v1.2.0", check.InstalledTag, check.RegistryTag, tt.lock.Tag)
+			}
+		})
+	}
+}
+
+func TestService_CheckInstallation_LockFile(t *testing.T) {
+	service := NewService()
+
+	t.Run("valid lock", func(t *testing.T) {
+		tempDir := createTestDirectory(t, map[string]interface{}{
+			config.StrategicClaudeBasicDir: nil,
+		})
+		lock := &state.Lock{Templates: []state.TemplateLock{
+			{TemplateID: "main", Commit: "abc"},
+			{TemplateID: "ccr", Commit: "def"},
+		}}
+		if err := state.WriteLock(tempDir, lock); err != nil {
+			t.Fatalf("WriteLock() error = %v", err)
+		}
+
+		status, err := service.CheckInstallation(tempDir)
+		if err != nil {
+			t.Fatalf("CheckInstallation() error = %v", err)
+		}
+		if status.Lock == nil || len(status.VersionChecks) != 2 {
+			t.Fatalf("Expected the lock and a version check per template, got %+v", status.VersionChecks)
+		}
+		if status.VersionChecks[1].TemplateID != "ccr" {
+			t.Errorf("Expected version checks in lock order, got %s second", status.VersionChecks[1].TemplateID)
+		}
+	})
+
+	t.Run("malformed lock", func(t *testing.T) {
+		tempDir := createTestDirectory(t, map[string]interface{}{
+			config.StrategicClaudeBasicDir: map[string]interface{}{
+				config.LockFileName: "{broken",
+			},
+		})
+
+		status, err := service.CheckInstallation(tempDir)
+		if err != nil {
+			t.Fatalf("CheckInstallation() error = %v", err)
+		}
+		if status.LockError == "" {
+			t.Error("Expected malformed lock to be reported")
+		}
+		if len(status.VersionChecks) != 0 {
+			t.Error("Expected no version check for malformed lock")
+		}
+	})
+}
@@ -0,0 +1,290 @@
+package variables
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+const (
+	// ProjectName defaults to the base name of the target directory
+	ProjectName = "ProjectName"
+
+	// GoModule defaults to the module path in the target's go.mod, when present
+	GoModule = "GoModule"
+
+	// placeholderMarker is how every template action starts; files without it are left alone
+	placeholderMarker = "{{"
+)
+
+// Service renders template variables in installed files
+type Service struct{}
+
+// New creates a new variables service instance
+func New() *Service {
+	return &Service{}
+}
+
+// RenderResult reports what a render pass did, with paths relative to the rendered root
+type RenderResult struct {
+	Rendered []string // Files whose placeholders were substituted
+	Skipped  []string // Files with placeholders that could not be parsed as templates
+	Missing  []string // Referenced variables that had no value and were left as written
+}
+
+// Defaults returns the built-in variable values derived from the target directory
+func (s *Service) Defaults(targetDir string) map[string]string {
+	values := map[string]string{
+		ProjectName: filepath.Base(targetDir),
+	}
+	if module := readGoModule(filepath.Join(targetDir, "go.mod")); module != "" {
+		values[GoModule] = module
+	}
+	return values
+}
+
+// Referenced returns the sorted names of the variables used by files under root
+// that match patterns. Files that cannot be parsed are ignored here and reported by Render.
+func (s *Service) Referenced(root string, patterns []string) ([]string, error) {
+	names := make(map[string]struct{})
+
+	err := walkCandidates(root, patterns, func(rel string, content []byte) error {
+		tmpl, err := parseTemplate(rel, content)
+		if err != nil {
+			return nil
+		}
+		collectFields(tmpl.Root, names)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedNames(names), nil
+}
+
+// Render substitutes values into files under root that match patterns, rewriting
+// them in place. In strict mode an unset variable or unparsable file is an error;
+// otherwise unset placeholders are kept as written and unparsable files are left untouched.
+func (s *Service) Render(root string, patterns []string, values map[string]string, strict bool) (*RenderResult, error) {
+	result := &RenderResult{}
+	missing := make(map[string]struct{})
+
+	err := walkCandidates(root, patterns, func(rel string, content []byte) error {
+		tmpl, err := parseTemplate(rel, content)
+		if err != nil {
+			if strict {
+				return models.NewAppError(
+					models.ErrorCodeValidationFailed,
+					fmt.Sprintf("Failed to parse template placeholders in %s", rel),
+					err,
+				)
+			}
+			result.Skipped = append(result.Skipped, rel)
+			return nil
+		}
+
+		referenced := make(map[string]struct{})
+		collectFields(tmpl.Root, referenced)
+
+		data := make(map[string]string, len(values))
+		for name, value := range values {
+			data[name] = value
+		}
+		for name := range referenced {
+			if _, ok := values[name]; !ok {
+				missing[name] = struct{}{}
+				data[name] = placeholderMarker + "." + name + "}}"
+			}
+		}
+
+		if strict && len(missing) > 0 {
+			return models.NewAppError(
+				models.ErrorCodeValidationFailed,
+				fmt.Sprintf("Template variables are not set: %s", strings.Join(sortedNames(missing), ", ")),
+				nil,
+			)
+		}
+
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, data); err != nil {
+			return models.NewAppError(
+				models.ErrorCodeValidationFailed,
+				fmt.Sprintf("Failed to render template variables in %s", rel),
+				err,
+			)
+		}
+
+		path := filepath.Join(root, rel)
+		info, err := os.Stat(path)
+		if err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+		}
+		if err := os.WriteFile(path, rendered.Bytes(), info.Mode().Perm()); err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+		}
+
+		result.Rendered = append(result.Rendered, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Missing = sortedNames(missing)
+	return result, nil
+}
+
+// ExpandEnv replaces $NAME and ${NAME} in a variable value with the environment
+// variable lookup finds for NAME, and $$ with a literal $. It is applied once to
+// values supplied on the command line, so an expanded value is never expanded
+// again. It returns the referenced environment variables that are not set, which
+// expand to an empty string.
+func (s *Service) ExpandEnv(value string, lookup func(string) (string, bool)) (string, []string) {
+	unset := make(map[string]struct{})
+	expanded := os.Expand(value, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		envValue, ok := lookup(name)
+		if !ok {
+			unset[name] = struct{}{}
+		}
+		return envValue
+	})
+	return expanded, sortedNames(unset)
+}
+
+// walkCandidates calls fn for each regular file under root whose name matches
+// one of patterns and whose content contains a placeholder
+func walkCandidates(root string, patterns []string, fn func(rel string, content []byte) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if !matchesAny(rel, patterns) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+		}
+		if !bytes.Contains(content, []byte(placeholderMarker)) {
+			return nil
+		}
+
+		return fn(rel, content)
+	})
+}
+
+// matchesAny reports whether a relative path matches a glob, either by file name
+// (*.md) or by full relative path (core/commands/*.md)
+func matchesAny(rel string, patterns []string) bool {
+	slashed := filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, filepath.Base(rel)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, slashed); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTemplate parses file content as a text/template. Every referenced
+// variable is given a value before execution, so a missing key is a bug.
+func parseTemplate(name string, content []byte) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(string(content))
+}
+
+// collectFields records the top-level field names ({{.Name}}) used in a template
+func collectFields(node parse.Node, names map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, names)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, names)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, names)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectFields(arg, names)
+		}
+	case *parse.FieldNode:
+		if len(n.Ident) > 0 {
+			names[n.Ident[0]] = struct{}{}
+		}
+	case *parse.IfNode:
+		collectBranch(&n.BranchNode, names)
+	case *parse.RangeNode:
+		collectBranch(&n.BranchNode, names)
+	case *parse.WithNode:
+		collectBranch(&n.BranchNode, names)
+	case *parse.TemplateNode:
+		collectFields(n.Pipe, names)
+	}
+}
+
+// collectBranch records field names used in an if, range, or with block
+func collectBranch(branch *parse.BranchNode, names map[string]struct{}) {
+	collectFields(branch.Pipe, names)
+	collectFields(branch.List, names)
+	collectFields(branch.ElseList, names)
+}
+
+// readGoModule returns the module path declared in a go.mod file, or "" if there is none
+func readGoModule(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if module, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`)
+		}
+	}
+	return ""
+}
+
+// sortedNames returns the keys of a name set in lexical order
+func sortedNames(names map[string]struct{}) []string {
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,200 @@
+package variables
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+)
+
+// writeFiles creates files under root from a map of relative path to content
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", rel, err)
+		}
+	}
+}
+
+func TestService_Render(t *testing.T) {
+	patterns := []string{"*.md", "*.tmpl"}
+
+	tests := []struct {
+		name         string
+		files        map[string]string
+		values       map[string]string
+		strict       bool
+		wantErr      bool
+		wantRendered []string
+		wantSkipped  []string
+		wantMissing  []string
+		wantContent  map[string]string
+	}{
+		{
+			name: "renders matching files only",
+			files: map[string]string{
+				"README.md":        "# {{.ProjectName}}\n",
+				"core/go.mod.tmpl": "module {{.GoModule}}\n",
+				"main.go":          "// {{.ProjectName}}\n",
+				"plain.md":         "no placeholders\n",
+			},
+			values:       map[string]string{"ProjectName": "demo", "GoModule": "example.com/demo"},
+			wantRendered: []string{"README.md", filepath.Join("core", "go.mod.tmpl")},
+			wantContent: map[string]string{
+				"README.md":        "# demo\n",
+				"core/go.mod.tmpl": "module example.com/demo\n",
+				"main.go":          "// {{.ProjectName}}\n",
+			},
+		},
+		{
+			name:         "unset variables are kept as written",
+			files:        map[string]string{"README.md": "{{.ProjectName}} uses {{.GoModule}}\n"},
+			values:       map[string]string{"ProjectName": "demo"},
+			wantRendered: []string{"README.md"},
+			wantMissing:  []string{"GoModule"},
+			wantContent:  map[string]string{"README.md": "demo uses {{.GoModule}}\n"},
+		},
+		{
+			name:    "strict mode rejects unset variables",
+			files:   map[string]string{"README.md": "{{.GoModule}}\n"},
+			values:  map[string]string{},
+			strict:  true,
+			wantErr: true,
+		},
+		{
+			name:        "invalid syntax is skipped",
+			files:       map[string]string{"ci.md": "run: ${{ secrets.TOKEN }}\n"},
+			values:      map[string]string{},
+			wantSkipped: []string{"ci.md"},
+			wantContent: map[string]string{"ci.md": "run: ${{ secrets.TOKEN }}\n"},
+		},
+		{
+			name:    "strict mode rejects invalid syntax",
+			files:   map[string]string{"ci.md": "run: ${{ secrets.TOKEN }}\n"},
+			values:  map[string]string{},
+			strict:  true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tt.files)
+
+			result, err := New().Render(root, patterns, tt.values, tt.strict)
+			if tt.wantErr {
+				if !models.IsErrorCode(err, models.ErrorCodeValidationFailed) {
+					t.Fatalf("Render() error = %v, want VALIDATION_FAILED", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(result.Rendered, tt.wantRendered) {
+				t.Errorf("Rendered = %v, want %v", result.Rendered, tt.wantRendered)
+			}
+			if !reflect.DeepEqual(result.Skipped, tt.wantSkipped) {
+				t.Errorf("Skipped = %v, want %v", result.Skipped, tt.wantSkipped)
+			}
+			if len(result.Missing) > 0 || len(tt.wantMissing) > 0 {
+				if !reflect.DeepEqual(result.Missing, tt.wantMissing) {
+					t.Errorf("Missing = %v, want %v", result.Missing, tt.wantMissing)
+				}
+			}
+			for rel, want := range tt.wantContent {
+				got, err := os.ReadFile(filepath.Join(root, rel))
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", rel, err)
+				}
+				if string(got) != want {
+					t.Errorf("%s = %q, want %q", rel, string(got), want)
+				}
+			}
+		})
+	}
+}
+
+func TestService_Referenced(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"a.md":   "{{.ProjectName}} {{if .Team}}{{.Team}}{{end}}\n",
+		"b.tmpl": "{{.GoModule | printf \"%s\"}}\n",
+		"c.txt":  "{{.Ignored}}\n",
+	})
+
+	names, err := New().Referenced(root, []string{"*.md", "*.tmpl"})
+	if err != nil {
+		t.Fatalf("Referenced() error = %v", err)
+	}
+
+	want := []string{"GoModule", "ProjectName", "Team"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Referenced() = %v, want %v", names, want)
+	}
+}
+
+func TestService_Defaults(t *testing.T) {
+	targetDir := filepath.Join(t.TempDir(), "my-project")
+	writeFiles(t, targetDir, map[string]string{"go.mod": "module github.com/acme/my-project\n\ngo 1.22\n"})
+
+	values := New().Defaults(targetDir)
+	if values[ProjectName] != "my-project" {
+		t.Errorf("ProjectName = %q, want %q", values[ProjectName], "my-project")
+	}
+	if values[GoModule] != "github.com/acme/my-project" {
+		t.Errorf("GoModule = %q, want %q", values[GoModule], "github.com/acme/my-project")
+	}
+
+	// Without a go.mod there is no module default
+	if _, ok := New().Defaults(t.TempDir())[GoModule]; ok {
+		t.Error("Expected no GoModule default without go.mod")
+	}
+}
+
+func TestService_ExpandEnv(t *testing.T) {
+	env := map[string]string{"CI_PROJECT": "platform", "EMPTY": "", "NESTED": "$CI_PROJECT"}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name      string
+		value     string
+		want      string
+		wantUnset []string
+	}{
+		{name: "no references", value: "plain value", want: "plain value"},
+		{name: "bare reference", value: "$CI_PROJECT", want: "platform"},
+		{name: "braced reference", value: "team-${CI_PROJECT}-svc", want: "team-platform-svc"},
+		{name: "set but empty", value: "x${EMPTY}y", want: "xy"},
+		{name: "unset variables", value: "$MISSING/${ALSO_MISSING}/$MISSING", want: "//", wantUnset: []string{"ALSO_MISSING", "MISSING"}},
+		{name: "escaped dollar", value: "cost: $$5", want: "cost: $5"},
+		{name: "expanded values are not expanded again", value: "$NESTED", want: "$CI_PROJECT"},
+	}
+
+	service := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unset := service.ExpandEnv(tt.value, lookup)
+			if got != tt.want {
+				t.Errorf("ExpandEnv(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+			if len(unset) != 0 || len(tt.wantUnset) != 0 {
+				if !reflect.DeepEqual(unset, tt.wantUnset) {
+					t.Errorf("ExpandEnv(%q) unset = %v, want %v", tt.value, unset, tt.wantUnset)
+				}
+			}
+		})
+	}
+}
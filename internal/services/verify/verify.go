@@ -0,0 +1,129 @@
+package verify
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+// Report lists how an installation has drifted from its manifest. Paths are
+// relative to the target directory, with forward slashes, and sorted.
+type Report struct {
+	// Files and symlinks recorded at install time that no longer exist
+	Missing []string `json:"missing"`
+
+	// Recorded files whose content, or symlinks whose target, changed
+	Modified []string `json:"modified"`
+
+	// Files in the template's framework directories that no install recorded
+	Untracked []string `json:"untracked"`
+
+	// Number of recorded files and symlinks checked
+	Checked int `json:"checked"`
+}
+
+// Drifted reports whether anything is missing, modified, or untracked
+func (r *Report) Drifted() bool {
+	return len(r.Missing)+len(r.Modified)+len(r.Untracked) > 0
+}
+
+// Service checks installed files against the lock file's manifest
+type Service struct{}
+
+// New creates a new verify service instance
+func New() *Service {
+	return &Service{}
+}
+
+// Verify compares the files in targetDir with the manifest in lock. Files in
+// the user content directories are skipped since they are meant to be edited.
+// Untracked files are only looked for in framework directories the manifest
+// has files in, so a partial install does not report the directories it left out.
+func (s *Service) Verify(targetDir string, lock *state.Lock) (*Report, error) {
+	report := &Report{Missing: []string{}, Modified: []string{}, Untracked: []string{}}
+
+	recorded := make(map[string]bool)
+	roots := make(map[string]bool)
+	for _, record := range lock.AllFiles() {
+		if isUserContent(record.Path) {
+			continue
+		}
+		recorded[record.Path] = true
+		if root := frameworkRoot(record.Path); root != "" {
+			roots[root] = true
+		}
+
+		path := filepath.Join(targetDir, filepath.FromSlash(record.Path))
+		report.Checked++
+		modified, err := record.Modified(path)
+		if os.IsNotExist(err) {
+			report.Missing = append(report.Missing, record.Path)
+			continue
+		}
+		if err != nil {
+			return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, path, err)
+		}
+		if modified {
+			report.Modified = append(report.Modified, record.Path)
+		}
+	}
+
+	for root := range roots {
+		rootPath := filepath.Join(targetDir, filepath.FromSlash(root))
+		err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && path == rootPath {
+					return filepath.SkipDir // Reported through its missing files
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(targetDir, path)
+			if err != nil {
+				return err
+			}
+			if rel = filepath.ToSlash(rel); !recorded[rel] {
+				report.Untracked = append(report.Untracked, rel)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, models.NewFileSystemError(models.ErrorCodeFileSystemError, rootPath, err)
+		}
+	}
+
+	sort.Strings(report.Missing)
+	sort.Strings(report.Modified)
+	sort.Strings(report.Untracked)
+	return report, nil
+}
+
+// frameworkRoot returns the framework directory a recorded path lies in, such
+// as .strategic-claude-basic/core, or "" for paths outside them
+func frameworkRoot(path string) string {
+	rel, ok := strings.CutPrefix(path, config.StrategicClaudeBasicDir+"/")
+	if !ok {
+		return ""
+	}
+	for _, dir := range config.GetFrameworkDirectories() {
+		if strings.HasPrefix(rel, dir+"/") {
+			return config.StrategicClaudeBasicDir + "/" + dir
+		}
+	}
+	return ""
+}
+
+// isUserContent reports whether a recorded path is in one of the user content
+// directories, which installs seed but never own
+func isUserContent(path string) bool {
+	rel, ok := strings.CutPrefix(path, config.StrategicClaudeBasicDir+"/")
+	return ok && config.IsUserPreservedPath(rel)
+}
@@ -0,0 +1,118 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/state"
+)
+
+func writeFile(t *testing.T, targetDir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(targetDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", rel, err)
+	}
+}
+
+// record returns the manifest entry for a file as it is now
+func record(t *testing.T, targetDir, rel string) state.FileRecord {
+	t.Helper()
+	sum, err := state.HashFile(filepath.Join(targetDir, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatalf("HashFile() error = %v", err)
+	}
+	return state.FileRecord{Path: rel, SHA256: sum}
+}
+
+func TestService_Verify(t *testing.T) {
+	targetDir := t.TempDir()
+	files := []string{
+		".strategic-claude-basic/core/README.md",
+		".strategic-claude-basic/core/commands/plan.md",
+		".strategic-claude-basic/templates/issue.md",
+		".strategic-claude-basic/plan/README.md",
+	}
+	for _, rel := range files {
+		writeFile(t, targetDir, rel, "# "+rel+"\n")
+	}
+	if err := os.MkdirAll(filepath.Join(targetDir, ".claude", "commands"), 0755); err != nil {
+		t.Fatalf("Failed to create .claude: %v", err)
+	}
+	link := "../../.strategic-claude-basic/core/commands"
+	if err := os.Symlink(link, filepath.Join(targetDir, ".claude", "commands", "strategic")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	lock := &state.Lock{Templates: []state.TemplateLock{{TemplateID: "main"}}}
+	for _, rel := range files {
+		lock.Templates[0].Files = append(lock.Templates[0].Files, record(t, targetDir, rel))
+	}
+	lock.Templates[0].Files = append(lock.Templates[0].Files, state.FileRecord{Path: ".claude/commands/strategic", Link: link})
+
+	service := New()
+	report, err := service.Verify(targetDir, lock)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if report.Drifted() || report.Checked != 4 {
+		t.Errorf("Expected a clean report checking 4 files (user content skipped), got %+v", report)
+	}
+
+	// Drift in every way, plus an edit to user content that is not reported
+	if err := os.Remove(filepath.Join(targetDir, ".strategic-claude-basic", "core", "README.md")); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	writeFile(t, targetDir, ".strategic-claude-basic/templates/issue.md", "# Edited\n")
+	writeFile(t, targetDir, ".strategic-claude-basic/core/commands/extra.md", "# Extra\n")
+	writeFile(t, targetDir, ".strategic-claude-basic/plan/README.md", "# My plan\n")
+	writeFile(t, targetDir, ".strategic-claude-basic/research/notes.md", "# Notes\n")
+	symlink := filepath.Join(targetDir, ".claude", "commands", "strategic")
+	if err := os.Remove(symlink); err != nil {
+		t.Fatalf("Failed to remove symlink: %v", err)
+	}
+	if err := os.Symlink("elsewhere", symlink); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	report, err = service.Verify(targetDir, lock)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !report.Drifted() {
+		t.Fatal("Expected drift to be reported")
+	}
+	for name, tc := range map[string]struct{ got, want []string }{
+		"missing":   {report.Missing, []string{".strategic-claude-basic/core/README.md"}},
+		"modified":  {report.Modified, []string{".claude/commands/strategic", ".strategic-claude-basic/templates/issue.md"}},
+		"untracked": {report.Untracked, []string{".strategic-claude-basic/core/commands/extra.md"}},
+	} {
+		if strings.Join(tc.got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s = %v, want %v", name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestService_Verify_PartialInstall(t *testing.T) {
+	targetDir := t.TempDir()
+	writeFile(t, targetDir, ".strategic-claude-basic/core/commands/plan.md", "# Plan\n")
+	// Not part of the --only install, so not untracked either
+	writeFile(t, targetDir, ".strategic-claude-basic/templates/local.md", "# Local\n")
+
+	lock := &state.Lock{Templates: []state.TemplateLock{{
+		TemplateID: "main",
+		Files:      []state.FileRecord{record(t, targetDir, ".strategic-claude-basic/core/commands/plan.md")},
+	}}}
+	report, err := New().Verify(targetDir, lock)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if report.Drifted() {
+		t.Errorf("Expected no drift outside the installed directories, got %+v", report)
+	}
+}
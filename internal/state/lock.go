@@ -0,0 +1,423 @@
+// Package state persists information about what is installed in a project,
+// such as the templates and commits recorded in the lock file.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+)
+
+// LockVersion is the current lock file format version. Version 1 recorded a
+// single template at the top level; version 2 lists every installed template.
+const LockVersion = 2
+
+// ErrMalformedLock is returned when the lock file exists but cannot be parsed
+var ErrMalformedLock = errors.New("malformed lock file")
+
+// ErrUnsupportedLock is returned by MigrateLock for a lock file whose format
+// version this CLI does not know, such as one written by a newer release
+var ErrUnsupportedLock = errors.New("unsupported lock file version")
+
+// Lock records which templates and commits were installed into a project
+type Lock struct {
+	// Format version of the lock file
+	Version int `json:"version"`
+
+	// Installed templates in order of precedence: where two templates install
+	// the same file, the one listed later wins
+	Templates []TemplateLock `json:"templates"`
+}
+
+// TemplateLock records one installed template and the files it put in place
+type TemplateLock struct {
+	// Template that was installed
+	TemplateID string `json:"template_id"`
+	RepoURL    string `json:"repo_url"`
+	Branch     string `json:"branch"`
+
+	// Commit that was actually checked out (resolved from the branch head if the template follows its branch)
+	Commit string `json:"commit"`
+
+	// Registry tag the template is pinned to; Commit is what it resolved to
+	Tag string `json:"tag,omitempty"`
+
+	// Whether Commit was chosen with --from-commit or --select-commit instead of taken from the registry
+	CommitOverride bool `json:"commit_override,omitempty"`
+
+	// Branch or tag given with --ref; Commit is what it resolved to
+	Ref string `json:"ref,omitempty"`
+
+	// SHA-256 of the archive installed with --archive ("sha256:<hex>"),
+	// recorded in place of a commit, which an archive does not have
+	ArchiveDigest string `json:"archive_digest,omitempty"`
+
+	// When the installation completed
+	InstalledAt time.Time `json:"installed_at"`
+
+	// Whether the template's git directory was kept in the framework directory
+	// with --keep-git. It is never part of Files, so verify leaves it alone.
+	KeepGit bool `json:"keep_git"`
+
+	// Whether the template's git submodules were installed with --include-submodules
+	Submodules bool `json:"submodules,omitempty"`
+
+	// Template subtrees installed with --only; empty for a full installation
+	Only []string `json:"only,omitempty"`
+
+	// Every file and symlink the installer created for this template, for
+	// uninstalling and change detection
+	Files []FileRecord `json:"files,omitempty"`
+}
+
+// FileOverlap is a path two layered templates both ship
+type FileOverlap struct {
+	Path       string
+	TemplateID string // Template whose copy was replaced, or kept when Shadowed
+
+	// Whether the other template's copy was kept because it comes later in
+	// the lock, rather than replaced by the template being installed
+	Shadowed bool
+}
+
+// FileHandoff is a file that uninstalling one template left in place because
+// another installed template also ships it
+type FileHandoff struct {
+	Path       string `json:"path"`
+	TemplateID string `json:"template_id"` // Template the file is now recorded under
+}
+
+// Find returns the entry for a template, or nil when it is not installed
+func (l *Lock) Find(templateID string) *TemplateLock {
+	for i := range l.Templates {
+		if l.Templates[i].TemplateID == templateID {
+			return &l.Templates[i]
+		}
+	}
+	return nil
+}
+
+// Index returns the position of a template in the precedence order, or -1
+func (l *Lock) Index(templateID string) int {
+	for i, entry := range l.Templates {
+		if entry.TemplateID == templateID {
+			return i
+		}
+	}
+	return -1
+}
+
+// TemplateIDs returns the installed templates in precedence order
+func (l *Lock) TemplateIDs() []string {
+	ids := make([]string, len(l.Templates))
+	for i, entry := range l.Templates {
+		ids[i] = entry.TemplateID
+	}
+	return ids
+}
+
+// AllFiles returns the files recorded for every template
+func (l *Lock) AllFiles() []FileRecord {
+	var files []FileRecord
+	for _, entry := range l.Templates {
+		files = append(files, entry.Files...)
+	}
+	return files
+}
+
+// Put records entry, replacing the template's previous entry in place or
+// appending it when the template is new. The entry's files are taken from
+// every other template, and those overlaps are returned. Each taken record's
+// template is added to the SharedWith of the entry's record, so the file can
+// be handed back if the entry's template is uninstalled.
+func (l *Lock) Put(entry TemplateLock) []FileOverlap {
+	sharers := make(map[string][]string, len(entry.Files))
+	for _, record := range entry.Files {
+		sharers[record.Path] = nil
+	}
+	if previous := l.Find(entry.TemplateID); previous != nil {
+		for _, record := range previous.Files {
+			if _, owned := sharers[record.Path]; owned {
+				sharers[record.Path] = append(sharers[record.Path], record.SharedWith...)
+			}
+		}
+	}
+
+	var overlaps []FileOverlap
+	for i := range l.Templates {
+		if l.Templates[i].TemplateID == entry.TemplateID {
+			continue
+		}
+
+		kept := l.Templates[i].Files[:0]
+		for _, record := range l.Templates[i].Files {
+			if _, taken := sharers[record.Path]; taken {
+				if !record.IsLink() {
+					overlaps = append(overlaps, FileOverlap{Path: record.Path, TemplateID: l.Templates[i].TemplateID})
+				}
+				sharers[record.Path] = append(sharers[record.Path], record.SharedWith...)
+				sharers[record.Path] = append(sharers[record.Path], l.Templates[i].TemplateID)
+				continue
+			}
+			kept = append(kept, record)
+		}
+		l.Templates[i].Files = kept
+	}
+
+	files := make([]FileRecord, len(entry.Files))
+	for i, record := range entry.Files {
+		record.SharedWith = l.inOrder(sharers[record.Path], entry.TemplateID)
+		files[i] = record
+	}
+	entry.Files = files
+
+	if existing := l.Find(entry.TemplateID); existing != nil {
+		*existing = entry
+	} else {
+		l.Templates = append(l.Templates, entry)
+	}
+
+	return overlaps
+}
+
+// RecordShadowed notes that templateID also ships paths, whose copies from
+// templates later in the lock were kept instead, so uninstalling those
+// templates hands the paths back. The kept copies are returned as overlaps.
+func (l *Lock) RecordShadowed(templateID string, paths []string) []FileOverlap {
+	shadowed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		shadowed[path] = true
+	}
+
+	index := l.Index(templateID)
+	if index < 0 {
+		return nil
+	}
+
+	var overlaps []FileOverlap
+	for i := index + 1; i < len(l.Templates); i++ {
+		entry := &l.Templates[i]
+		for j := range entry.Files {
+			record := &entry.Files[j]
+			if !shadowed[record.Path] {
+				continue
+			}
+			record.SharedWith = l.inOrder(append(record.SharedWith, templateID), entry.TemplateID)
+			overlaps = append(overlaps, FileOverlap{Path: record.Path, TemplateID: entry.TemplateID, Shadowed: true})
+		}
+	}
+	return overlaps
+}
+
+// Remove takes a template out of the lock and returns its entry with only
+// the files no other installed template ships. Files another template also
+// ships are recorded under the last of those instead, and returned as
+// handoffs; their content stays whatever the removed template installed.
+// Remove returns nil when the template is not installed.
+func (l *Lock) Remove(templateID string) (*TemplateLock, []FileHandoff) {
+	index := l.Index(templateID)
+	if index < 0 {
+		return nil, nil
+	}
+	removed := l.Templates[index]
+	l.Templates = append(l.Templates[:index:index], l.Templates[index+1:]...)
+
+	var unique []FileRecord
+	var handoffs []FileHandoff
+	for _, record := range removed.Files {
+		sharers := l.inOrder(record.SharedWith, "")
+		if len(sharers) == 0 {
+			unique = append(unique, record)
+			continue
+		}
+
+		owner := l.Find(sharers[len(sharers)-1])
+		record.SharedWith = sharers[:len(sharers)-1]
+		if len(record.SharedWith) == 0 {
+			record.SharedWith = nil
+		}
+		owner.Files = append(owner.Files, record)
+		sort.Slice(owner.Files, func(i, j int) bool {
+			return owner.Files[i].Path < owner.Files[j].Path
+		})
+		if !record.IsLink() {
+			handoffs = append(handoffs, FileHandoff{Path: record.Path, TemplateID: owner.TemplateID})
+		}
+	}
+	removed.Files = unique
+
+	return &removed, handoffs
+}
+
+// inOrder returns the installed templates among ids, once each and in
+// precedence order, leaving out exclude
+func (l *Lock) inOrder(ids []string, exclude string) []string {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var ordered []string
+	for _, entry := range l.Templates {
+		if wanted[entry.TemplateID] && entry.TemplateID != exclude {
+			ordered = append(ordered, entry.TemplateID)
+		}
+	}
+	return ordered
+}
+
+// LockPath returns the location of the lock file for a target directory
+func LockPath(targetDir string) string {
+	return filepath.Join(targetDir, config.StrategicClaudeBasicDir, config.LockFileName)
+}
+
+// ReadLock loads the lock file from targetDir. A missing lock file is not an
+// error: it returns nil, meaning nothing has been installed by the CLI.
+func ReadLock(targetDir string) (*Lock, error) {
+	lockPath := LockPath(targetDir)
+
+	data, err := os.ReadFile(lockPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file %s: %w", lockPath, err)
+	}
+
+	var file struct {
+		Lock
+
+		// Version 1 kept its only template at the top level
+		TemplateLock
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrMalformedLock, lockPath, err)
+	}
+
+	lock := file.Lock
+	if lock.Version < 2 {
+		// Upgraded in memory, so writing the lock back saves the current format
+		lock.Templates = []TemplateLock{file.TemplateLock}
+		lock.Version = LockVersion
+	}
+
+	if len(lock.Templates) == 0 {
+		return nil, fmt.Errorf("%w %s: no templates recorded", ErrMalformedLock, lockPath)
+	}
+	for _, entry := range lock.Templates {
+		if entry.TemplateID == "" {
+			return nil, fmt.Errorf("%w %s: missing template_id", ErrMalformedLock, lockPath)
+		}
+		// Recorded paths are removed and rewritten later, so none may leave the project
+		for _, record := range entry.Files {
+			if !filepath.IsLocal(filepath.FromSlash(record.Path)) {
+				return nil, fmt.Errorf("%w %s: file path %q is outside the project", ErrMalformedLock, lockPath, record.Path)
+			}
+		}
+	}
+
+	return &lock, nil
+}
+
+// WriteLock saves the lock file into targetDir, replacing any existing one
+func WriteLock(targetDir string, lock *Lock) error {
+	lockPath := LockPath(targetDir)
+
+	if lock.Version == 0 {
+		lock.Version = LockVersion
+	}
+
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(lockPath), config.DirPermissions); err != nil {
+		return fmt.Errorf("failed to create lock file directory: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated lock
+	tmpPath := lockPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, config.FilePermissions); err != nil {
+		return fmt.Errorf("failed to write lock file %s: %w", lockPath, err)
+	}
+
+	if err := os.Rename(tmpPath, lockPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write lock file %s: %w", lockPath, err)
+	}
+
+	return nil
+}
+
+// LockMigration describes what MigrateLock did to a lock file
+type LockMigration struct {
+	// Format version the lock file had, and now has
+	FromVersion int
+	ToVersion   int
+
+	// Copy of the original file, or "" when it was already current
+	BackupPath string
+}
+
+// Migrated reports whether the lock file was upgraded
+func (m *LockMigration) Migrated() bool {
+	return m.FromVersion != m.ToVersion
+}
+
+// MigrateLock upgrades the lock file in targetDir to LockVersion in place,
+// first copying the original to "<lock file>.v<version>.bak". A lock file
+// already at LockVersion is left untouched, and a missing one returns nil.
+// Files without a version are from before it was recorded and read as version
+// 1; a version newer than LockVersion is an ErrUnsupportedLock error.
+func MigrateLock(targetDir string) (*LockMigration, error) {
+	lockPath := LockPath(targetDir)
+
+	data, err := os.ReadFile(lockPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file %s: %w", lockPath, err)
+	}
+
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrMalformedLock, lockPath, err)
+	}
+	version := header.Version
+	if version == 0 {
+		version = 1
+	}
+	if version < 1 || version > LockVersion {
+		return nil, fmt.Errorf("%w %d in %s: this CLI reads versions 1 to %d", ErrUnsupportedLock, version, lockPath, LockVersion)
+	}
+
+	migration := &LockMigration{FromVersion: version, ToVersion: LockVersion}
+	if !migration.Migrated() {
+		return migration, nil
+	}
+
+	lock, err := ReadLock(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	migration.BackupPath = fmt.Sprintf("%s.v%d.bak", lockPath, version)
+	if err := os.WriteFile(migration.BackupPath, data, config.FilePermissions); err != nil {
+		return nil, fmt.Errorf("failed to back up lock file to %s: %w", migration.BackupPath, err)
+	}
+	lock.Version = LockVersion
+	if err := WriteLock(targetDir, lock); err != nil {
+		return nil, err
+	}
+	return migration, nil
+}
@@ -0,0 +1,321 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+)
+
+func TestWriteLockAndReadLock(t *testing.T) {
+	targetDir := t.TempDir()
+
+	entry := TemplateLock{
+		TemplateID:  "main",
+		RepoURL:     "https://example.com/repo.git",
+		Branch:      "main",
+		Commit:      "1234567890abcdef1234567890abcdef12345678",
+		InstalledAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	lock := &Lock{Templates: []TemplateLock{entry, {TemplateID: "ccr", Commit: "abc"}}}
+
+	if err := WriteLock(targetDir, lock); err != nil {
+		t.Fatalf("WriteLock() error = %v", err)
+	}
+
+	got, err := ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if got == nil {
+		t.Fatal("ReadLock() returned nil lock")
+	}
+
+	if got.Version != LockVersion {
+		t.Errorf("Version = %d, want %d", got.Version, LockVersion)
+	}
+	if len(got.Templates) != 2 {
+		t.Fatalf("ReadLock() templates = %+v, want 2 entries", got.Templates)
+	}
+	first := got.Templates[0]
+	if first.TemplateID != entry.TemplateID || first.Commit != entry.Commit || first.Branch != entry.Branch || first.RepoURL != entry.RepoURL {
+		t.Errorf("ReadLock() first entry = %+v, want %+v", first, entry)
+	}
+	if !first.InstalledAt.Equal(entry.InstalledAt) {
+		t.Errorf("InstalledAt = %v, want %v", first.InstalledAt, entry.InstalledAt)
+	}
+	if got.Templates[1].TemplateID != "ccr" {
+		t.Errorf("Second entry = %+v, want ccr", got.Templates[1])
+	}
+
+	if _, err := os.Stat(LockPath(targetDir) + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Temporary lock file was left behind")
+	}
+}
+
+func TestReadLock_Version1(t *testing.T) {
+	targetDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(targetDir, config.StrategicClaudeBasicDir), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	content := `{
+  "version": 1,
+  "template_id": "main",
+  "branch": "main",
+  "commit": "abc",
+  "files": [{"path": ".strategic-claude-basic/core/README.md", "sha256": "00"}]
+}`
+	if err := os.WriteFile(LockPath(targetDir), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write lock: %v", err)
+	}
+
+	lock, err := ReadLock(targetDir)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if len(lock.Templates) != 1 {
+		t.Fatalf("ReadLock() templates = %+v, want one entry", lock.Templates)
+	}
+	entry := lock.Templates[0]
+	if entry.TemplateID != "main" || entry.Commit != "abc" || len(entry.Files) != 1 {
+		t.Errorf("ReadLock() entry = %+v, want the version 1 fields", entry)
+	}
+	if lock.Version != LockVersion {
+		t.Errorf("Version = %d, want the upgraded %d", lock.Version, LockVersion)
+	}
+}
+
+func TestMigrateLock(t *testing.T) {
+	version1 := `{"version": 1, "template_id": "main", "commit": "abc"}`
+	tests := []struct {
+		name        string
+		content     string
+		wantFrom    int
+		wantBackup  bool
+		wantErr     error
+		wantNothing bool
+	}{
+		{name: "version 1", content: version1, wantFrom: 1, wantBackup: true},
+		{name: "unversioned", content: `{"template_id": "main", "commit": "abc"}`, wantFrom: 1, wantBackup: true},
+		{name: "current", content: `{"version": 2, "templates": [{"template_id": "main", "commit": "abc"}]}`, wantFrom: LockVersion},
+		{name: "newer", content: `{"version": 3, "templates": []}`, wantErr: ErrUnsupportedLock},
+		{name: "not JSON", content: `template_id: main`, wantErr: ErrMalformedLock},
+		{name: "version 1 without a template", content: `{"version": 1}`, wantErr: ErrMalformedLock},
+		{name: "no lock file", wantNothing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetDir := t.TempDir()
+			if tt.content != "" {
+				if err := os.MkdirAll(filepath.Join(targetDir, config.StrategicClaudeBasicDir), 0755); err != nil {
+					t.Fatalf("Failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(LockPath(targetDir), []byte(tt.content), 0644); err != nil {
+					t.Fatalf("Failed to write lock: %v", err)
+				}
+			}
+
+			migration, err := MigrateLock(targetDir)
+			if tt.wantErr != nil || err != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("MigrateLock() error = %v, want %v", err, tt.wantErr)
+				}
+				// A lock file that cannot be migrated is left as it was
+				if data, _ := os.ReadFile(LockPath(targetDir)); string(data) != tt.content {
+					t.Errorf("Lock file changed to %s", data)
+				}
+				return
+			}
+			if tt.wantNothing {
+				if migration != nil {
+					t.Errorf("MigrateLock() = %+v, want nil without a lock file", migration)
+				}
+				return
+			}
+
+			if migration.FromVersion != tt.wantFrom || migration.ToVersion != LockVersion || migration.Migrated() != tt.wantBackup {
+				t.Errorf("MigrateLock() = %+v, want from %d to %d", migration, tt.wantFrom, LockVersion)
+			}
+			if !tt.wantBackup {
+				if migration.BackupPath != "" {
+					t.Errorf("Expected no backup of a current lock file, got %s", migration.BackupPath)
+				}
+				return
+			}
+			if backup, err := os.ReadFile(migration.BackupPath); err != nil || string(backup) != tt.content {
+				t.Errorf("Backup = %q, %v; want the original", backup, err)
+			}
+			data, err := os.ReadFile(LockPath(targetDir))
+			if err != nil || !strings.Contains(string(data), `"templates"`) || !strings.Contains(string(data), `"version": 2`) {
+				t.Errorf("Migrated lock file = %s, %v", data, err)
+			}
+			lock, err := ReadLock(targetDir)
+			if err != nil || len(lock.Templates) != 1 || lock.Templates[0].TemplateID != "main" {
+				t.Errorf("ReadLock() after migrating = %+v, %v", lock, err)
+			}
+		})
+	}
+}
+
+func TestLock_Put(t *testing.T) {
+	lock := &Lock{Templates: []TemplateLock{
+		{TemplateID: "main", Files: []FileRecord{
+			{Path: "a.md", SHA256: "1"},
+			{Path: "b.md", SHA256: "2"},
+			{Path: ".claude/commands/strategic", Link: "target"},
+		}},
+	}}
+
+	overlaps := lock.Put(TemplateLock{TemplateID: "ccr", Files: []FileRecord{
+		{Path: "b.md", SHA256: "3"},
+		{Path: "c.md", SHA256: "4"},
+		{Path: ".claude/commands/strategic", Link: "target"},
+	}})
+
+	if got := strings.Join(lock.TemplateIDs(), ","); got != "main,ccr" {
+		t.Errorf("TemplateIDs() = %s, want main,ccr", got)
+	}
+	if len(overlaps) != 1 || overlaps[0] != (FileOverlap{Path: "b.md", TemplateID: "main"}) {
+		t.Errorf("Put() overlaps = %+v, want b.md from main", overlaps)
+	}
+	if main := lock.Find("main"); len(main.Files) != 1 || main.Files[0].Path != "a.md" {
+		t.Errorf("main files = %+v, want only a.md", main.Files)
+	}
+	if got := len(lock.AllFiles()); got != 4 {
+		t.Errorf("AllFiles() has %d records, want 4", got)
+	}
+	for _, record := range lock.Find("ccr").Files {
+		want := ""
+		if record.Path != "c.md" {
+			want = "main"
+		}
+		if got := strings.Join(record.SharedWith, ","); got != want {
+			t.Errorf("%s shared with %q, want %q", record.Path, got, want)
+		}
+	}
+
+	// Re-recording a template keeps its place in the order
+	lock.Put(TemplateLock{TemplateID: "main", Commit: "new"})
+	if got := strings.Join(lock.TemplateIDs(), ","); got != "main,ccr" {
+		t.Errorf("TemplateIDs() after re-recording = %s, want main,ccr", got)
+	}
+	if lock.Find("main").Commit != "new" || lock.Find("missing") != nil {
+		t.Errorf("Find() did not return the re-recorded entry")
+	}
+}
+
+func TestLock_SharedFiles(t *testing.T) {
+	lock := &Lock{}
+	lock.Put(TemplateLock{TemplateID: "main", Files: []FileRecord{
+		{Path: "a.md", SHA256: "1"},
+		{Path: "shared.md", SHA256: "2"},
+		{Path: ".claude/commands/strategic", Link: "target"},
+	}})
+	lock.Put(TemplateLock{TemplateID: "ccr", Files: []FileRecord{
+		{Path: "shared.md", SHA256: "3"},
+		{Path: "c.md", SHA256: "4"},
+		{Path: ".claude/commands/strategic", Link: "target"},
+	}})
+	lock.Put(TemplateLock{TemplateID: "extra", Files: []FileRecord{
+		{Path: "shared.md", SHA256: "5"},
+		{Path: ".claude/commands/strategic", Link: "target"},
+	}})
+
+	// Re-recording a template keeps what it shared with earlier ones
+	lock.Put(TemplateLock{TemplateID: "extra", Files: []FileRecord{
+		{Path: "shared.md", SHA256: "6"},
+		{Path: ".claude/commands/strategic", Link: "target"},
+	}})
+	shared := lock.Find("extra").Files[0]
+	if shared.Path != "shared.md" || strings.Join(shared.SharedWith, ",") != "main,ccr" {
+		t.Fatalf("extra's %s shared with %v, want main,ccr", shared.Path, shared.SharedWith)
+	}
+
+	// An earlier template left its copy of c.md to a later one
+	overlaps := lock.RecordShadowed("main", []string{"c.md"})
+	if len(overlaps) != 1 || overlaps[0] != (FileOverlap{Path: "c.md", TemplateID: "ccr", Shadowed: true}) {
+		t.Errorf("RecordShadowed() = %+v, want c.md kept from ccr", overlaps)
+	}
+
+	removed, handoffs := lock.Remove("extra")
+	if removed == nil || len(removed.Files) != 0 {
+		t.Fatalf("Remove(extra) = %+v, want no files of its own", removed)
+	}
+	if len(handoffs) != 1 || handoffs[0] != (FileHandoff{Path: "shared.md", TemplateID: "ccr"}) {
+		t.Errorf("Remove(extra) handoffs = %+v, want shared.md to ccr", handoffs)
+	}
+	if got := strings.Join(lock.TemplateIDs(), ","); got != "main,ccr" {
+		t.Errorf("TemplateIDs() after Remove = %s, want main,ccr", got)
+	}
+
+	removed, handoffs = lock.Remove("ccr")
+	if removed == nil || len(removed.Files) != 0 {
+		t.Errorf("Remove(ccr) kept files %+v, want c.md handed to main", removed)
+	}
+	if len(handoffs) != 2 {
+		t.Errorf("Remove(ccr) handoffs = %+v, want c.md and shared.md", handoffs)
+	}
+	main := lock.Find("main")
+	if len(main.Files) != 4 {
+		t.Fatalf("main files = %+v, want 4 records", main.Files)
+	}
+	for _, record := range main.Files {
+		if len(record.SharedWith) != 0 {
+			t.Errorf("%s still shared with %v", record.Path, record.SharedWith)
+		}
+	}
+
+	removed, handoffs = lock.Remove("main")
+	if removed == nil || len(removed.Files) != 4 || len(handoffs) != 0 || len(lock.Templates) != 0 {
+		t.Errorf("Remove(main) = %+v, %+v; want all four files", removed, handoffs)
+	}
+	if removed, _ := lock.Remove("missing"); removed != nil {
+		t.Errorf("Remove(missing) = %+v, want nil", removed)
+	}
+}
+
+func TestReadLock_Missing(t *testing.T) {
+	lock, err := ReadLock(t.TempDir())
+	if err != nil {
+		t.Errorf("ReadLock() error = %v, want nil", err)
+	}
+	if lock != nil {
+		t.Errorf("ReadLock() = %+v, want nil", lock)
+	}
+}
+
+func TestReadLock_Malformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid json", content: "{not json"},
+		{name: "missing template id", content: `{"version": 1, "commit": "abc"}`},
+		{name: "no templates", content: `{"version": 2, "templates": []}`},
+		{name: "entry missing template id", content: `{"version": 2, "templates": [{"commit": "abc"}]}`},
+		{name: "file path escapes the project", content: `{"version": 2, "templates": [{"template_id": "main", "files": [{"path": "../../.bashrc"}]}]}`},
+		{name: "absolute file path", content: `{"version": 2, "templates": [{"template_id": "main", "files": [{"path": "/etc/passwd"}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetDir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(targetDir, config.StrategicClaudeBasicDir), 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(LockPath(targetDir), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write lock: %v", err)
+			}
+
+			_, err := ReadLock(targetDir)
+			if !errors.Is(err, ErrMalformedLock) {
+				t.Errorf("ReadLock() error = %v, want ErrMalformedLock", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// FileRecord describes one path the installer created
+type FileRecord struct {
+	// Path relative to the target directory, with forward slashes
+	Path string `json:"path"`
+
+	// SHA-256 of a regular file's content as installed
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Target of a symlink, set instead of SHA256
+	Link string `json:"link,omitempty"`
+
+	// Earlier templates in the lock that also ship this path, in precedence
+	// order. The template the record is filed under won; uninstalling it hands
+	// the path to the last of these instead of deleting it.
+	SharedWith []string `json:"shared_with,omitempty"`
+}
+
+// IsLink reports whether the record describes a symlink
+func (r FileRecord) IsLink() bool {
+	return r.Link != ""
+}
+
+// HashFile returns the hex-encoded SHA-256 of a file's content
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Modified reports whether the file at path no longer matches the record:
+// its content hash or symlink target changed, or it was replaced by something
+// of another type. A missing path is not modified; callers check for it first.
+func (r FileRecord) Modified(path string) (bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false, err
+	}
+
+	if r.IsLink() {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return true, nil
+		}
+		target, err := os.Readlink(path)
+		if err != nil {
+			return false, err
+		}
+		return target != r.Link, nil
+	}
+
+	if !info.Mode().IsRegular() {
+		return true, nil
+	}
+	sum, err := HashFile(path)
+	if err != nil {
+		return false, err
+	}
+	return sum != r.SHA256, nil
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+)
+
+// ErrMalformedVersionFile is returned when a project's version file exists but
+// does not name one template
+var ErrMalformedVersionFile = errors.New("malformed version file")
+
+// VersionPin is the template, and optionally the commit, a project pins in its
+// version file, so that everyone running init or update gets the same files.
+// The file holds one line naming the template ID and commit, such as
+//
+//	main 1234567890abcdef1234567890abcdef12345678
+//
+// Blank lines and text after # are ignored.
+type VersionPin struct {
+	TemplateID string
+
+	// Full commit to install; empty to install the registry's commit
+	Commit string
+}
+
+// VersionFilePath returns the location of the version file for a target directory
+func VersionFilePath(targetDir string) string {
+	return filepath.Join(targetDir, config.VersionFileName)
+}
+
+// ReadVersionPin loads the version file from targetDir. A missing version file
+// is not an error: it returns nil, nil.
+func ReadVersionPin(targetDir string) (*VersionPin, error) {
+	path := VersionFilePath(targetDir)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version file %s: %w", path, err)
+	}
+
+	var pin *VersionPin
+	for number, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if pin != nil {
+			return nil, fmt.Errorf("%w %s: line %d pins a second template; only one is supported", ErrMalformedVersionFile, path, number+1)
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("%w %s: line %d must be a template ID and an optional commit", ErrMalformedVersionFile, path, number+1)
+		}
+
+		pin = &VersionPin{TemplateID: fields[0]}
+		if len(fields) == 2 {
+			if !isFullCommit(fields[1]) {
+				return nil, fmt.Errorf("%w %s: commit '%s' must be a full 40-character hex string", ErrMalformedVersionFile, path, fields[1])
+			}
+			pin.Commit = strings.ToLower(fields[1])
+		}
+	}
+	if pin == nil {
+		return nil, fmt.Errorf("%w %s: no template ID", ErrMalformedVersionFile, path)
+	}
+	return pin, nil
+}
+
+// isFullCommit reports whether s is a full hex commit hash
+func isFullCommit(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
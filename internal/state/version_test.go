@@ -0,0 +1,85 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadVersionPin(t *testing.T) {
+	commit := "1234567890abcdef1234567890abcdef12345678"
+
+	tests := []struct {
+		name    string
+		content string
+		want    *VersionPin
+		wantErr string
+	}{
+		{
+			name:    "template and commit",
+			content: "main " + commit + "\n",
+			want:    &VersionPin{TemplateID: "main", Commit: commit},
+		},
+		{
+			name:    "template only with comments",
+			content: "# Installed by everyone on the team\n\nccr   # follows the registry\n",
+			want:    &VersionPin{TemplateID: "ccr"},
+		},
+		{
+			name:    "upper case commit",
+			content: "main " + strings.ToUpper(commit),
+			want:    &VersionPin{TemplateID: "main", Commit: commit},
+		},
+		{
+			name:    "empty",
+			content: "# nothing pinned\n",
+			wantErr: "no template ID",
+		},
+		{
+			name:    "short commit",
+			content: "main 1234567\n",
+			wantErr: "must be a full 40-character hex string",
+		},
+		{
+			name:    "extra fields",
+			content: "main " + commit + " extra\n",
+			wantErr: "line 1 must be a template ID and an optional commit",
+		},
+		{
+			name:    "second template",
+			content: "main\nccr\n",
+			wantErr: "line 2 pins a second template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetDir := t.TempDir()
+			if err := os.WriteFile(VersionFilePath(targetDir), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadVersionPin(targetDir)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrMalformedVersionFile) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadVersionPin() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadVersionPin() error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("ReadVersionPin() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadVersionPin_Missing(t *testing.T) {
+	pin, err := ReadVersionPin(t.TempDir())
+	if err != nil || pin != nil {
+		t.Errorf("ReadVersionPin() = %v, %v; want nil, nil", pin, err)
+	}
+}
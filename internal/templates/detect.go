@@ -0,0 +1,88 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// languageMarkers lists, in order of precedence, the files at the top of a
+// project that identify its language. Patterns are matched with filepath.Glob.
+// TypeScript comes before JavaScript since its projects have a package.json too.
+var languageMarkers = []struct {
+	language string
+	patterns []string
+}{
+	{"go", []string{"go.mod"}},
+	{"rust", []string{"Cargo.toml"}},
+	{"python", []string{"pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", "Pipfile"}},
+	{"typescript", []string{"tsconfig.json"}},
+	{"javascript", []string{"package.json"}},
+	{"ruby", []string{"Gemfile"}},
+	{"java", []string{"pom.xml", "build.gradle", "build.gradle.kts"}},
+	{"csharp", []string{"*.csproj", "*.sln"}},
+	{"php", []string{"composer.json"}},
+	{"elixir", []string{"mix.exs"}},
+	{"scala", []string{"build.sbt"}},
+	{"swift", []string{"Package.swift"}},
+}
+
+// DetectLanguage guesses the language of the project in dir from the build
+// files at its top level, such as go.mod for Go or Cargo.toml for Rust, and
+// returns one of KnownLanguages. Only the first match in languageMarkers
+// counts, so a Go service with a package.json for its front end is Go. It
+// returns "" when no marker is found.
+func DetectLanguage(dir string) string {
+	for _, marker := range languageMarkers {
+		for _, pattern := range marker.patterns {
+			matches, err := filepath.Glob(filepath.Join(dir, pattern))
+			if err != nil {
+				continue
+			}
+			for _, match := range matches {
+				if info, err := os.Stat(match); err == nil && !info.IsDir() {
+					return marker.language
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// GetDefaultTemplateForDir returns the default template for the project in
+// dir: an active template written for the language DetectLanguage finds, the
+// one tagged default when there are several, and otherwise the first by ID.
+// When the language is unknown or no template is written for it, this is
+// GetDefaultTemplate.
+func GetDefaultTemplateForDir(dir string) (Template, error) {
+	language := DetectLanguage(dir)
+	if language == "" {
+		return GetDefaultTemplate()
+	}
+
+	var candidates []Template
+	for _, template := range FilterTemplatesByLanguage(language) {
+		// Language-agnostic templates match too; the generic default covers them
+		if template.Language == language && template.IsValid() == nil {
+			candidates = append(candidates, template)
+		}
+	}
+	if len(candidates) == 0 {
+		return GetDefaultTemplate()
+	}
+
+	for _, template := range candidates {
+		if template.HasTag("default") {
+			return template, nil
+		}
+	}
+	return candidates[0], nil
+}
+
+// GetTemplateOrDefaultForDir retrieves a template by ID, or the default for
+// the project in dir, as GetDefaultTemplateForDir picks it, when id is empty
+func GetTemplateOrDefaultForDir(id, dir string) (Template, error) {
+	if id == "" {
+		return GetDefaultTemplateForDir(dir)
+	}
+	return GetTemplate(id)
+}
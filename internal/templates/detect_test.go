@@ -0,0 +1,133 @@
+package templates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		dirs  []string
+		want  string
+	}{
+		{name: "empty", want: ""},
+		{name: "go", files: []string{"go.mod"}, want: "go"},
+		{name: "go wins over a front end", files: []string{"package.json", "go.mod"}, want: "go"},
+		{name: "typescript before javascript", files: []string{"package.json", "tsconfig.json"}, want: "typescript"},
+		{name: "javascript", files: []string{"package.json"}, want: "javascript"},
+		{name: "python", files: []string{"requirements.txt"}, want: "python"},
+		{name: "csharp by glob", files: []string{"App.csproj"}, want: "csharp"},
+		{name: "directory is not a marker", dirs: []string{"go.mod"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, name := range tt.dirs {
+				if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := DetectLanguage(dir); got != tt.want {
+				t.Errorf("DetectLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDefaultTemplateForDir(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	template := func(id, language string, tags ...string) Template {
+		return Template{ID: id, Name: id, RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit, Language: language, Tags: tags}
+	}
+	deprecated := template("old-rust", "rust")
+	deprecated.Deprecated = true
+	Registry.Set(map[string]Template{
+		"main":     template("main", ""),
+		"go-a":     template("go-a", "go"),
+		"go-b":     template("go-b", "go", "default"),
+		"python":   template("python", "python"),
+		"old-rust": deprecated,
+	})
+
+	tests := []struct {
+		name   string
+		marker string
+		want   string
+	}{
+		{name: "no marker", want: "main"},
+		{name: "tagged default among several", marker: "go.mod", want: "go-b"},
+		{name: "only match", marker: "pyproject.toml", want: "python"},
+		{name: "no template for the language", marker: "Gemfile", want: "main"},
+		{name: "deprecated templates are skipped", marker: "Cargo.toml", want: "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.marker != "" {
+				if err := os.WriteFile(filepath.Join(dir, tt.marker), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := GetDefaultTemplateForDir(dir)
+			if err != nil {
+				t.Fatalf("GetDefaultTemplateForDir() error = %v", err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("GetDefaultTemplateForDir() = %s, want %s", got.ID, tt.want)
+			}
+		})
+	}
+
+	// Detection never changes the plain default
+	if got, err := GetDefaultTemplate(); err != nil || got.ID != "main" {
+		t.Errorf("GetDefaultTemplate() = %s, %v; want main", got.ID, err)
+	}
+}
+
+func TestGetTemplateOrDefaultForDir(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	Registry.Set(map[string]Template{
+		"main": {ID: "main", Name: "main", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit},
+		"go":   {ID: "go", Name: "go", RepoURL: "https://example.com/repo.git", Branch: "go", Commit: commit, Language: "go"},
+	})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	template, err := GetTemplateOrDefaultForDir("", dir)
+	if err != nil || template.ID != "go" {
+		t.Errorf("GetTemplateOrDefaultForDir(\"\") = %q, %v; want go", template.ID, err)
+	}
+
+	// A given ID is used whatever the project's language
+	template, err = GetTemplateOrDefaultForDir("main", dir)
+	if err != nil || template.ID != "main" {
+		t.Errorf("GetTemplateOrDefaultForDir(main) = %q, %v; want main", template.ID, err)
+	}
+
+	// An unknown ID fails just as it does with GetTemplate
+	_, err = GetTemplateOrDefaultForDir("nonexistent", dir)
+	_, want := GetTemplate("nonexistent")
+	if !errors.Is(err, ErrNotFound) || err.Error() != want.Error() {
+		t.Errorf("GetTemplateOrDefaultForDir(nonexistent) error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// GuardedRegistry holds a set of templates keyed by ID and is safe for
+// concurrent use, so commands can read it while a registry file or URL is
+// still being merged in. The templates it hands out are deep copies, down to
+// their slices and variables; changing one does not change the registry.
+type GuardedRegistry struct {
+	mu        sync.RWMutex
+	templates map[string]Template
+}
+
+// NewGuardedRegistry creates a registry holding a copy of templates
+func NewGuardedRegistry(templates map[string]Template) *GuardedRegistry {
+	return &GuardedRegistry{templates: cloneTemplates(templates)}
+}
+
+// Get returns the template with the given ID, and whether there is one
+func (r *GuardedRegistry) Get(id string) (Template, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	template, exists := r.templates[id]
+	return template.clone(), exists
+}
+
+// List returns every template, sorted by ID
+func (r *GuardedRegistry) List() []Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	templates := make([]Template, 0, len(r.templates))
+	for _, template := range r.templates {
+		templates = append(templates, template.clone())
+	}
+	slices.SortFunc(templates, func(a, b Template) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return templates
+}
+
+// Snapshot returns a copy of the templates keyed by ID, which the caller may
+// modify freely
+func (r *GuardedRegistry) Snapshot() map[string]Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return cloneTemplates(r.templates)
+}
+
+// Set replaces every template with a copy of templates
+func (r *GuardedRegistry) Set(templates map[string]Template) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates = cloneTemplates(templates)
+}
+
+// Update replaces the templates with the ones fn returns for the current
+// set, holding the registry for the whole call so concurrent loads cannot
+// lose each other's changes. fn must not modify current or call back into the
+// registry. When fn fails the registry is left unchanged.
+func (r *GuardedRegistry) Update(fn func(current map[string]Template) (map[string]Template, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	updated, err := fn(r.templates)
+	if err != nil {
+		return err
+	}
+	r.templates = updated
+	return nil
+}
+
+// cloneTemplates deep-copies a set of templates keyed by ID
+func cloneTemplates(templates map[string]Template) map[string]Template {
+	if templates == nil {
+		return nil
+	}
+	cloned := make(map[string]Template, len(templates))
+	for id, template := range templates {
+		cloned[id] = template.clone()
+	}
+	return cloned
+}
+
+// clone returns a copy of the template sharing no slices or maps with it
+func (t Template) clone() Template {
+	t.Tags = slices.Clone(t.Tags)
+	t.ExcludePatterns = slices.Clone(t.ExcludePatterns)
+	t.IncludePatterns = slices.Clone(t.IncludePatterns)
+	t.PostInstall = slices.Clone(t.PostInstall)
+	t.Variables = maps.Clone(t.Variables)
+	return t
+}
@@ -0,0 +1,133 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestGuardedRegistry(t *testing.T) {
+	source := map[string]Template{
+		"main":  {ID: "main", Name: "Main"},
+		"extra": {ID: "extra", Name: "Extra"},
+	}
+	registry := NewGuardedRegistry(source)
+
+	// The registry keeps its own copy
+	source["other"] = Template{ID: "other"}
+	if _, exists := registry.Get("other"); exists {
+		t.Error("Expected changes to the source map not to reach the registry")
+	}
+
+	if template, exists := registry.Get("main"); !exists || template.Name != "Main" {
+		t.Errorf("Get(main) = %+v, %v", template, exists)
+	}
+	if _, exists := registry.Get("missing"); exists {
+		t.Error("Expected Get(missing) to report no template")
+	}
+
+	list := registry.List()
+	if len(list) != 2 || list[0].ID != "extra" || list[1].ID != "main" {
+		t.Errorf("List() = %+v, want extra then main", list)
+	}
+
+	// Nor do changes to the slices and variables of a returned template
+	registry.Set(map[string]Template{"main": {ID: "main", Name: "Main", Tags: []string{"general"}, Variables: map[string]Variable{"Team": {Default: "core"}}}})
+	got, _ := registry.Get("main")
+	got.Tags[0] = "changed"
+	got.Variables["Team"] = Variable{Default: "changed"}
+	listed := registry.List()[0]
+	listed.Tags[0] = "changed"
+	registry.Snapshot()["main"].Variables["Other"] = Variable{}
+	if template, _ := registry.Get("main"); template.Tags[0] != "general" || template.Variables["Team"].Default != "core" || len(template.Variables) != 1 {
+		t.Errorf("Expected returned templates to share nothing with the registry, got %+v", template)
+	}
+	registry.Set(source)
+
+	snapshot := registry.Snapshot()
+	delete(snapshot, "main")
+	if _, exists := registry.Get("main"); !exists {
+		t.Error("Expected changes to a snapshot not to reach the registry")
+	}
+
+	registry.Set(map[string]Template{"only": {ID: "only"}})
+	if list := registry.List(); len(list) != 1 || list[0].ID != "only" {
+		t.Errorf("List() after Set = %+v, want only", list)
+	}
+
+	err := registry.Update(func(current map[string]Template) (map[string]Template, error) {
+		return nil, fmt.Errorf("rejected")
+	})
+	if err == nil {
+		t.Fatal("Expected Update() to return the error from fn")
+	}
+	if list := registry.List(); len(list) != 1 || list[0].ID != "only" {
+		t.Errorf("Expected a failed Update to leave the registry alone, got %+v", list)
+	}
+}
+
+// Run with -race: readers go through the accessors while registry files are merged in
+func TestRegistry_ConcurrentAccessDuringLoad(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	dir := t.TempDir()
+	paths := make([]string, 4)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("registry-%d.yaml", i))
+		content := fmt.Sprintf(`templates:
+  loaded-%d:
+    name: Loaded %d
+    repo_url: https://example.com/repo.git
+    branch: main
+    commit: 1234567890abcdef1234567890abcdef12345678
+`, i, i)
+		if err := os.WriteFile(paths[i], []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write registry file: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_ = ListTemplates()
+				_ = GetTemplateIDs()
+				if _, err := GetTemplate(DefaultTemplateID); err != nil {
+					t.Errorf("GetTemplate(%s) error = %v", DefaultTemplateID, err)
+					return
+				}
+			}
+		}()
+	}
+
+	var loads sync.WaitGroup
+	for _, path := range paths {
+		loads.Add(1)
+		go func() {
+			defer loads.Done()
+			if _, err := LoadRegistryFile(path, false); err != nil {
+				t.Errorf("LoadRegistryFile(%s) error = %v", path, err)
+			}
+		}()
+	}
+	loads.Wait()
+	close(stop)
+	wg.Wait()
+
+	// Concurrent loads do not lose each other's templates
+	for i := range paths {
+		if _, exists := Registry.Get(fmt.Sprintf("loaded-%d", i)); !exists {
+			t.Errorf("Expected loaded-%d to be registered", i)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RegistryFile represents the on-disk format of a user-defined template registry.
+// Templates are keyed by ID; an entry without an explicit ID inherits its key.
+type RegistryFile struct {
+	Templates map[string]Template `json:"templates" yaml:"templates"`
+}
+
+// SkippedTemplate is a registry entry that was left out of Registry because it
+// is invalid
+type SkippedTemplate struct {
+	// ID is the entry's key in its registry document
+	ID string
+
+	// Source is the file or URL the entry was read from
+	Source string
+
+	// Err holds every problem found with the entry, joined
+	Err error
+}
+
+// Skipped records the entries left out by every registry loaded so far, so
+// they can be reported in full rather than only as load warnings
+var Skipped []SkippedTemplate
+
+// LoadRegistryFile reads user-defined templates from path and merges them into Registry.
+// Invalid entries are skipped and reported as warnings. When allowOverride is false,
+// an entry whose ID collides with an existing template is treated as an error.
+func LoadRegistryFile(path string, allowOverride bool) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file %s: %w", path, err)
+	}
+
+	file, err := parseRegistry(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry file %s: %w", path, err)
+	}
+
+	return mergeRegistry(file, path, allowOverride)
+}
+
+// ParseRegistry decodes a registry document without validating or loading its
+// templates, giving each template without an ID its key
+func ParseRegistry(data []byte) (map[string]Template, error) {
+	file, err := parseRegistry(data)
+	if err != nil {
+		return nil, err
+	}
+	for key, template := range file.Templates {
+		if template.ID == "" {
+			template.ID = key
+			file.Templates[key] = template
+		}
+	}
+	return file.Templates, nil
+}
+
+// parseRegistry decodes a registry document. A document that starts like a
+// JSON object must be valid JSON; anything else is read as YAML.
+func parseRegistry(data []byte) (*RegistryFile, error) {
+	var file RegistryFile
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+		return &file, nil
+	}
+
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// mergeRegistry validates the templates of a parsed registry document from
+// source and merges the valid ones into Registry
+func mergeRegistry(file *RegistryFile, source string, allowOverride bool) ([]string, error) {
+	// Process entries in a stable order so warnings are deterministic
+	keys := make([]string, 0, len(file.Templates))
+	for key := range file.Templates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	warnings := make([]string, 0)
+	var skipped []SkippedTemplate
+	accepted := make([]Template, 0, len(keys))
+	for _, key := range keys {
+		template := file.Templates[key]
+		if template.ID == "" {
+			template.ID = key
+		}
+
+		if template.ID != key {
+			err := fmt.Errorf("id '%s' does not match its key", template.ID)
+			warnings = append(warnings, fmt.Sprintf("skipping template '%s': %v", key, err))
+			skipped = append(skipped, SkippedTemplate{ID: key, Source: source, Err: err})
+			continue
+		}
+
+		if problems := template.fieldProblems(); len(problems) > 0 {
+			warnings = append(warnings, fmt.Sprintf("skipping template '%s': %v", key, problems[0]))
+			skipped = append(skipped, SkippedTemplate{ID: key, Source: source, Err: errors.Join(problems...)})
+			continue
+		}
+
+		accepted = append(accepted, template)
+	}
+
+	// Check and merge while holding the registry so concurrent readers never
+	// see half a file, and a conflict leaves Registry untouched. Skipped is
+	// only written here, under the same lock.
+	err := Registry.Update(func(current map[string]Template) (map[string]Template, error) {
+		Skipped = append(Skipped, skipped...)
+
+		// Without allowOverride, the file may only add new IDs
+		if !allowOverride {
+			overlay := make(map[string]Template, len(accepted))
+			for _, template := range accepted {
+				overlay[template.ID] = template
+			}
+			if _, conflicts, err := MergeRegistries(current, overlay, ErrorOnConflict); err != nil {
+				return nil, fmt.Errorf("template '%s' from %s conflicts with an existing template: %w", conflicts[0].ID, source, err)
+			}
+		}
+
+		// Replacements may point at built-in templates or other entries in the same file
+		known := make(map[string]Template, len(current)+len(accepted))
+		for id, template := range current {
+			known[id] = template
+		}
+		for _, template := range accepted {
+			known[template.ID] = template
+		}
+
+		valid := make(map[string]Template, len(accepted))
+		for _, template := range accepted {
+			if err := template.validateReplacement(known); err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipping template '%s': %v", template.ID, err))
+				Skipped = append(Skipped, SkippedTemplate{ID: template.ID, Source: source, Err: err})
+				continue
+			}
+			valid[template.ID] = template
+		}
+
+		merged, _, err := MergeRegistries(current, valid, OverlayWins)
+		return merged, err
+	})
+	return warnings, err
+}
@@ -0,0 +1,202 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withRegistrySnapshot restores the registry after a test mutates it
+func withRegistrySnapshot(t *testing.T) {
+	t.Helper()
+	snapshot := Registry.Snapshot()
+	skipped := Skipped
+	t.Cleanup(func() {
+		Registry.Set(snapshot)
+		Skipped = skipped
+	})
+}
+
+func writeRegistryFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "templates.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write registry file: %v", err)
+	}
+	return path
+}
+
+func TestLoadRegistryFile(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	path := writeRegistryFile(t, `
+templates:
+  custom:
+    name: Custom Template
+    description: A user-defined template
+    repo_url: https://example.com/custom.git
+    branch: main
+    commit: 1234567890abcdef1234567890abcdef12345678
+    tags: [custom, internal]
+  broken:
+    repo_url: https://example.com/broken.git
+    branch: main
+    commit: not-a-hash
+`)
+
+	Skipped = nil
+	warnings, err := LoadRegistryFile(path, false)
+	if err != nil {
+		t.Fatalf("LoadRegistryFile() error = %v", err)
+	}
+
+	if len(warnings) != 1 {
+		t.Errorf("LoadRegistryFile() got %d warnings, want 1: %v", len(warnings), warnings)
+	}
+
+	template, err := GetTemplate("custom")
+	if err != nil {
+		t.Fatalf("GetTemplate(custom) error = %v", err)
+	}
+	if template.ID != "custom" {
+		t.Errorf("Expected ID to default to key, got %q", template.ID)
+	}
+	if !template.HasTag("internal") {
+		t.Error("Expected custom template to carry its tags")
+	}
+
+	if _, err := GetTemplate("broken"); err == nil {
+		t.Error("Expected invalid template to be skipped")
+	}
+
+	// The skipped entry is recorded with every problem, not only the warned one
+	if len(Skipped) != 1 || Skipped[0].ID != "broken" || Skipped[0].Source != path {
+		t.Fatalf("Skipped = %v, want the broken entry from %s", Skipped, path)
+	}
+	if problems := strings.Split(Skipped[0].Err.Error(), "\n"); len(problems) != 2 {
+		t.Errorf("Expected the missing name and the bad commit, got %q", problems)
+	}
+
+	found := false
+	for _, listed := range ListTemplates() {
+		if listed.ID == "custom" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("ListTemplates() does not include user-defined template")
+	}
+}
+
+func TestLoadRegistryFile_Collision(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	content := `
+templates:
+  main:
+    name: Overridden Main
+    repo_url: https://example.com/fork.git
+    branch: main
+    commit: abcdefabcdefabcdefabcdefabcdefabcdefabcd
+  extra:
+    name: Extra Template
+    repo_url: https://example.com/extra.git
+    branch: main
+    commit: abcdefabcdefabcdefabcdefabcdefabcdefabcd
+`
+
+	t.Run("error without override", func(t *testing.T) {
+		path := writeRegistryFile(t, content)
+		if _, err := LoadRegistryFile(path, false); err == nil {
+			t.Fatal("Expected collision error, got nil")
+		}
+		if Registry.Snapshot()["main"].Name == "Overridden Main" {
+			t.Error("Built-in template was replaced despite collision error")
+		}
+		if _, exists := Registry.Get("extra"); exists {
+			t.Error("Registry was partially updated despite collision error")
+		}
+	})
+
+	t.Run("override allowed", func(t *testing.T) {
+		path := writeRegistryFile(t, content)
+		if _, err := LoadRegistryFile(path, true); err != nil {
+			t.Fatalf("LoadRegistryFile() error = %v", err)
+		}
+		if Registry.Snapshot()["main"].Name != "Overridden Main" {
+			t.Errorf("Expected main to be overridden, got %q", Registry.Snapshot()["main"].Name)
+		}
+	})
+}
+
+func TestLoadRegistryFile_Errors(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	if _, err := LoadRegistryFile(filepath.Join(t.TempDir(), "missing.yaml"), false); err == nil {
+		t.Error("Expected error for missing file")
+	}
+
+	path := writeRegistryFile(t, "templates: [this is: not valid")
+	if _, err := LoadRegistryFile(path, false); err == nil {
+		t.Error("Expected error for malformed file")
+	}
+
+	path = writeRegistryFile(t, `
+templates:
+  alias:
+    id: other
+    name: Mismatched
+    repo_url: https://example.com/repo.git
+    branch: main
+    commit: abcdefabcdefabcdefabcdefabcdefabcdefabcd
+`)
+	warnings, err := LoadRegistryFile(path, false)
+	if err != nil {
+		t.Fatalf("LoadRegistryFile() error = %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("Expected mismatched id to produce a warning, got %v", warnings)
+	}
+}
+
+func TestLoadRegistryFile_Replacement(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	path := writeRegistryFile(t, `
+templates:
+  legacy:
+    name: Legacy
+    repo_url: https://example.com/repo.git
+    branch: main
+    commit: abcdefabcdefabcdefabcdefabcdefabcdefabcd
+    deprecated: true
+    replaced_by: modern
+  modern:
+    name: Modern
+    repo_url: https://example.com/repo.git
+    branch: main
+    commit: abcdefabcdefabcdefabcdefabcdefabcdefabcd
+  dangling:
+    name: Dangling
+    repo_url: https://example.com/repo.git
+    branch: main
+    commit: abcdefabcdefabcdefabcdefabcdefabcdefabcd
+    replaced_by: nowhere
+`)
+
+	warnings, err := LoadRegistryFile(path, false)
+	if err != nil {
+		t.Fatalf("LoadRegistryFile() error = %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("Expected one warning for dangling replacement, got %v", warnings)
+	}
+
+	if Registry.Snapshot()["legacy"].ReplacedBy != "modern" {
+		t.Error("Expected replacement within the same file to be accepted")
+	}
+	if _, exists := Registry.Get("dangling"); exists {
+		t.Error("Expected template with unknown replacement to be skipped")
+	}
+}
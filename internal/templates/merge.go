@@ -0,0 +1,93 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergePolicy decides which template is kept when two registries being merged
+// both define an ID
+type MergePolicy int
+
+const (
+	// OverlayWins keeps the overlay's template, replacing the base's
+	OverlayWins MergePolicy = iota
+
+	// BaseWins keeps the base's template and ignores the overlay's
+	BaseWins
+
+	// ErrorOnConflict fails the merge if any ID is defined in both
+	ErrorOnConflict
+)
+
+// String returns the policy's name
+func (p MergePolicy) String() string {
+	switch p {
+	case OverlayWins:
+		return "overlay-wins"
+	case BaseWins:
+		return "base-wins"
+	case ErrorOnConflict:
+		return "error-on-conflict"
+	default:
+		return fmt.Sprintf("MergePolicy(%d)", int(p))
+	}
+}
+
+// ErrConflict is wrapped by the error MergeRegistries returns under
+// ErrorOnConflict
+var ErrConflict = errors.New("defined in both registries")
+
+// MergeConflict is a template ID that both registries of a merge define
+type MergeConflict struct {
+	ID      string
+	Base    Template // The base registry's template
+	Overlay Template // The overlay registry's template
+}
+
+// MergeRegistries merges the overlay registry into the base one and returns
+// the result as a new map, leaving both arguments untouched, along with every
+// ID both define, sorted. policy decides which template each conflict keeps;
+// under ErrorOnConflict any conflict fails the merge with an error wrapping
+// ErrConflict, and no merged registry is returned.
+func MergeRegistries(base, overlay map[string]Template, policy MergePolicy) (map[string]Template, []MergeConflict, error) {
+	merged := make(map[string]Template, len(base)+len(overlay))
+	for id, template := range base {
+		merged[id] = template
+	}
+
+	var conflicts []MergeConflict
+	for id, template := range overlay {
+		existing, exists := base[id]
+		if exists {
+			conflicts = append(conflicts, MergeConflict{ID: id, Base: existing, Overlay: template})
+			if policy == BaseWins {
+				continue
+			}
+		}
+		merged[id] = template
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].ID < conflicts[j].ID })
+
+	switch policy {
+	case OverlayWins, BaseWins:
+	case ErrorOnConflict:
+		if len(conflicts) > 0 {
+			ids := make([]string, len(conflicts))
+			for i, conflict := range conflicts {
+				ids[i] = "'" + conflict.ID + "'"
+			}
+			noun := "template"
+			if len(ids) > 1 {
+				noun = "templates"
+			}
+			return nil, conflicts, fmt.Errorf("%s %s %w", noun, strings.Join(ids, ", "), ErrConflict)
+		}
+	default:
+		return nil, conflicts, fmt.Errorf("unknown merge policy %s", policy)
+	}
+
+	return merged, conflicts, nil
+}
@@ -0,0 +1,100 @@
+package templates
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergeRegistries(t *testing.T) {
+	base := map[string]Template{
+		"main":   {ID: "main", Name: "Base main"},
+		"shared": {ID: "shared", Name: "Base shared"},
+	}
+	overlay := map[string]Template{
+		"shared": {ID: "shared", Name: "Overlay shared"},
+		"main":   {ID: "main", Name: "Overlay main"},
+		"extra":  {ID: "extra", Name: "Overlay extra"},
+	}
+
+	tests := []struct {
+		name      string
+		policy    MergePolicy
+		wantNames map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "overlay wins",
+			policy:    OverlayWins,
+			wantNames: map[string]string{"main": "Overlay main", "shared": "Overlay shared", "extra": "Overlay extra"},
+		},
+		{
+			name:      "base wins",
+			policy:    BaseWins,
+			wantNames: map[string]string{"main": "Base main", "shared": "Base shared", "extra": "Overlay extra"},
+		},
+		{
+			name:    "error on conflict",
+			policy:  ErrorOnConflict,
+			wantErr: true,
+		},
+		{
+			name:    "unknown policy",
+			policy:  MergePolicy(42),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts, err := MergeRegistries(base, overlay, tt.policy)
+
+			if len(conflicts) != 2 || conflicts[0].ID != "main" || conflicts[1].ID != "shared" {
+				t.Fatalf("Expected conflicts main and shared in order, got %+v", conflicts)
+			}
+			if conflicts[0].Base.Name != "Base main" || conflicts[0].Overlay.Name != "Overlay main" {
+				t.Errorf("Expected the conflict to hold both templates, got %+v", conflicts[0])
+			}
+
+			if tt.wantErr {
+				if err == nil || merged != nil {
+					t.Fatalf("Expected an error and no merged registry, got %v, %v", merged, err)
+				}
+				if tt.policy == ErrorOnConflict && !errors.Is(err, ErrConflict) {
+					t.Errorf("Expected the error to wrap ErrConflict, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeRegistries() error = %v", err)
+			}
+
+			if len(merged) != len(tt.wantNames) {
+				t.Errorf("Expected %d templates, got %d", len(tt.wantNames), len(merged))
+			}
+			for id, name := range tt.wantNames {
+				if merged[id].Name != name {
+					t.Errorf("merged[%s].Name = %q, want %q", id, merged[id].Name, name)
+				}
+			}
+		})
+	}
+
+	// Neither argument is modified
+	if len(base) != 2 || base["main"].Name != "Base main" || len(overlay) != 3 {
+		t.Errorf("Expected the inputs to be left untouched, got base %v, overlay %v", base, overlay)
+	}
+}
+
+func TestMergeRegistries_NoConflicts(t *testing.T) {
+	merged, conflicts, err := MergeRegistries(
+		map[string]Template{"main": {ID: "main"}},
+		map[string]Template{"extra": {ID: "extra"}},
+		ErrorOnConflict,
+	)
+	if err != nil {
+		t.Fatalf("MergeRegistries() error = %v", err)
+	}
+	if len(conflicts) != 0 || len(merged) != 2 {
+		t.Errorf("Expected two templates and no conflicts, got %v, %+v", merged, conflicts)
+	}
+}
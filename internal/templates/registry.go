@@ -1,8 +1,10 @@
 package templates
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"strings"
 )
 
 const (
@@ -13,8 +15,33 @@ const (
 	DefaultRepoURL = "https://github.com/Fomo-Driven-Development/strategic-claude-base.git"
 )
 
-// Registry holds all available templates
-var Registry = map[string]Template{
+// ErrNotFound is wrapped by the error for a template ID missing from the registry
+var ErrNotFound = errors.New("not found")
+
+// ErrNoTemplate is returned by GetTemplate for an empty template ID, such as a
+// blank positional argument
+var ErrNoTemplate = errors.New("no template specified")
+
+// NotFoundError is the error for a template ID missing from the registry. It
+// wraps ErrNotFound.
+type NotFoundError struct {
+	ID string
+}
+
+// Error implements the error interface
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("template '%s' %v", e.ID, ErrNotFound)
+}
+
+// Unwrap returns ErrNotFound
+func (e *NotFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+// Registry holds all available templates. Registry files and URLs are merged
+// into it at startup while other goroutines may be reading it, so it is only
+// accessed through its methods.
+var Registry = NewGuardedRegistry(map[string]Template{
 	"main": {
 		ID:          "main",
 		Name:        "Strategic Claude Basic",
@@ -45,13 +72,18 @@ var Registry = map[string]Template{
 		Language:    "",
 		Tags:        []string{"web", "explorer"},
 	},
-}
+})
 
-// GetTemplate retrieves a template by ID
+// GetTemplate retrieves a template by ID. An empty ID fails with ErrNoTemplate;
+// use GetTemplateOrDefaultForDir to fall back to a default template instead.
 func GetTemplate(id string) (Template, error) {
-	template, exists := Registry[id]
+	if id == "" {
+		return Template{}, ErrNoTemplate
+	}
+
+	template, exists := Registry.Get(id)
 	if !exists {
-		return Template{}, fmt.Errorf("template '%s' not found", id)
+		return Template{}, &NotFoundError{ID: id}
 	}
 
 	if err := template.IsValid(); err != nil {
@@ -68,17 +100,7 @@ func GetDefaultTemplate() (Template, error) {
 
 // ListTemplates returns all available templates, sorted by ID
 func ListTemplates() []Template {
-	templates := make([]Template, 0, len(Registry))
-	for _, template := range Registry {
-		templates = append(templates, template)
-	}
-
-	// Sort by ID for consistent ordering
-	sort.Slice(templates, func(i, j int) bool {
-		return templates[i].ID < templates[j].ID
-	})
-
-	return templates
+	return Registry.List()
 }
 
 // ListActiveTemplates returns all non-deprecated templates
@@ -95,13 +117,40 @@ func ListActiveTemplates() []Template {
 	return active
 }
 
-// FilterTemplatesByLanguage returns templates for a specific language
+// ListDeprecatedTemplates returns the deprecated templates ListActiveTemplates
+// leaves out, sorted by ID
+func ListDeprecatedTemplates() []Template {
+	templates := ListTemplates()
+	deprecated := make([]Template, 0)
+
+	for _, template := range templates {
+		if template.Deprecated {
+			deprecated = append(deprecated, template)
+		}
+	}
+
+	return deprecated
+}
+
+// FilterTemplatesByLanguage returns templates for a specific language, including
+// language-agnostic templates
 func FilterTemplatesByLanguage(language string) []Template {
+	return filterTemplatesByLanguage(language, false)
+}
+
+// FilterTemplatesByLanguageStrict returns only the templates written for a
+// specific language, leaving out language-agnostic templates
+func FilterTemplatesByLanguageStrict(language string) []Template {
+	return filterTemplatesByLanguage(language, true)
+}
+
+// filterTemplatesByLanguage returns the active templates matching language
+func filterTemplatesByLanguage(language string, strict bool) []Template {
 	templates := ListActiveTemplates()
 	filtered := make([]Template, 0)
 
 	for _, template := range templates {
-		if template.Language == "" || template.Language == language {
+		if template.MatchesLanguage(language, strict) {
 			filtered = append(filtered, template)
 		}
 	}
@@ -123,6 +172,207 @@ func FilterTemplatesByTag(tag string) []Template {
 	return filtered
 }
 
+// FilterTemplatesByTags returns templates matching the given tags. When matchAll is
+// true a template must have every tag; otherwise any single tag is enough.
+func FilterTemplatesByTags(tags []string, matchAll bool) []Template {
+	templates := ListActiveTemplates()
+	filtered := make([]Template, 0)
+
+	for _, template := range templates {
+		if template.MatchesTags(tags, matchAll) {
+			filtered = append(filtered, template)
+		}
+	}
+
+	return filtered
+}
+
+// MatchesTags reports whether the template satisfies the tag filter: every tag
+// when matchAll is set, otherwise any of them
+func (t *Template) MatchesTags(tags []string, matchAll bool) bool {
+	for _, tag := range tags {
+		hasTag := t.HasTag(tag)
+		if matchAll && !hasTag {
+			return false
+		}
+		if !matchAll && hasTag {
+			return true
+		}
+	}
+
+	// All tags matched (matchAll) or none matched (any)
+	return matchAll
+}
+
+// TagsIndex groups the active templates by each tag they carry, for views that
+// list templates by tag. Tags match case-insensitively, as in HasTag, so keys
+// are lowercase; each group is sorted by ID. Untagged templates are left out.
+func TagsIndex() map[string][]Template {
+	return GroupByTag(ListActiveTemplates())
+}
+
+// GroupByTag groups templates by each tag they carry, like TagsIndex, keeping
+// their order within each group
+func GroupByTag(templates []Template) map[string][]Template {
+	index := make(map[string][]Template)
+
+	for _, template := range templates {
+		seen := make(map[string]bool, len(template.Tags))
+		for _, tag := range template.Tags {
+			key := strings.ToLower(tag)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			index[key] = append(index[key], template)
+		}
+	}
+
+	return index
+}
+
+// AllTags returns the tags of the active templates, lowercase, unique, and sorted
+func AllTags() []string {
+	index := TagsIndex()
+	tags := make([]string, 0, len(index))
+	for tag := range index {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+	return tags
+}
+
+// SearchTemplates returns active templates whose ID, name, description, or tags
+// contain query (case-insensitive), sorted by ID
+func SearchTemplates(query string) []Template {
+	templates := ListActiveTemplates()
+	matches := make([]Template, 0)
+
+	for _, template := range templates {
+		if template.MatchesQuery(query) {
+			matches = append(matches, template)
+		}
+	}
+
+	return matches
+}
+
+// ResolveReplacement follows the ReplacedBy chain from id and returns the first
+// template that has no further replacement
+func ResolveReplacement(id string) (Template, error) {
+	visited := make(map[string]bool)
+
+	template, err := GetTemplate(id)
+	for err == nil && template.ReplacedBy != "" {
+		if visited[template.ID] {
+			return Template{}, fmt.Errorf("template replacement cycle detected at '%s'", template.ID)
+		}
+		visited[template.ID] = true
+		template, err = GetTemplate(template.ReplacedBy)
+	}
+
+	return template, err
+}
+
+// SuggestTemplate returns the active template whose ID is closest to id,
+// however far off, for pointing an installation whose template left the
+// registry at its likely successor
+func SuggestTemplate(id string) (Template, bool) {
+	var best Template
+	bestDistance := -1
+
+	for _, template := range ListActiveTemplates() {
+		distance := editDistance(strings.ToLower(id), strings.ToLower(template.ID))
+		if bestDistance < 0 || distance < bestDistance {
+			best = template
+			bestDistance = distance
+		}
+	}
+
+	return best, bestDistance >= 0
+}
+
+// Limits on the "did you mean" candidates SuggestTemplateIDs returns
+const (
+	maxSuggestions        = 3
+	maxSuggestionDistance = 3
+)
+
+// SuggestTemplateIDs returns up to three active template IDs within a small
+// edit distance of id, closest first, for "did you mean" hints on an unknown
+// ID. IDs no closer than rewriting id entirely are left out.
+func SuggestTemplateIDs(id string) []string {
+	type candidate struct {
+		id       string
+		distance int
+	}
+
+	var candidates []candidate
+	for _, template := range ListActiveTemplates() {
+		distance := editDistance(strings.ToLower(id), strings.ToLower(template.ID))
+		if distance <= maxSuggestionDistance && distance < len(id) {
+			candidates = append(candidates, candidate{id: template.ID, distance: distance})
+		}
+	}
+
+	// ListActiveTemplates is sorted, so equally close IDs stay in order
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	suggestions := make([]string, 0, min(len(candidates), maxSuggestions))
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		suggestions = append(suggestions, c.id)
+	}
+	return suggestions
+}
+
+// editDistance computes the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// FindTemplateByBranch returns the template that follows branch. It is an
+// error when no template, or more than one, uses that branch.
+func FindTemplateByBranch(branch string) (Template, error) {
+	var matches []Template
+	for _, template := range ListTemplates() {
+		if template.Branch == branch {
+			matches = append(matches, template)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Template{}, fmt.Errorf("template with branch '%s' %w", branch, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, template := range matches {
+		ids = append(ids, template.ID)
+	}
+	return Template{}, fmt.Errorf("branch '%s' is used by %d templates: %s", branch, len(matches), strings.Join(ids, ", "))
+}
+
 // ValidateTemplateID checks if a template ID exists and is valid
 func ValidateTemplateID(id string) error {
 	_, err := GetTemplate(id)
@@ -131,11 +381,10 @@ func ValidateTemplateID(id string) error {
 
 // GetTemplateIDs returns a list of all template IDs
 func GetTemplateIDs() []string {
-	ids := make([]string, 0, len(Registry))
-	for id := range Registry {
-		ids = append(ids, id)
+	templates := Registry.List()
+	ids := make([]string, 0, len(templates))
+	for _, template := range templates {
+		ids = append(ids, template.ID)
 	}
-
-	sort.Strings(ids)
 	return ids
 }
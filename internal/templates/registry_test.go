@@ -1,14 +1,17 @@
 package templates
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
 func TestGetTemplate(t *testing.T) {
 	tests := []struct {
-		name    string
-		id      string
-		wantErr bool
+		name      string
+		id        string
+		wantErr   bool
+		wantErrIs error
 	}{
 		{
 			name:    "get main template",
@@ -26,14 +29,16 @@ func TestGetTemplate(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "get non-existent template",
-			id:      "nonexistent",
-			wantErr: true,
+			name:      "get non-existent template",
+			id:        "nonexistent",
+			wantErr:   true,
+			wantErrIs: ErrNotFound,
 		},
 		{
-			name:    "empty id",
-			id:      "",
-			wantErr: true,
+			name:      "empty id",
+			id:        "",
+			wantErr:   true,
+			wantErrIs: ErrNoTemplate,
 		},
 	}
 
@@ -51,11 +56,28 @@ func TestGetTemplate(t *testing.T) {
 				if err := got.IsValid(); err != nil {
 					t.Errorf("GetTemplate() returned invalid template: %v", err)
 				}
+			} else if !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("GetTemplate() error = %v, want it to wrap %v", err, tt.wantErrIs)
 			}
 		})
 	}
 }
 
+func TestGetTemplate_EmptyID(t *testing.T) {
+	_, err := GetTemplate("")
+	if !errors.Is(err, ErrNoTemplate) || errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTemplate(\"\") error = %v, want ErrNoTemplate", err)
+	}
+
+	// Even a registry entry stored under an empty ID is not looked up
+	original := Registry.Snapshot()
+	defer Registry.Set(original)
+	Registry.Set(map[string]Template{"": {ID: "", Name: "Blank"}})
+	if _, err := GetTemplate(""); !errors.Is(err, ErrNoTemplate) {
+		t.Errorf("GetTemplate(\"\") error = %v, want ErrNoTemplate", err)
+	}
+}
+
 func TestGetDefaultTemplate(t *testing.T) {
 	template, err := GetDefaultTemplate()
 	if err != nil {
@@ -115,6 +137,25 @@ func TestListActiveTemplates(t *testing.T) {
 	}
 }
 
+func TestListDeprecatedTemplates(t *testing.T) {
+	original := Registry.Snapshot()
+	t.Cleanup(func() { Registry.Set(original) })
+
+	Registry.Set(map[string]Template{
+		"new":     {ID: "new", Name: "New", RepoURL: "https://example.com/new.git", FollowBranch: true},
+		"old":     {ID: "old", Name: "Old", RepoURL: "https://example.com/old.git", FollowBranch: true, Deprecated: true, ReplacedBy: "new"},
+		"retired": {ID: "retired", Name: "Retired", RepoURL: "https://example.com/retired.git", FollowBranch: true, Deprecated: true, DeprecationNote: "No longer maintained"},
+	})
+
+	var ids []string
+	for _, template := range ListDeprecatedTemplates() {
+		ids = append(ids, template.ID)
+	}
+	if strings.Join(ids, ",") != "old,retired" {
+		t.Errorf("ListDeprecatedTemplates() = %v, want [old retired]", ids)
+	}
+}
+
 func TestValidateTemplateID(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -231,6 +272,47 @@ func TestFilterTemplatesByLanguage(t *testing.T) {
 	}
 }
 
+func TestFilterTemplatesByLanguageStrict(t *testing.T) {
+	original := Registry.Snapshot()
+	t.Cleanup(func() { Registry.Set(original) })
+
+	Registry.Set(map[string]Template{
+		"any":     {ID: "any"},
+		"go":      {ID: "go", Language: "go"},
+		"python":  {ID: "python", Language: "python"},
+		"retired": {ID: "retired", Language: "go", Deprecated: true},
+	})
+
+	tests := []struct {
+		name     string
+		language string
+		strict   bool
+		wantIDs  []string
+	}{
+		{name: "lenient includes agnostic", language: "go", strict: false, wantIDs: []string{"any", "go"}},
+		{name: "strict excludes agnostic", language: "go", strict: true, wantIDs: []string{"go"}},
+		{name: "strict with no match", language: "rust", strict: true, wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates := FilterTemplatesByLanguage(tt.language)
+			if tt.strict {
+				templates = FilterTemplatesByLanguageStrict(tt.language)
+			}
+
+			gotIDs := make([]string, 0, len(templates))
+			for _, template := range templates {
+				gotIDs = append(gotIDs, template.ID)
+			}
+
+			if strings.Join(gotIDs, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("filter by language %q (strict %v) = %v, want %v", tt.language, tt.strict, gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
 func TestFilterTemplatesByTag(t *testing.T) {
 	tests := []struct {
 		name string
@@ -263,3 +345,275 @@ func TestFilterTemplatesByTag(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterTemplatesByTags(t *testing.T) {
+	original := Registry.Snapshot()
+	t.Cleanup(func() { Registry.Set(original) })
+
+	Registry.Set(map[string]Template{
+		"web":     {ID: "web", Tags: []string{"web", "workflow"}},
+		"cli":     {ID: "cli", Tags: []string{"cli", "workflow"}},
+		"api":     {ID: "api", Tags: []string{"web", "api"}},
+		"retired": {ID: "retired", Tags: []string{"web", "workflow"}, Deprecated: true},
+	})
+
+	tests := []struct {
+		name     string
+		tags     []string
+		matchAll bool
+		wantIDs  []string
+	}{
+		{
+			name:     "match all",
+			tags:     []string{"web", "workflow"},
+			matchAll: true,
+			wantIDs:  []string{"web"},
+		},
+		{
+			name:     "match any",
+			tags:     []string{"web", "workflow"},
+			matchAll: false,
+			wantIDs:  []string{"api", "cli", "web"},
+		},
+		{
+			name:     "case insensitive",
+			tags:     []string{"API"},
+			matchAll: true,
+			wantIDs:  []string{"api"},
+		},
+		{
+			name:     "no tags with match all",
+			tags:     nil,
+			matchAll: true,
+			wantIDs:  []string{"api", "cli", "web"},
+		},
+		{
+			name:     "no tags with match any",
+			tags:     nil,
+			matchAll: false,
+			wantIDs:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates := FilterTemplatesByTags(tt.tags, tt.matchAll)
+
+			gotIDs := make([]string, 0, len(templates))
+			for _, template := range templates {
+				gotIDs = append(gotIDs, template.ID)
+			}
+
+			if strings.Join(gotIDs, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("FilterTemplatesByTags() = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestTagsIndex(t *testing.T) {
+	original := Registry.Snapshot()
+	t.Cleanup(func() { Registry.Set(original) })
+
+	Registry.Set(map[string]Template{
+		"web":      {ID: "web", Tags: []string{"web", "workflow"}},
+		"cli":      {ID: "cli", Tags: []string{"CLI", "Workflow", "workflow"}},
+		"api":      {ID: "api", Tags: []string{"web", "api"}},
+		"untagged": {ID: "untagged"},
+		"retired":  {ID: "retired", Tags: []string{"web", "legacy"}, Deprecated: true},
+	})
+
+	want := map[string][]string{
+		"api":      {"api"},
+		"cli":      {"cli"},
+		"web":      {"api", "web"},
+		"workflow": {"cli", "web"},
+	}
+
+	index := TagsIndex()
+	if len(index) != len(want) {
+		t.Errorf("TagsIndex() has tags %v, want %v", AllTags(), want)
+	}
+	for tag, wantIDs := range want {
+		gotIDs := make([]string, 0, len(index[tag]))
+		for _, template := range index[tag] {
+			gotIDs = append(gotIDs, template.ID)
+		}
+		if strings.Join(gotIDs, ",") != strings.Join(wantIDs, ",") {
+			t.Errorf("TagsIndex()[%q] = %v, want %v", tag, gotIDs, wantIDs)
+		}
+	}
+
+	if got := strings.Join(AllTags(), ","); got != "api,cli,web,workflow" {
+		t.Errorf("AllTags() = %s, want api,cli,web,workflow", got)
+	}
+}
+
+func TestFindTemplateByBranch(t *testing.T) {
+	// The built-in templates share a repository and differ by branch
+	template, err := FindTemplateByBranch("web-explorer")
+	if err != nil || template.ID != "web-explorer" {
+		t.Errorf("FindTemplateByBranch(web-explorer) = %q, %v; want web-explorer", template.ID, err)
+	}
+
+	if _, err := FindTemplateByBranch("no-such-branch"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unused branch, got %v", err)
+	}
+
+	original := Registry.Snapshot()
+	t.Cleanup(func() { Registry.Set(original) })
+	Registry.Set(map[string]Template{
+		"a": {ID: "a", Branch: "shared"},
+		"b": {ID: "b", Branch: "shared"},
+	})
+	if _, err := FindTemplateByBranch("shared"); err == nil || !strings.Contains(err.Error(), "a, b") {
+		t.Errorf("Expected an error naming both templates on a shared branch, got %v", err)
+	}
+}
+
+func TestSuggestTemplate(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"mian", "main"},
+		{"CCR", "ccr"},
+		{"web-explore", "web-explorer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, ok := SuggestTemplate(tt.id)
+			if !ok {
+				t.Fatal("SuggestTemplate() found no suggestion")
+			}
+			if got.ID != tt.want {
+				t.Errorf("SuggestTemplate(%q) = %q, want %q", tt.id, got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestTemplateIDs(t *testing.T) {
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{"web-explore", []string{"web-explorer"}},
+		{"mian", []string{"main"}},
+		{"CCR", []string{"ccr"}},
+		{"xyz", []string{}},
+		{"completely-unrelated", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := SuggestTemplateIDs(tt.id); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SuggestTemplateIDs(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("at most three, closest first", func(t *testing.T) {
+		original := Registry.Snapshot()
+		t.Cleanup(func() { Registry.Set(original) })
+		registry := map[string]Template{}
+		for _, id := range []string{"tpl-api", "tpl1", "tpl2", "tpl3", "tpl4", "unrelated"} {
+			registry[id] = Template{ID: id}
+		}
+		Registry.Set(registry)
+
+		got := SuggestTemplateIDs("tpl")
+		if strings.Join(got, ",") != "tpl1,tpl2,tpl3" {
+			t.Errorf("SuggestTemplateIDs(\"tpl\") = %v, want [tpl1 tpl2 tpl3]", got)
+		}
+	})
+
+	t.Run("deprecated templates are not suggested", func(t *testing.T) {
+		original := Registry.Snapshot()
+		t.Cleanup(func() { Registry.Set(original) })
+		Registry.Set(map[string]Template{
+			"legacy": {ID: "legacy", Deprecated: true},
+			"legend": {ID: "legend"},
+		})
+
+		if got := SuggestTemplateIDs("legac"); strings.Join(got, ",") != "legend" {
+			t.Errorf("SuggestTemplateIDs(\"legac\") = %v, want [legend]", got)
+		}
+	})
+}
+
+func TestSearchTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantIDs   []string
+		wantEmpty bool
+	}{
+		{name: "description match", query: "browser", wantIDs: []string{"web-explorer"}},
+		{name: "case insensitive name match", query: "CCR TEMPLATE", wantIDs: []string{"ccr"}},
+		{name: "tag match", query: "workflow", wantIDs: []string{"ccr"}},
+		{name: "no match", query: "no-such-template", wantEmpty: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := SearchTemplates(tt.query)
+
+			if tt.wantEmpty {
+				if len(results) != 0 {
+					t.Errorf("SearchTemplates(%q) = %d results, want none", tt.query, len(results))
+				}
+				return
+			}
+
+			for _, wantID := range tt.wantIDs {
+				found := false
+				for _, template := range results {
+					if template.ID == wantID {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("SearchTemplates(%q) missing %s", tt.query, wantID)
+				}
+			}
+
+			for i := 1; i < len(results); i++ {
+				if results[i-1].ID > results[i].ID {
+					t.Errorf("SearchTemplates() results not sorted by ID")
+				}
+			}
+		})
+	}
+}
+
+func TestResolveReplacement(t *testing.T) {
+	original := Registry.Snapshot()
+	t.Cleanup(func() { Registry.Set(original) })
+
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	newTemplate := func(id, replacedBy string) Template {
+		return Template{ID: id, Name: id, RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit, Deprecated: replacedBy != "", ReplacedBy: replacedBy}
+	}
+
+	Registry.Set(map[string]Template{
+		"old":     newTemplate("old", "older"),
+		"older":   newTemplate("older", "current"),
+		"current": newTemplate("current", ""),
+		"loop-a":  newTemplate("loop-a", "loop-b"),
+		"loop-b":  newTemplate("loop-b", "loop-a"),
+	})
+
+	got, err := ResolveReplacement("old")
+	if err != nil {
+		t.Fatalf("ResolveReplacement() error = %v", err)
+	}
+	if got.ID != "current" {
+		t.Errorf("ResolveReplacement() = %s, want current", got.ID)
+	}
+
+	if _, err := ResolveReplacement("loop-a"); err == nil {
+		t.Error("Expected cycle to be detected")
+	}
+}
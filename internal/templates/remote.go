@@ -0,0 +1,270 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxRegistrySize bounds how much of a remote registry document is read
+const maxRegistrySize = 10 << 20
+
+// defaultRegistryAttempts is the most times a registry fetch is tried when
+// RemoteOptions.Attempts is not set
+const defaultRegistryAttempts = 3
+
+// Backoff between fetch attempts doubles from registryRetryBaseDelay up to
+// registryRetryMaxDelay. They are variables so tests can shorten them.
+var (
+	registryRetryBaseDelay = 1 * time.Second
+	registryRetryMaxDelay  = 30 * time.Second
+)
+
+// RemoteOptions controls how a remote registry is fetched and cached
+type RemoteOptions struct {
+	// Directory holding the last fetched copy of each registry ("" disables caching)
+	CacheDir string
+
+	// How long a cached copy is used before fetching again
+	TTL time.Duration
+
+	// Limit on each attempt at fetching the document
+	Timeout time.Duration
+
+	// Most attempts at fetching the document when the connection fails or the
+	// server reports a temporary error (0 uses three)
+	Attempts int
+
+	// OnRetry receives a message before each retry (optional)
+	OnRetry func(message string)
+
+	// Offline never fetches the document: the cached copy is used however old
+	// it is, or only the templates already registered when there is none
+	Offline bool
+}
+
+// LoadRegistryURL fetches a registry document in the registry file format,
+// JSON or YAML, from an http(s) URL and merges its templates into Registry,
+// replacing built-in templates with the same ID so pins can be updated without
+// a new release.
+//
+// A copy younger than TTL is used without fetching. An older copy is fetched
+// again with its ETag, so an unchanged document is not downloaded. A fetch
+// that fails on the network or with a server error is retried with backoff.
+// When it still fails, or the document is malformed, the last cached copy is
+// used instead, or only the templates already registered when there is none;
+// either way it is reported as a warning rather than an error.
+func LoadRegistryURL(rawURL string, opts RemoteOptions) ([]string, error) {
+	if err := validateRegistryURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	cachePath := ""
+	if opts.CacheDir != "" {
+		sum := sha256.Sum256([]byte(rawURL))
+		cachePath = filepath.Join(opts.CacheDir, hex.EncodeToString(sum[:])[:16]+".registry")
+	}
+
+	var cached []byte
+	var meta registryCacheMeta
+	if cachePath != "" {
+		cached, meta = readRegistryCache(cachePath)
+	}
+
+	if cached != nil && (opts.Offline || time.Since(meta.FetchedAt) < opts.TTL) {
+		if file, err := parseRegistry(cached); err == nil {
+			return mergeRegistry(file, rawURL, true)
+		}
+	}
+	if opts.Offline {
+		return []string{fmt.Sprintf("offline and no cached copy of registry %s; using the built-in templates", rawURL)}, nil
+	}
+
+	etag := ""
+	if cached != nil {
+		etag = meta.ETag
+	}
+	fetched, fetchErr := fetchRegistryWithRetries(rawURL, etag, opts)
+	if fetchErr == nil && fetched.notModified {
+		if file, err := parseRegistry(cached); err == nil {
+			if cachePath != "" {
+				meta.FetchedAt = time.Now()
+				_ = writeRegistryMeta(cachePath, meta) // A failed write only costs a conditional fetch next time
+			}
+			return mergeRegistry(file, rawURL, true)
+		}
+		fetchErr = fmt.Errorf("server reported the document unchanged, but the cached copy is unreadable")
+	}
+	if fetchErr == nil {
+		if cachePath != "" {
+			// A failed cache write only costs a fetch next time
+			if writeRegistryCache(cachePath, fetched.data) == nil {
+				_ = writeRegistryMeta(cachePath, registryCacheMeta{URL: rawURL, ETag: fetched.etag, FetchedAt: time.Now()})
+			}
+		}
+		return mergeRegistry(fetched.file, rawURL, true)
+	}
+
+	if cached != nil {
+		if file, err := parseRegistry(cached); err == nil {
+			warnings, err := mergeRegistry(file, rawURL, true)
+			warning := fmt.Sprintf("could not fetch registry %s (%v); using the copy cached %s", rawURL, fetchErr, meta.FetchedAt.Format(time.RFC3339))
+			return append([]string{warning}, warnings...), err
+		}
+	}
+	return []string{fmt.Sprintf("could not fetch registry %s (%v); using the built-in templates", rawURL, fetchErr)}, nil
+}
+
+// validateRegistryURL checks that a registry URL can be fetched over HTTP
+func validateRegistryURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("registry URL must be an http or https URL: %s", rawURL)
+	}
+	return nil
+}
+
+// fetchedRegistry is the result of fetching a registry document: the raw
+// bytes for caching and the parsed document, or only notModified when the
+// server confirmed the cached copy's ETag
+type fetchedRegistry struct {
+	data        []byte
+	file        *RegistryFile
+	etag        string
+	notModified bool
+}
+
+// fetchRegistryWithRetries fetches a registry document, trying again with
+// backoff while the attempt fails in a way that retrying can fix
+func fetchRegistryWithRetries(rawURL, etag string, opts RemoteOptions) (*fetchedRegistry, error) {
+	attempts := opts.Attempts
+	if attempts <= 0 {
+		attempts = defaultRegistryAttempts
+	}
+
+	delay := registryRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		fetched, transient, err := fetchRegistry(rawURL, etag, opts.Timeout)
+		if err == nil || !transient || attempt >= attempts {
+			return fetched, err
+		}
+
+		if opts.OnRetry != nil {
+			opts.OnRetry(fmt.Sprintf("Attempt %d/%d failed fetching registry %s, retrying in %s: %v", attempt, attempts, rawURL, delay, err))
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, registryRetryMaxDelay)
+	}
+}
+
+// fetchRegistry downloads and parses a registry document, sending etag, when
+// there is one, for the server to confirm the cached copy instead. It reports
+// whether a failure is transient: a connection error or a server error, as
+// opposed to a missing or malformed document. A malformed document is rejected
+// as a whole.
+func fetchRegistry(rawURL, etag string, timeout time.Duration) (*fetchedRegistry, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && etag != "":
+		return &fetchedRegistry{etag: etag, notModified: true}, false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return nil, true, fmt.Errorf("server returned %s", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistrySize+1))
+	if err != nil {
+		return nil, true, err
+	}
+	if len(data) > maxRegistrySize {
+		return nil, false, fmt.Errorf("document is larger than %d bytes", maxRegistrySize)
+	}
+
+	file, err := parseRegistry(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("malformed registry document: %w", err)
+	}
+	return &fetchedRegistry{data: data, file: file, etag: resp.Header.Get("ETag")}, false, nil
+}
+
+// registryCacheMeta is stored next to a cached registry document, in the same
+// file name with a .meta suffix, for conditional requests once it goes stale
+type registryCacheMeta struct {
+	URL       string    `json:"url"`
+	ETag      string    `json:"etag,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// readRegistryCache returns the cached document at path and its metadata, or
+// nil when there is none. A copy cached without metadata, by an earlier
+// version, counts as fetched when it was written and has no ETag.
+func readRegistryCache(path string) ([]byte, registryCacheMeta) {
+	var meta registryCacheMeta
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, meta
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, meta
+	}
+
+	if raw, err := os.ReadFile(path + ".meta"); err != nil || json.Unmarshal(raw, &meta) != nil || meta.FetchedAt.IsZero() {
+		meta = registryCacheMeta{FetchedAt: info.ModTime()}
+	}
+	return data, meta
+}
+
+// writeRegistryMeta stores the metadata of the document cached at path
+func writeRegistryMeta(path string, meta registryCacheMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeRegistryCache(path+".meta", data)
+}
+
+// writeRegistryCache stores a fetched document, replacing the file in one step
+// so readers never see a partial copy
+func writeRegistryCache(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
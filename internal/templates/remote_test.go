@@ -0,0 +1,250 @@
+package templates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const remoteRegistryJSON = `{
+  "templates": {
+    "remote": {
+      "name": "Remote Template",
+      "repo_url": "https://example.com/remote.git",
+      "branch": "main",
+      "commit": "1234567890abcdef1234567890abcdef12345678"
+    },
+    "broken": {
+      "name": "Missing repository URL"
+    }
+  }
+}`
+
+// registryServer serves body, counting requests; it fails every request once down is set
+func registryServer(t *testing.T, body string, down *atomic.Bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// unregister removes id from the registry so a later load has to add it again
+func unregister(id string) {
+	current := Registry.Snapshot()
+	delete(current, id)
+	Registry.Set(current)
+}
+
+func TestLoadRegistryURL(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	var down atomic.Bool
+	server, requests := registryServer(t, remoteRegistryJSON, &down)
+	opts := RemoteOptions{CacheDir: t.TempDir(), TTL: time.Hour, Timeout: 5 * time.Second, Attempts: 1}
+
+	warnings, err := LoadRegistryURL(server.URL, opts)
+	if err != nil {
+		t.Fatalf("LoadRegistryURL() error = %v", err)
+	}
+	if _, ok := Registry.Get("remote"); !ok {
+		t.Fatal("Expected the remote template to be merged into the registry")
+	}
+	if _, ok := Registry.Get("broken"); ok || len(warnings) != 1 || !strings.Contains(warnings[0], "broken") {
+		t.Errorf("Expected the invalid entry to be skipped with a warning, got %v", warnings)
+	}
+
+	// A fresh cached copy is used without fetching
+	unregister("remote")
+	if _, err := LoadRegistryURL(server.URL, opts); err != nil {
+		t.Fatalf("LoadRegistryURL() from cache error = %v", err)
+	}
+	if _, ok := Registry.Get("remote"); !ok || requests.Load() != 1 {
+		t.Errorf("Expected the cached copy to be used, got %d requests", requests.Load())
+	}
+
+	// A stale copy is still used when the server is down
+	unregister("remote")
+	down.Store(true)
+	opts.TTL = 0
+	warnings, err = LoadRegistryURL(server.URL, opts)
+	if err != nil {
+		t.Fatalf("LoadRegistryURL() while offline error = %v", err)
+	}
+	if _, ok := Registry.Get("remote"); !ok || requests.Load() != 2 {
+		t.Errorf("Expected a fetch attempt falling back to the cached copy, got %d requests", requests.Load())
+	}
+	if len(warnings) == 0 || !strings.Contains(warnings[0], "using the copy cached") {
+		t.Errorf("Expected a warning about the cached copy, got %v", warnings)
+	}
+}
+
+func TestLoadRegistryURL_Offline(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	var down atomic.Bool
+	server, requests := registryServer(t, remoteRegistryJSON, &down)
+	opts := RemoteOptions{CacheDir: t.TempDir(), TTL: 0, Timeout: 5 * time.Second, Offline: true}
+
+	// Nothing is fetched, and without a cached copy the built-in templates are used
+	warnings, err := LoadRegistryURL(server.URL, opts)
+	if err != nil {
+		t.Fatalf("LoadRegistryURL() error = %v", err)
+	}
+	if _, ok := Registry.Get("remote"); ok || requests.Load() != 0 {
+		t.Errorf("Expected no fetch while offline, got %d requests", requests.Load())
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "offline and no cached copy") {
+		t.Errorf("Expected a warning about the missing copy, got %v", warnings)
+	}
+
+	// A cached copy is used however old it is
+	opts.Offline = false
+	if _, err := LoadRegistryURL(server.URL, opts); err != nil {
+		t.Fatalf("LoadRegistryURL() error = %v", err)
+	}
+	unregister("remote")
+	opts.Offline = true
+	if _, err := LoadRegistryURL(server.URL, opts); err != nil {
+		t.Fatalf("LoadRegistryURL() from cache error = %v", err)
+	}
+	if _, ok := Registry.Get("remote"); !ok || requests.Load() != 1 {
+		t.Errorf("Expected the stale cached copy to be used offline, got %d requests", requests.Load())
+	}
+}
+
+func TestLoadRegistryURL_RejectsMalformedDocument(t *testing.T) {
+	withRegistrySnapshot(t)
+	before := len(Registry.List())
+
+	var down atomic.Bool
+	server, _ := registryServer(t, `{"templates": {"half": {"name": "Half"`, &down)
+	cacheDir := t.TempDir()
+
+	warnings, err := LoadRegistryURL(server.URL, RemoteOptions{CacheDir: cacheDir, TTL: time.Hour, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("LoadRegistryURL() error = %v", err)
+	}
+	if len(Registry.List()) != before {
+		t.Errorf("Expected the registry to be left alone, got %d templates, want %d", len(Registry.List()), before)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "malformed registry document") || !strings.Contains(warnings[0], "built-in templates") {
+		t.Errorf("Expected a warning about the malformed document, got %v", warnings)
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected a malformed document not to be cached, found %v", entries)
+	}
+}
+
+func TestLoadRegistryURL_InvalidURL(t *testing.T) {
+	for _, rawURL := range []string{"ftp://example.com/templates.json", filepath.Join(t.TempDir(), "templates.json"), "https://"} {
+		if _, err := LoadRegistryURL(rawURL, RemoteOptions{}); err == nil {
+			t.Errorf("LoadRegistryURL(%q) expected an error", rawURL)
+		}
+	}
+}
+
+func TestLoadRegistryURL_Retries(t *testing.T) {
+	withRegistrySnapshot(t)
+	baseDelay, maxDelay := registryRetryBaseDelay, registryRetryMaxDelay
+	registryRetryBaseDelay, registryRetryMaxDelay = time.Millisecond, time.Millisecond
+	t.Cleanup(func() { registryRetryBaseDelay, registryRetryMaxDelay = baseDelay, maxDelay })
+
+	// The first two requests fail with a server error, then the document is served
+	var requests atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			http.Error(w, "unavailable", int(status.Load()))
+			return
+		}
+		_, _ = w.Write([]byte(remoteRegistryJSON))
+	}))
+	t.Cleanup(server.Close)
+
+	var retries []string
+	opts := RemoteOptions{Timeout: 5 * time.Second, Attempts: 3, OnRetry: func(message string) { retries = append(retries, message) }}
+	if _, err := LoadRegistryURL(server.URL, opts); err != nil {
+		t.Fatalf("LoadRegistryURL() error = %v", err)
+	}
+	if _, ok := Registry.Get("remote"); !ok || requests.Load() != 3 || len(retries) != 2 {
+		t.Errorf("Expected two retries before the document loaded, got %d requests and retries %v", requests.Load(), retries)
+	}
+
+	// A missing document is not retried
+	unregister("remote")
+	requests.Store(0)
+	status.Store(http.StatusNotFound)
+	warnings, err := LoadRegistryURL(server.URL, opts)
+	if err != nil {
+		t.Fatalf("LoadRegistryURL() error = %v", err)
+	}
+	if requests.Load() != 1 || len(warnings) != 1 || !strings.Contains(warnings[0], "404") {
+		t.Errorf("Expected one request and a warning for a missing document, got %d requests and %v", requests.Load(), warnings)
+	}
+}
+
+func TestLoadRegistryURL_ETag(t *testing.T) {
+	withRegistrySnapshot(t)
+
+	const etag = `"v1"`
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(remoteRegistryJSON))
+	}))
+	t.Cleanup(server.Close)
+
+	cacheDir := t.TempDir()
+	opts := RemoteOptions{CacheDir: cacheDir, TTL: time.Hour, Timeout: 5 * time.Second}
+	if _, err := LoadRegistryURL(server.URL, opts); err != nil {
+		t.Fatalf("LoadRegistryURL() error = %v", err)
+	}
+
+	metas, err := filepath.Glob(filepath.Join(cacheDir, "*.registry.meta"))
+	if err != nil || len(metas) != 1 {
+		t.Fatalf("Expected one metadata file next to the cached copy, got %v, %v", metas, err)
+	}
+	_, meta := readRegistryCache(strings.TrimSuffix(metas[0], ".meta"))
+	if meta.ETag != etag || meta.URL != server.URL || time.Since(meta.FetchedAt) > time.Minute {
+		t.Errorf("Expected the ETag, URL, and fetch time to be cached, got %+v", meta)
+	}
+
+	// A stale copy is confirmed by the server rather than downloaded again
+	unregister("remote")
+	opts.TTL = 0
+	if _, err := LoadRegistryURL(server.URL, opts); err != nil {
+		t.Fatalf("LoadRegistryURL() error = %v", err)
+	}
+	if _, ok := Registry.Get("remote"); !ok || requests.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("Expected a conditional request answered from the cache, got %d requests, %d not modified", requests.Load(), notModified.Load())
+	}
+	_, refreshed := readRegistryCache(strings.TrimSuffix(metas[0], ".meta"))
+	if !refreshed.FetchedAt.After(meta.FetchedAt) || refreshed.ETag != etag {
+		t.Errorf("Expected the fetch time to move forward, got %+v after %+v", refreshed, meta)
+	}
+}
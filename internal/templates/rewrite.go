@@ -0,0 +1,114 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetRegistryFields rewrites field values of templates in a registry document,
+// YAML or JSON, such as a new commit for a template whose branch moved on.
+// updates maps a template's key in the document to the values to set, by
+// field name (commit, tree_hash). Only the values themselves are replaced, so
+// comments, key order, and formatting are kept. Every field updated must
+// already be set in the document.
+func SetRegistryFields(data []byte, updates map[string]map[string]string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	entries := mappingValue(documentRoot(&doc), "templates")
+	if entries == nil {
+		return nil, fmt.Errorf("registry document has no templates")
+	}
+
+	type edit struct {
+		node  *yaml.Node
+		value string
+	}
+	var edits []edit
+	for id, fields := range updates {
+		entry := mappingValue(entries, id)
+		if entry == nil {
+			return nil, fmt.Errorf("template '%s' is not in the registry document", id)
+		}
+		for field, value := range fields {
+			node := mappingValue(entry, field)
+			if node == nil || node.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("template '%s' does not set %s", id, field)
+			}
+			edits = append(edits, edit{node: node, value: value})
+		}
+	}
+
+	// Later values first, so the positions of earlier ones stay valid
+	sort.Slice(edits, func(i, j int) bool {
+		a, b := edits[i].node, edits[j].node
+		return a.Line > b.Line || (a.Line == b.Line && a.Column > b.Column)
+	})
+	for _, e := range edits {
+		var err error
+		if data, err = replaceScalar(data, e.node, e.value); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// documentRoot returns the top-level node of a parsed document
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// mappingValue returns the value of key in a mapping node, or nil when node
+// is not a mapping or lacks the key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// replaceScalar replaces the text of a scalar node's value in data with value,
+// leaving any quotes around it
+func replaceScalar(data []byte, node *yaml.Node, value string) ([]byte, error) {
+	start := 0
+	for line := 1; line < node.Line; line++ {
+		next := bytes.IndexByte(data[start:], '\n')
+		if next < 0 {
+			return nil, fmt.Errorf("line %d is past the end of the document", node.Line)
+		}
+		start += next + 1
+	}
+	// Columns count characters, not bytes
+	for column := 1; column < node.Column && start < len(data); column++ {
+		_, size := utf8.DecodeRune(data[start:])
+		start += size
+	}
+
+	end := bytes.IndexByte(data[start:], '\n')
+	if end < 0 {
+		end = len(data) - start
+	}
+	offset := bytes.Index(data[start:start+end], []byte(node.Value))
+	if node.Value == "" || offset < 0 {
+		return nil, fmt.Errorf("value %q on line %d cannot be rewritten in place", node.Value, node.Line)
+	}
+	start += offset
+
+	replaced := make([]byte, 0, len(data)-len(node.Value)+len(value))
+	replaced = append(replaced, data[:start]...)
+	replaced = append(replaced, value...)
+	return append(replaced, data[start+len(node.Value):]...), nil
+}
@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetRegistryFields(t *testing.T) {
+	oldCommit := "1234567890abcdef1234567890abcdef12345678"
+	newCommit := "fedcba0987654321fedcba0987654321fedcba09"
+
+	tests := []struct {
+		name     string
+		document string
+		updates  map[string]map[string]string
+		want     string
+		wantErr  string
+	}{
+		{
+			name: "yaml keeps comments and quoting",
+			document: `# Team templates
+templates:
+  custom:
+    name: Custom # shown in list
+    branch: main
+    commit: ` + oldCommit + ` # bumped by refresh
+    tree_hash: "sha256:aaaa"
+  other:
+    branch: main
+    commit: ` + oldCommit + `
+`,
+			updates: map[string]map[string]string{
+				"custom": {"commit": newCommit, "tree_hash": "sha256:bbbb"},
+			},
+			want: `# Team templates
+templates:
+  custom:
+    name: Custom # shown in list
+    branch: main
+    commit: ` + newCommit + ` # bumped by refresh
+    tree_hash: "sha256:bbbb"
+  other:
+    branch: main
+    commit: ` + oldCommit + `
+`,
+		},
+		{
+			name:     "json on one line",
+			document: `{"templates": {"ü": {"name": "Ünïcode", "commit": "` + oldCommit + `"}, "b": {"commit": "` + oldCommit + `"}}}`,
+			updates:  map[string]map[string]string{"ü": {"commit": newCommit}, "b": {"commit": "abc"}},
+			want:     `{"templates": {"ü": {"name": "Ünïcode", "commit": "` + newCommit + `"}, "b": {"commit": "abc"}}}`,
+		},
+		{
+			name:     "unknown template",
+			document: "templates:\n  custom:\n    commit: " + oldCommit + "\n",
+			updates:  map[string]map[string]string{"missing": {"commit": newCommit}},
+			wantErr:  "template 'missing' is not in the registry document",
+		},
+		{
+			name:     "field not set",
+			document: "templates:\n  custom:\n    follow_branch: true\n",
+			updates:  map[string]map[string]string{"custom": {"commit": newCommit}},
+			wantErr:  "template 'custom' does not set commit",
+		},
+		{
+			name:     "no templates",
+			document: "name: not a registry\n",
+			updates:  map[string]map[string]string{"custom": {"commit": newCommit}},
+			wantErr:  "registry document has no templates",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetRegistryFields([]byte(tt.document), tt.updates)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SetRegistryFields() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetRegistryFields() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("SetRegistryFields() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
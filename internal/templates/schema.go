@@ -0,0 +1,120 @@
+package templates
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	// SchemaVersion is bumped whenever the registry format changes incompatibly
+	SchemaVersion = "1"
+
+	// SchemaID identifies the registry JSON Schema of SchemaVersion
+	SchemaID = "https://github.com/Fomo-Driven-Development/strategic-claude-basic-cli/schemas/registry-v" + SchemaVersion + ".json"
+)
+
+// fieldDescriptions documents each registry field in the schema, keyed by its
+// JSON name. Every Template field needs an entry; the schema test checks this.
+var fieldDescriptions = map[string]string{
+	"id":               "Unique identifier of the template; defaults to its key in templates",
+	"name":             "Display name of the template",
+	"description":      "What the template is for",
+	"repo_url":         "Repository to install from: an https, ssh, git, or file URL, or a local path",
+	"branch":           "Git branch to install from",
+	"commit":           "Full 40-character commit to install, or HEAD with follow_branch",
+	"tag":              "Git tag to install, such as v1.2.0, instead of a commit",
+	"follow_branch":    "Install the latest commit on branch instead of the pinned commit",
+	"language":         "Language the template is written for; leave out for language-agnostic templates",
+	"tags":             "Tags for filtering the template list, such as web or cli",
+	"deprecated":       "Whether the template is deprecated",
+	"replaced_by":      "ID of the template that supersedes this one",
+	"deprecation_note": "Why the template is deprecated, when there is no replacement",
+	"exclude_patterns": "Gitignore-style patterns, relative to the repository root, for files not to install",
+	"include_patterns": "Gitignore-style patterns, relative to the repository root, selecting the only files to install",
+	"tree_hash":        "Expected hash of the installed framework files, checked after checkout",
+	"post_install":     "Shell commands run in the target directory after installing, with --run-hooks",
+	"variables":        "Variables the template's files reference: a default value, or an object with default and description",
+	"min_cli_version":  "Oldest CLI version, such as 1.4.0, that can install the template",
+}
+
+// RegistrySchema returns a JSON Schema for registry documents, for editors to
+// validate templates.yaml and templates.json files. Template fields and their
+// types come from the Template struct, so new fields appear without further
+// changes; required fields and value formats mirror the checks made on load.
+func RegistrySchema() map[string]any {
+	properties := make(map[string]any)
+	templateType := reflect.TypeOf(Template{})
+	for i := 0; i < templateType.NumField(); i++ {
+		field := templateType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		property := schemaType(field.Type)
+		if description, ok := fieldDescriptions[name]; ok {
+			property["description"] = description
+		}
+		properties[name] = property
+	}
+
+	properties["commit"].(map[string]any)["pattern"] = "^([0-9a-fA-F]{40}|" + HeadCommit + ")$"
+	properties["tree_hash"].(map[string]any)["pattern"] = "^" + TreeHashPrefix + "[0-9a-fA-F]{64}$"
+	properties["language"].(map[string]any)["enum"] = KnownLanguages
+	properties["min_cli_version"].(map[string]any)["pattern"] = `^v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)([-+].*)?$`
+	properties["tags"].(map[string]any)["items"].(map[string]any)["examples"] = AllTags()
+	properties["variables"].(map[string]any)["propertyNames"] = map[string]any{"pattern": "^[A-Za-z_][A-Za-z0-9_]*$"}
+	properties["variables"].(map[string]any)["additionalProperties"] = map[string]any{
+		"oneOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"default":     map[string]any{"type": "string"},
+					"description": map[string]any{"type": "string"},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+
+	return map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         SchemaID,
+		"title":       "Strategic Claude Basic template registry",
+		"description": "Templates keyed by ID, loaded with --registry or --registry-url",
+		"version":     SchemaVersion,
+		"type":        "object",
+		"required":    []string{"templates"},
+		"properties": map[string]any{
+			"templates": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"$ref": "#/$defs/template"},
+			},
+		},
+		"$defs": map[string]any{
+			"template": map[string]any{
+				"type":                 "object",
+				"required":             []string{"name", "repo_url"},
+				"properties":           properties,
+				"additionalProperties": false,
+			},
+		},
+	}
+}
+
+// schemaType describes a Go field type as a JSON Schema type
+func schemaType(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaType(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaType(t.Elem())}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
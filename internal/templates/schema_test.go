@@ -0,0 +1,54 @@
+package templates
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegistrySchema(t *testing.T) {
+	schema := RegistrySchema()
+	if schema["$id"] != SchemaID || schema["version"] != SchemaVersion {
+		t.Errorf("Expected $id %s and version %s, got %v and %v", SchemaID, SchemaVersion, schema["$id"], schema["version"])
+	}
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("Schema does not encode as JSON: %v", err)
+	}
+
+	definition := schema["$defs"].(map[string]any)["template"].(map[string]any)
+	properties := definition["properties"].(map[string]any)
+
+	// Every field is in the schema, described, and of the right type
+	templateType := reflect.TypeOf(Template{})
+	for i := 0; i < templateType.NumField(); i++ {
+		name, _, _ := strings.Cut(templateType.Field(i).Tag.Get("json"), ",")
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			t.Errorf("Field %s is missing from the schema", name)
+			continue
+		}
+		if property["description"] == nil {
+			t.Errorf("Field %s has no description; add it to fieldDescriptions", name)
+		}
+	}
+	if len(properties) != templateType.NumField() {
+		t.Errorf("Schema has %d properties, want %d", len(properties), templateType.NumField())
+	}
+	if properties["follow_branch"].(map[string]any)["type"] != "boolean" || properties["tags"].(map[string]any)["type"] != "array" {
+		t.Errorf("Unexpected field types: %v", properties)
+	}
+	if !slices.Equal(properties["language"].(map[string]any)["enum"].([]string), KnownLanguages) {
+		t.Error("Expected language to be limited to the known languages")
+	}
+
+	// The built-in templates satisfy the value formats
+	commit := regexp.MustCompile(properties["commit"].(map[string]any)["pattern"].(string))
+	for _, template := range ListTemplates() {
+		if !commit.MatchString(template.Commit) {
+			t.Errorf("Built-in template %s commit %q does not match the schema", template.ID, template.Commit)
+		}
+	}
+}
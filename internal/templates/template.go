@@ -1,36 +1,97 @@
 package templates
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+	"unicode"
 )
 
+const (
+	// fileURLPrefix marks a repository URL that points at the local filesystem
+	fileURLPrefix = "file://"
+
+	// HeadCommit may be used as the commit of a template that follows its branch
+	HeadCommit = "HEAD"
+
+	// TreeHashPrefix names the algorithm of a template tree hash
+	TreeHashPrefix = "sha256:"
+)
+
+// KnownLanguages lists the values Validate accepts for a template's Language
+var KnownLanguages = []string{"c", "cpp", "csharp", "elixir", "go", "java", "javascript", "kotlin", "php", "python", "ruby", "rust", "scala", "shell", "swift", "typescript"}
+
 // Template represents a Strategic Claude Basic template variant
 type Template struct {
 	// Unique identifier for the template
-	ID string `json:"id"`
+	ID string `json:"id" yaml:"id"`
 
 	// Display name for the template
-	Name string `json:"name"`
+	Name string `json:"name" yaml:"name"`
 
 	// Description of what this template is for
-	Description string `json:"description"`
+	Description string `json:"description" yaml:"description"`
 
 	// Repository URL (can be same repo with different branches)
-	RepoURL string `json:"repo_url"`
+	RepoURL string `json:"repo_url" yaml:"repo_url"`
 
 	// Git branch to use
-	Branch string `json:"branch"`
+	Branch string `json:"branch" yaml:"branch"`
 
 	// Specific commit hash to checkout (pinned for stability)
-	Commit string `json:"commit"`
+	Commit string `json:"commit" yaml:"commit"`
+
+	// Git tag to install instead of a pinned Commit, such as "v1.2.0"; it is
+	// resolved to its commit at checkout
+	Tag string `json:"tag,omitempty" yaml:"tag,omitempty"`
+
+	// Whether to install the latest commit on Branch instead of the pinned Commit
+	FollowBranch bool `json:"follow_branch,omitempty" yaml:"follow_branch,omitempty"`
 
 	// Optional metadata for filtering/categorization
-	Language string   `json:"language,omitempty"` // e.g., "go", "python", "typescript"
-	Tags     []string `json:"tags,omitempty"`     // e.g., ["web", "cli", "api"]
+	Language string   `json:"language,omitempty" yaml:"language,omitempty"` // e.g., "go", "python", "typescript"
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`         // e.g., ["web", "cli", "api"]
 
 	// Whether this template is deprecated
-	Deprecated bool `json:"deprecated,omitempty"`
+	Deprecated bool `json:"deprecated" yaml:"deprecated,omitempty"`
+
+	// ID of the template that supersedes this one (usually set with Deprecated)
+	ReplacedBy string `json:"replaced_by,omitempty" yaml:"replaced_by,omitempty"`
+
+	// Why the template is deprecated, for deprecated templates without a replacement
+	DeprecationNote string `json:"deprecation_note,omitempty" yaml:"deprecation_note,omitempty"`
+
+	// Gitignore-style patterns, relative to the repository root, for template
+	// files that should not be installed (in addition to the defaults)
+	ExcludePatterns []string `json:"exclude_patterns,omitempty" yaml:"exclude_patterns,omitempty"`
+
+	// Gitignore-style patterns, relative to the repository root, selecting the
+	// only files to install; ExcludePatterns still remove files from them
+	IncludePatterns []string `json:"include_patterns,omitempty" yaml:"include_patterns,omitempty"`
+
+	// Expected hash of the installed framework files after ExcludePatterns are
+	// applied ("sha256:<hex>"), checked after checkout when set
+	TreeHash string `json:"tree_hash,omitempty" yaml:"tree_hash,omitempty"`
+
+	// Shell commands run in the target directory, in order, after a successful
+	// install; they only run when the user passes --run-hooks
+	PostInstall []string `json:"post_install,omitempty" yaml:"post_install,omitempty"`
+
+	// Variables the template's files reference, with the defaults used when
+	// --set does not supply a value
+	Variables map[string]Variable `json:"variables,omitempty" yaml:"variables,omitempty"`
+
+	// Oldest CLI version, such as "1.4.0", with the features the template
+	// relies on; older versions refuse to install it
+	MinCLIVersion string `json:"min_cli_version,omitempty" yaml:"min_cli_version,omitempty"`
 }
 
 // TemplateInfo represents metadata about an installed template
@@ -50,40 +111,398 @@ type TemplateInfo struct {
 
 // IsValid checks if the template configuration is valid
 func (t *Template) IsValid() error {
+	if err := t.validateFields(); err != nil {
+		return err
+	}
+	return t.validateReplacement(Registry.Snapshot())
+}
+
+// Validate checks the template more thoroughly than IsValid, for registry
+// authors: besides IsValid's checks, a deprecated template must name its
+// replacement or explain itself in DeprecationNote, and Language must be one of
+// KnownLanguages. Every problem found is returned, joined into one error,
+// rather than only the first.
+func (t *Template) Validate() error {
+	return t.validateAll(Registry.Snapshot())
+}
+
+// validateAll runs every check of Validate, resolving ReplacedBy in known
+func (t *Template) validateAll(known map[string]Template) error {
+	problems := t.fieldProblems()
+	if err := t.validateReplacement(known); err != nil {
+		problems = append(problems, err)
+	}
+
+	if t.Deprecated && t.ReplacedBy == "" && strings.TrimSpace(t.DeprecationNote) == "" {
+		problems = append(problems, fmt.Errorf("deprecated template must set replaced_by or deprecation_note"))
+	}
+	if !t.Deprecated && t.DeprecationNote != "" {
+		problems = append(problems, fmt.Errorf("template has a deprecation note but is not deprecated"))
+	}
+
+	if t.Language != "" && !slices.Contains(KnownLanguages, t.Language) {
+		problems = append(problems, fmt.Errorf("template language '%s' is not one of %s", t.Language, strings.Join(KnownLanguages, ", ")))
+	}
+
+	return errors.Join(problems...)
+}
+
+// validateFields checks the template's own fields without consulting the registry
+func (t *Template) validateFields() error {
+	if problems := t.fieldProblems(); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// fieldProblems returns every problem with the template's own fields, most
+// fundamental first
+func (t *Template) fieldProblems() []error {
+	var problems []error
 	if t.ID == "" {
-		return fmt.Errorf("template ID cannot be empty")
+		problems = append(problems, fmt.Errorf("template ID cannot be empty"))
 	}
 
 	if t.Name == "" {
-		return fmt.Errorf("template name cannot be empty")
+		problems = append(problems, fmt.Errorf("template name cannot be empty"))
 	}
 
 	if t.RepoURL == "" {
-		return fmt.Errorf("template repository URL cannot be empty")
+		problems = append(problems, fmt.Errorf("template repository URL cannot be empty"))
+	} else if err := validateRepoURL(t.RepoURL); err != nil {
+		problems = append(problems, fmt.Errorf("template '%s' has invalid RepoURL '%s': %w", t.ID, t.RepoURL, err))
+	}
+
+	for _, pattern := range t.ExcludePatterns {
+		if err := validatePattern("exclude", pattern); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	for _, pattern := range t.IncludePatterns {
+		if err := validatePattern("include", pattern); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	for i, command := range t.PostInstall {
+		if strings.TrimSpace(command) == "" {
+			problems = append(problems, fmt.Errorf("template post-install hook %d is empty", i+1))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(t.Variables)) {
+		if !IsVariableName(name) {
+			problems = append(problems, fmt.Errorf("template variable '%s' is not a valid name; use letters, digits, and underscores, like ProjectName", name))
+		}
+	}
+
+	if t.TreeHash != "" {
+		digest, ok := strings.CutPrefix(strings.ToLower(t.TreeHash), TreeHashPrefix)
+		if !ok || len(digest) != 64 || !isHexString(digest) {
+			problems = append(problems, fmt.Errorf("template tree hash must be %s followed by 64 hex characters", TreeHashPrefix))
+		}
+	}
+
+	if t.MinCLIVersion != "" {
+		if err := validateVersion(t.MinCLIVersion); err != nil {
+			problems = append(problems, fmt.Errorf("template min_cli_version is invalid: %w", err))
+		}
+	}
+
+	// Plain local directories are copied as-is, so there is no branch or commit to pin
+	if t.RepoURL != "" && t.IsLocal() && !t.IsLocalGitRepo() {
+		return problems
 	}
 
 	if t.Branch == "" {
-		return fmt.Errorf("template branch cannot be empty")
+		problems = append(problems, fmt.Errorf("template branch cannot be empty"))
 	}
 
-	if t.Commit == "" {
-		return fmt.Errorf("template commit cannot be empty")
+	// A template is pinned by exactly one of a commit, a tag, or following its
+	// branch; HEAD only stands in for the commit of templates following theirs
+	pins := 0
+	for _, set := range []bool{t.Commit != "" && t.Commit != HeadCommit, t.Tag != "", t.FollowBranch} {
+		if set {
+			pins++
+		}
+	}
+
+	switch {
+	case pins > 1:
+		problems = append(problems, fmt.Errorf("template must set only one of commit, tag, and follow_branch"))
+	case t.Commit == HeadCommit && !t.FollowBranch:
+		problems = append(problems, fmt.Errorf("template commit HEAD requires follow_branch to be enabled"))
+	case t.FollowBranch:
+		// Templates following their branch may leave the commit empty or set it to HEAD
+	case t.Tag != "":
+		if err := validateRef(t.Tag); err != nil {
+			problems = append(problems, fmt.Errorf("template tag '%s' is invalid: %w", t.Tag, err))
+		}
+	case t.Commit == "":
+		problems = append(problems, fmt.Errorf("template commit cannot be empty; set commit, tag, or follow_branch"))
+	case len(t.Commit) != 40 || !isHexString(t.Commit):
+		// Validate commit hash format (basic check)
+		problems = append(problems, fmt.Errorf("template commit must be a valid 40-character hex string"))
+	}
+
+	return problems
+}
+
+// validateReplacement checks that ReplacedBy, when set, names another known template
+func (t *Template) validateReplacement(known map[string]Template) error {
+	if t.ReplacedBy == "" {
+		return nil
+	}
+
+	if t.ReplacedBy == t.ID {
+		return fmt.Errorf("template cannot be replaced by itself")
+	}
+
+	if _, exists := known[t.ReplacedBy]; !exists {
+		return fmt.Errorf("template replacement '%s' does not exist", t.ReplacedBy)
+	}
+
+	return nil
+}
+
+// IsPinned reports whether the template installs a fixed commit: a full
+// 40-character commit is set and the template does not follow its branch.
+// Templates tracking their branch and plain local directories are not pinned,
+// nor are templates pinned to a tag, whose commit is only known at checkout.
+func (t *Template) IsPinned() bool {
+	return !t.FollowBranch && len(t.Commit) == 40 && isHexString(t.Commit)
+}
+
+// PinnedCommit returns the commit to check out, or an empty string when the
+// template follows the latest commit on its branch or checks out its tag
+func (t *Template) PinnedCommit() string {
+	if t.FollowBranch {
+		return ""
+	}
+	return t.Commit
+}
+
+// CheckoutRef returns the branch or tag to clone: the template's Tag when it
+// is pinned to one, its Branch otherwise
+func (t *Template) CheckoutRef() string {
+	if t.Tag != "" {
+		return t.Tag
+	}
+	return t.Branch
+}
+
+// CacheKey returns a filesystem-safe name for what the template installs: a
+// hash of its repository and pinned commit or tag, or of its branch when it
+// follows the branch. Templates with different IDs but the same source share
+// a key, and commits are compared regardless of case.
+func (t *Template) CacheKey() string {
+	revision := "commit:" + strings.ToLower(t.Commit)
+	switch {
+	case t.FollowBranch:
+		revision = "branch:" + t.Branch
+	case t.Tag != "":
+		revision = "tag:" + t.Tag
+	}
+	sum := sha256.Sum256([]byte(t.RepoURL + "\x00" + revision))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// WithCommit returns a copy of the template pinned to commit instead of its
+// registry pin, for installing another revision of the same branch. The tree
+// hash describes the registry pin's files, so the copy does not carry it.
+func (t Template) WithCommit(commit string) (Template, error) {
+	if t.IsLocal() && !t.IsLocalGitRepo() {
+		return Template{}, fmt.Errorf("template '%s' is a plain local directory with no commits to choose from", t.ID)
+	}
+
+	if len(commit) != 40 || !isHexString(commit) {
+		return Template{}, fmt.Errorf("commit '%s' must be a full 40-character hex string", commit)
+	}
+
+	t.Commit = strings.ToLower(commit)
+	t.Tag = ""
+	t.FollowBranch = false
+	t.TreeHash = ""
+	return t, nil
+}
+
+// WithRef returns a copy of the template that installs whatever commit ref, a
+// branch or tag of its repository, points to. As with WithCommit, the tree
+// hash of the registry pin is dropped.
+func (t Template) WithRef(ref string) (Template, error) {
+	if t.IsLocal() && !t.IsLocalGitRepo() {
+		return Template{}, fmt.Errorf("template '%s' is a plain local directory with no refs to choose from", t.ID)
+	}
+
+	if err := validateRef(ref); err != nil {
+		return Template{}, fmt.Errorf("ref '%s' is invalid: %w", ref, err)
+	}
+
+	t.Branch = ref
+	t.Commit = HeadCommit
+	t.Tag = ""
+	t.FollowBranch = true
+	t.TreeHash = ""
+	return t, nil
+}
+
+// FromRepository describes a template that is not in the registry: the
+// repository at repoURL, installed at ref. Its ID is the repository name.
+func FromRepository(repoURL, ref string) (Template, error) {
+	if err := validateRepoURL(repoURL); err != nil {
+		return Template{}, fmt.Errorf("repository URL '%s' is invalid: %w", repoURL, err)
+	}
+
+	id := strings.TrimSuffix(path.Base(strings.TrimRight(filepath.ToSlash(repoURL), "/")), ".git")
+	if isSCPLikeURL(repoURL) {
+		_, repoPath, _ := strings.Cut(repoURL, ":")
+		id = strings.TrimSuffix(path.Base(repoPath), ".git")
+	}
+	if id == "" || id == "." || id == "/" {
+		return Template{}, fmt.Errorf("repository URL '%s' has no repository name", repoURL)
 	}
 
-	// Validate commit hash format (basic check)
-	if len(t.Commit) != 40 || !isHexString(t.Commit) {
-		return fmt.Errorf("template commit must be a valid 40-character hex string")
+	template := Template{
+		ID:          id,
+		Name:        id,
+		Description: "Installed from " + repoURL,
+		RepoURL:     repoURL,
+	}
+	return template.WithRef(ref)
+}
+
+// ArchiveExtensions are the file extensions of the archives FromArchive
+// accepts, longest first so ".tar.gz" is not taken for ".gz"
+var ArchiveExtensions = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// FromArchive describes a template that is not in the registry: the exported
+// template tree in the tar, gzip-compressed tar, or zip archive at
+// archivePath, installed without git. Its ID is the archive's file name
+// without the extension, and its RepoURL the archive's absolute path.
+func FromArchive(archivePath string) (Template, error) {
+	absPath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return Template{}, fmt.Errorf("archive path '%s' is invalid: %w", archivePath, err)
 	}
 
+	name := filepath.Base(absPath)
+	id := ""
+	for _, ext := range ArchiveExtensions {
+		if stem, ok := strings.CutSuffix(strings.ToLower(name), ext); ok {
+			id = name[:len(stem)]
+			break
+		}
+	}
+	if id == "" {
+		return Template{}, fmt.Errorf("archive '%s' must be a %s file", archivePath, strings.Join(ArchiveExtensions, ", "))
+	}
+
+	return Template{
+		ID:          id,
+		Name:        id,
+		Description: "Installed from " + absPath,
+		RepoURL:     absPath,
+	}, nil
+}
+
+// validateRef rejects refs git would refuse or could read as an option
+func validateRef(ref string) error {
+	switch {
+	case ref == "":
+		return fmt.Errorf("it is empty")
+	case strings.HasPrefix(ref, "-"):
+		return fmt.Errorf("it must not start with '-'")
+	case strings.ContainsAny(ref, " \t\n~^:?*[\\"), strings.Contains(ref, ".."), strings.Contains(ref, "@{"):
+		return fmt.Errorf("it contains characters git does not allow in branch or tag names")
+	case strings.HasSuffix(ref, "/"), strings.HasSuffix(ref, ".lock"):
+		return fmt.Errorf("it is not a valid branch or tag name")
+	}
 	return nil
 }
 
-// DisplayName returns a formatted display name for UI
+// ShortCommit returns an abbreviated commit for compact display
+func (t *Template) ShortCommit() string {
+	if t.FollowBranch {
+		return HeadCommit
+	}
+	if t.Tag != "" {
+		return t.Tag
+	}
+	if len(t.Commit) > 7 {
+		return t.Commit[:7]
+	}
+	if t.Commit == "" {
+		return "local"
+	}
+	return t.Commit
+}
+
+// IsLocal reports whether the repository URL points at the local filesystem,
+// either as a file:// URL or as a plain absolute or relative path
+func (t *Template) IsLocal() bool {
+	if strings.HasPrefix(t.RepoURL, fileURLPrefix) {
+		return true
+	}
+
+	// Any other scheme (https://, ssh://, git://) is remote
+	if strings.Contains(t.RepoURL, "://") {
+		return false
+	}
+
+	// scp-like syntax (git@github.com:org/repo.git) is remote
+	if isSCPLikeURL(t.RepoURL) {
+		return false
+	}
+
+	return t.RepoURL != ""
+}
+
+// IsSSH reports whether the repository is cloned over SSH, either as an
+// ssh:// URL or in scp-like form (git@github.com:org/repo.git)
+func (t *Template) IsSSH() bool {
+	return strings.HasPrefix(t.RepoURL, "ssh://") || isSCPLikeURL(t.RepoURL)
+}
+
+// LocalPath returns the absolute filesystem path of a local template source
+func (t *Template) LocalPath() (string, error) {
+	if !t.IsLocal() {
+		return "", fmt.Errorf("template repository URL is not a local path: %s", t.RepoURL)
+	}
+	return filepath.Abs(strings.TrimPrefix(t.RepoURL, fileURLPrefix))
+}
+
+// IsLocalGitRepo reports whether a local template source is a git checkout
+func (t *Template) IsLocalGitRepo() bool {
+	path, err := t.LocalPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// DisplayName returns the name and ID every command shows a template by, such
+// as "Strategic Claude Basic (main)", or the ID alone when the name is the
+// same or empty. Deprecated templates end in "[deprecated]", naming their
+// replacement when they have one: "Old (old) [deprecated, use main]".
 func (t *Template) DisplayName() string {
+	label := t.Name
+	switch {
+	case t.ID == "" || t.Name == t.ID:
+	case t.Name == "":
+		label = t.ID
+	default:
+		label = fmt.Sprintf("%s (%s)", t.Name, t.ID)
+	}
+
+	if t.Deprecated && t.ReplacedBy != "" {
+		return fmt.Sprintf("%s [deprecated, use %s]", label, t.ReplacedBy)
+	}
 	if t.Deprecated {
-		return fmt.Sprintf("%s (deprecated)", t.Name)
+		return label + " [deprecated]"
 	}
-	return t.Name
+	return label
 }
 
 // ShortDescription returns a truncated description for compact display
@@ -104,6 +523,101 @@ func (t *Template) HasTag(tag string) bool {
 	return false
 }
 
+// MatchesLanguage reports whether the template is for language. Language-agnostic
+// templates match any language unless strict is set.
+func (t *Template) MatchesLanguage(language string, strict bool) bool {
+	if t.Language == "" {
+		return !strict
+	}
+	return t.Language == language
+}
+
+// MatchesQuery reports whether query appears, case-insensitively, in the
+// template's ID, name, description, or tags
+func (t *Template) MatchesQuery(query string) bool {
+	needle := strings.ToLower(strings.TrimSpace(query))
+	fields := append([]string{t.ID, t.Name, t.Description}, t.Tags...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// supportedURLSchemes lists the URL schemes git can clone templates from
+var supportedURLSchemes = []string{"https", "http", "ssh", "git", "file"}
+
+// validateRepoURL checks that a repository URL uses a form git can clone: a
+// supported scheme with a host and path, scp-like SSH syntax, or a local path
+func validateRepoURL(repoURL string) error {
+	if strings.TrimSpace(repoURL) != repoURL || strings.ContainsAny(repoURL, " \t\n") {
+		return fmt.Errorf("it contains whitespace")
+	}
+
+	if !strings.Contains(repoURL, "://") {
+		if !isSCPLikeURL(repoURL) {
+			return nil // A local path
+		}
+		host, repoPath, _ := strings.Cut(repoURL[strings.Index(repoURL, "@")+1:], ":")
+		if host == "" || repoPath == "" {
+			return fmt.Errorf("SSH URLs must be in user@host:path form")
+		}
+		return nil
+	}
+
+	parsed, err := url.Parse(repoURL)
+	if err != nil || !slices.Contains(supportedURLSchemes, parsed.Scheme) {
+		return fmt.Errorf("it must use https, ssh, git, or file, or be in user@host:path form")
+	}
+	if parsed.Scheme != "file" && parsed.Host == "" {
+		return fmt.Errorf("it has no host")
+	}
+	if strings.Trim(parsed.Path, "/") == "" {
+		return fmt.Errorf("it has no repository path")
+	}
+
+	return nil
+}
+
+// isSCPLikeURL reports whether a URL uses git's scp-like SSH syntax (user@host:path)
+func isSCPLikeURL(repoURL string) bool {
+	if strings.Contains(repoURL, "://") {
+		return false
+	}
+	at := strings.Index(repoURL, "@")
+	return at > 0 && strings.Contains(repoURL[at:], ":")
+}
+
+// validatePattern checks that every segment of a gitignore-style include or
+// exclude pattern is a valid glob
+func validatePattern(kind, pattern string) error {
+	trimmed := strings.Trim(strings.TrimPrefix(strings.TrimSpace(pattern), "!"), "/")
+	if trimmed == "" {
+		return fmt.Errorf("template %s pattern '%s' is empty", kind, pattern)
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("template %s pattern '%s' is invalid: %w", kind, pattern, err)
+		}
+	}
+	return nil
+}
+
+// IsVariableName reports whether a name can be referenced as {{.Name}} in a template
+func IsVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
 // isHexString checks if a string contains only hexadecimal characters
 func isHexString(s string) bool {
 	for _, c := range s {
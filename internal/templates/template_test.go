@@ -1,6 +1,9 @@
 package templates
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -82,6 +85,707 @@ func TestTemplate_IsValid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "follow branch without commit",
+			template: Template{
+				ID:           "test",
+				Name:         "Test Template",
+				RepoURL:      "https://example.com/repo.git",
+				Branch:       "main",
+				FollowBranch: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "follow branch with HEAD commit",
+			template: Template{
+				ID:           "test",
+				Name:         "Test Template",
+				RepoURL:      "https://example.com/repo.git",
+				Branch:       "main",
+				Commit:       HeadCommit,
+				FollowBranch: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "HEAD commit without follow branch",
+			template: Template{
+				ID:      "test",
+				Name:    "Test Template",
+				RepoURL: "https://example.com/repo.git",
+				Branch:  "main",
+				Commit:  HeadCommit,
+			},
+			wantErr: true,
+		},
+		{
+			name: "pinned to a tag",
+			template: Template{
+				ID:      "test",
+				Name:    "Test Template",
+				RepoURL: "https://example.com/repo.git",
+				Branch:  "main",
+				Tag:     "v1.2.0",
+			},
+			wantErr: false,
+		},
+		{
+			name: "tag and commit",
+			template: Template{
+				ID:      "test",
+				Name:    "Test Template",
+				RepoURL: "https://example.com/repo.git",
+				Branch:  "main",
+				Commit:  "1234567890abcdef1234567890abcdef12345678",
+				Tag:     "v1.2.0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "tag and follow branch",
+			template: Template{
+				ID:           "test",
+				Name:         "Test Template",
+				RepoURL:      "https://example.com/repo.git",
+				Branch:       "main",
+				Tag:          "v1.2.0",
+				FollowBranch: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "commit and follow branch",
+			template: Template{
+				ID:           "test",
+				Name:         "Test Template",
+				RepoURL:      "https://example.com/repo.git",
+				Branch:       "main",
+				Commit:       "1234567890abcdef1234567890abcdef12345678",
+				FollowBranch: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid tag",
+			template: Template{
+				ID:      "test",
+				Name:    "Test Template",
+				RepoURL: "https://example.com/repo.git",
+				Branch:  "main",
+				Tag:     "v1..2",
+			},
+			wantErr: true,
+		},
+		{
+			name: "replacement points to existing template",
+			template: Template{
+				ID:         "test",
+				Name:       "Test Template",
+				RepoURL:    "https://example.com/repo.git",
+				Branch:     "main",
+				Commit:     "1234567890abcdef1234567890abcdef12345678",
+				Deprecated: true,
+				ReplacedBy: "main",
+			},
+			wantErr: false,
+		},
+		{
+			name: "replacement points to unknown template",
+			template: Template{
+				ID:         "test",
+				Name:       "Test Template",
+				RepoURL:    "https://example.com/repo.git",
+				Branch:     "main",
+				Commit:     "1234567890abcdef1234567890abcdef12345678",
+				Deprecated: true,
+				ReplacedBy: "does-not-exist",
+			},
+			wantErr: true,
+		},
+		{
+			name: "follow branch without branch",
+			template: Template{
+				ID:           "test",
+				Name:         "Test Template",
+				RepoURL:      "https://example.com/repo.git",
+				FollowBranch: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid tree hash",
+			template: Template{
+				ID:       "test",
+				Name:     "Test Template",
+				RepoURL:  "https://example.com/repo.git",
+				Branch:   "main",
+				Commit:   "1234567890abcdef1234567890abcdef12345678",
+				TreeHash: "sha256:" + strings.Repeat("ab", 32),
+			},
+			wantErr: false,
+		},
+		{
+			name: "tree hash without algorithm",
+			template: Template{
+				ID:       "test",
+				Name:     "Test Template",
+				RepoURL:  "https://example.com/repo.git",
+				Branch:   "main",
+				Commit:   "1234567890abcdef1234567890abcdef12345678",
+				TreeHash: strings.Repeat("ab", 32),
+			},
+			wantErr: true,
+		},
+		{
+			name: "truncated tree hash",
+			template: Template{
+				ID:       "test",
+				Name:     "Test Template",
+				RepoURL:  "https://example.com/repo.git",
+				Branch:   "main",
+				Commit:   "1234567890abcdef1234567890abcdef12345678",
+				TreeHash: "sha256:abcdef",
+			},
+			wantErr: true,
+		},
+		{
+			name: "minimum CLI version",
+			template: Template{
+				ID:            "test",
+				Name:          "Test Template",
+				RepoURL:       "https://example.com/repo.git",
+				Branch:        "main",
+				Commit:        "1234567890abcdef1234567890abcdef12345678",
+				MinCLIVersion: "v1.4.0",
+			},
+			wantErr: false,
+		},
+		{
+			name: "minimum CLI version that is not semver",
+			template: Template{
+				ID:            "test",
+				Name:          "Test Template",
+				RepoURL:       "https://example.com/repo.git",
+				Branch:        "main",
+				Commit:        "1234567890abcdef1234567890abcdef12345678",
+				MinCLIVersion: "1.4",
+			},
+			wantErr: true,
+		},
+		{
+			name: "post-install hooks",
+			template: Template{
+				ID:          "test",
+				Name:        "Test Template",
+				RepoURL:     "https://example.com/repo.git",
+				Branch:      "main",
+				Commit:      "1234567890abcdef1234567890abcdef12345678",
+				PostInstall: []string{"make setup", "./scripts/bootstrap.sh --quiet"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty post-install hook",
+			template: Template{
+				ID:          "test",
+				Name:        "Test Template",
+				RepoURL:     "https://example.com/repo.git",
+				Branch:      "main",
+				Commit:      "1234567890abcdef1234567890abcdef12345678",
+				PostInstall: []string{"make setup", "  "},
+			},
+			wantErr: true,
+		},
+		{
+			name: "variable defaults",
+			template: Template{
+				ID:        "test",
+				Name:      "Test Template",
+				RepoURL:   "https://example.com/repo.git",
+				Branch:    "main",
+				Commit:    "1234567890abcdef1234567890abcdef12345678",
+				Variables: map[string]Variable{"Team": {Default: "platform"}, "Go_Version2": {Default: "1.24"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid variable name",
+			template: Template{
+				ID:        "test",
+				Name:      "Test Template",
+				RepoURL:   "https://example.com/repo.git",
+				Branch:    "main",
+				Commit:    "1234567890abcdef1234567890abcdef12345678",
+				Variables: map[string]Variable{"team-name": {Default: "platform"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.IsValid()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Template.IsValid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	valid := Template{
+		ID:      "test",
+		Name:    "Test Template",
+		RepoURL: "https://example.com/repo.git",
+		Branch:  "main",
+		Commit:  "1234567890abcdef1234567890abcdef12345678",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Template)
+		want   []string // Substrings of each expected problem, in order
+	}{
+		{name: "valid template", modify: func(*Template) {}},
+		{name: "known language", modify: func(t *Template) { t.Language = "go" }},
+		{
+			name:   "unknown language",
+			modify: func(t *Template) { t.Language = "Golang" },
+			want:   []string{"language 'Golang' is not one of"},
+		},
+		{
+			name:   "deprecated with replacement",
+			modify: func(t *Template) { t.Deprecated = true; t.ReplacedBy = "main" },
+		},
+		{
+			name:   "deprecated with note",
+			modify: func(t *Template) { t.Deprecated = true; t.DeprecationNote = "No longer maintained" },
+		},
+		{
+			name:   "deprecated without replacement or note",
+			modify: func(t *Template) { t.Deprecated = true },
+			want:   []string{"must set replaced_by or deprecation_note"},
+		},
+		{
+			name:   "note on an active template",
+			modify: func(t *Template) { t.DeprecationNote = "No longer maintained" },
+			want:   []string{"not deprecated"},
+		},
+		{
+			name:   "pinned without a full commit",
+			modify: func(t *Template) { t.Commit = "1234567" },
+			want:   []string{"40-character hex"},
+		},
+		{
+			name: "every problem at once",
+			modify: func(t *Template) {
+				t.Name = ""
+				t.Branch = ""
+				t.Commit = HeadCommit
+				t.ExcludePatterns = []string{"[", "/"}
+				t.ReplacedBy = "missing"
+				t.Language = "cobol"
+			},
+			want: []string{
+				"name cannot be empty",
+				"exclude pattern '[' is invalid",
+				"exclude pattern '/' is empty",
+				"branch cannot be empty",
+				"HEAD requires follow_branch",
+				"replacement 'missing' does not exist",
+				"language 'cobol'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := valid
+			tt.modify(&template)
+
+			err := template.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want problems")
+			}
+
+			problems := strings.Split(err.Error(), "\n")
+			if len(problems) != len(tt.want) {
+				t.Fatalf("Validate() found %d problems, want %d:\n%v", len(problems), len(tt.want), err)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("Problem %d = %q, want it to mention %q", i, problems[i], want)
+				}
+			}
+
+			// IsValid stops at the first problem Validate shares with it
+			if first := template.IsValid(); first != nil && first.Error() != problems[0] {
+				t.Errorf("IsValid() = %v, want the first problem %q", first, problems[0])
+			}
+		})
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	for id, template := range Registry.Snapshot() {
+		if err := template.Validate(); err != nil {
+			t.Errorf("Built-in template %s does not validate: %v", id, err)
+		}
+	}
+}
+
+func TestTemplate_PinnedCommit(t *testing.T) {
+	pinned := Template{Commit: "1234567890abcdef1234567890abcdef12345678"}
+	if got := pinned.PinnedCommit(); got != pinned.Commit {
+		t.Errorf("PinnedCommit() = %q, want %q", got, pinned.Commit)
+	}
+	if got := pinned.ShortCommit(); got != "1234567" {
+		t.Errorf("ShortCommit() = %q, want %q", got, "1234567")
+	}
+
+	following := Template{Commit: HeadCommit, FollowBranch: true}
+	if got := following.PinnedCommit(); got != "" {
+		t.Errorf("PinnedCommit() = %q, want empty for branch-following template", got)
+	}
+	if got := following.ShortCommit(); got != HeadCommit {
+		t.Errorf("ShortCommit() = %q, want %q", got, HeadCommit)
+	}
+
+	local := Template{RepoURL: "./templates/plain"}
+	if got := local.ShortCommit(); got != "local" {
+		t.Errorf("ShortCommit() = %q, want %q for a template without a commit", got, "local")
+	}
+}
+
+func TestTemplate_IsPinned(t *testing.T) {
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	tests := []struct {
+		name     string
+		template Template
+		want     bool
+	}{
+		{"fixed commit", Template{Commit: commit}, true},
+		{"following its branch", Template{Commit: HeadCommit, FollowBranch: true}, false},
+		{"following with a commit set", Template{Commit: commit, FollowBranch: true}, false},
+		{"abbreviated commit", Template{Commit: commit[:7]}, false},
+		{"plain local directory", Template{RepoURL: "./templates/plain"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.template.IsPinned(); got != tt.want {
+				t.Errorf("IsPinned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_WithCommit(t *testing.T) {
+	base := Template{
+		ID:           "test",
+		Name:         "Test Template",
+		RepoURL:      "https://example.com/repo.git",
+		Branch:       "main",
+		Commit:       HeadCommit,
+		FollowBranch: true,
+		TreeHash:     TreeHashPrefix + strings.Repeat("a", 64),
+	}
+	commit := "ABCDEF1234567890ABCDEF1234567890ABCDEF12"
+
+	pinned, err := base.WithCommit(commit)
+	if err != nil {
+		t.Fatalf("WithCommit() error = %v", err)
+	}
+	if pinned.PinnedCommit() != strings.ToLower(commit) {
+		t.Errorf("PinnedCommit() = %q, want %q", pinned.PinnedCommit(), strings.ToLower(commit))
+	}
+	if pinned.TreeHash != "" {
+		t.Errorf("Expected the tree hash to be dropped, got %q", pinned.TreeHash)
+	}
+	if err := pinned.IsValid(); err != nil {
+		t.Errorf("Expected the overridden template to be valid, got %v", err)
+	}
+	if base.Commit != HeadCommit || !base.FollowBranch {
+		t.Error("Expected the original template to be unchanged")
+	}
+
+	for name, tt := range map[string]struct {
+		template Template
+		commit   string
+	}{
+		"abbreviated commit":   {template: base, commit: "abcdef1"},
+		"not hex":              {template: base, commit: strings.Repeat("z", 40)},
+		"plain local template": {template: Template{ID: "plain", RepoURL: t.TempDir()}, commit: commit},
+	} {
+		if _, err := tt.template.WithCommit(tt.commit); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestTemplate_WithRef(t *testing.T) {
+	base := Template{
+		ID:       "test",
+		Name:     "Test Template",
+		RepoURL:  "https://example.com/repo.git",
+		Branch:   "main",
+		Commit:   "1234567890abcdef1234567890abcdef12345678",
+		TreeHash: TreeHashPrefix + strings.Repeat("a", 64),
+	}
+
+	branch, err := base.WithRef("feature/agents")
+	if err != nil {
+		t.Fatalf("WithRef() error = %v", err)
+	}
+	if branch.Branch != "feature/agents" || branch.PinnedCommit() != "" || branch.TreeHash != "" {
+		t.Errorf("WithRef() = %+v, want the branch head of feature/agents without a tree hash", branch)
+	}
+	if err := branch.IsValid(); err != nil {
+		t.Errorf("Expected the template at a ref to be valid, got %v", err)
+	}
+
+	for _, ref := range []string{"", "--upload-pack=evil", "a..b", "has space", "topic/", "main.lock", "HEAD@{1}"} {
+		if _, err := base.WithRef(ref); err == nil {
+			t.Errorf("WithRef(%q) expected an error", ref)
+		}
+	}
+	if _, err := (Template{ID: "plain", RepoURL: t.TempDir()}).WithRef("main"); err == nil {
+		t.Error("WithRef() expected an error for a plain local directory")
+	}
+}
+
+func TestFromRepository(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		wantID  string
+		wantErr bool
+	}{
+		{repoURL: "https://github.com/acme/claude-template.git", wantID: "claude-template"},
+		{repoURL: "https://github.com/acme/claude-template/", wantID: "claude-template"},
+		{repoURL: "git@github.com:acme/claude-template.git", wantID: "claude-template"},
+		{repoURL: "ftp://example.com/repo.git", wantErr: true},
+		{repoURL: "https://example.com/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			template, err := FromRepository(tt.repoURL, "v1.2.0")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("FromRepository() = %+v, want an error", template)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromRepository() error = %v", err)
+			}
+			if template.ID != tt.wantID || template.RepoURL != tt.repoURL || template.Branch != "v1.2.0" {
+				t.Errorf("FromRepository() = %+v, want ID %s at v1.2.0", template, tt.wantID)
+			}
+			if err := template.IsValid(); err != nil {
+				t.Errorf("Expected a valid template, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFromArchive(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantID  string
+		wantErr bool
+	}{
+		{path: "/tmp/claude-template.tar.gz", wantID: "claude-template"},
+		{path: "/tmp/claude-template.TGZ", wantID: "claude-template"},
+		{path: "/tmp/v1.2.0.tar", wantID: "v1.2.0"},
+		{path: "/tmp/claude-template.zip", wantID: "claude-template"},
+		{path: "/tmp/claude-template.gz", wantErr: true},
+		{path: "/tmp/.zip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			template, err := FromArchive(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("FromArchive() = %+v, want an error", template)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromArchive() error = %v", err)
+			}
+			if template.ID != tt.wantID || template.RepoURL != tt.path {
+				t.Errorf("FromArchive() = %+v, want ID %s from %s", template, tt.wantID, tt.path)
+			}
+			if err := template.IsValid(); err != nil {
+				t.Errorf("Expected a valid template, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTemplate_IsLocal(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		want    bool
+	}{
+		{"https://example.com/repo.git", false},
+		{"ssh://git@example.com/repo.git", false},
+		{"git@github.com:org/repo.git", false},
+		{"file:///home/user/templates/base", true},
+		{"/home/user/templates/base", true},
+		{"./templates/base", true},
+		{"../strategic-claude-base", true},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			template := Template{RepoURL: tt.repoURL}
+			if got := template.IsLocal(); got != tt.want {
+				t.Errorf("Template.IsLocal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_IsValid_RepoURL(t *testing.T) {
+	commit := "2ddc5e7e7c71a84e0c0ac16c1d4c6a237a8b5e8d"
+
+	tests := []struct {
+		repoURL string
+		wantErr bool
+		wantSSH bool
+	}{
+		{"https://github.com/org/repo.git", false, false},
+		{"ssh://git@github.com/org/repo.git", false, true},
+		{"git@github.com:org/repo.git", false, true},
+		{"deploy@git.internal.example.com:team/strategic-claude-base.git", false, true},
+		{"git://example.com/repo.git", false, false},
+		{"http://git.internal.example.com/repo.git", false, false},
+		{"file:///srv/templates/base", false, false},
+		{"/srv/templates/base", false, false},
+		{"./templates/base", false, false},
+		{"ftp://example.com/repo.git", true, false},
+		{"https://", true, false},
+		{"https:///org/repo.git", true, false},
+		{"https://github.com", true, false},
+		{"https://github.com/org/my repo.git", true, false},
+		{" https://github.com/org/repo.git", true, false},
+		{"git@github.com:", true, true},
+		{"file://", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			template := Template{
+				ID:      "test",
+				Name:    "Test",
+				RepoURL: tt.repoURL,
+				Branch:  "main",
+				Commit:  commit,
+			}
+			err := template.IsValid()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Template.IsValid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := template.IsSSH(); got != tt.wantSSH {
+				t.Errorf("Template.IsSSH() = %v, want %v", got, tt.wantSSH)
+			}
+		})
+	}
+}
+
+func TestTemplate_IsValid_Patterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  bool
+	}{
+		{"no patterns", nil, false},
+		{"gitignore-style patterns", []string{"/docs/", "**/*.draft.md", "!keep.md"}, false},
+		{"invalid glob", []string{"docs/["}, true},
+		{"empty pattern", []string{"/"}, true},
+	}
+
+	// Include and exclude patterns share the gitignore syntax
+	for _, kind := range []string{"exclude", "include"} {
+		for _, tt := range tests {
+			t.Run(kind+" "+tt.name, func(t *testing.T) {
+				template := Template{
+					ID:      "test",
+					Name:    "Test",
+					RepoURL: "https://github.com/org/repo.git",
+					Branch:  "main",
+					Commit:  "2ddc5e7e7c71a84e0c0ac16c1d4c6a237a8b5e8d",
+				}
+				if kind == "exclude" {
+					template.ExcludePatterns = tt.patterns
+				} else {
+					template.IncludePatterns = tt.patterns
+				}
+				err := template.IsValid()
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Template.IsValid() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if err != nil && !strings.Contains(err.Error(), kind+" pattern") {
+					t.Errorf("Expected the error to name the %s pattern, got %v", kind, err)
+				}
+			})
+		}
+	}
+}
+
+func TestTemplate_IsValid_LocalSource(t *testing.T) {
+	plainDir := t.TempDir()
+
+	gitDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(gitDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git directory: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		template Template
+		wantErr  bool
+	}{
+		{
+			name: "plain directory without commit",
+			template: Template{
+				ID:      "local",
+				Name:    "Local Template",
+				RepoURL: "file://" + plainDir,
+			},
+			wantErr: false,
+		},
+		{
+			name: "local git repository without commit",
+			template: Template{
+				ID:      "local",
+				Name:    "Local Template",
+				RepoURL: gitDir,
+				Branch:  "main",
+			},
+			wantErr: true,
+		},
+		{
+			name: "local git repository with commit",
+			template: Template{
+				ID:      "local",
+				Name:    "Local Template",
+				RepoURL: gitDir,
+				Branch:  "main",
+				Commit:  "1234567890abcdef1234567890abcdef12345678",
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -128,18 +832,40 @@ func TestTemplate_DisplayName(t *testing.T) {
 		{
 			name: "normal template",
 			template: Template{
+				ID:         "test",
 				Name:       "Test Template",
 				Deprecated: false,
 			},
-			wantResult: "Test Template",
+			wantResult: "Test Template (test)",
+		},
+		{
+			name:       "name same as ID",
+			template:   Template{ID: "claude-template", Name: "claude-template"},
+			wantResult: "claude-template",
+		},
+		{
+			name:       "no name",
+			template:   Template{ID: "test"},
+			wantResult: "test",
 		},
 		{
 			name: "deprecated template",
 			template: Template{
+				ID:         "old",
 				Name:       "Old Template",
 				Deprecated: true,
 			},
-			wantResult: "Old Template (deprecated)",
+			wantResult: "Old Template (old) [deprecated]",
+		},
+		{
+			name: "deprecated template with replacement",
+			template: Template{
+				ID:         "old",
+				Name:       "Old Template",
+				Deprecated: true,
+				ReplacedBy: "main",
+			},
+			wantResult: "Old Template (old) [deprecated, use main]",
 		},
 	}
 
@@ -187,3 +913,48 @@ func TestTemplate_ShortDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplate_CacheKey(t *testing.T) {
+	commit := "1234567890abcdef1234567890abcdef12345678"
+	base := Template{ID: "base", RepoURL: "https://example.com/repo.git", Branch: "main", Commit: commit}
+	following := Template{RepoURL: base.RepoURL, Branch: "main", Commit: HeadCommit, FollowBranch: true}
+
+	key := base.CacheKey()
+	if len(key) != 16 || !isHexString(key) {
+		t.Fatalf("CacheKey() = %q, want 16 hex characters", key)
+	}
+
+	tests := []struct {
+		name     string
+		template Template
+		wantSame bool
+	}{
+		{"same source under another ID", Template{ID: "other", Name: "Other", RepoURL: base.RepoURL, Branch: "main", Commit: commit}, true},
+		{"uppercase commit", Template{RepoURL: base.RepoURL, Branch: "main", Commit: strings.ToUpper(commit)}, true},
+		{"pinned commit on another branch", Template{RepoURL: base.RepoURL, Branch: "develop", Commit: commit}, true},
+		{"different commit", Template{RepoURL: base.RepoURL, Branch: "main", Commit: strings.Repeat("a", 40)}, false},
+		{"different repository", Template{RepoURL: "https://example.com/other.git", Branch: "main", Commit: commit}, false},
+		{"following the branch", following, false},
+		{"tag", Template{RepoURL: base.RepoURL, Branch: "main", Tag: "v1.2.0"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.template.CacheKey() == key; got != tt.wantSame {
+				t.Errorf("CacheKey() = %q, base key %q; same = %v, want %v", tt.template.CacheKey(), key, got, tt.wantSame)
+			}
+		})
+	}
+
+	t.Run("following another branch", func(t *testing.T) {
+		other := following
+		other.Branch = "develop"
+		if other.CacheKey() == following.CacheKey() {
+			t.Errorf("Expected branches followed to have different keys")
+		}
+		// The commit of a template following its branch does not name its source
+		other.Branch, other.Commit = "main", commit
+		if other.CacheKey() != following.CacheKey() {
+			t.Errorf("Expected the commit to be ignored when following the branch")
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Variable is a variable a template declares for its files to reference. In a
+// registry it is written either as its default value alone,
+//
+//	variables:
+//	  Team: platform
+//
+// or with a description, shown when init --prompt asks for a value:
+//
+//	variables:
+//	  Team:
+//	    default: platform
+//	    description: Team that owns the project
+type Variable struct {
+	// Value used when --set does not supply one
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// What the variable is for, in a few words
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// variableFields is Variable without its methods, for decoding the long form
+type variableFields Variable
+
+// UnmarshalYAML accepts a default value alone or a mapping of the fields
+func (v *Variable) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*v = Variable{Default: node.Value}
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: a variable must be a default value or have default and description fields", node.Line)
+	}
+	var fields variableFields
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	*v = Variable(fields)
+	return nil
+}
+
+// MarshalYAML writes a variable without a description as its default alone
+func (v Variable) MarshalYAML() (any, error) {
+	if v.Description == "" {
+		return v.Default, nil
+	}
+	return variableFields(v), nil
+}
+
+// UnmarshalJSON accepts a default value alone or an object of the fields
+func (v *Variable) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = Variable{Default: value}
+		return nil
+	}
+	var fields variableFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("a variable must be a default value or have default and description fields: %w", err)
+	}
+	*v = Variable(fields)
+	return nil
+}
+
+// MarshalJSON writes a variable without a description as its default alone
+func (v Variable) MarshalJSON() ([]byte, error) {
+	if v.Description == "" {
+		return json.Marshal(v.Default)
+	}
+	return json.Marshal(variableFields(v))
+}
+
+// VariableDefaults returns the default value of each declared variable
+func (t Template) VariableDefaults() map[string]string {
+	defaults := make(map[string]string, len(t.Variables))
+	for name, variable := range t.Variables {
+		defaults[name] = variable.Default
+	}
+	return defaults
+}
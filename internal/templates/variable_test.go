@@ -0,0 +1,91 @@
+package templates
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseRegistry_Variables(t *testing.T) {
+	want := map[string]Variable{
+		"Team":   {Default: "platform", Description: "Team that owns the project"},
+		"Editor": {Default: "vim"},
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		wantErr  string
+	}{
+		{
+			name: "yaml",
+			document: `
+templates:
+  custom:
+    variables:
+      Editor: vim
+      Team:
+        default: platform
+        description: Team that owns the project
+`,
+		},
+		{
+			name:     "json",
+			document: `{"templates": {"custom": {"variables": {"Editor": "vim", "Team": {"default": "platform", "description": "Team that owns the project"}}}}}`,
+		},
+		{
+			name:     "yaml list",
+			document: "templates:\n  custom:\n    variables:\n      Team: [platform]\n",
+			wantErr:  "a variable must be a default value",
+		},
+		{
+			name:     "json number",
+			document: `{"templates": {"custom": {"variables": {"Team": 1}}}}`,
+			wantErr:  "a variable must be a default value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseRegistry([]byte(tt.document))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseRegistry() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRegistry() error = %v", err)
+			}
+			got := parsed["custom"].Variables
+			if len(got) != len(want) || got["Team"] != want["Team"] || got["Editor"] != want["Editor"] {
+				t.Errorf("Variables = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestVariable_Marshal(t *testing.T) {
+	variables := map[string]Variable{
+		"Editor": {Default: "vim"},
+		"Team":   {Default: "platform", Description: "Team that owns the project"},
+	}
+
+	data, err := json.Marshal(variables)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"Editor":"vim","Team":{"default":"platform","description":"Team that owns the project"}}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	data, err = yaml.Marshal(variables)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() error = %v", err)
+	}
+	if want := "Editor: vim\nTeam:\n    default: platform\n    description: Team that owns the project\n"; string(data) != want {
+		t.Errorf("yaml.Marshal() = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,73 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CLIVersion is the version of the running CLI, which a template's
+// MinCLIVersion is checked against. main sets it to the version embedded at
+// build time.
+var CLIVersion string
+
+// parseVersion parses a semantic version such as "1.4.0" or "v1.4.0-rc.1"
+// into its major, minor, and patch numbers. A leading v and any pre-release
+// or build suffix are accepted and ignored.
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	core := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+
+	fields := strings.Split(core, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 || field != strconv.Itoa(n) {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// validateVersion checks that version is a semantic version parseVersion accepts
+func validateVersion(version string) error {
+	if _, ok := parseVersion(version); !ok {
+		return fmt.Errorf("'%s' is not a semantic version such as 1.4.0", version)
+	}
+	return nil
+}
+
+// CheckCLIVersion returns an error asking for an upgrade when the template
+// sets MinCLIVersion and cliVersion is older. A cliVersion that is not a
+// semantic version, such as that of a development build, satisfies every
+// template.
+func (t *Template) CheckCLIVersion(cliVersion string) error {
+	if t.MinCLIVersion == "" {
+		return nil
+	}
+	required, ok := parseVersion(t.MinCLIVersion)
+	if !ok {
+		return fmt.Errorf("template '%s' has invalid min_cli_version: %w", t.ID, validateVersion(t.MinCLIVersion))
+	}
+	current, ok := parseVersion(cliVersion)
+	if !ok {
+		return nil
+	}
+
+	for i := range required {
+		if current[i] != required[i] {
+			if current[i] > required[i] {
+				return nil
+			}
+			return fmt.Errorf("template '%s' requires strategic-claude-basic-cli %s or newer, but this is %s; upgrade the CLI to install it",
+				t.ID, strings.TrimPrefix(t.MinCLIVersion, "v"), strings.TrimPrefix(cliVersion, "v"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    [3]int
+		wantOK  bool
+	}{
+		{version: "1.4.0", want: [3]int{1, 4, 0}, wantOK: true},
+		{version: "v0.12.3", want: [3]int{0, 12, 3}, wantOK: true},
+		{version: "2.0.0-rc.1", want: [3]int{2, 0, 0}, wantOK: true},
+		{version: "v1.2.3-4-gabcdef0-dirty", want: [3]int{1, 2, 3}, wantOK: true},
+		{version: "1.2.3+build.5", want: [3]int{1, 2, 3}, wantOK: true},
+		{version: "1.4"},
+		{version: "1.4.0.1"},
+		{version: "01.4.0"},
+		{version: "1.-4.0"},
+		{version: "dev"},
+		{version: ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseVersion(tt.version)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("parseVersion(%q) = %v, %v; want %v, %v", tt.version, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestTemplate_CheckCLIVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		minVersion string
+		cliVersion string
+		wantErr    bool
+	}{
+		{name: "no minimum", cliVersion: "0.1.0"},
+		{name: "same version", minVersion: "1.4.0", cliVersion: "1.4.0"},
+		{name: "newer patch", minVersion: "1.4.0", cliVersion: "v1.4.2"},
+		{name: "newer major", minVersion: "1.4.0", cliVersion: "2.0.0"},
+		{name: "older minor", minVersion: "1.4.0", cliVersion: "1.3.9", wantErr: true},
+		{name: "older major", minVersion: "v1.4.0", cliVersion: "0.9.0", wantErr: true},
+		{name: "development build", minVersion: "1.4.0", cliVersion: "dev"},
+		{name: "invalid minimum", minVersion: "latest", cliVersion: "1.4.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := Template{ID: "test", MinCLIVersion: tt.minVersion}
+			err := template.CheckCLIVersion(tt.cliVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckCLIVersion(%q) error = %v, wantErr %v", tt.cliVersion, err, tt.wantErr)
+			}
+			if err != nil && tt.minVersion == "1.4.0" && !strings.Contains(err.Error(), "requires strategic-claude-basic-cli 1.4.0 or newer") {
+				t.Errorf("Expected an upgrade message, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/services/git"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// shortHashLength is how much of a commit hash the commit selector shows
+const shortHashLength = 7
+
+// CommitSelectorModel represents the state of the commit selector
+type CommitSelectorModel struct {
+	branch   string
+	commits  []git.CommitInfo
+	cursor   int
+	selected string
+	quitting bool
+}
+
+// NewCommitSelectorModel creates a commit selector over commits, newest first,
+// with the cursor on the latest one
+func NewCommitSelectorModel(branch string, commits []git.CommitInfo) CommitSelectorModel {
+	return CommitSelectorModel{
+		branch:  branch,
+		commits: commits,
+	}
+}
+
+// Init is called when the program starts
+func (m CommitSelectorModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles input events and updates the model state
+func (m CommitSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case keyCtrlC, keyQ, keyEsc:
+			m.quitting = true
+			return m, tea.Quit
+		case keyEnter:
+			if len(m.commits) > 0 {
+				m.selected = m.commits[m.cursor].Hash
+			}
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case keyDown, "j":
+			if m.cursor < len(m.commits)-1 {
+				m.cursor++
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the commit selector UI
+func (m CommitSelectorModel) View() string {
+	if m.quitting {
+		if m.selected == "" {
+			return quitTextStyle.Render("Selection cancelled.\n")
+		}
+		return ""
+	}
+
+	var s strings.Builder
+
+	// Title
+	s.WriteString(titleStyle.Render("Select Commit on " + m.branch))
+	s.WriteString("\n\n")
+
+	// Commit list
+	for i, commit := range m.commits {
+		cursor := " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+
+		line := fmt.Sprintf("%s %s", cursor, commitLine(commit, i == 0))
+		if i == m.cursor {
+			s.WriteString(selectedItemStyle.Render(line))
+		} else {
+			s.WriteString(itemStyle.Render(line))
+		}
+		s.WriteString("\n")
+	}
+
+	// Help text
+	s.WriteString(helpStyle.Render("↑/↓: navigate • enter: select • q: quit"))
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// GetSelectedCommit returns the full hash of the selected commit
+func (m CommitSelectorModel) GetSelectedCommit() string {
+	return m.selected
+}
+
+// IsQuitting returns whether the user cancelled the selection
+func (m CommitSelectorModel) IsQuitting() bool {
+	return m.quitting && m.selected == ""
+}
+
+// commitLine describes a commit on one line: short hash, author date, and
+// subject, marking the branch head as the latest
+func commitLine(commit git.CommitInfo, latest bool) string {
+	hash := commit.Hash
+	if len(hash) > shortHashLength {
+		hash = hash[:shortHashLength]
+	}
+
+	line := fmt.Sprintf("%s  %s  %s", hash, commit.AuthorDate.Format("2006-01-02"), commit.Subject)
+	if latest {
+		line += " (latest)"
+	}
+	return line
+}
+
+// IsTTY reports whether an interactive selector can be shown on this terminal
+func IsTTY() bool {
+	return isTTY()
+}
+
+// fallbackSelectCommit provides a simple prompt-based selector when the interactive one fails
+func fallbackSelectCommit(commits []git.CommitInfo) (string, error) {
+	// Display commit options
+	fmt.Println()
+	fmt.Println("Recent commits:")
+	for i, commit := range commits {
+		fmt.Printf("  %d. %s\n", i+1, commitLine(commit, i == 0))
+	}
+	fmt.Println()
+
+	// Get user selection
+	interactionService := utils.NewInteractionService()
+	for {
+		input, err := interactionService.PromptWithDefault(fmt.Sprintf("Select commit (1-%d)", len(commits)), "1")
+		if err != nil {
+			return "", fmt.Errorf("failed to get user input: %w", err)
+		}
+
+		choice, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil || choice < 1 || choice > len(commits) {
+			fmt.Printf("Invalid selection. Please enter a number between 1 and %d.\n", len(commits))
+			continue
+		}
+
+		selected := commits[choice-1]
+		fmt.Printf("Selected: %s\n", commitLine(selected, choice == 1))
+		return selected.Hash, nil
+	}
+}
+
+// SelectCommit runs the interactive commit selector over the recent commits of
+// branch, newest first, and returns the full hash of the selected commit
+func SelectCommit(branch string, commits []git.CommitInfo) (string, error) {
+	if len(commits) == 0 {
+		return "", fmt.Errorf("no commits found on %s", branch)
+	}
+
+	// Run interactive Bubble Tea selector
+	m := NewCommitSelectorModel(branch, commits)
+	p := tea.NewProgram(m)
+
+	finalModel, err := p.Run()
+	if err != nil {
+		// If Bubble Tea fails, fallback to simple prompts
+		fmt.Printf("Interactive mode failed (%v), falling back to simple mode...\n", err)
+		return fallbackSelectCommit(commits)
+	}
+
+	model := finalModel.(CommitSelectorModel)
+	if model.IsQuitting() {
+		return "", fmt.Errorf("commit selection cancelled by user")
+	}
+
+	selected := model.GetSelectedCommit()
+	if selected == "" {
+		return "", fmt.Errorf("no commit selected")
+	}
+
+	for i, commit := range commits {
+		if commit.Hash == selected {
+			fmt.Printf("\nSelected: %s\n", commitLine(commit, i == 0))
+			break
+		}
+	}
+	return selected, nil
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/models"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
 	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/utils"
 
@@ -51,6 +52,8 @@ var (
 // TemplateSelectorModel represents the state of the template selector
 type TemplateSelectorModel struct {
 	templates []templates.Template
+	visible   []templates.Template // Templates matching the current filter
+	filter    string
 	cursor    int
 	selected  string
 	quitting  bool
@@ -71,6 +74,7 @@ func NewTemplateSelectorModel() TemplateSelectorModel {
 
 	return TemplateSelectorModel{
 		templates: templateList,
+		visible:   templateList,
 		cursor:    cursor,
 	}
 }
@@ -80,32 +84,76 @@ func (m TemplateSelectorModel) Init() tea.Cmd {
 	return nil
 }
 
-// Update handles input events and updates the model state
+// Update handles input events and updates the model state. Typed characters
+// filter the list, so navigation uses the arrow keys.
 func (m TemplateSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		switch keyMsg.String() {
-		case "ctrl+c", "q", "esc":
-			m.quitting = true
-			return m, tea.Quit
-		case "enter", "tab":
-			if len(m.templates) > 0 {
-				m.selected = m.templates[m.cursor].ID
-			}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		// The first escape clears the filter, the second cancels
+		if m.filter != "" {
+			m.setFilter("")
+			return m, nil
+		}
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyEnter, tea.KeyTab:
+		if len(m.visible) > 0 {
+			m.selected = m.visible[m.cursor].ID
 			return m, tea.Quit
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case "down", "j":
-			if m.cursor < len(m.templates)-1 {
-				m.cursor++
-			}
 		}
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlN:
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+		}
+	case tea.KeyBackspace:
+		if m.filter != "" {
+			runes := []rune(m.filter)
+			m.setFilter(string(runes[:len(runes)-1]))
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.setFilter(m.filter + string(keyMsg.Runes))
 	}
 
 	return m, nil
 }
 
+// setFilter narrows the visible templates to those matching filter, keeping the
+// cursor on the same template when it is still visible
+func (m *TemplateSelectorModel) setFilter(filter string) {
+	current := ""
+	if m.cursor < len(m.visible) {
+		current = m.visible[m.cursor].ID
+	}
+
+	m.filter = filter
+	m.visible = make([]templates.Template, 0, len(m.templates))
+	for _, template := range m.templates {
+		if template.MatchesQuery(filter) {
+			m.visible = append(m.visible, template)
+		}
+	}
+
+	m.cursor = 0
+	for i, template := range m.visible {
+		if template.ID == current {
+			m.cursor = i
+			break
+		}
+	}
+}
+
 // View renders the template selector UI
 func (m TemplateSelectorModel) View() string {
 	if m.quitting {
@@ -121,15 +169,24 @@ func (m TemplateSelectorModel) View() string {
 	s.WriteString(titleStyle.Render("Select Template"))
 	s.WriteString("\n\n")
 
+	// Filter input
+	s.WriteString(itemStyle.Render("Filter: " + m.filter + "_"))
+	s.WriteString("\n\n")
+
+	if len(m.visible) == 0 {
+		s.WriteString(descriptionStyle.Render(fmt.Sprintf("No templates match %q", m.filter)))
+		s.WriteString("\n\n")
+	}
+
 	// Template list
-	for i, template := range m.templates {
+	for i, template := range m.visible {
 		cursor := " "
 		if i == m.cursor {
 			cursor = ">"
 		}
 
-		// Template name and ID
-		line := fmt.Sprintf("%s %s (%s)", cursor, template.DisplayName(), template.ID)
+		// Template name and ID, marked when deprecated
+		line := fmt.Sprintf("%s %s", cursor, template.DisplayName())
 
 		if i == m.cursor {
 			s.WriteString(selectedItemStyle.Render(line))
@@ -138,22 +195,27 @@ func (m TemplateSelectorModel) View() string {
 		}
 		s.WriteString("\n")
 
-		// Description
+		// Description and tags
+		details := []string{}
 		if template.Description != "" {
-			var desc string
+			details = append(details, template.Description)
+		}
+		if len(template.Tags) > 0 {
+			details = append(details, "tags: "+strings.Join(template.Tags, ", "))
+		}
+		for _, detail := range details {
 			if i == m.cursor {
-				desc = selectedDescriptionStyle.Render(template.Description)
+				s.WriteString(selectedDescriptionStyle.Render(detail))
 			} else {
-				desc = descriptionStyle.Render(template.Description)
+				s.WriteString(descriptionStyle.Render(detail))
 			}
-			s.WriteString(desc)
 			s.WriteString("\n")
 		}
 		s.WriteString("\n")
 	}
 
 	// Help text
-	s.WriteString(helpStyle.Render("↑/↓: navigate • enter: select • q: quit"))
+	s.WriteString(helpStyle.Render("type to filter • ↑/↓: navigate • enter: select • esc: clear/quit"))
 	s.WriteString("\n")
 
 	return s.String()
@@ -184,7 +246,7 @@ func fallbackSelectTemplate(availableTemplates []templates.Template) (string, er
 	fmt.Println()
 	fmt.Println("Available templates:")
 	for i, template := range availableTemplates {
-		fmt.Printf("  %d. %s (%s)\n", i+1, template.DisplayName(), template.ID)
+		fmt.Printf("  %d. %s\n", i+1, template.DisplayName())
 		if template.Description != "" {
 			fmt.Printf("     %s\n", template.Description)
 		}
@@ -206,7 +268,7 @@ func fallbackSelectTemplate(availableTemplates []templates.Template) (string, er
 		}
 
 		selectedTemplate := availableTemplates[choice-1]
-		fmt.Printf("Selected: %s (%s)\n", selectedTemplate.DisplayName(), selectedTemplate.ID)
+		fmt.Printf("Selected: %s\n", selectedTemplate.DisplayName())
 		return selectedTemplate.ID, nil
 	}
 }
@@ -221,14 +283,21 @@ func SelectTemplate() (string, error) {
 	// If only one template, use it automatically
 	if len(availableTemplates) == 1 {
 		template := availableTemplates[0]
-		fmt.Printf("Using template: %s (%s)\n", template.DisplayName(), template.ID)
+		fmt.Printf("Using template: %s\n", template.DisplayName())
 		return template.ID, nil
 	}
 
-	// Check if we have a TTY for interactive mode
+	// Without a terminal there is nobody to pick, so the template must be named explicitly
 	if !isTTY() {
-		// Fallback to simple prompts
-		return fallbackSelectTemplate(availableTemplates)
+		ids := make([]string, 0, len(availableTemplates))
+		for _, template := range availableTemplates {
+			ids = append(ids, template.ID)
+		}
+		return "", models.NewAppError(
+			models.ErrorCodeInvalidConfiguration,
+			fmt.Sprintf("no terminal available for template selection; pass --template with one of: %s (or --yes for the default)", strings.Join(ids, ", ")),
+			nil,
+		)
 	}
 
 	// Run interactive Bubble Tea selector
@@ -258,6 +327,6 @@ func SelectTemplate() (string, error) {
 		return "", fmt.Errorf("failed to get selected template: %w", err)
 	}
 
-	fmt.Printf("\nSelected: %s (%s)\n", selectedTemplate.DisplayName(), selectedTemplate.ID)
+	fmt.Printf("\nSelected: %s\n", selectedTemplate.DisplayName())
 	return selectedID, nil
 }
@@ -0,0 +1,350 @@
+// Package userconfig reads and writes the user's CLI defaults, stored in
+// config.yaml under the user configuration directory, and resolves them
+// against environment variables and the project's own settings in
+// .strategic-claude/config.yaml.
+//
+// Settings apply in this order, highest first: an explicit command-line flag,
+// the setting's environment variable, the project config, the user's config
+// file, the built-in default.
+package userconfig
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/config"
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/templates"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings saved in the config file. Empty fields are unset.
+type Config struct {
+	DefaultTemplate string `yaml:"default_template,omitempty"`
+	RegistryURL     string `yaml:"registry_url,omitempty"`
+	NoBackup        *bool  `yaml:"no_backup,omitempty"`
+	Jobs            int    `yaml:"jobs,omitempty"`
+	Offline         *bool  `yaml:"offline,omitempty"`
+	ArchiveDir      string `yaml:"archive_dir,omitempty"`
+}
+
+// Project holds the settings in a project's .strategic-claude/config.yaml,
+// committed so everyone working on the project installs the same way. It takes
+// the same keys as the user's config file, which it overrides, plus the exclude
+// patterns and variable values init and update apply.
+type Project struct {
+	Config          `yaml:",inline"`
+	ExcludePatterns []string          `yaml:"exclude_patterns,omitempty"`
+	Variables       map[string]string `yaml:"variables,omitempty"`
+}
+
+// Key describes one setting
+type Key struct {
+	Name        string // Key in the config file
+	Env         string // Environment variable overriding the file
+	Flag        string // Command-line flag overriding both
+	Description string
+
+	validate func(value string) error
+	get      func(c *Config) string
+	put      func(c *Config, value string) // value is valid, or "" to unset
+}
+
+// Keys lists every supported setting
+var Keys = []Key{
+	{
+		Name:        "default_template",
+		Env:         "STRATEGIC_CLAUDE_TEMPLATE",
+		Flag:        "template",
+		Description: "template installed by init when --template is not given",
+		get:         func(c *Config) string { return c.DefaultTemplate },
+		put:         func(c *Config, value string) { c.DefaultTemplate = value },
+	},
+	{
+		Name:        "registry_url",
+		Env:         "STRATEGIC_CLAUDE_REGISTRY_URL",
+		Flag:        "registry-url",
+		Description: "URL of a remote template registry merged with the built-in templates",
+		validate:    validateHTTPURL,
+		get:         func(c *Config) string { return c.RegistryURL },
+		put:         func(c *Config, value string) { c.RegistryURL = value },
+	},
+	{
+		Name:        "no_backup",
+		Env:         "STRATEGIC_CLAUDE_NO_BACKUP",
+		Flag:        "no-backup",
+		Description: "skip backing up existing files (true or false)",
+		validate:    validateBool,
+		get: func(c *Config) string {
+			if c.NoBackup == nil {
+				return ""
+			}
+			return strconv.FormatBool(*c.NoBackup)
+		},
+		put: func(c *Config, value string) {
+			c.NoBackup = nil
+			if value != "" {
+				noBackup, _ := strconv.ParseBool(value)
+				c.NoBackup = &noBackup
+			}
+		},
+	},
+	{
+		Name:        "jobs",
+		Env:         "STRATEGIC_CLAUDE_JOBS",
+		Flag:        "jobs",
+		Description: "number of templates fetched at once when installing several",
+		validate:    validatePositiveInt,
+		get: func(c *Config) string {
+			if c.Jobs == 0 {
+				return ""
+			}
+			return strconv.Itoa(c.Jobs)
+		},
+		put: func(c *Config, value string) {
+			c.Jobs, _ = strconv.Atoi(value)
+		},
+	},
+	{
+		Name:        "offline",
+		Env:         "STRATEGIC_CLAUDE_OFFLINE",
+		Flag:        "offline",
+		Description: "use only cached templates and registries, never the network (true or false)",
+		validate:    validateBool,
+		get: func(c *Config) string {
+			if c.Offline == nil {
+				return ""
+			}
+			return strconv.FormatBool(*c.Offline)
+		},
+		put: func(c *Config, value string) {
+			c.Offline = nil
+			if value != "" {
+				offline, _ := strconv.ParseBool(value)
+				c.Offline = &offline
+			}
+		},
+	},
+	{
+		Name:        "archive_dir",
+		Env:         "STRATEGIC_CLAUDE_ARCHIVE_DIR",
+		Flag:        "archive-dir",
+		Description: "directory of tar archives of pinned template commits, installed from without git",
+		get:         func(c *Config) string { return c.ArchiveDir },
+		put:         func(c *Config, value string) { c.ArchiveDir = value },
+	},
+}
+
+// Source says where a resolved value came from
+type Source string
+
+const (
+	SourceUnset   Source = ""
+	SourceFile    Source = "config file"
+	SourceProject Source = "project config"
+	SourceEnv     Source = "environment"
+)
+
+// Setting is a key with its resolved value
+type Setting struct {
+	Key
+	Value  string
+	Source Source
+}
+
+// LookupKey returns the setting with the given name
+func LookupKey(name string) (Key, error) {
+	for _, key := range Keys {
+		if key.Name == name {
+			return key, nil
+		}
+	}
+
+	names := make([]string, len(Keys))
+	for i, key := range Keys {
+		names[i] = key.Name
+	}
+	return Key{}, fmt.Errorf("unknown config key '%s' (valid keys: %s)", name, strings.Join(names, ", "))
+}
+
+// Validate checks a value for the key
+func (k Key) Validate(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%s cannot be empty", k.Name)
+	}
+	if k.validate != nil {
+		if err := k.validate(value); err != nil {
+			return fmt.Errorf("invalid %s '%s': %w", k.Name, value, err)
+		}
+	}
+	return nil
+}
+
+// Get returns the key's value in the config file, or "" when unset
+func (c *Config) Get(key Key) string {
+	return key.get(c)
+}
+
+// Set stores a validated value for the key
+func (c *Config) Set(key Key, value string) error {
+	if err := key.Validate(value); err != nil {
+		return err
+	}
+	key.put(c, value)
+	return nil
+}
+
+// Unset removes the key from the config
+func (c *Config) Unset(key Key) {
+	key.put(c, "")
+}
+
+// Resolve returns every key with its value from the environment or, failing
+// that, the config file. Environment values are validated like file values.
+func (c *Config) Resolve() ([]Setting, error) {
+	return (&Project{}).Resolve(c)
+}
+
+// Resolve returns every key with its value from the environment, the project
+// config, or the user's config file, in that order of precedence
+func (p *Project) Resolve(user *Config) ([]Setting, error) {
+	settings := make([]Setting, 0, len(Keys))
+	for _, key := range Keys {
+		setting := Setting{Key: key}
+		if value, ok := os.LookupEnv(key.Env); ok && value != "" {
+			if err := key.Validate(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", key.Env, err)
+			}
+			setting.Value, setting.Source = value, SourceEnv
+		} else if value := p.Get(key); value != "" {
+			setting.Value, setting.Source = value, SourceProject
+		} else if value := user.Get(key); value != "" {
+			setting.Value, setting.Source = value, SourceFile
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// Path returns the location of the config file
+func Path() (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, config.UserConfigFileName), nil
+}
+
+// ProjectPath returns the location of the project config in dir
+func ProjectPath(dir string) string {
+	return filepath.Join(dir, config.ProjectConfigDirName, config.ProjectConfigFileName)
+}
+
+// Load reads the config file at path. A missing file is an empty config.
+func Load(path string) (*Config, error) {
+	var c Config
+	if err := load(path, "config file", &c); err != nil {
+		return nil, err
+	}
+	if err := c.validate(path, "config file"); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// LoadProject reads the project config at path. A missing file is an empty
+// config.
+func LoadProject(path string) (*Project, error) {
+	var p Project
+	if err := load(path, "project config", &p); err != nil {
+		return nil, err
+	}
+	if err := p.validate(path, "project config"); err != nil {
+		return nil, err
+	}
+	for name := range p.Variables {
+		if !templates.IsVariableName(name) {
+			return nil, fmt.Errorf("project config %s: invalid variable name '%s'", path, name)
+		}
+	}
+	for _, pattern := range p.ExcludePatterns {
+		if strings.TrimSpace(pattern) == "" {
+			return nil, fmt.Errorf("project config %s: exclude_patterns cannot contain an empty pattern", path)
+		}
+	}
+	return &p, nil
+}
+
+// load decodes the YAML file at path into v, leaving v empty when the file is
+// missing
+func load(path, kind string, v any) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s %s: %w", kind, path, err)
+	}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s %s: %w", kind, path, err)
+	}
+	return nil
+}
+
+// validate checks every set key of a config loaded from path
+func (c *Config) validate(path, kind string) error {
+	for _, key := range Keys {
+		if value := c.Get(key); value != "" {
+			if err := key.Validate(value); err != nil {
+				return fmt.Errorf("%s %s: %w", kind, path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Save writes the config to path, creating its directory if needed
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), config.DirPermissions); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, config.FilePermissions); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// validateHTTPURL accepts absolute http and https URLs
+func validateHTTPURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("must be an http or https URL")
+	}
+	return nil
+}
+
+// validateBool accepts the values strconv.ParseBool does
+func validateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("must be true or false")
+	}
+	return nil
+}
+
+// validatePositiveInt accepts whole numbers of at least 1
+func validatePositiveInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return fmt.Errorf("must be a whole number of at least 1")
+	}
+	return nil
+}
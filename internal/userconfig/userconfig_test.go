@@ -0,0 +1,200 @@
+package userconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfig_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+
+	c := &Config{}
+	for name, value := range map[string]string{
+		"default_template": "ccr",
+		"registry_url":     "https://example.com/templates.json",
+		"no_backup":        "true",
+		"jobs":             "4",
+	} {
+		key, err := LookupKey(name)
+		if err != nil {
+			t.Fatalf("LookupKey(%q) error = %v", name, err)
+		}
+		if err := c.Set(key, value); err != nil {
+			t.Fatalf("Set(%s, %s) error = %v", name, value, err)
+		}
+	}
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "no_backup: true\n") || !strings.Contains(string(data), "jobs: 4\n") {
+		t.Errorf("Expected typed YAML values, got:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.DefaultTemplate != "ccr" || loaded.RegistryURL != "https://example.com/templates.json" || loaded.NoBackup == nil || !*loaded.NoBackup || loaded.Jobs != 4 {
+		t.Errorf("Load() = %+v, want %+v", loaded, c)
+	}
+
+	key, _ := LookupKey("jobs")
+	loaded.Unset(key)
+	if loaded.Get(key) != "" {
+		t.Errorf("Get() after Unset = %q, want empty", loaded.Get(key))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "missing file"},
+		{name: "empty file", content: " "},
+		{name: "invalid jobs", content: "jobs: -1\n", wantErr: "invalid jobs"},
+		{name: "wrong type", content: "no_backup: sometimes\n", wantErr: "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := Load(path)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Load() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Load() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Resolve(t *testing.T) {
+	c := &Config{DefaultTemplate: "main", Jobs: 4}
+	t.Setenv("STRATEGIC_CLAUDE_TEMPLATE", "ccr")
+	t.Setenv("STRATEGIC_CLAUDE_JOBS", "")
+
+	settings, err := c.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	got := make(map[string]Setting)
+	for _, setting := range settings {
+		got[setting.Name] = setting
+	}
+	if s := got["default_template"]; s.Value != "ccr" || s.Source != SourceEnv {
+		t.Errorf("default_template = %q from %q, want ccr from the environment", s.Value, s.Source)
+	}
+	if s := got["jobs"]; s.Value != "4" || s.Source != SourceFile {
+		t.Errorf("jobs = %q from %q, want 4 from the config file", s.Value, s.Source)
+	}
+	if s := got["no_backup"]; s.Source != SourceUnset {
+		t.Errorf("no_backup source = %q, want unset", s.Source)
+	}
+
+	t.Setenv("STRATEGIC_CLAUDE_NO_BACKUP", "maybe")
+	if _, err := c.Resolve(); err == nil || !strings.Contains(err.Error(), "STRATEGIC_CLAUDE_NO_BACKUP") {
+		t.Errorf("Resolve() with an invalid environment value error = %v", err)
+	}
+}
+
+func TestProject_Resolve(t *testing.T) {
+	user := &Config{DefaultTemplate: "main", Jobs: 4, RegistryURL: "https://example.com/templates.json"}
+	project := &Project{Config: Config{DefaultTemplate: "ccr", Jobs: 2}}
+	t.Setenv("STRATEGIC_CLAUDE_TEMPLATE", "")
+	t.Setenv("STRATEGIC_CLAUDE_REGISTRY_URL", "")
+	t.Setenv("STRATEGIC_CLAUDE_JOBS", "8")
+
+	settings, err := project.Resolve(user)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	got := make(map[string]Setting)
+	for _, setting := range settings {
+		got[setting.Name] = setting
+	}
+	if s := got["jobs"]; s.Value != "8" || s.Source != SourceEnv {
+		t.Errorf("jobs = %q from %q, want 8 from the environment", s.Value, s.Source)
+	}
+	if s := got["default_template"]; s.Value != "ccr" || s.Source != SourceProject {
+		t.Errorf("default_template = %q from %q, want ccr from the project config", s.Value, s.Source)
+	}
+	if s := got["registry_url"]; s.Source != SourceFile {
+		t.Errorf("registry_url source = %q, want the config file", s.Source)
+	}
+}
+
+func TestLoadProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *Project
+		wantErr string
+	}{
+		{name: "missing file", want: &Project{}},
+		{
+			name:    "all settings",
+			content: "default_template: ccr\nexclude_patterns: [drafts/]\nvariables:\n  Team: platform\n",
+			want: &Project{
+				Config:          Config{DefaultTemplate: "ccr"},
+				ExcludePatterns: []string{"drafts/"},
+				Variables:       map[string]string{"Team": "platform"},
+			},
+		},
+		{name: "invalid key value", content: "jobs: -1\n", wantErr: "invalid jobs"},
+		{name: "invalid variable name", content: "variables:\n  my-team: x\n", wantErr: "invalid variable name"},
+		{name: "empty pattern", content: "exclude_patterns: ['']\n", wantErr: "empty pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ProjectPath(t.TempDir())
+			if tt.content != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadProject(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadProject() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadProject() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadProject() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupKey_Unknown(t *testing.T) {
+	if _, err := LookupKey("colour"); err == nil || !strings.Contains(err.Error(), "default_template") {
+		t.Errorf("LookupKey() error = %v, want one listing the valid keys", err)
+	}
+}
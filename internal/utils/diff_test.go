@@ -0,0 +1,67 @@
+package utils
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "identical",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			from: "a\nb\nc\n",
+			to:   "a\nB\nc\n",
+			want: "--- local\n+++ upstream\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "added to empty file",
+			from: "",
+			to:   "a\n",
+			want: "--- local\n+++ upstream\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		{
+			name: "distant changes get separate hunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want: "--- local\n+++ upstream\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name: "nearby changes share a hunk",
+			from: "1\n2\n3\n4\n5\n",
+			to:   "one\n2\n3\n4\nfive\n",
+			want: "--- local\n+++ upstream\n@@ -1,5 +1,5 @@\n-1\n+one\n 2\n 3\n 4\n-5\n+five\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnifiedDiff("local", "upstream", []byte(tt.from), []byte(tt.to))
+			if got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorizeDiff(t *testing.T) {
+	diff := "--- a\n+++ b\n@@ -1,2 +1,2 @@\n same\n-old\n+new\n"
+	want := "\033[1m--- a\033[0m\n\033[1m+++ b\033[0m\n\033[36m@@ -1,2 +1,2 @@\033[0m\n same\n" +
+		"\033[31m-old\033[0m\n\033[32m+new\033[0m\n"
+
+	if got := ColorizeDiff(diff); got != want {
+		t.Errorf("ColorizeDiff() = %q, want %q", got, want)
+	}
+	if got := ColorizeDiff(""); got != "" {
+		t.Errorf("ColorizeDiff(\"\") = %q, want empty", got)
+	}
+}
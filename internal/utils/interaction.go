@@ -3,8 +3,11 @@ package utils
 import (
 	"bufio"
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/logging"
 )
 
 // InteractionService provides utilities for user interaction
@@ -63,32 +66,38 @@ func DisplayError(err error) {
 	fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
 }
 
-// DisplaySuccess displays a success message
+// DisplaySuccess displays a success message, unless only errors are shown
 func DisplaySuccess(message string) {
-	fmt.Printf("✅ %s\n", message)
+	if logging.Enabled(slog.LevelInfo) {
+		fmt.Printf("✅ %s\n", message)
+	}
 }
 
-// DisplayWarning displays a warning message
+// DisplayWarning displays a warning message, unless only errors are shown
 func DisplayWarning(message string) {
-	fmt.Printf("⚠️  %s\n", message)
+	if logging.Enabled(slog.LevelWarn) {
+		fmt.Printf("⚠️  %s\n", message)
+	}
 }
 
-// DisplayInfo displays an informational message
+// DisplayInfo displays an informational message, unless only errors are shown
 func DisplayInfo(message string) {
-	fmt.Printf("ℹ️  %s\n", message)
+	if logging.Enabled(slog.LevelInfo) {
+		fmt.Printf("ℹ️  %s\n", message)
+	}
 }
 
-// VerbosePrintln prints a message only if verbose mode is enabled
+// VerbosePrintln logs a debug message if verbose mode is enabled
 func VerbosePrintln(verbose bool, message string) {
 	if verbose {
-		fmt.Printf("🔍 %s\n", message)
+		slog.Debug(message)
 	}
 }
 
-// VerbosePrintf prints a formatted message only if verbose mode is enabled
+// VerbosePrintf logs a formatted debug message if verbose mode is enabled
 func VerbosePrintf(verbose bool, format string, args ...interface{}) {
 	if verbose {
-		fmt.Printf("🔍 "+format, args...)
+		slog.Debug(strings.TrimSuffix(fmt.Sprintf(format, args...), "\n"))
 	}
 }
 
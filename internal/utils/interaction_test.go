@@ -5,9 +5,12 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/Fomo-Driven-Development/strategic-claude-basic-cli/internal/logging"
 )
 
 func TestInteractionService_ConfirmPrompt(t *testing.T) {
@@ -349,27 +352,14 @@ func TestVerbosePrintln(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Capture stdout
-			oldStdout := os.Stdout
-			r, w, err := os.Pipe()
-			if err != nil {
-				t.Fatalf("Failed to create pipe: %v", err)
-			}
-			os.Stdout = w
+			// Capture the debug log
+			var output bytes.Buffer
+			logging.Setup(&output, slog.LevelDebug)
+			defer logging.Setup(os.Stderr, slog.LevelInfo)
 
 			// Run the function
 			VerbosePrintln(tt.verbose, tt.message)
 
-			// Restore stdout and read output
-			w.Close()
-			os.Stdout = oldStdout
-
-			var output bytes.Buffer
-			_, err = io.Copy(&output, r)
-			if err != nil {
-				t.Fatalf("Failed to read output: %v", err)
-			}
-
 			outputStr := output.String()
 			hasOutput := len(strings.TrimSpace(outputStr)) > 0
 
@@ -412,27 +402,14 @@ func TestVerbosePrintf(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Capture stdout
-			oldStdout := os.Stdout
-			r, w, err := os.Pipe()
-			if err != nil {
-				t.Fatalf("Failed to create pipe: %v", err)
-			}
-			os.Stdout = w
+			// Capture the debug log
+			var output bytes.Buffer
+			logging.Setup(&output, slog.LevelDebug)
+			defer logging.Setup(os.Stderr, slog.LevelInfo)
 
 			// Run the function
 			VerbosePrintf(tt.verbose, tt.format, tt.args...)
 
-			// Restore stdout and read output
-			w.Close()
-			os.Stdout = oldStdout
-
-			var output bytes.Buffer
-			_, err = io.Copy(&output, r)
-			if err != nil {
-				t.Fatalf("Failed to read output: %v", err)
-			}
-
 			outputStr := output.String()
 			hasOutput := len(strings.TrimSpace(outputStr)) > 0
 